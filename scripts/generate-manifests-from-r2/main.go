@@ -25,6 +25,7 @@ type BinaryMeta struct {
 	SourceURL    string `json:"source_url"`
 	MirroredAt   string `json:"mirrored_at"`
 	Size         int64  `json:"size"`
+	ReleaseDate  string `json:"release_date,omitempty"` // upstream release date (YYYY-MM-DD), if known
 }
 
 // ManifestDownload represents a download entry in the manifest
@@ -32,16 +33,24 @@ type ManifestDownload struct {
 	URL          string `json:"url"`
 	SHA256       string `json:"sha256,omitempty"`
 	SHA256Source string `json:"sha256_source,omitempty"`
+	Build        string `json:"build,omitempty"`
+}
+
+// ManifestRelease represents a version's release information in the manifest
+type ManifestRelease struct {
+	Date string `json:"date,omitempty"`
+	EOL  bool   `json:"eol,omitempty"`
 }
 
 // Manifest represents the output manifest structure
 type Manifest struct {
 	Version  int                                      `json:"version"`
 	Versions map[string]map[string]*ManifestDownload `json:"versions"`
+	Releases map[string]*ManifestRelease             `json:"releases,omitempty"`
 }
 
 var (
-	runtimeFlag  = flag.String("runtime", "", "Runtime to generate (node, python, ruby, or all)")
+	runtimeFlag  = flag.String("runtime", "", "Runtime to generate (node, python, ruby, php, or all)")
 	outputDir    = flag.String("output-dir", "src/internal/manifest/data", "Output directory for manifests")
 	baseURL      = flag.String("base-url", "https://builds.dtvem.io", "Base URL for binary downloads")
 	r2Endpoint   = flag.String("r2-endpoint", "", "R2 endpoint URL")
@@ -54,11 +63,15 @@ var (
 // metaKeyPattern matches paths like "node/20.18.0/linux-amd64.meta.json"
 var metaKeyPattern = regexp.MustCompile(`^([^/]+)/([^/]+)/([^/]+)\.meta\.json$`)
 
+// pythonBuildPattern captures the release date from python-build-standalone archive names
+// like "cpython-3.13.1+20251209-x86_64-unknown-linux-gnu-install_only.tar.gz"
+var pythonBuildPattern = regexp.MustCompile(`cpython-[0-9.]+\+([0-9]{8})-`)
+
 func main() {
 	flag.Parse()
 
 	if *runtimeFlag == "" {
-		fmt.Fprintln(os.Stderr, "Error: --runtime is required (node, python, ruby, or all)")
+		fmt.Fprintln(os.Stderr, "Error: --runtime is required (node, python, ruby, php, or all)")
 		os.Exit(1)
 	}
 
@@ -69,7 +82,7 @@ func main() {
 
 	runtimes := []string{*runtimeFlag}
 	if *runtimeFlag == "all" {
-		runtimes = []string{"node", "python", "ruby"}
+		runtimes = []string{"node", "python", "ruby", "php"}
 	}
 
 	// Create S3 client
@@ -106,6 +119,10 @@ func main() {
 
 		// Write manifest file
 		outputPath := filepath.Join(*outputDir, runtime+".json")
+		if err := mergeReleases(manifest, outputPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading existing manifest for %s: %v\n", runtime, err)
+			os.Exit(1)
+		}
 		if err := writeManifest(manifest, outputPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing manifest for %s: %v\n", runtime, err)
 			os.Exit(1)
@@ -141,6 +158,7 @@ func generateManifest(client *s3.Client, runtime string) (*Manifest, error) {
 	manifest := &Manifest{
 		Version:  1,
 		Versions: make(map[string]map[string]*ManifestDownload),
+		Releases: make(map[string]*ManifestRelease),
 	}
 
 	// List all .meta.json files for this runtime
@@ -204,12 +222,68 @@ func generateManifest(client *s3.Client, runtime string) (*Manifest, error) {
 			URL:          binaryURL,
 			SHA256:       meta.SHA256,
 			SHA256Source: meta.SHA256Source,
+			Build:        buildTag(runtime, meta.SourceURL),
+		}
+
+		if meta.ReleaseDate != "" {
+			if manifest.Releases[version] == nil {
+				manifest.Releases[version] = &ManifestRelease{}
+			}
+			manifest.Releases[version].Date = meta.ReleaseDate
 		}
 	}
 
 	return manifest, nil
 }
 
+// mergeReleases carries release information from the existing manifest at path into
+// the generated one. EOL status is maintained by hand in the manifest files, so it
+// must survive regeneration; release dates from metadata take precedence.
+func mergeReleases(manifest *Manifest, path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var existing Manifest
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return err
+	}
+
+	for version, release := range existing.Releases {
+		if manifest.Versions[version] == nil || release == nil {
+			continue
+		}
+		merged := manifest.Releases[version]
+		if merged == nil {
+			merged = &ManifestRelease{}
+			manifest.Releases[version] = merged
+		}
+		if merged.Date == "" {
+			merged.Date = release.Date
+		}
+		merged.EOL = merged.EOL || release.EOL
+	}
+
+	return nil
+}
+
+// buildTag returns the upstream build tag of a binary, if its runtime publishes one
+func buildTag(runtime, sourceURL string) string {
+	if runtime != "python" {
+		return ""
+	}
+	// The "+" may be percent-encoded in release URLs
+	sourceURL = strings.ReplaceAll(sourceURL, "%2B", "+")
+	if matches := pythonBuildPattern.FindStringSubmatch(sourceURL); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
 func downloadMeta(client *s3.Client, key string) (*BinaryMeta, error) {
 	resp, err := client.GetObject(context.Background(), &s3.GetObjectInput{
 		Bucket: r2Bucket,
@@ -263,6 +337,7 @@ func writeManifest(manifest *Manifest, path string) error {
 	sortedManifest := &Manifest{
 		Version:  manifest.Version,
 		Versions: make(map[string]map[string]*ManifestDownload),
+		Releases: manifest.Releases,
 	}
 
 	// Get sorted version keys
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Upstream lists of releases with their dates, one per runtime
+const (
+	nodeReleasesURL   = "https://nodejs.org/dist/index.json"
+	pythonReleasesURL = "https://www.python.org/api/v2/downloads/release/?is_published=true"
+	rubyReleasesURL   = "https://raw.githubusercontent.com/ruby/www.ruby-lang.org/master/_data/releases.yml"
+	phpReleasesURL    = "https://www.php.net/releases/index.php?json&max=-1&version=%d"
+)
+
+// phpMajorVersions are the PHP major versions whose release dates are looked up
+var phpMajorVersions = []int{7, 8}
+
+// pythonReleaseNamePattern matches the names of final Python releases, e.g. "Python 3.12.1"
+var pythonReleaseNamePattern = regexp.MustCompile(`^Python (\d+\.\d+\.\d+)$`)
+
+// rubyReleaseLinePattern matches the version and date lines of Ruby's releases.yml
+var rubyReleaseLinePattern = regexp.MustCompile(`^\s*-?\s*(version|date):\s*"?([^"\s]+)"?\s*$`)
+
+// fetchReleaseDates returns the upstream release date (YYYY-MM-DD) of each version
+// of a runtime
+func fetchReleaseDates(runtime string) (map[string]string, error) {
+	switch runtime {
+	case "node":
+		var releases []struct {
+			Version string `json:"version"`
+			Date    string `json:"date"`
+		}
+		if err := fetchJSON(nodeReleasesURL, &releases); err != nil {
+			return nil, err
+		}
+		dates := make(map[string]string, len(releases))
+		for _, r := range releases {
+			dates[strings.TrimPrefix(r.Version, "v")] = r.Date
+		}
+		return dates, nil
+
+	case "python":
+		var releases []struct {
+			Name        string `json:"name"`
+			ReleaseDate string `json:"release_date"`
+		}
+		if err := fetchJSON(pythonReleasesURL, &releases); err != nil {
+			return nil, err
+		}
+		dates := make(map[string]string, len(releases))
+		for _, r := range releases {
+			if matches := pythonReleaseNamePattern.FindStringSubmatch(r.Name); matches != nil && len(r.ReleaseDate) >= 10 {
+				dates[matches[1]] = r.ReleaseDate[:10]
+			}
+		}
+		return dates, nil
+
+	case "ruby":
+		resp, err := http.Get(rubyReleasesURL)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, rubyReleasesURL)
+		}
+		return parseRubyReleaseDates(bufio.NewScanner(resp.Body))
+
+	case "php":
+		dates := make(map[string]string)
+		for _, major := range phpMajorVersions {
+			var releases map[string]struct {
+				Date string `json:"date"`
+			}
+			if err := fetchJSON(fmt.Sprintf(phpReleasesURL, major), &releases); err != nil {
+				return nil, err
+			}
+			for version, r := range releases {
+				// php.net writes dates like "18 Dec 2025"
+				if date, err := time.Parse("02 Jan 2006", r.Date); err == nil {
+					dates[version] = date.Format(time.DateOnly)
+				}
+			}
+		}
+		return dates, nil
+	}
+	return nil, fmt.Errorf("no release dates known for runtime %s", runtime)
+}
+
+// parseRubyReleaseDates reads the entries of Ruby's releases.yml, each a version line
+// followed by a date line
+func parseRubyReleaseDates(scanner *bufio.Scanner) (map[string]string, error) {
+	dates := make(map[string]string)
+	version := ""
+	for scanner.Scan() {
+		matches := rubyReleaseLinePattern.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+		switch matches[1] {
+		case "version":
+			version = matches[2]
+		case "date":
+			if version != "" {
+				dates[version] = matches[2]
+				version = ""
+			}
+		}
+	}
+	return dates, scanner.Err()
+}
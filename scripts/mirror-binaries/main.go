@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -21,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 // Manifest represents the structure of a runtime manifest
@@ -41,6 +43,17 @@ type BinaryMeta struct {
 	SourceURL    string `json:"source_url"`
 	MirroredAt   string `json:"mirrored_at"`
 	Size         int64  `json:"size"`
+	ReleaseDate  string `json:"release_date,omitempty"` // upstream release date (YYYY-MM-DD), if known
+}
+
+// MismatchReport is stored next to a quarantined binary whose checksum didn't
+// match the upstream manifest, for investigating tampering or corruption
+type MismatchReport struct {
+	ExpectedSHA256 string `json:"expected_sha256"`
+	ActualSHA256   string `json:"actual_sha256"`
+	SourceURL      string `json:"source_url"`
+	DetectedAt     string `json:"detected_at"`
+	Size           int64  `json:"size"`
 }
 
 // MirrorJob represents a single file to mirror
@@ -50,39 +63,42 @@ type MirrorJob struct {
 	Platform       string
 	URL            string
 	UpstreamSHA256 string // Checksum from upstream manifest (may be empty)
+	ReleaseDate    string // Upstream release date (may be empty)
 	R2Key          string
 	MetaKey        string
 }
 
 // Stats tracks mirroring statistics
 type Stats struct {
-	Total            int64
-	Skipped          int64
-	Mirrored         int64
-	Failed           int64
-	BytesDown        int64
-	UpstreamChecksum int64
+	Total             int64
+	Skipped           int64
+	Mirrored          int64
+	Failed            int64
+	BytesDown         int64
+	UpstreamChecksum  int64
 	GeneratedChecksum int64
 }
 
 var (
-	runtimeFlag  = flag.String("runtime", "", "Runtime to mirror (node, python, ruby, or all)")
-	dryRun       = flag.Bool("dry-run", false, "Report what would be done without doing it")
-	syncOnly     = flag.Bool("sync-only", false, "Only mirror files not already in R2")
-	manifestDir  = flag.String("manifest-dir", "src/internal/manifest/data", "Directory containing manifest files")
-	r2Endpoint   = flag.String("r2-endpoint", "", "R2 endpoint URL")
-	r2Bucket     = flag.String("r2-bucket", "", "R2 bucket name")
-	r2AccessKey  = flag.String("r2-access-key", "", "R2 access key ID")
-	r2SecretKey  = flag.String("r2-secret-key", "", "R2 secret access key")
-	workers      = flag.Int("workers", 10, "Number of parallel workers")
-	retries      = flag.Int("retries", 3, "Number of retries for failed downloads")
+	runtimeFlag   = flag.String("runtime", "", "Runtime to mirror (node, python, ruby, php, or all)")
+	dryRun        = flag.Bool("dry-run", false, "Report what would be done without doing it")
+	syncOnly      = flag.Bool("sync-only", false, "Only mirror files not already in R2")
+	manifestDir   = flag.String("manifest-dir", "src/internal/manifest/data", "Directory containing manifest files")
+	r2Endpoint    = flag.String("r2-endpoint", "", "R2 endpoint URL")
+	r2Bucket      = flag.String("r2-bucket", "", "R2 bucket name")
+	r2AccessKey   = flag.String("r2-access-key", "", "R2 access key ID")
+	r2SecretKey   = flag.String("r2-secret-key", "", "R2 secret access key")
+	workers       = flag.Int("workers", 10, "Number of parallel workers")
+	retries       = flag.Int("retries", 3, "Number of retries for failed downloads")
+	quarantine    = flag.Bool("quarantine", true, "Upload downloads with a checksum mismatch under quarantine/ for investigation")
+	backfillDates = flag.Bool("backfill-dates", false, "Only add release dates to the metadata of files already in R2")
 )
 
 func main() {
 	flag.Parse()
 
 	if *runtimeFlag == "" {
-		fmt.Fprintln(os.Stderr, "Error: --runtime is required (node, python, ruby, or all)")
+		fmt.Fprintln(os.Stderr, "Error: --runtime is required (node, python, ruby, php, or all)")
 		os.Exit(1)
 	}
 
@@ -95,7 +111,7 @@ func main() {
 
 	runtimes := []string{*runtimeFlag}
 	if *runtimeFlag == "all" {
-		runtimes = []string{"node", "python", "ruby"}
+		runtimes = []string{"node", "python", "ruby", "php"}
 	}
 
 	// Initialize S3 client for R2
@@ -124,15 +140,52 @@ func main() {
 	// Collect all jobs
 	var jobs []MirrorJob
 	for _, rt := range runtimes {
-		manifestPath := filepath.Join(*manifestDir, rt+".json")
-		rtJobs, err := loadJobs(rt, manifestPath)
+		var rtJobs []MirrorJob
+		var err error
+		if rt == "php" {
+			// PHP's builds are listed upstream, not in a data manifest
+			var manifest *Manifest
+			if manifest, err = generatePhpManifest(); err == nil {
+				rtJobs = jobsFromManifest(rt, manifest)
+			}
+		} else {
+			rtJobs, err = loadJobs(rt, filepath.Join(*manifestDir, rt+".json"))
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading manifest for %s: %v\n", rt, err)
 			os.Exit(1)
 		}
+
+		// Release dates are informational, so mirror without them if they can't be fetched
+		dates, err := fetchReleaseDates(rt)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: no release dates for %s: %v\n", rt, err)
+		}
+		for i := range rtJobs {
+			rtJobs[i].ReleaseDate = dates[rtJobs[i].Version]
+		}
 		jobs = append(jobs, rtJobs...)
 	}
 
+	if *backfillDates {
+		if *dryRun {
+			dated := 0
+			for _, job := range jobs {
+				if job.ReleaseDate != "" {
+					dated++
+				}
+			}
+			fmt.Printf("\n[DRY RUN] Would add release dates to the metadata of up to %d of %d files\n", dated, len(jobs))
+			return
+		}
+		updated, failed := backfillReleaseDates(s3Client, jobs)
+		fmt.Printf("\nAdded release dates to %d metadata files (%d failed)\n", updated, failed)
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Printf("Total jobs to process: %d\n", len(jobs))
 
 	if *dryRun {
@@ -241,6 +294,11 @@ func loadJobs(runtime, manifestPath string) ([]MirrorJob, error) {
 		return nil, err
 	}
 
+	return jobsFromManifest(runtime, &manifest), nil
+}
+
+// jobsFromManifest returns a mirror job for every download in manifest
+func jobsFromManifest(runtime string, manifest *Manifest) []MirrorJob {
 	var jobs []MirrorJob
 	for version, platforms := range manifest.Versions {
 		for platform, dl := range platforms {
@@ -265,7 +323,7 @@ func loadJobs(runtime, manifestPath string) ([]MirrorJob, error) {
 		}
 	}
 
-	return jobs, nil
+	return jobs
 }
 
 func getExtension(url string) string {
@@ -372,7 +430,15 @@ func doMirror(client *s3.Client, job MirrorJob, stats *Stats) error {
 	var checksumSource string
 	if job.UpstreamSHA256 != "" {
 		if actualChecksum != job.UpstreamSHA256 {
-			return fmt.Errorf("checksum mismatch: expected %s, got %s", job.UpstreamSHA256, actualChecksum)
+			mismatch := fmt.Errorf("checksum mismatch: expected %s, got %s", job.UpstreamSHA256, actualChecksum)
+			if !*quarantine {
+				return mismatch
+			}
+			quarantineKey, err := quarantineFile(client, job, body, actualChecksum)
+			if err != nil {
+				return fmt.Errorf("%w (quarantine failed: %v)", mismatch, err)
+			}
+			return fmt.Errorf("%w (quarantined as %s)", mismatch, quarantineKey)
 		}
 		checksumSource = "upstream"
 		atomic.AddInt64(&stats.UpstreamChecksum, 1)
@@ -410,8 +476,18 @@ func doMirror(client *s3.Client, job MirrorJob, stats *Stats) error {
 		SourceURL:    job.URL,
 		MirroredAt:   time.Now().UTC().Format(time.RFC3339),
 		Size:         int64(len(body)),
+		ReleaseDate:  job.ReleaseDate,
+	}
+	if err := putMeta(client, job.MetaKey, meta); err != nil {
+		return err
 	}
 
+	fmt.Printf("Mirrored: %s (%d bytes, checksum: %s)\n", job.R2Key, len(body), checksumSource)
+	return nil
+}
+
+// putMeta uploads the metadata of a mirrored binary
+func putMeta(client *s3.Client, key string, meta BinaryMeta) error {
 	metaJSON, err := json.MarshalIndent(meta, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal metadata failed: %w", err)
@@ -419,7 +495,7 @@ func doMirror(client *s3.Client, job MirrorJob, stats *Stats) error {
 
 	_, err = client.PutObject(context.Background(), &s3.PutObjectInput{
 		Bucket:       r2Bucket,
-		Key:          aws.String(job.MetaKey),
+		Key:          aws.String(key),
 		Body:         bytes.NewReader(metaJSON),
 		ContentType:  aws.String("application/json"),
 		CacheControl: aws.String("public, max-age=300"), // Short cache for metadata
@@ -427,7 +503,98 @@ func doMirror(client *s3.Client, job MirrorJob, stats *Stats) error {
 	if err != nil {
 		return fmt.Errorf("upload metadata failed: %w", err)
 	}
-
-	fmt.Printf("Mirrored: %s (%d bytes, checksum: %s)\n", job.R2Key, len(body), checksumSource)
 	return nil
 }
+
+// backfillReleaseDates adds the release date of each job to the metadata already in R2
+// for it, for files mirrored before metadata carried release dates. Files that aren't
+// mirrored yet, or whose metadata already has a date, are left alone.
+func backfillReleaseDates(client *s3.Client, jobs []MirrorJob) (updated, failed int) {
+	for _, job := range jobs {
+		if job.ReleaseDate == "" {
+			continue
+		}
+
+		obj, err := client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: r2Bucket,
+			Key:    aws.String(job.MetaKey),
+		})
+		if err != nil {
+			var noKey *types.NoSuchKey
+			if !errors.As(err, &noKey) {
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", job.MetaKey, err)
+				failed++
+			}
+			continue
+		}
+		var meta BinaryMeta
+		err = json.NewDecoder(obj.Body).Decode(&meta)
+		_ = obj.Body.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", job.MetaKey, err)
+			failed++
+			continue
+		}
+		if meta.ReleaseDate != "" {
+			continue
+		}
+
+		meta.ReleaseDate = job.ReleaseDate
+		if err := putMeta(client, job.MetaKey, meta); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", job.MetaKey, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Dated: %s (%s)\n", job.MetaKey, job.ReleaseDate)
+		updated++
+	}
+	return updated, failed
+}
+
+// quarantineKeys returns the keys a mismatched download and its report are uploaded to:
+// quarantine/<runtime>/<version>/<platform><ext> and the matching .mismatch.json
+func quarantineKeys(job MirrorJob) (binaryKey, reportKey string) {
+	prefix := fmt.Sprintf("quarantine/%s/%s/%s", job.Runtime, job.Version, job.Platform)
+	return prefix + getExtension(job.URL), prefix + ".mismatch.json"
+}
+
+// quarantineFile uploads a download whose checksum didn't match the upstream manifest,
+// along with a report of the expected and actual checksums. It returns the binary's key.
+func quarantineFile(client *s3.Client, job MirrorJob, body []byte, actualChecksum string) (string, error) {
+	binaryKey, reportKey := quarantineKeys(job)
+
+	_, err := client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      r2Bucket,
+		Key:         aws.String(binaryKey),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/octet-stream"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("upload quarantined binary failed: %w", err)
+	}
+
+	report := MismatchReport{
+		ExpectedSHA256: job.UpstreamSHA256,
+		ActualSHA256:   actualChecksum,
+		SourceURL:      job.URL,
+		DetectedAt:     time.Now().UTC().Format(time.RFC3339),
+		Size:           int64(len(body)),
+	}
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal mismatch report failed: %w", err)
+	}
+
+	_, err = client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      r2Bucket,
+		Key:         aws.String(reportKey),
+		Body:        bytes.NewReader(reportJSON),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("upload mismatch report failed: %w", err)
+	}
+
+	fmt.Printf("Quarantined: %s (expected %s, got %s)\n", binaryKey, job.UpstreamSHA256, actualChecksum)
+	return binaryKey, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// PHP has no single upstream manifest: Windows builds come from windows.php.net,
+// macOS and Linux builds from static-php-cli
+const (
+	phpWindowsReleasesURL = "https://windows.php.net/downloads/releases/releases.json"
+	phpWindowsBaseURL     = "https://windows.php.net/downloads/releases/"
+	phpStaticListURL      = "https://dl.static-php.dev/static-php-cli/common/?format=json"
+	phpStaticBaseURL      = "https://dl.static-php.dev/static-php-cli/common/"
+)
+
+// phpWindowsBuild is a build of one release in windows.php.net's releases.json
+type phpWindowsBuild struct {
+	Zip struct {
+		Path   string `json:"path"`
+		SHA256 string `json:"sha256"`
+	} `json:"zip"`
+}
+
+// phpStaticFilePattern matches the CLI tarballs of static-php-cli, e.g.
+// php-8.3.10-cli-linux-x86_64.tar.gz
+var phpStaticFilePattern = regexp.MustCompile(`^php-(\d+\.\d+\.\d+)-cli-(linux|macos)-(x86_64|aarch64)\.tar\.gz$`)
+
+// phpStaticPlatforms maps the OS and architecture of static-php-cli file names to platforms
+var phpStaticPlatforms = map[string]string{
+	"linux-x86_64":  "linux-amd64",
+	"linux-aarch64": "linux-arm64",
+	"macos-x86_64":  "darwin-amd64",
+	"macos-aarch64": "darwin-arm64",
+}
+
+// generatePhpManifest builds the upstream manifest of PHP from the Windows releases
+// and the static-php-cli file listing, keyed on platform strings like the data manifests
+func generatePhpManifest() (*Manifest, error) {
+	manifest := &Manifest{Versions: map[string]map[string]*Download{}}
+	add := func(version, platform string, dl *Download) {
+		if manifest.Versions[version] == nil {
+			manifest.Versions[version] = map[string]*Download{}
+		}
+		manifest.Versions[version][platform] = dl
+	}
+
+	var releases map[string]map[string]json.RawMessage
+	if err := fetchJSON(phpWindowsReleasesURL, &releases); err != nil {
+		return nil, fmt.Errorf("failed to fetch Windows releases: %w", err)
+	}
+	for _, release := range releases {
+		var version string
+		if err := json.Unmarshal(release["version"], &version); err != nil || version == "" {
+			continue
+		}
+		// Non-thread-safe x64 builds are the ones meant for the CLI
+		for key, raw := range release {
+			if !strings.HasPrefix(key, "nts-") || !strings.HasSuffix(key, "-x64") {
+				continue
+			}
+			var build phpWindowsBuild
+			if err := json.Unmarshal(raw, &build); err != nil || build.Zip.Path == "" {
+				continue
+			}
+			add(version, "windows-amd64", &Download{URL: phpWindowsBaseURL + build.Zip.Path, SHA256: build.Zip.SHA256})
+		}
+	}
+
+	var files []struct {
+		Name string `json:"name"`
+	}
+	if err := fetchJSON(phpStaticListURL, &files); err != nil {
+		return nil, fmt.Errorf("failed to fetch static-php-cli builds: %w", err)
+	}
+	for _, file := range files {
+		matches := phpStaticFilePattern.FindStringSubmatch(file.Name)
+		if matches == nil {
+			continue
+		}
+		if platform, ok := phpStaticPlatforms[matches[2]+"-"+matches[3]]; ok {
+			add(matches[1], platform, &Download{URL: phpStaticBaseURL + file.Name})
+		}
+	}
+
+	return manifest, nil
+}
+
+// fetchJSON decodes the JSON response of a GET request to url into v
+func fetchJSON(url string, v any) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
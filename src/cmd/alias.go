@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/tui"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias <runtime> <name> <version>",
+	Short: "Define a named alias for a runtime version",
+	Long: `Define a personal alias for a runtime version.
+
+Aliases are stored in aliases.json in the dtvem config directory and can be
+used anywhere a version is accepted (install, global, local).
+The built-in names latest, lts and system cannot be redefined.
+
+Examples:
+  dtvem alias node work 18.16.0
+  dtvem alias python stable 3.12.1
+  dtvem install node work
+  dtvem alias list
+  dtvem alias remove node work`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		runtimeName, name, version := args[0], args[1], runtime.NormalizeVersion(args[2])
+
+		provider, err := runtime.Get(runtimeName)
+		if err != nil {
+			reportError(err)
+			ui.Info("Available runtimes: %s", availableRuntimes())
+			os.Exit(1)
+		}
+
+		if err := config.SetAlias(runtimeName, name, version); err != nil {
+			ui.Error("Failed to set alias: %v", err)
+			os.Exit(1)
+		}
+
+		ui.Success("%s alias %s now points to %s", provider.DisplayName(), ui.Highlight(name), ui.HighlightVersion(version))
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list [runtime]",
+	Short: "List defined version aliases",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		aliases, err := config.LoadAliases()
+		if err != nil {
+			ui.Error("Failed to read aliases: %v", err)
+			os.Exit(1)
+		}
+
+		runtimeNames := make([]string, 0, len(aliases))
+		for runtimeName := range aliases {
+			if len(args) == 1 && runtimeName != args[0] {
+				continue
+			}
+			runtimeNames = append(runtimeNames, runtimeName)
+		}
+		sort.Strings(runtimeNames)
+
+		if len(runtimeNames) == 0 {
+			ui.Info("No aliases defined")
+			ui.Info("Define one with: dtvem alias <runtime> <name> <version>")
+			return
+		}
+
+		table := tui.NewTable("Runtime", "Alias", "Version")
+		table.SetTitle("Version Aliases")
+		for _, runtimeName := range runtimeNames {
+			names := make([]string, 0, len(aliases[runtimeName]))
+			for name := range aliases[runtimeName] {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				table.AddRow(runtimeName, name, aliases[runtimeName][name])
+			}
+		}
+		fmt.Println(table.Render())
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:   "remove <runtime> <name>",
+	Short: "Remove a version alias",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.RemoveAlias(args[0], args[1]); err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		ui.Success("Removed %s alias %s", args[0], args[1])
+	},
+}
+
+// resolveVersionArg resolves a user-supplied version argument, normalizing it
+// (e.g. "v20.11.1" becomes "20.11.1") and expanding aliases
+func resolveVersionArg(runtimeName, version string) string {
+	version = runtime.NormalizeVersion(version)
+	resolved := runtime.NormalizeVersion(config.ResolveAlias(runtimeName, version))
+	if resolved != version {
+		ui.Info("Using alias %s -> %s", ui.Highlight(version), ui.HighlightVersion(resolved))
+	}
+	return resolved
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
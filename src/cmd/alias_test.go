@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+func TestResolveVersionArg(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	if err := config.SetAlias("node", "work", "v18.16.0"); err != nil {
+		t.Fatalf("SetAlias() error: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"20.11.1", "20.11.1"},
+		{"v20.11.1", "20.11.1"},
+		{" 20.11.1\n", "20.11.1"},
+		{"work", "18.16.0"},
+		{"work\n", "18.16.0"},
+		{"lts", "lts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := resolveVersionArg("node", tt.input); got != tt.want {
+				t.Errorf("resolveVersionArg(node, %q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
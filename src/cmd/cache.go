@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var cacheVerifyYesFlag bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the download cache",
+	Long: `Manage the cache of downloaded runtime archives.
+
+Examples:
+  dtvem cache verify
+  dtvem cache verify --yes   # Delete damaged archives without asking`,
+	Args: cobra.NoArgs,
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cached archives against their recorded checksums",
+	Long: `Re-hash every archive in the download cache and compare it with the checksum
+recorded when it was downloaded. Archives that no longer match (disk corruption,
+tampering) or that were never completely downloaded are reported, and you are
+offered to delete them so they are downloaded again on the next install.
+
+Exits with a non-zero status if damaged archives are left in the cache.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		remaining, err := runCacheVerify(cacheVerifyYesFlag)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		if remaining > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// runCacheVerify verifies the archive cache, reports each archive and deletes the
+// damaged ones after confirmation. It returns how many damaged archives are left.
+func runCacheVerify(skipConfirmation bool) (int, error) {
+	statuses, err := download.VerifyCache()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(statuses) == 0 {
+		ui.Info("The download cache has no archives")
+		return 0, nil
+	}
+
+	var damaged []download.CachedArchiveStatus
+	for _, status := range statuses {
+		name := status.Runtime + "/" + status.Version + "/" + status.Name
+		switch status.State {
+		case download.CachedArchiveOK:
+			ui.Success("%s", name)
+		case download.CachedArchiveIncomplete:
+			ui.Warning("%s: incomplete download (%v)", name, status.Err)
+			damaged = append(damaged, status)
+		default:
+			ui.Error("%s: %v", name, status.Err)
+			damaged = append(damaged, status)
+		}
+	}
+
+	if len(damaged) == 0 {
+		ui.Success("All %d cached archive(s) match their checksums", len(statuses))
+		return 0, nil
+	}
+
+	if !skipConfirmation && !confirmDeleteArchives(len(damaged)) {
+		return len(damaged), nil
+	}
+
+	for _, status := range damaged {
+		download.RemoveCachedArchive(status.Runtime, status.Version, status.Name)
+	}
+	ui.Success("Deleted %d damaged archive(s); they will be downloaded again when needed", len(damaged))
+	return 0, nil
+}
+
+// confirmDeleteArchives asks the user whether to delete the damaged archives
+func confirmDeleteArchives(count int) bool {
+	ui.Printf("Delete %d damaged archive(s)? [y/N]: ", count)
+
+	var response string
+	_, _ = fmt.Scanln(&response)
+	response = strings.ToLower(strings.TrimSpace(response))
+
+	return response == constants.ResponseY || response == constants.ResponseYes
+}
+
+func init() {
+	cacheVerifyCmd.Flags().BoolVarP(&cacheVerifyYesFlag, "yes", "y", false, "Delete damaged archives without confirmation")
+	cacheCmd.AddCommand(cacheVerifyCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/download"
+)
+
+// writeCachedArchive puts an archive into the archive cache, recording checksum for it
+// unless checksum is empty
+func writeCachedArchive(t *testing.T, runtimeName, version, archiveName, content, checksum string) string {
+	t.Helper()
+	archivePath := download.ArchiveCachePath(runtimeName, version, archiveName)
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		t.Fatalf("Failed to create cache directory: %v", err)
+	}
+	if err := os.WriteFile(archivePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	if checksum != "" {
+		if err := os.WriteFile(archivePath+".sha256", []byte(checksum+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write checksum: %v", err)
+		}
+	}
+	return archivePath
+}
+
+func TestRunCacheVerify_DeletesDamagedArchives(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	// SHA256 of "good"
+	goodSum := "770e607624d689265ca6c44884d0807d9b054d23c473c106c72be9de08b7376c"
+	good := writeCachedArchive(t, "node", "20.0.0", "node-v20.0.0.tar.gz", "good", goodSum)
+	corrupt := writeCachedArchive(t, "python", "3.13.1", "cpython-3.13.1.tar.gz", "tampered", goodSum)
+
+	remaining, err := runCacheVerify(true)
+	if err != nil {
+		t.Fatalf("runCacheVerify() error: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("runCacheVerify() left %d damaged archive(s), want 0", remaining)
+	}
+
+	if _, err := os.Stat(good); err != nil {
+		t.Errorf("good archive was removed: %v", err)
+	}
+	if _, err := os.Stat(corrupt); !os.IsNotExist(err) {
+		t.Errorf("corrupt archive was not removed: %v", err)
+	}
+}
+
+func TestRunCacheVerify_EmptyCache(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	if remaining, err := runCacheVerify(true); err != nil || remaining != 0 {
+		t.Errorf("runCacheVerify() = %d, %v, want 0 and no error", remaining, err)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var cleanCacheFlag bool
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove temporary files left by failed installs",
+	Long: `Remove the temporary directories (dtvem-*) that failed or interrupted installs
+leave in the system temp directory, and downloads in the archive cache that never
+finished. With --cache, every cached archive is removed too.
+
+Don't run this while an install is in progress.
+
+Examples:
+  dtvem clean
+  dtvem clean --cache   # Also empty the download cache`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runClean(os.TempDir(), cleanCacheFlag); err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// cleanStep is one kind of leftover removed by dtvem clean
+type cleanStep struct {
+	// What describes the items, e.g. "temporary directory(ies)"
+	What  string
+	Clean func() ([]download.CleanedItem, error)
+}
+
+// cleanSteps returns what dtvem clean removes: temp directories under tempRoot,
+// partial downloads and, if cache is set, the whole archive cache
+func cleanSteps(tempRoot string, cache bool) []cleanStep {
+	steps := []cleanStep{
+		{What: "temporary directory(ies)", Clean: func() ([]download.CleanedItem, error) { return download.CleanTempDirs(tempRoot) }},
+	}
+	if cache {
+		steps = append(steps, cleanStep{What: "cached file(s)", Clean: download.CleanArchiveCache})
+	} else {
+		steps = append(steps, cleanStep{What: "partial download(s)", Clean: download.CleanPartialArchives})
+	}
+	return steps
+}
+
+// runClean removes dtvem's leftovers and reports how much space was freed
+func runClean(tempRoot string, cache bool) error {
+	var total int64
+	var count int
+	var errs []error
+
+	for _, step := range cleanSteps(tempRoot, cache) {
+		removed, err := step.Clean()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(removed) == 0 {
+			continue
+		}
+
+		var freed int64
+		for _, item := range removed {
+			ui.Debug("Removed %s", item.Path)
+			freed += item.Size
+		}
+		total += freed
+		count += len(removed)
+		ui.Success("Removed %d %s (%s)", len(removed), step.What, ui.FormatBytes(freed))
+	}
+
+	if count == 0 && len(errs) == 0 {
+		ui.Info("Nothing to clean")
+	} else if count > 0 {
+		ui.Info("Freed %s", ui.FormatBytes(total))
+	}
+
+	return errors.Join(errs...)
+}
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanCacheFlag, "cache", false, "Also remove every archive in the download cache")
+	rootCmd.AddCommand(cleanCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/download"
+)
+
+func TestRunClean(t *testing.T) {
+	setupDebugEnv(t)
+	tempRoot := t.TempDir()
+
+	writeFile := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", path, err)
+		}
+	}
+
+	leftover := filepath.Join(tempRoot, download.TempDirPrefix+"node-20.11.1", "node")
+	unrelated := filepath.Join(tempRoot, "other-tool", "file")
+	complete := download.ArchiveCachePath("node", "1.0.0", "node.tar.gz")
+	partial := download.ArchiveCachePath("node", "1.0.0", "partial.tar.gz")
+	for _, path := range []string{leftover, unrelated, complete, complete + ".sha256", partial} {
+		writeFile(path)
+	}
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	if err := runClean(tempRoot, false); err != nil {
+		t.Fatalf("runClean() error: %v", err)
+	}
+	if exists(leftover) || exists(partial) {
+		t.Error("runClean() should remove dtvem temp directories and partial downloads")
+	}
+	if !exists(unrelated) || !exists(complete) {
+		t.Error("runClean() should keep other temp files and complete archives")
+	}
+
+	if err := runClean(tempRoot, true); err != nil {
+		t.Fatalf("runClean(cache) error: %v", err)
+	}
+	if exists(complete) {
+		t.Error("runClean() with --cache should empty the archive cache")
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/tui"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// settingValidators check values before they are stored with `dtvem config set`
+var settingValidators = map[string]func(value string) error{
+	config.SettingAutoInstall:   validateBoolSetting,
+	config.SettingAutoReshim:    validateBoolSetting,
+	config.SettingDotEnv:        validateBoolSetting,
+	config.SettingNode7z:        validateBoolSetting,
+	config.SettingNodeCorepack:  validateBoolSetting,
+	config.SettingNoUpdateCheck: validateBoolSetting,
+	config.SettingTrustProject:  validateBoolSetting,
+	config.SettingNetworkTimeout: func(value string) error {
+		_, err := download.ParseTimeout(value)
+		return err
+	},
+	config.SettingExtractWorkers: func(value string) error {
+		_, err := download.ParseExtractWorkers(value)
+		return err
+	},
+	config.SettingArch: func(value string) error {
+		_, err := manifest.PlatformKey(goruntime.GOOS, value)
+		return err
+	},
+}
+
+// validateBoolSetting accepts the values of a true/false setting
+func validateBoolSetting(value string) error {
+	if value != "true" && value != "false" {
+		return fmt.Errorf("must be true or false")
+	}
+	return nil
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change persistent settings",
+	Long: `View and change persistent dtvem settings.
+
+Settings are stored in config.json in the dtvem config directory.
+Each setting can also be set with an environment variable, which takes
+precedence over the stored value.
+
+A project can set network-timeout, arch, dotenv, node-7z and extract-workers
+in its own .dtvem/config.json, which takes precedence over config.json inside
+the project. Other settings are only read from a project after you opt in with
+'dtvem config set trust-project-settings true'.
+
+Examples:
+  dtvem config get
+  dtvem config get network-timeout
+  dtvem config set network-timeout 5m
+  dtvem config set auto-install true
+  dtvem config unset auto-install`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		printSettings()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Show settings and where their values come from",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			printSettings()
+			return
+		}
+
+		if _, ok := config.LookupSetting(args[0]); !ok {
+			ui.Error("Unknown setting: %s", args[0])
+			ui.Info("Available settings: %s", strings.Join(settingKeys(), ", "))
+			os.Exit(1)
+		}
+
+		value, source := config.GetSetting(args[0])
+		if source == config.SettingFromDefault {
+			ui.Info("%s is not set", args[0])
+			return
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Store a setting",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, value := args[0], strings.TrimSpace(args[1])
+
+		def, ok := config.LookupSetting(key)
+		if !ok {
+			ui.Error("Unknown setting: %s", key)
+			ui.Info("Available settings: %s", strings.Join(settingKeys(), ", "))
+			os.Exit(1)
+		}
+
+		if err := validateSetting(key, value); err != nil {
+			ui.Error("Invalid value for %s: %v", key, err)
+			os.Exit(1)
+		}
+
+		if err := config.SetSetting(key, value); err != nil {
+			ui.Error("Failed to save setting: %v", err)
+			os.Exit(1)
+		}
+
+		ui.Success("Set %s to %s", ui.Highlight(key), value)
+		switch _, source := config.GetSetting(key); source {
+		case config.SettingFromEnv:
+			ui.Warning("%s is set in your environment and overrides this setting", def.EnvVar)
+		case config.SettingFromProject:
+			_, projectPath, _ := config.LoadProjectSettings()
+			ui.Warning("%s sets %s and overrides this setting in this project", projectPath, key)
+		}
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a stored setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.UnsetSetting(args[0]); err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		ui.Success("Unset %s", args[0])
+	},
+}
+
+// validateSetting checks a value for a setting before it is stored
+func validateSetting(key, value string) error {
+	if value == "" {
+		return fmt.Errorf("value cannot be empty (use 'dtvem config unset %s')", key)
+	}
+	if validate, ok := settingValidators[key]; ok {
+		return validate(value)
+	}
+	return nil
+}
+
+// settingKeys returns the keys of all settings
+func settingKeys() []string {
+	defs := config.SettingDefinitions()
+	keys := make([]string, 0, len(defs))
+	for _, def := range defs {
+		keys = append(keys, def.Key)
+	}
+	return keys
+}
+
+// printSettings shows every setting with its effective value and source
+func printSettings() {
+	table := tui.NewTable("Setting", "Value", "Source", "Description")
+	table.SetTitle("Settings")
+
+	for _, def := range config.SettingDefinitions() {
+		value, source := config.GetSetting(def.Key)
+		sourceLabel := string(source)
+		if source == config.SettingFromEnv {
+			sourceLabel = def.EnvVar
+		}
+		table.AddRow(def.Key, value, sourceLabel, def.Description)
+	}
+
+	fmt.Println(table.Render())
+	ui.Info("Settings file: %s", config.SettingsPath())
+	if _, projectPath, err := config.LoadProjectSettings(); err != nil {
+		ui.Warning("Ignoring project settings: %v", err)
+	} else if projectPath != "" {
+		ui.Info("Project settings file: %s", projectPath)
+	}
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	rootCmd.AddCommand(configCmd)
+}
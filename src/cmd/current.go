@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/tui"
@@ -19,6 +20,45 @@ type runtimeStatus struct {
 	provider  runtime.Provider
 	version   string
 	installed bool
+	// systemPath is the system installation of a runtime set to the system version,
+	// empty when none was found
+	systemPath string
+}
+
+// currentStatus returns the status of a runtime's configured version. The system
+// version counts as installed when a system installation is found on PATH.
+func currentStatus(provider runtime.Provider, version string) runtimeStatus {
+	status := runtimeStatus{provider: provider, version: version}
+	if runtime.IsSystemVersion(version) {
+		status.systemPath, _ = systemExecutable(provider)
+		status.installed = status.systemPath != ""
+		return status
+	}
+	status.installed, _ = provider.IsInstalled(version)
+	return status
+}
+
+// statusText describes the status for the Status column
+func (rs runtimeStatus) statusText() string {
+	switch {
+	case runtime.IsSystemVersion(rs.version) && rs.installed:
+		return tui.CheckMark + " " + rs.systemPath
+	case runtime.IsSystemVersion(rs.version):
+		return tui.CrossMark + " not found in PATH"
+	case rs.installed:
+		return tui.CheckMark + " installed"
+	default:
+		return tui.CrossMark + " not installed"
+	}
+}
+
+// addStatusRow adds the status to a table, highlighted when the version is usable
+func addStatusRow(table *tui.Table, rs runtimeStatus) {
+	if rs.installed {
+		table.AddActiveRow(rs.provider.DisplayName(), rs.version, rs.statusText())
+	} else {
+		table.AddRow(rs.provider.DisplayName(), rs.version, rs.statusText())
+	}
 }
 
 var currentCmd = &cobra.Command{
@@ -60,12 +100,7 @@ func showAllVersions(yes, noInstall bool) {
 			// Not configured - skip it
 			continue
 		}
-		installed, _ := provider.IsInstalled(version)
-		configured = append(configured, runtimeStatus{
-			provider:  provider,
-			version:   version,
-			installed: installed,
-		})
+		configured = append(configured, currentStatus(provider, version))
 	}
 
 	if len(configured) == 0 {
@@ -79,10 +114,9 @@ func showAllVersions(yes, noInstall bool) {
 	var missing []runtimeStatus
 
 	for _, rs := range configured {
-		if rs.installed {
-			table.AddActiveRow(rs.provider.DisplayName(), rs.version, tui.CheckMark+" installed")
-		} else {
-			table.AddRow(rs.provider.DisplayName(), rs.version, tui.CrossMark+" not installed")
+		addStatusRow(table, rs)
+		// A missing system installation isn't dtvem's to install
+		if !rs.installed && !runtime.IsSystemVersion(rs.version) {
 			missing = append(missing, rs)
 		}
 	}
@@ -99,14 +133,23 @@ func showAllVersions(yes, noInstall bool) {
 		fmt.Println()
 		shouldInstall := yes || ui.PromptInstallMissing(missing)
 		if shouldInstall {
+			failed := false
 			for _, rs := range missing {
 				ui.Info("Installing %s %s...", rs.provider.DisplayName(), rs.version)
-				if err := rs.provider.Install(rs.version); err != nil {
+				err := runtime.CheckSupportedVersion(rs.provider, rs.version)
+				if err == nil {
+					err = rs.provider.Install(rs.version)
+				}
+				if err != nil {
 					ui.Error("Failed to install %s %s: %v", rs.provider.DisplayName(), rs.version, err)
+					failed = true
 				} else {
 					ui.Success("%s %s installed successfully", rs.provider.DisplayName(), rs.version)
 				}
 			}
+			if failed {
+				os.Exit(1)
+			}
 		}
 	}
 }
@@ -117,29 +160,31 @@ func showAllVersions(yes, noInstall bool) {
 func showSingleVersion(runtimeName string, yes, noInstall bool) {
 	provider, err := runtime.Get(runtimeName)
 	if err != nil {
-		ui.Error("%v", err)
-		ui.Info("Available runtimes: %v", runtime.List())
-		return
+		reportError(err)
+		ui.Info("Available runtimes: %s", availableRuntimes())
+		os.Exit(1)
 	}
 
 	version, err := provider.CurrentVersion()
 	if err != nil {
-		ui.Error("%v", err)
-		return
+		reportError(err)
+		os.Exit(1)
 	}
 
-	installed, _ := provider.IsInstalled(version)
+	rs := currentStatus(provider, version)
 
 	table := tui.NewTable("Runtime", "Version", "Status")
-	if installed {
-		table.AddActiveRow(provider.DisplayName(), version, tui.CheckMark+" installed")
-		fmt.Println(table.Render())
+	addStatusRow(table, rs)
+	fmt.Println(table.Render())
+	if rs.installed {
 		return
 	}
 
-	// Not installed - show with warning and prompt
-	table.AddRow(provider.DisplayName(), version, tui.CrossMark+" not installed")
-	fmt.Println(table.Render())
+	// A missing system installation isn't dtvem's to install
+	if runtime.IsSystemVersion(version) {
+		ui.Info("Install %s on the system, or choose a dtvem version: dtvem local %s <version>", provider.DisplayName(), provider.Name())
+		return
+	}
 
 	// Skip install prompts if --no-install flag is set
 	if noInstall {
@@ -149,9 +194,13 @@ func showSingleVersion(runtimeName string, yes, noInstall bool) {
 	fmt.Println()
 	shouldInstall := yes || ui.PromptInstall(provider.DisplayName(), version)
 	if shouldInstall {
-		if err := provider.Install(version); err != nil {
+		err := runtime.CheckSupportedVersion(provider, version)
+		if err == nil {
+			err = provider.Install(version)
+		}
+		if err != nil {
 			ui.Error("Failed to install %s %s: %v", provider.DisplayName(), version, err)
-			return
+			os.Exit(1)
 		}
 		ui.Success("%s %s installed successfully", provider.DisplayName(), version)
 	}
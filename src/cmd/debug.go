@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/path"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Troubleshoot dtvem internals",
+	Long: `Commands for troubleshooting how dtvem resolves runtimes and versions.
+
+Examples:
+  dtvem debug shim python
+  dtvem debug shim npm install -g typescript`,
+}
+
+var debugShimCmd = &cobra.Command{
+	Use:   "shim <name> [args...]",
+	Short: "Show how a shim resolves without executing it",
+	Long: `Walk through the same resolution steps the shim performs, without running anything.
+
+This shows:
+  - The runtime the shim maps to, and whether the mapping came from the
+    shim map cache or a scan of the registered providers
+  - The resolved version and where it was configured (local or global)
+  - Whether that version is installed
+  - The executable the provider reports, the one that would be invoked and
+    whether it exists
+  - The environment variables the provider sets for the executable
+  - Whether running it with the given arguments would trigger a reshim
+
+Examples:
+  dtvem debug shim python
+  dtvem debug shim pip3
+  dtvem debug shim npm install -g typescript`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		report := buildShimReport(args[0], args[1:])
+		printShimReport(report)
+	},
+}
+
+// shimReport describes each step of resolving a shim to an executable
+type shimReport struct {
+	ShimName      string
+	ShimPath      string
+	ShimExists    bool
+	RuntimeName   string
+	MappingSource shim.MappingSource
+	ProviderFound bool
+	DisplayName   string
+	Version       string
+	VersionSource config.VersionSource
+	VersionFile   string
+	Installed     bool
+	// ProviderExecutablePath is the provider's ExecutablePath for the version, and
+	// ExecutablePath the executable the shim would run for ShimName
+	ProviderExecutablePath string
+	ExecutablePath         string
+	ExecutableExists       bool
+	SystemFallback         bool
+	// Environment holds the variables the provider sets when running the executable
+	Environment map[string]string
+	ReshimAfter bool
+	// Problem describes the step where resolution stopped, if any
+	Problem string
+}
+
+// buildShimReport resolves a shim the same way the shim executable does
+func buildShimReport(shimName string, args []string) shimReport {
+	report := shimReport{
+		ShimName: shimName,
+		ShimPath: config.ShimPath(shimName),
+	}
+	_, err := os.Stat(report.ShimPath)
+	report.ShimExists = err == nil
+
+	report.RuntimeName, report.MappingSource = shim.ResolveRuntime(shimName)
+
+	provider, err := runtime.GetShimProvider(report.RuntimeName)
+	if err != nil {
+		report.Problem = fmt.Sprintf("no provider registered for runtime %q", report.RuntimeName)
+		return report
+	}
+	report.ProviderFound = true
+	report.DisplayName = provider.DisplayName()
+	report.ReshimAfter = provider.ShouldReshimAfter(shimName, args)
+
+	resolved, err := config.ResolveVersionWithSource(report.RuntimeName)
+	if err != nil {
+		report.Problem = "no version configured; the shim would fall back to the system PATH"
+		return report
+	}
+	report.Version = resolved.Version
+	report.VersionSource = resolved.Source
+	report.VersionFile = resolved.File
+
+	// The system version runs the system installation, skipping dtvem's shims
+	if runtime.IsSystemVersion(resolved.Version) {
+		report.ExecutablePath = path.LookPathExcludingShims(shimName)
+		if report.ExecutablePath == "" {
+			report.Problem = fmt.Sprintf("%s is set to the system version, but no system %s was found in PATH", report.DisplayName, shimName)
+			return report
+		}
+		report.Installed = true
+		report.ExecutableExists = true
+		return report
+	}
+
+	installed, err := provider.IsInstalled(resolved.Version)
+	if err != nil {
+		report.Problem = fmt.Sprintf("could not check installation: %v", err)
+		return report
+	}
+	report.Installed = installed
+	if !installed {
+		report.Problem = fmt.Sprintf("version %s is configured but not installed", resolved.Version)
+		return report
+	}
+
+	execPath, err := provider.ExecutablePath(resolved.Version)
+	if err != nil {
+		report.Problem = fmt.Sprintf("could not determine executable path: %v", err)
+		return report
+	}
+	report.ProviderExecutablePath = execPath
+	resolution, err := shim.ResolveExecutable(execPath, shimName, report.RuntimeName)
+	if err != nil {
+		report.ExecutablePath = shim.AdjustExecutablePath(execPath, shimName, report.RuntimeName)
+		report.Problem = fmt.Sprintf("%s does not exist in %s %s or on the system PATH; run 'dtvem reshim' to remove stale shims", shimName, report.DisplayName, resolved.Version)
+		return report
+	}
+	report.ExecutablePath = resolution.Path
+	report.ExecutableExists = true
+	report.SystemFallback = resolution.SystemFallback
+	if resolution.SystemFallback {
+		report.Problem = fmt.Sprintf("%s does not exist in %s %s; the shim would fall back to the system installation", shimName, report.DisplayName, resolved.Version)
+		return report
+	}
+
+	env, err := provider.GetEnvironment(resolved.Version)
+	if err != nil {
+		report.Problem = fmt.Sprintf("could not determine the environment: %v; the shim would run without it", err)
+		return report
+	}
+	report.Environment = env
+
+	return report
+}
+
+// printShimReport displays a shim resolution report
+func printShimReport(report shimReport) {
+	ui.Header("Shim: %s", ui.Highlight(report.ShimName))
+	fmt.Println()
+
+	ui.Info("Shim path:   %s (%s)", report.ShimPath, existsLabel(report.ShimExists))
+	ui.Info("Runtime:     %s (from %s)", report.RuntimeName, report.MappingSource)
+
+	if report.ProviderFound && report.Version != "" {
+		ui.Info("Version:     %s (%s, %s)", ui.HighlightVersion(report.Version), report.VersionSource, report.VersionFile)
+	}
+	if report.ProviderExecutablePath != "" {
+		ui.Info("Provider executable: %s", report.ProviderExecutablePath)
+	}
+	if report.ExecutablePath != "" {
+		ui.Info("Executable:  %s (%s)", report.ExecutablePath, existsLabel(report.ExecutableExists))
+	}
+	if report.Environment != nil {
+		if len(report.Environment) == 0 {
+			ui.Info("Environment: none")
+		} else {
+			ui.Info("Environment:")
+			keys := make([]string, 0, len(report.Environment))
+			for key := range report.Environment {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				ui.Progress("%s=%s", key, report.Environment[key])
+			}
+		}
+	}
+	if report.ProviderFound {
+		ui.Info("Reshim after: %t", report.ReshimAfter)
+	}
+
+	fmt.Println()
+	if report.Problem != "" {
+		ui.Warning("%s", report.Problem)
+		return
+	}
+	ui.Success("Shim resolves to %s %s", report.DisplayName, report.Version)
+}
+
+// existsLabel returns a short label describing whether a path exists
+func existsLabel(exists bool) string {
+	if exists {
+		return "exists"
+	}
+	return "missing"
+}
+
+func init() {
+	debugCmd.AddCommand(debugShimCmd)
+	rootCmd.AddCommand(debugCmd)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
+)
+
+// setupDebugEnv points dtvem at a temporary root and working directory
+func setupDebugEnv(t *testing.T) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tempDir)
+	config.ResetPathsCache()
+	shim.ResetShimMapCache()
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.Chdir(originalDir)
+		config.ResetPathsCache()
+		shim.ResetShimMapCache()
+	})
+
+	return tempDir
+}
+
+func TestBuildShimReport(t *testing.T) {
+	tempDir := setupDebugEnv(t)
+
+	execPath := filepath.Join(tempDir, "versions", "dbgrt", "1.0.0", "bin", "dbgrt")
+	if err := os.MkdirAll(filepath.Dir(execPath), 0755); err != nil {
+		t.Fatalf("Failed to create bin directory: %v", err)
+	}
+	if err := os.WriteFile(execPath, []byte("binary"), 0755); err != nil {
+		t.Fatalf("Failed to create executable: %v", err)
+	}
+
+	provider := &mockProvider{
+		name:        "dbgrt",
+		displayName: "Debug Runtime",
+		installed:   true,
+		execPath:    execPath,
+		reshimAfter: true,
+		env:         map[string]string{"DBGRT_HOME": filepath.Dir(execPath)},
+	}
+	useTestRegistry(t)
+	if err := runtime.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	if err := config.SetGlobalVersion("dbgrt", "1.0.0"); err != nil {
+		t.Fatalf("Failed to set global version: %v", err)
+	}
+
+	report := buildShimReport("dbgrt", []string{"install", "-g", "pkg"})
+
+	if report.RuntimeName != "dbgrt" {
+		t.Errorf("RuntimeName = %q, want %q", report.RuntimeName, "dbgrt")
+	}
+	if report.MappingSource != shim.MappingFromProvider {
+		t.Errorf("MappingSource = %q, want %q", report.MappingSource, shim.MappingFromProvider)
+	}
+	if report.Version != "1.0.0" || report.VersionSource != config.VersionSourceGlobal {
+		t.Errorf("Version = %q (%s), want 1.0.0 (global)", report.Version, report.VersionSource)
+	}
+	if report.ExecutablePath != execPath || !report.ExecutableExists {
+		t.Errorf("ExecutablePath = %q (exists=%v), want %q (exists=true)", report.ExecutablePath, report.ExecutableExists, execPath)
+	}
+	if report.ProviderExecutablePath != execPath {
+		t.Errorf("ProviderExecutablePath = %q, want %q", report.ProviderExecutablePath, execPath)
+	}
+	if got := report.Environment["DBGRT_HOME"]; got != filepath.Dir(execPath) {
+		t.Errorf("Environment[DBGRT_HOME] = %q, want %q", got, filepath.Dir(execPath))
+	}
+	if !report.ReshimAfter {
+		t.Error("ReshimAfter = false, want true")
+	}
+	if report.Problem != "" {
+		t.Errorf("Problem = %q, want none", report.Problem)
+	}
+}
+
+func TestBuildShimReport_Problems(t *testing.T) {
+	tests := []struct {
+		name          string
+		installed     bool
+		setVersion    bool
+		register      bool
+		wantInstalled bool
+	}{
+		{name: "unknown runtime", register: false},
+		{name: "no version configured", register: true},
+		{name: "configured but not installed", register: true, setVersion: true},
+		{name: "executable missing", register: true, setVersion: true, installed: true, wantInstalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := setupDebugEnv(t)
+
+			if tt.register {
+				provider := &mockProvider{
+					name:        "dbgrt",
+					displayName: "Debug Runtime",
+					installed:   tt.installed,
+					execPath:    filepath.Join(tempDir, "missing", "dbgrt"),
+				}
+				useTestRegistry(t)
+				if err := runtime.Register(provider); err != nil {
+					t.Fatalf("Failed to register provider: %v", err)
+				}
+			}
+			if tt.setVersion {
+				if err := config.SetGlobalVersion("dbgrt", "1.0.0"); err != nil {
+					t.Fatalf("Failed to set global version: %v", err)
+				}
+			}
+
+			report := buildShimReport("dbgrt", nil)
+
+			if report.Problem == "" {
+				t.Error("Problem is empty, want a description of the failed step")
+			}
+			if report.ProviderFound != tt.register {
+				t.Errorf("ProviderFound = %v, want %v", report.ProviderFound, tt.register)
+			}
+			if report.Installed != tt.wantInstalled {
+				t.Errorf("Installed = %v, want %v", report.Installed, tt.wantInstalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,528 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/path"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorFixFlag bool
+	doctorYesFlag bool
+)
+
+// newShimManager creates the shim manager used by doctor fixes (replaced in tests)
+var newShimManager = shim.NewManager
+
+// findShimExecutable locates dtvem-shim for the doctor check (replaced in tests)
+var findShimExecutable = shim.FindShimExecutable
+
+// doctorIssue is a problem found by a doctor check
+type doctorIssue struct {
+	// Problem describes what is wrong
+	Problem string
+	// FixDescription describes what Fix will do
+	FixDescription string
+	// Fix repairs the problem, or is nil if it must be fixed by hand
+	Fix func() error
+	// Hint tells the user how to fix the problem by hand
+	Hint string
+}
+
+// doctorCheck inspects one aspect of the dtvem installation
+type doctorCheck struct {
+	Name string
+	Run  func() []doctorIssue
+}
+
+// doctorChecks are run in order, so that fixes from earlier checks
+// (e.g. creating directories) are seen by later ones
+func doctorChecks() []doctorCheck {
+	return []doctorCheck{
+		{Name: "dtvem directories", Run: checkDirectories},
+		{Name: "Shims directory in PATH", Run: checkShimsInPath},
+		{Name: "Shims first in PATH", Run: checkShadowedShims},
+		{Name: "Single dtvem installation", Run: checkMultipleInstallations},
+		{Name: "Shim executable", Run: checkShimExecutable},
+		{Name: "Shim map", Run: checkShimMap},
+		{Name: "Shims", Run: checkMissingShims},
+		{Name: "Global versions", Run: checkGlobalVersions},
+		{Name: "Version executables", Run: checkVersionExecutables},
+		{Name: "Installed versions", Run: checkInstalledVersions},
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose and repair common problems",
+	Long: `Check the dtvem installation for common problems.
+
+Checks that the dtvem directories exist, that the shims directory is in your
+PATH ahead of any system installs of the same commands, that only one dtvem
+installation is on your PATH, that dtvem-shim is next to dtvem, that the shim
+map and shims match the installed versions, that global versions point at
+installed versions, and that installed versions have their executables and work
+(for Python, that pip is present and runs).
+
+With --fix, doctor offers to repair each problem it finds. Each fix is
+confirmed first unless --yes is given.
+
+Exits with a non-zero status if problems are left, so scripts and CI can use it.
+
+Examples:
+  dtvem doctor
+  dtvem doctor --fix
+  dtvem doctor --fix --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		remaining := runDoctor(doctorChecks(), doctorFixFlag, doctorYesFlag)
+
+		fmt.Println()
+		if remaining == 0 {
+			ui.Success("No problems found")
+			return
+		}
+
+		ui.Warning("%d problem(s) found", remaining)
+		if !doctorFixFlag {
+			ui.Info("Run 'dtvem doctor --fix' to repair them")
+		}
+		os.Exit(1)
+	},
+}
+
+// runDoctor runs the checks, applying fixes if requested, and returns the number of unresolved issues
+func runDoctor(checks []doctorCheck, fix, skipConfirmation bool) int {
+	remaining := 0
+
+	for _, check := range checks {
+		issues := check.Run()
+		if len(issues) == 0 {
+			ui.Success("%s", check.Name)
+			continue
+		}
+
+		ui.Error("%s", check.Name)
+		for _, issue := range issues {
+			ui.Info("  %s", issue.Problem)
+
+			if !fix || issue.Fix == nil {
+				if issue.Hint != "" {
+					ui.Info("    %s", ui.DimText(issue.Hint))
+				}
+				remaining++
+				continue
+			}
+
+			if !skipConfirmation && !confirmFix(issue.FixDescription) {
+				remaining++
+				continue
+			}
+
+			if err := issue.Fix(); err != nil {
+				ui.Error("    Fix failed: %v", err)
+				remaining++
+				continue
+			}
+			ui.Success("    Fixed: %s", issue.FixDescription)
+		}
+	}
+
+	return remaining
+}
+
+// confirmFix asks the user whether to apply a fix
+func confirmFix(description string) bool {
+	ui.Printf("    %s? [Y/n]: ", description)
+
+	var response string
+	_, _ = fmt.Scanln(&response)
+	response = strings.ToLower(strings.TrimSpace(response))
+
+	return response == "" || response == constants.ResponseY || response == constants.ResponseYes
+}
+
+// checkDirectories reports missing dtvem directories
+func checkDirectories() []doctorIssue {
+	paths := config.DefaultPaths()
+
+	var missing []string
+	for _, dir := range []string{paths.Root, paths.Shims, paths.Versions, paths.Config, paths.Cache} {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			missing = append(missing, dir)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return []doctorIssue{{
+		Problem:        fmt.Sprintf("Missing directories: %s", strings.Join(missing, ", ")),
+		FixDescription: "Create the missing directories",
+		Fix:            config.EnsureDirectories,
+		Hint:           "Run: dtvem init",
+	}}
+}
+
+// checkShimsInPath reports when the shims directory is not in PATH
+func checkShimsInPath() []doctorIssue {
+	shimsDir := path.ShimsDir()
+	if path.IsInPath(shimsDir) {
+		return nil
+	}
+
+	return []doctorIssue{{
+		Problem:        fmt.Sprintf("%s is not in your PATH", shimsDir),
+		FixDescription: "Add the shims directory to your PATH",
+		Fix: func() error {
+			return path.AddToPath(shimsDir, true)
+		},
+		Hint: "Run: dtvem init",
+	}}
+}
+
+// checkShadowedShims reports shims that run another executable instead, because a
+// directory with a system install of the same command comes before the shims in PATH.
+// Without the shims directory in PATH there is nothing to shadow (checkShimsInPath
+// reports that).
+func checkShadowedShims() []doctorIssue {
+	shimsDir := path.ShimsDir()
+	if !path.IsInPath(shimsDir) {
+		return nil
+	}
+
+	shadowed := shadowedShims(os.Getenv("PATH"), shimsDir, expectedShimNames())
+	if len(shadowed) == 0 {
+		return nil
+	}
+
+	commands := make([]string, 0, len(shadowed))
+	for _, s := range shadowed {
+		commands = append(commands, fmt.Sprintf("%s (%s)", s.Name, s.Path))
+	}
+	return []doctorIssue{{
+		Problem:        fmt.Sprintf("These commands run an executable that comes before the dtvem shims in PATH: %s", strings.Join(commands, ", ")),
+		FixDescription: "Move the shims directory to the front of your PATH",
+		Fix: func() error {
+			return path.RepairPath(shimsDir, path.RepairOptions{SkipConfirmation: true})
+		},
+		Hint: pathOrderRemedy(shimsDir, path.DetectShell()) + "\n    Or run: dtvem repair-path",
+	}}
+}
+
+// checkShimExecutable reports a missing dtvem-shim, without which no shim can be created
+func checkShimExecutable() []doctorIssue {
+	if _, err := findShimExecutable(); err != nil {
+		return []doctorIssue{{
+			Problem: fmt.Sprintf("dtvem-shim is missing: %v", err),
+			Hint:    "Reinstall dtvem; dtvem-shim must be in the same directory as dtvem",
+		}}
+	}
+	return nil
+}
+
+// checkMultipleInstallations reports when more than one PATH directory holds dtvem
+// or dtvem-shim, e.g. a manual build alongside a package manager install
+func checkMultipleInstallations() []doctorIssue {
+	dirs := path.ExecutableDirs(os.Getenv("PATH"), "dtvem", "dtvem-shim")
+	if len(dirs) < 2 {
+		return nil
+	}
+
+	return []doctorIssue{{
+		Problem: fmt.Sprintf("Found dtvem installations in %d PATH directories: %s", len(dirs), strings.Join(dirs, ", ")),
+		Hint:    fmt.Sprintf("The one in %s runs first; remove the installations you don't use", dirs[0]),
+	}}
+}
+
+// checkShimMap reports a missing shim map, or one that doesn't match the installed runtimes
+func checkShimMap() []doctorIssue {
+	installed := installedRuntimeNames()
+	if len(installed) == 0 {
+		return nil
+	}
+
+	shim.ResetShimMapCache()
+	shimMap, err := shim.LoadShimMap()
+
+	problem := ""
+	switch {
+	case err != nil:
+		problem = "The shim map is missing or unreadable"
+	default:
+		mapped := make(map[string]bool)
+		for _, runtimeName := range shimMap {
+			mapped[runtimeName] = true
+		}
+
+		var stale []string
+		for _, runtimeName := range installed {
+			if !mapped[runtimeName] {
+				stale = append(stale, runtimeName)
+			}
+		}
+		for runtimeName := range mapped {
+			if !containsString(installed, runtimeName) {
+				stale = append(stale, runtimeName)
+			}
+		}
+		if len(stale) > 0 {
+			sort.Strings(stale)
+			problem = fmt.Sprintf("The shim map is out of date for: %s", strings.Join(stale, ", "))
+		}
+	}
+
+	if problem == "" {
+		return nil
+	}
+
+	return []doctorIssue{{
+		Problem:        problem,
+		FixDescription: "Regenerate shims (reshim)",
+		Fix:            rehashShims,
+		Hint:           "Run: dtvem reshim",
+	}}
+}
+
+// checkMissingShims reports shims that should exist but don't
+func checkMissingShims() []doctorIssue {
+	var missing []string
+	for _, shimName := range expectedShimNames() {
+		if _, err := os.Stat(config.ShimPath(shimName)); os.IsNotExist(err) {
+			missing = append(missing, shimName)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return []doctorIssue{{
+		Problem:        fmt.Sprintf("Missing shims: %s", strings.Join(missing, ", ")),
+		FixDescription: "Recreate the missing shims",
+		Fix: func() error {
+			if err := config.EnsureDirectories(); err != nil {
+				return err
+			}
+			manager, err := newShimManager()
+			if err != nil {
+				return err
+			}
+			return manager.CreateShims(missing)
+		},
+		Hint: "Run: dtvem reshim",
+	}}
+}
+
+// checkGlobalVersions reports global versions that aren't installed, and runtimes set
+// to the system version without a system installation
+func checkGlobalVersions() []doctorIssue {
+	var issues []doctorIssue
+
+	runtimeNames := runtime.List()
+	sort.Strings(runtimeNames)
+
+	for _, runtimeName := range runtimeNames {
+		provider, err := runtime.Get(runtimeName)
+		if err != nil {
+			continue
+		}
+
+		version, err := config.GlobalVersion(runtimeName)
+		if err != nil || version == "" {
+			continue
+		}
+
+		if runtime.IsSystemVersion(version) {
+			if _, err := systemExecutable(provider); err != nil {
+				issues = append(issues, doctorIssue{
+					Problem: fmt.Sprintf("Global %s version is system, but no system %s was found in PATH", provider.DisplayName(), runtimeName),
+					Hint:    fmt.Sprintf("Install %s on the system, or choose a dtvem version: dtvem global %s <version>", provider.DisplayName(), runtimeName),
+				})
+			}
+			continue
+		}
+
+		if installed, err := isVersionOrPinInstalled(provider, version); err != nil || installed {
+			continue
+		}
+
+		issues = append(issues, doctorIssue{
+			Problem:        fmt.Sprintf("Global %s version %s is not installed", provider.DisplayName(), version),
+			FixDescription: fmt.Sprintf("Unset the global %s version", provider.DisplayName()),
+			Fix: func() error {
+				return config.UnsetGlobalVersion(runtimeName)
+			},
+			Hint: fmt.Sprintf("Run: dtvem install %s %s", runtimeName, version),
+		})
+	}
+
+	return issues
+}
+
+// checkVersionExecutables reports installed versions whose executable is missing,
+// e.g. after an interrupted install or files removed by hand
+func checkVersionExecutables() []doctorIssue {
+	var issues []doctorIssue
+
+	runtimeNames := runtime.List()
+	sort.Strings(runtimeNames)
+
+	for _, runtimeName := range runtimeNames {
+		provider, err := runtime.Get(runtimeName)
+		if err != nil {
+			continue
+		}
+		installed, err := provider.ListInstalled()
+		if err != nil {
+			continue
+		}
+
+		for _, iv := range installed {
+			version := iv.Version.Raw
+			execPath, err := provider.ExecutablePath(version)
+			if err == nil {
+				if _, statErr := os.Stat(execPath); statErr != nil {
+					err = fmt.Errorf("%s does not exist", execPath)
+				}
+			}
+			if err == nil {
+				continue
+			}
+
+			issues = append(issues, doctorIssue{
+				Problem: fmt.Sprintf("%s %s has no executable: %v", provider.DisplayName(), version, err),
+				Hint:    fmt.Sprintf("Reinstall it: dtvem uninstall %s %s && dtvem install %s %s", runtimeName, version, runtimeName, version),
+			})
+		}
+	}
+
+	return issues
+}
+
+// checkInstalledVersions reports the problems that providers find in their installed
+// versions, such as a Python whose pip is missing or broken
+func checkInstalledVersions() []doctorIssue {
+	var issues []doctorIssue
+
+	runtimeNames := runtime.List()
+	sort.Strings(runtimeNames)
+
+	for _, runtimeName := range runtimeNames {
+		provider, err := runtime.Get(runtimeName)
+		if err != nil {
+			continue
+		}
+		checker, ok := provider.(runtime.HealthChecker)
+		if !ok {
+			continue
+		}
+
+		installed, err := provider.ListInstalled()
+		if err != nil {
+			continue
+		}
+		for _, version := range installed {
+			for _, problem := range checker.CheckHealth(version.Version.Raw) {
+				issues = append(issues, doctorIssue(problem))
+			}
+		}
+	}
+
+	return issues
+}
+
+// expectedShimNames returns the sorted shims the installed runtimes and the shim map call for
+func expectedShimNames() []string {
+	expected := make(map[string]bool)
+	for _, runtimeName := range installedRuntimeNames() {
+		for _, shimName := range shim.RuntimeShims(runtimeName) {
+			expected[shimName] = true
+		}
+	}
+
+	shim.ResetShimMapCache()
+	if shimMap, err := shim.LoadShimMap(); err == nil {
+		for shimName := range shimMap {
+			expected[shimName] = true
+		}
+	}
+
+	names := make([]string, 0, len(expected))
+	for shimName := range expected {
+		names = append(names, shimName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rehashShims regenerates all shims and the shim map
+func rehashShims() error {
+	if err := config.EnsureDirectories(); err != nil {
+		return err
+	}
+
+	manager, err := newShimManager()
+	if err != nil {
+		return err
+	}
+	_, err = manager.Rehash()
+	shim.ResetShimMapCache()
+	return err
+}
+
+// installedRuntimeNames returns the runtimes that have at least one installed version
+func installedRuntimeNames() []string {
+	versionsDir := config.DefaultPaths().Versions
+
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		runtimeDir := filepath.Join(versionsDir, entry.Name())
+		versionEntries, err := os.ReadDir(runtimeDir)
+		if err != nil {
+			continue
+		}
+		for _, ve := range versionEntries {
+			if runtime.IsVersionDir(runtimeDir, ve) {
+				names = append(names, entry.Name())
+				break
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFixFlag, "fix", false, "Offer to repair the problems found")
+	doctorCmd.Flags().BoolVarP(&doctorYesFlag, "yes", "y", false, "Apply fixes without confirmation")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
+)
+
+// setupDoctorEnv creates a broken dtvem root with an installed version of a mock runtime
+// but no shims or shim map, and makes doctor fixes copy shims from a fake shim executable
+func setupDoctorEnv(t *testing.T) (string, *mockProvider) {
+	t.Helper()
+	tempDir := setupDebugEnv(t)
+
+	useTestRegistry(t)
+	provider := &mockProvider{name: "docrt", displayName: "Doctor Runtime", installed: true}
+	if err := runtime.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "versions", "docrt", "1.0.0", "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+
+	fakeShim := filepath.Join(t.TempDir(), "dtvem-shim")
+	if err := os.WriteFile(fakeShim, []byte("shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+	originalManager := newShimManager
+	newShimManager = func() (*shim.Manager, error) { return shim.NewManagerWithSource(fakeShim), nil }
+	t.Cleanup(func() { newShimManager = originalManager })
+
+	return tempDir, provider
+}
+
+// applyFixes runs every fix and fails the test if one fails
+func applyFixes(t *testing.T, issues []doctorIssue) {
+	t.Helper()
+	for _, issue := range issues {
+		if issue.Fix == nil {
+			t.Fatalf("issue %q has no fix", issue.Problem)
+		}
+		if err := issue.Fix(); err != nil {
+			t.Fatalf("fix for %q failed: %v", issue.Problem, err)
+		}
+	}
+}
+
+func TestCheckDirectories(t *testing.T) {
+	setupDoctorEnv(t)
+
+	issues := checkDirectories()
+	if len(issues) != 1 {
+		t.Fatalf("checkDirectories() = %d issues, want 1 (shims, config and cache are missing)", len(issues))
+	}
+
+	applyFixes(t, issues)
+
+	if issues := checkDirectories(); len(issues) != 0 {
+		t.Errorf("checkDirectories() after fix = %+v, want none", issues)
+	}
+}
+
+func TestCheckShimMap(t *testing.T) {
+	setupDoctorEnv(t)
+
+	issues := checkShimMap()
+	if len(issues) != 1 {
+		t.Fatalf("checkShimMap() = %d issues, want 1 for a missing shim map", len(issues))
+	}
+
+	applyFixes(t, issues)
+
+	if issues := checkShimMap(); len(issues) != 0 {
+		t.Errorf("checkShimMap() after fix = %+v, want none", issues)
+	}
+	if _, err := os.Stat(config.ShimPath("docrt")); err != nil {
+		t.Errorf("reshim fix did not create the docrt shim: %v", err)
+	}
+}
+
+func TestCheckShimMap_Stale(t *testing.T) {
+	setupDoctorEnv(t)
+
+	// The map only knows about a runtime that is no longer installed
+	if err := shim.SaveShimMap(shim.ShimMap{"oldtool": "removedrt"}); err != nil {
+		t.Fatalf("SaveShimMap() error: %v", err)
+	}
+
+	issues := checkShimMap()
+	if len(issues) != 1 || !strings.Contains(issues[0].Problem, "docrt") || !strings.Contains(issues[0].Problem, "removedrt") {
+		t.Fatalf("checkShimMap() = %+v, want one stale-map issue naming docrt and removedrt", issues)
+	}
+
+	applyFixes(t, issues)
+
+	if issues := checkShimMap(); len(issues) != 0 {
+		t.Errorf("checkShimMap() after fix = %+v, want none", issues)
+	}
+}
+
+func TestCheckMissingShims(t *testing.T) {
+	setupDoctorEnv(t)
+
+	if err := config.EnsureDirectories(); err != nil {
+		t.Fatalf("EnsureDirectories() error: %v", err)
+	}
+	if err := shim.SaveShimMap(shim.ShimMap{"docrt": "docrt", "doctool": "docrt"}); err != nil {
+		t.Fatalf("SaveShimMap() error: %v", err)
+	}
+
+	issues := checkMissingShims()
+	if len(issues) != 1 || !strings.Contains(issues[0].Problem, "docrt, doctool") {
+		t.Fatalf("checkMissingShims() = %+v, want one issue naming docrt and doctool", issues)
+	}
+
+	applyFixes(t, issues)
+
+	if issues := checkMissingShims(); len(issues) != 0 {
+		t.Errorf("checkMissingShims() after fix = %+v, want none", issues)
+	}
+}
+
+func TestCheckGlobalVersions(t *testing.T) {
+	_, provider := setupDoctorEnv(t)
+	provider.installed = false
+
+	if err := config.SetGlobalVersion("docrt", "9.9.9"); err != nil {
+		t.Fatalf("SetGlobalVersion() error: %v", err)
+	}
+
+	issues := checkGlobalVersions()
+	if len(issues) != 1 {
+		t.Fatalf("checkGlobalVersions() = %d issues, want 1", len(issues))
+	}
+
+	applyFixes(t, issues)
+
+	if version, _ := config.GlobalVersion("docrt"); version != "" {
+		t.Errorf("global version after fix = %q, want it unset", version)
+	}
+	if issues := checkGlobalVersions(); len(issues) != 0 {
+		t.Errorf("checkGlobalVersions() after fix = %+v, want none", issues)
+	}
+}
+
+func TestCheckShimsInPath(t *testing.T) {
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("PATH fix modifies the Windows registry")
+	}
+	tempDir, _ := setupDoctorEnv(t)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/bash")
+	t.Setenv("PATH", "/usr/bin")
+
+	issues := checkShimsInPath()
+	if len(issues) != 1 {
+		t.Fatalf("checkShimsInPath() = %d issues, want 1", len(issues))
+	}
+
+	applyFixes(t, issues)
+
+	data, err := os.ReadFile(filepath.Join(home, ".bash_profile"))
+	if err != nil {
+		t.Fatalf("PATH fix did not write the shell config: %v", err)
+	}
+	if !strings.Contains(string(data), filepath.Join(tempDir, "shims")) {
+		t.Errorf("shell config = %q, want it to add the shims directory", string(data))
+	}
+}
+
+func TestCheckMultipleInstallations(t *testing.T) {
+	setupDoctorEnv(t)
+
+	tempDir := t.TempDir()
+	manualDir := filepath.Join(tempDir, "manual")
+	packageDir := filepath.Join(tempDir, "package")
+	for _, dir := range []string{manualDir, packageDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		name := "dtvem"
+		if goruntime.GOOS == constants.OSWindows {
+			name += ".exe"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("exec"), 0755); err != nil {
+			t.Fatalf("Failed to create dtvem in %s: %v", dir, err)
+		}
+	}
+
+	t.Setenv("PATH", manualDir)
+	if issues := checkMultipleInstallations(); len(issues) != 0 {
+		t.Errorf("checkMultipleInstallations() with one installation = %+v, want none", issues)
+	}
+
+	t.Setenv("PATH", manualDir+string(os.PathListSeparator)+packageDir)
+	issues := checkMultipleInstallations()
+	if len(issues) != 1 {
+		t.Fatalf("checkMultipleInstallations() = %d issues, want 1", len(issues))
+	}
+	if !strings.Contains(issues[0].Problem, manualDir) || !strings.Contains(issues[0].Problem, packageDir) {
+		t.Errorf("Problem = %q, want both locations", issues[0].Problem)
+	}
+	if !strings.Contains(issues[0].Hint, manualDir) {
+		t.Errorf("Hint = %q, want it to name the installation that runs first", issues[0].Hint)
+	}
+	if issues[0].Fix != nil {
+		t.Error("multiple installations must be resolved by hand")
+	}
+}
+
+func TestRunDoctor_FixesWithoutConfirmation(t *testing.T) {
+	setupDoctorEnv(t)
+
+	checks := []doctorCheck{
+		{Name: "directories", Run: checkDirectories},
+		{Name: "shim map", Run: checkShimMap},
+	}
+
+	if remaining := runDoctor(checks, false, false); remaining != 2 {
+		t.Errorf("runDoctor() without --fix = %d remaining, want 2", remaining)
+	}
+	if remaining := runDoctor(checks, true, true); remaining != 0 {
+		t.Errorf("runDoctor() with --fix --yes = %d remaining, want 0", remaining)
+	}
+	if remaining := runDoctor(checks, false, false); remaining != 0 {
+		t.Errorf("runDoctor() after fixing = %d remaining, want 0", remaining)
+	}
+}
+
+// mockHealthProvider is a mockProvider with installed versions that report health problems
+type mockHealthProvider struct {
+	mockProvider
+	versions []string
+	problems map[string][]runtime.HealthProblem
+}
+
+func (m *mockHealthProvider) ListInstalled() ([]runtime.InstalledVersion, error) {
+	installed := make([]runtime.InstalledVersion, 0, len(m.versions))
+	for _, v := range m.versions {
+		installed = append(installed, runtime.InstalledVersion{Version: runtime.NewVersion(v)})
+	}
+	return installed, nil
+}
+
+func (m *mockHealthProvider) CheckHealth(version string) []runtime.HealthProblem {
+	return m.problems[version]
+}
+
+func TestCheckInstalledVersions(t *testing.T) {
+	setupDoctorEnv(t)
+
+	fixed := false
+	provider := &mockHealthProvider{
+		mockProvider: mockProvider{name: "healthrt", displayName: "Health Runtime"},
+		versions:     []string{"1.0.0", "2.0.0"},
+		problems: map[string][]runtime.HealthProblem{
+			"2.0.0": {{
+				Problem:        "Health Runtime 2.0.0: pip is missing",
+				FixDescription: "Reinstall pip",
+				Fix:            func() error { fixed = true; return nil },
+				Hint:           "Run get-pip.py",
+			}},
+		},
+	}
+	if err := runtime.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	issues := checkInstalledVersions()
+	if len(issues) != 1 {
+		t.Fatalf("checkInstalledVersions() = %+v, want 1 issue", issues)
+	}
+	if issues[0].Problem != "Health Runtime 2.0.0: pip is missing" || issues[0].Hint != "Run get-pip.py" {
+		t.Errorf("checkInstalledVersions() issue = %+v", issues[0])
+	}
+
+	applyFixes(t, issues)
+	if !fixed {
+		t.Error("the provider's fix was not run")
+	}
+}
+
+func TestCheckShimExecutable(t *testing.T) {
+	originalFind := findShimExecutable
+	t.Cleanup(func() { findShimExecutable = originalFind })
+
+	findShimExecutable = func() (string, error) { return "/opt/dtvem/dtvem-shim", nil }
+	if issues := checkShimExecutable(); len(issues) != 0 {
+		t.Errorf("checkShimExecutable() = %+v, want none", issues)
+	}
+
+	findShimExecutable = func() (string, error) { return "", os.ErrNotExist }
+	issues := checkShimExecutable()
+	if len(issues) != 1 || issues[0].Fix != nil || !strings.Contains(issues[0].Problem, "dtvem-shim is missing") {
+		t.Errorf("checkShimExecutable() = %+v, want one issue without a fix", issues)
+	}
+}
+
+func TestCheckVersionExecutables(t *testing.T) {
+	tempDir, _ := setupDoctorEnv(t)
+
+	execPath := filepath.Join(tempDir, "versions", "exert", "1.0.0", "bin", "exert")
+	if err := os.MkdirAll(filepath.Dir(execPath), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	provider := &mockHealthProvider{
+		mockProvider: mockProvider{name: "exert", displayName: "Exec Runtime", execPath: execPath},
+		versions:     []string{"1.0.0"},
+	}
+	if err := runtime.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	issues := checkVersionExecutables()
+	if len(issues) != 1 || !strings.Contains(issues[0].Problem, "Exec Runtime 1.0.0 has no executable") {
+		t.Fatalf("checkVersionExecutables() with a missing executable = %+v, want one issue", issues)
+	}
+	if !strings.Contains(issues[0].Hint, "dtvem install exert 1.0.0") {
+		t.Errorf("checkVersionExecutables() hint = %q, want a reinstall command", issues[0].Hint)
+	}
+
+	if err := os.WriteFile(execPath, []byte("binary"), 0755); err != nil {
+		t.Fatalf("Failed to create executable: %v", err)
+	}
+	if issues := checkVersionExecutables(); len(issues) != 0 {
+		t.Errorf("checkVersionExecutables() = %+v, want none", issues)
+	}
+}
+
+func TestCheckShadowedShims(t *testing.T) {
+	tempDir, _ := setupDoctorEnv(t)
+	shimsDir := filepath.Join(tempDir, "shims")
+	if err := os.MkdirAll(shimsDir, 0755); err != nil {
+		t.Fatalf("Failed to create shims directory: %v", err)
+	}
+
+	systemDir := t.TempDir()
+	systemName := "docrt"
+	if goruntime.GOOS == constants.OSWindows {
+		systemName += ".exe"
+	}
+	systemExe := filepath.Join(systemDir, systemName)
+	if err := os.WriteFile(systemExe, []byte("system"), 0755); err != nil {
+		t.Fatalf("Failed to create system executable: %v", err)
+	}
+
+	// Shims first: nothing is shadowed
+	t.Setenv("PATH", shimsDir+string(os.PathListSeparator)+systemDir)
+	if issues := checkShadowedShims(); len(issues) != 0 {
+		t.Errorf("checkShadowedShims() with the shims first = %+v, want none", issues)
+	}
+
+	// A system install first shadows the docrt shim
+	t.Setenv("PATH", systemDir+string(os.PathListSeparator)+shimsDir)
+	issues := checkShadowedShims()
+	if len(issues) != 1 || !strings.Contains(issues[0].Problem, "docrt ("+systemExe+")") {
+		t.Fatalf("checkShadowedShims() with a system install first = %+v, want docrt reported", issues)
+	}
+	if issues[0].Fix == nil || !strings.Contains(issues[0].Hint, "dtvem repair-path") {
+		t.Errorf("checkShadowedShims() issue = %+v, want a fix and a repair-path hint", issues[0])
+	}
+
+	// Without the shims in PATH, checkShimsInPath reports the problem instead
+	t.Setenv("PATH", systemDir)
+	if issues := checkShadowedShims(); len(issues) != 0 {
+		t.Errorf("checkShadowedShims() without the shims in PATH = %+v, want none", issues)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/path"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+// Shells 'dtvem env' writes setup for, besides POSIX shells
+const (
+	envShellPosix      = "sh"
+	envShellPowerShell = "powershell"
+	envShellCmd        = "cmd"
+)
+
+var envShellFlag string
+
+var envCmd = &cobra.Command{
+	Use:   "env <runtime> [--shell <shell>]",
+	Short: "Print shell commands that set up a runtime's environment",
+	Long: `Print shell commands that put the configured version of a runtime first on
+PATH and set the environment variables it needs, for scripts and CI that run
+it without shims.
+
+Values are prepended to any existing value, the way the shims apply them. The
+shell is detected, unless it's given with --shell: bash, zsh, sh, fish,
+powershell (or pwsh) or cmd.
+
+Examples:
+  eval "$(dtvem env ruby)"
+  dtvem env node --shell fish | source
+  dtvem env python --shell powershell | Invoke-Expression`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shell := envShellFlag
+		if shell == "" {
+			shell = path.DetectShell()
+		}
+		shell, err := normalizeEnvShell(shell, envShellFlag != "")
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		provider, err := runtime.Get(args[0])
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		result, err := resolveInterpreter(provider, "")
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		// A system installation is already set up; there is nothing to print
+		if runtime.IsSystemVersion(result.Version) {
+			fmt.Fprintf(os.Stderr, "%s is set to the system version (%s); nothing to set up\n", provider.DisplayName(), result.Path)
+			return
+		}
+
+		env := make(map[string]string, len(result.Env)+1)
+		for key, value := range result.Env {
+			env[key] = value
+		}
+		if dirs := versionPathDirs(provider.Name(), result.Version, result.Path); len(dirs) > 0 {
+			env["PATH"] = strings.Join(dirs, string(os.PathListSeparator))
+		}
+
+		for _, line := range envLines(shell, env) {
+			fmt.Println(line)
+		}
+	},
+}
+
+// normalizeEnvShell maps a shell name to the syntax 'dtvem env' writes for it. A
+// detected shell that isn't known falls back to POSIX syntax; one given explicitly
+// is an error.
+func normalizeEnvShell(shell string, explicit bool) (string, error) {
+	switch strings.ToLower(strings.TrimSuffix(shell, ".exe")) {
+	case constants.ShellBash, constants.ShellZsh, envShellPosix:
+		return envShellPosix, nil
+	case constants.ShellFish:
+		return constants.ShellFish, nil
+	case envShellPowerShell, "pwsh":
+		return envShellPowerShell, nil
+	case envShellCmd:
+		return envShellCmd, nil
+	}
+	if explicit {
+		return "", fmt.Errorf("unsupported shell %q; use bash, zsh, sh, fish, powershell or cmd", shell)
+	}
+	return envShellPosix, nil
+}
+
+// envLines returns the commands that prepend each variable of env to its current
+// value in shell, sorted by variable name
+func envLines(shell string, env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, envLine(shell, key, env[key]))
+	}
+	return lines
+}
+
+// envLine returns the command that prepends value to the variable key in shell,
+// without leaving a trailing separator when the variable isn't set
+func envLine(shell, key, value string) string {
+	switch shell {
+	case constants.ShellFish:
+		quoted := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+		return fmt.Sprintf("set -gx --path %s '%s' $%s", key, quoted, key)
+	case envShellPowerShell:
+		quoted := strings.ReplaceAll(value, "'", "''")
+		return fmt.Sprintf("$env:%s = '%s' + $(if ($env:%s) { [IO.Path]::PathSeparator + $env:%s })", key, quoted, key, key)
+	case envShellCmd:
+		return fmt.Sprintf(`if defined %s (set "%s=%s;%%%s%%") else (set "%s=%s")`, key, key, value, key, key, value)
+	default:
+		quoted := strings.ReplaceAll(value, "'", `'\''`)
+		return fmt.Sprintf(`export %s='%s'"${%s:+:$%s}"`, key, quoted, key, key)
+	}
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envShellFlag, "shell", "", "Shell to write commands for (default: detected)")
+	rootCmd.AddCommand(envCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEnvShell(t *testing.T) {
+	tests := []struct {
+		shell    string
+		explicit bool
+		want     string
+		wantErr  bool
+	}{
+		{"bash", true, "sh", false},
+		{"zsh", false, "sh", false},
+		{"fish", true, "fish", false},
+		{"pwsh", true, "powershell", false},
+		{"PowerShell.exe", true, "powershell", false},
+		{"cmd", true, "cmd", false},
+		{"unknown", false, "sh", false},
+		{"tcsh", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			got, err := normalizeEnvShell(tt.shell, tt.explicit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeEnvShell(%q) error = %v, wantErr %v", tt.shell, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeEnvShell(%q) = %q, want %q", tt.shell, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvLines(t *testing.T) {
+	env := map[string]string{"PATH": "/opt/ruby/bin", "LD_LIBRARY_PATH": "/opt/it's/lib"}
+
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"sh", []string{
+			`export LD_LIBRARY_PATH='/opt/it'\''s/lib'"${LD_LIBRARY_PATH:+:$LD_LIBRARY_PATH}"`,
+			`export PATH='/opt/ruby/bin'"${PATH:+:$PATH}"`,
+		}},
+		{"fish", []string{
+			`set -gx --path LD_LIBRARY_PATH '/opt/it\'s/lib' $LD_LIBRARY_PATH`,
+			`set -gx --path PATH '/opt/ruby/bin' $PATH`,
+		}},
+		{"powershell", []string{
+			`$env:LD_LIBRARY_PATH = '/opt/it''s/lib' + $(if ($env:LD_LIBRARY_PATH) { [IO.Path]::PathSeparator + $env:LD_LIBRARY_PATH })`,
+			`$env:PATH = '/opt/ruby/bin' + $(if ($env:PATH) { [IO.Path]::PathSeparator + $env:PATH })`,
+		}},
+		{"cmd", []string{
+			`if defined LD_LIBRARY_PATH (set "LD_LIBRARY_PATH=/opt/it's/lib;%LD_LIBRARY_PATH%") else (set "LD_LIBRARY_PATH=/opt/it's/lib")`,
+			`if defined PATH (set "PATH=/opt/ruby/bin;%PATH%") else (set "PATH=/opt/ruby/bin")`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			if got := envLines(tt.shell, env); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("envLines(%q) =\n%s\nwant\n%s", tt.shell, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestEnvLines_PosixEval(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+
+	script := strings.Join(envLines("sh", map[string]string{"DTVEM_TEST_SET": "/new", "DTVEM_TEST_UNSET": "/new"}), "\n") +
+		"\necho \"$DTVEM_TEST_SET|$DTVEM_TEST_UNSET\""
+	cmd := exec.Command(sh, "-c", script)
+	cmd.Env = []string{"DTVEM_TEST_SET=/old"}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("sh failed: %v", err)
+	}
+
+	// Prepended to a set variable, and no trailing separator on an unset one
+	if got, want := strings.TrimSpace(string(out)), "/new:/old|/new"; got != want {
+		t.Errorf("evaluated env = %q, want %q", got, want)
+	}
+}
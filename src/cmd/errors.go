@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// Error codes reported in the JSON error envelope (DTVEM_OUTPUT=json)
+const (
+	errorCodeRuntimeNotFound     = "runtime_not_found"
+	errorCodeVersionNotAvailable = "version_not_available"
+	errorCodeVersionNotSupported = "version_not_supported"
+	errorCodeNotImplemented      = "not_implemented"
+	errorCodePermissionDenied    = "permission_denied"
+	errorCodeInternal            = "internal_error"
+)
+
+// errorDetail describes err for the JSON error envelope, using the code and
+// fields of the typed error it wraps
+func errorDetail(err error) ui.ErrorDetail {
+	detail := ui.ErrorDetail{Code: ui.ErrorCodeGeneric, Message: err.Error()}
+
+	var notFound *runtime.RuntimeNotFoundError
+	var notAvailable *runtime.VersionNotAvailableError
+	var notSupported *runtime.VersionNotSupportedError
+	switch {
+	case errors.As(err, &notFound):
+		detail.Code = errorCodeRuntimeNotFound
+		detail.Runtime = notFound.Runtime
+	case errors.As(err, &notAvailable):
+		detail.Code = errorCodeVersionNotAvailable
+		detail.Runtime = notAvailable.Runtime
+		detail.Version = notAvailable.Version
+	case errors.As(err, &notSupported):
+		detail.Code = errorCodeVersionNotSupported
+		detail.Runtime = notSupported.Runtime
+		detail.Version = notSupported.Version
+	case errors.Is(err, runtime.ErrNotImplemented):
+		detail.Code = errorCodeNotImplemented
+	case errors.Is(err, fs.ErrPermission):
+		detail.Code = errorCodePermissionDenied
+	}
+	return detail
+}
+
+// reportError prints a command failure: as the JSON error envelope on stdout when
+// DTVEM_OUTPUT=json, otherwise as a regular error message with advice for
+// permission errors. The caller exits with status 1.
+func reportError(err error) {
+	if ui.IsJSONOutput() {
+		ui.ReportJSONError(errorDetail(err))
+		return
+	}
+	ui.Error("%v", err)
+	explainPermissionError(err)
+}
+
+// availableRuntimes lists the registered runtimes for hints, grouped by language
+// family when a family has more than one runtime (e.g. "JavaScript: bun, node; Python: python")
+func availableRuntimes() string {
+	groups := runtime.Groups()
+	grouped := false
+	for _, group := range groups {
+		if len(group.Runtimes) > 1 {
+			grouped = true
+			break
+		}
+	}
+	if !grouped {
+		return strings.Join(runtime.List(), ", ")
+	}
+
+	parts := make([]string, 0, len(groups))
+	for _, group := range groups {
+		parts = append(parts, group.Family+": "+strings.Join(group.Runtimes, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// writeErrorJSON writes the JSON error envelope for err to w
+func writeErrorJSON(w io.Writer, err error) error {
+	return ui.WriteJSONError(w, errorDetail(err))
+}
+
+// reportPanic reports a panic in a command as an internal error in JSON output
+// mode, and re-panics otherwise so the stack trace is kept
+func reportPanic(r interface{}) {
+	if !ui.IsJSONOutput() {
+		panic(r)
+	}
+	ui.ReportJSONError(ui.ErrorDetail{Code: errorCodeInternal, Message: fmt.Sprint(r)})
+	os.Exit(1)
+}
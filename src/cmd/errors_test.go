@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+func TestWriteErrorJSON(t *testing.T) {
+	_, notFound := runtime.Get("cobol")
+
+	notAvailable := &runtime.VersionNotAvailableError{
+		Runtime:     "node",
+		DisplayName: "Node.js",
+		Version:     "99.0.0",
+		Platform:    "linux-amd64",
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want ui.ErrorDetail
+	}{
+		{
+			name: "version not available",
+			err:  notAvailable,
+			want: ui.ErrorDetail{
+				Code:    "version_not_available",
+				Message: "Node.js 99.0.0 is not available for linux-amd64",
+				Runtime: "node",
+				Version: "99.0.0",
+			},
+		},
+		{
+			name: "wrapped version not available",
+			err:  fmt.Errorf("failed to resolve download: %w", notAvailable),
+			want: ui.ErrorDetail{
+				Code:    "version_not_available",
+				Message: "failed to resolve download: Node.js 99.0.0 is not available for linux-amd64",
+				Runtime: "node",
+				Version: "99.0.0",
+			},
+		},
+		{
+			name: "version not supported",
+			err:  &runtime.VersionNotSupportedError{Runtime: "python", DisplayName: "Python", Version: "2.7.18", MinVersion: "3.5.0"},
+			want: ui.ErrorDetail{
+				Code:    "version_not_supported",
+				Message: "Python 2.7.18 cannot be installed with dtvem; the oldest supported version is 3.5.0",
+				Runtime: "python",
+				Version: "2.7.18",
+			},
+		},
+		{
+			name: "unknown runtime",
+			err:  notFound,
+			want: ui.ErrorDetail{
+				Code:    "runtime_not_found",
+				Message: "runtime provider 'cobol' not found",
+				Runtime: "cobol",
+			},
+		},
+		{
+			name: "not implemented",
+			err:  fmt.Errorf("uninstall: %w", runtime.ErrNotImplemented),
+			want: ui.ErrorDetail{Code: "not_implemented", Message: "uninstall: not implemented"},
+		},
+		{
+			name: "permission denied",
+			err:  fmt.Errorf("failed to create shims: %w", &fs.PathError{Op: "open", Path: "/opt/dtvem/shims/node", Err: fs.ErrPermission}),
+			want: ui.ErrorDetail{Code: "permission_denied", Message: "failed to create shims: open /opt/dtvem/shims/node: permission denied"},
+		},
+		{
+			name: "untyped error",
+			err:  errors.New("disk full"),
+			want: ui.ErrorDetail{Code: "error", Message: "disk full"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeErrorJSON(&buf, tt.err); err != nil {
+				t.Fatalf("writeErrorJSON() error: %v", err)
+			}
+
+			var envelope struct {
+				Error ui.ErrorDetail `json:"error"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+				t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+			}
+			if envelope.Error != tt.want {
+				t.Errorf("envelope error = %+v, want %+v", envelope.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteErrorJSON_OmitsEmptyFields(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeErrorJSON(&buf, errors.New("disk full")); err != nil {
+		t.Fatalf("writeErrorJSON() error: %v", err)
+	}
+
+	want := `{"error":{"code":"error","message":"disk full"}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("writeErrorJSON() = %q, want %q", buf.String(), want)
+	}
+}
+
+// mockFamilyProvider is a mock provider that reports a language family
+type mockFamilyProvider struct {
+	mockProvider
+	family string
+}
+
+func (m *mockFamilyProvider) Family() string { return m.family }
+
+func TestAvailableRuntimes(t *testing.T) {
+	useTestRegistry(t)
+	for _, p := range []runtime.Provider{
+		&mockProvider{name: "ruby", displayName: "Ruby"},
+		&mockFamilyProvider{mockProvider{name: "node", displayName: "Node.js"}, "JavaScript"},
+		&mockProvider{name: "python", displayName: "Python"},
+	} {
+		runtime.RegisterOrReplace(p)
+	}
+
+	// One runtime per family: a plain sorted list
+	if got, want := availableRuntimes(), "node, python, ruby"; got != want {
+		t.Errorf("availableRuntimes() = %q, want %q", got, want)
+	}
+
+	// Several runtimes of one family: grouped
+	runtime.RegisterOrReplace(&mockFamilyProvider{mockProvider{name: "bun", displayName: "Bun"}, "JavaScript"})
+	if got, want := availableRuntimes(), "JavaScript: bun, node; Python: python; Ruby: ruby"; got != want {
+		t.Errorf("availableRuntimes() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/path"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
+	"github.com/spf13/cobra"
+)
+
+var execVersionFlag string
+
+var errExecCommandNotFound = errors.New("command not found")
+
+// execRequest is a parsed 'dtvem exec' command line
+type execRequest struct {
+	RuntimeName string
+	// Version is the requested version, empty to use the configured one
+	Version string
+	// Command is the command to run, empty to run the runtime itself
+	Command string
+	Args    []string
+}
+
+// execTarget is the resolved executable and environment to run
+type execTarget struct {
+	Version  string
+	ExecPath string
+	Env      map[string]string
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec [--version <version>] <runtime>[@<version>] [--] [command] [args...]",
+	Short: "Run a command with a specific runtime version",
+	Long: `Run a one-off command with a runtime version, without changing any config
+or going through shims.
+
+The version is the configured one (like the shims use), unless it's given as
+runtime@version or with --version. The runtime's environment is applied and the
+version's executable directories are put first on PATH, so the command and
+anything it starts use that version.
+
+Without a command the runtime itself runs with the given arguments. Put the
+command after -- to run another executable, such as a package's CLI; it's looked
+up in the version first and then on the system PATH.
+
+The exit code is the command's exit code.
+
+Examples:
+  dtvem exec node --version
+  dtvem exec python@3.11.0 -- pytest
+  dtvem exec --version 18.16.0 node -- npm ci`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		request, err := parseExecArgs(args, execVersionFlag)
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		provider, err := runtime.Get(request.RuntimeName)
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+		if request.Version != "" {
+			request.Version = resolveVersionArg(request.RuntimeName, request.Version)
+		}
+
+		target, err := resolveExecTarget(provider, request)
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		if err := shim.Exec(target.ExecPath, request.Args, target.Env); err != nil {
+			reportError(fmt.Errorf("failed to execute %s: %w", target.ExecPath, err))
+			os.Exit(1)
+		}
+	},
+}
+
+// parseExecArgs splits the arguments of 'dtvem exec' into the runtime, the version
+// and the command to run. The version comes from runtime@version or --version.
+func parseExecArgs(args []string, versionFlag string) (execRequest, error) {
+	runtimeName, version, _ := strings.Cut(args[0], "@")
+	if runtimeName == "" {
+		return execRequest{}, fmt.Errorf("no runtime given in %q", args[0])
+	}
+	if version != "" && versionFlag != "" && version != versionFlag {
+		return execRequest{}, fmt.Errorf("conflicting versions %s and --version %s", version, versionFlag)
+	}
+	if version == "" {
+		version = versionFlag
+	}
+
+	request := execRequest{RuntimeName: runtimeName, Version: version, Args: args[1:]}
+	if len(request.Args) > 0 && request.Args[0] == "--" {
+		if len(request.Args) == 1 {
+			return execRequest{}, fmt.Errorf("no command given after --")
+		}
+		request.Command = request.Args[1]
+		request.Args = request.Args[2:]
+	}
+	return request, nil
+}
+
+// resolveExecTarget finds the version to use and the executable to run for request,
+// along with the environment to run it in
+func resolveExecTarget(provider runtime.Provider, request execRequest) (execTarget, error) {
+	version, runtimePath, err := resolveInstalledExecutable(provider, request.Version,
+		fmt.Sprintf("give one with %s@<version>", provider.Name()))
+	if err != nil {
+		return execTarget{}, err
+	}
+
+	// A system installation runs in the environment as it is
+	env := map[string]string{}
+	var dirs []string
+	if !runtime.IsSystemVersion(version) {
+		if providerEnv, err := provider.GetEnvironment(version); err == nil && providerEnv != nil {
+			env = providerEnv
+		}
+		dirs = versionPathDirs(provider.Name(), version, runtimePath)
+		if len(dirs) > 0 {
+			env["PATH"] = strings.Join(dirs, string(os.PathListSeparator))
+		}
+	}
+
+	target := execTarget{Version: version, ExecPath: runtimePath, Env: env}
+	if request.Command != "" {
+		if target.ExecPath, err = findExecCommand(request.Command, dirs); err != nil {
+			return execTarget{}, err
+		}
+	}
+	return target, nil
+}
+
+// resolveInstalledExecutable returns the version to use, the configured one when version
+// is empty, and the executable of that version, which must be installed. hint tells the
+// user how to give a version when none is configured. It never installs anything.
+func resolveInstalledExecutable(provider runtime.Provider, version, hint string) (string, string, error) {
+	if version == "" {
+		resolved, err := config.ResolveVersionWithSource(provider.Name())
+		if err != nil {
+			return "", "", fmt.Errorf("%w for %s; %s", errNoVersionConfigured, provider.Name(), hint)
+		}
+		version = resolved.Version
+	}
+
+	// The system version runs whatever is installed on the system PATH
+	if runtime.IsSystemVersion(version) {
+		execPath, err := systemExecutable(provider)
+		return runtime.SystemVersion, execPath, err
+	}
+
+	installed, err := provider.IsInstalled(version)
+	if err != nil {
+		return "", "", fmt.Errorf("could not check if %s %s is installed: %w", provider.Name(), version, err)
+	}
+	if !installed {
+		return "", "", fmt.Errorf("%w: %s %s; install it with 'dtvem install %s %s'",
+			errVersionNotInstalled, provider.Name(), version, provider.Name(), version)
+	}
+
+	execPath, err := provider.ExecutablePath(version)
+	if err != nil {
+		return "", "", fmt.Errorf("could not find %s %s executable: %w", provider.Name(), version, err)
+	}
+	return version, execPath, nil
+}
+
+// versionPathDirs returns the existing executable directories of an installed
+// version, starting with the one holding the runtime's executable
+func versionPathDirs(runtimeName, version, runtimePath string) []string {
+	candidates := append([]string{filepath.Dir(runtimePath)},
+		shim.VersionExecutableDirs(runtimeName, version, config.RuntimeVersionPath(runtimeName, version))...)
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, dir := range candidates {
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// findExecCommand finds the executable for command: a path is used as given,
+// otherwise it is looked up in the version's directories and then on the system
+// PATH (skipping the shims, which would pick their own version)
+func findExecCommand(command string, dirs []string) (string, error) {
+	if strings.ContainsRune(command, '/') || strings.ContainsRune(command, filepath.Separator) {
+		return exec.LookPath(command)
+	}
+
+	for _, dir := range dirs {
+		if found, err := exec.LookPath(filepath.Join(dir, command)); err == nil {
+			return found, nil
+		}
+	}
+	if found := path.LookPathExcludingShims(command); found != "" {
+		return found, nil
+	}
+	return "", fmt.Errorf("%w: %s", errExecCommandNotFound, command)
+}
+
+func init() {
+	execCmd.Flags().StringVar(&execVersionFlag, "version", "", "Version to run instead of the configured one")
+	// Flags after the runtime belong to the command being run
+	execCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(execCmd)
+}
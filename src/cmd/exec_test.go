@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+func TestParseExecArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		versionFlag string
+		want        execRequest
+		wantErr     bool
+	}{
+		{
+			name: "runtime with its own flags",
+			args: []string{"node", "--version"},
+			want: execRequest{RuntimeName: "node", Args: []string{"--version"}},
+		},
+		{
+			name: "version and command after --",
+			args: []string{"python@3.11.0", "--", "pytest", "-x"},
+			want: execRequest{RuntimeName: "python", Version: "3.11.0", Command: "pytest", Args: []string{"-x"}},
+		},
+		{
+			name:        "version flag",
+			args:        []string{"node", "--", "npm", "ci"},
+			versionFlag: "18.16.0",
+			want:        execRequest{RuntimeName: "node", Version: "18.16.0", Command: "npm", Args: []string{"ci"}},
+		},
+		{
+			name:        "same version twice",
+			args:        []string{"node@18.16.0"},
+			versionFlag: "18.16.0",
+			want:        execRequest{RuntimeName: "node", Version: "18.16.0", Args: []string{}},
+		},
+		{name: "conflicting versions", args: []string{"node@20.11.1"}, versionFlag: "18.16.0", wantErr: true},
+		{name: "nothing after --", args: []string{"node", "--"}, wantErr: true},
+		{name: "no runtime", args: []string{"@20.11.1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExecArgs(tt.args, tt.versionFlag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExecArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseExecArgs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsVersionRequest(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--version"}, true},
+		{[]string{"-v"}, true},
+		{[]string{"list", "-v"}, true},
+		{[]string{"exec", "node", "--version"}, false},
+		{[]string{"exec", "--version", "18.16.0", "node"}, false},
+		{[]string{"--verbose", "exec", "node", "-v"}, false},
+		{[]string{"interpreter", "node", "--version", "22.0.0"}, false},
+		{[]string{"--verbose", "--version"}, true},
+		{[]string{"list"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := isVersionRequest(tt.args); got != tt.want {
+				t.Errorf("isVersionRequest(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveExecTarget(t *testing.T) {
+	setupDebugEnv(t)
+	provider := installFakeVersion(t, "1.2.3", "1.2.3")
+	provider.installed = true
+
+	binDir := filepath.Dir(provider.execPath)
+	toolPath := filepath.Join(binDir, "verifytool")
+	if err := os.WriteFile(toolPath, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+
+	// The runtime itself, with the version's bin directory first on PATH
+	target, err := resolveExecTarget(provider, execRequest{RuntimeName: "verifyrt", Version: "1.2.3"})
+	if err != nil {
+		t.Fatalf("resolveExecTarget() error: %v", err)
+	}
+	if target.ExecPath != provider.execPath {
+		t.Errorf("ExecPath = %q, want the runtime %q", target.ExecPath, provider.execPath)
+	}
+	if got := strings.Split(target.Env["PATH"], string(os.PathListSeparator))[0]; got != binDir {
+		t.Errorf("PATH starts with %q, want %q", got, binDir)
+	}
+
+	// A command from the version
+	target, err = resolveExecTarget(provider, execRequest{RuntimeName: "verifyrt", Version: "1.2.3", Command: "verifytool"})
+	if err != nil {
+		t.Fatalf("resolveExecTarget() error: %v", err)
+	}
+	if target.ExecPath != toolPath {
+		t.Errorf("ExecPath = %q, want %q", target.ExecPath, toolPath)
+	}
+
+	// A command that exists nowhere
+	_, err = resolveExecTarget(provider, execRequest{RuntimeName: "verifyrt", Version: "1.2.3", Command: "no-such-dtvem-command"})
+	if !errors.Is(err, errExecCommandNotFound) {
+		t.Errorf("resolveExecTarget() error = %v, want %v", err, errExecCommandNotFound)
+	}
+
+	// The configured version when none is given
+	if err := config.SetGlobalVersion("verifyrt", "1.2.3"); err != nil {
+		t.Fatalf("SetGlobalVersion() error: %v", err)
+	}
+	if target, err := resolveExecTarget(provider, execRequest{RuntimeName: "verifyrt"}); err != nil || target.Version != "1.2.3" {
+		t.Errorf("resolveExecTarget() without a version = %+v, %v, want the global 1.2.3", target, err)
+	}
+
+	provider.installed = false
+	_, err = resolveExecTarget(provider, execRequest{RuntimeName: "verifyrt", Version: "1.2.3"})
+	if !errors.Is(err, errVersionNotInstalled) {
+		t.Errorf("resolveExecTarget() for a missing version error = %v, want %v", err, errVersionNotInstalled)
+	}
+}
@@ -1,68 +1,204 @@
 package cmd
 
 import (
+	"os"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/path"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-// setRuntimeVersion is a helper function for setting runtime versions (global or local)
-func setRuntimeVersion(runtimeName, version, scope string, setter func(string) error) {
+// pinDecision is how global and local handle the version they are asked to set
+type pinDecision int
+
+const (
+	// pinSet sets the version
+	pinSet pinDecision = iota
+	// pinInstall installs the version, then sets it
+	pinInstall
+	// pinRefuse leaves the configuration alone
+	pinRefuse
+)
+
+// decidePin decides how to set a version. Installed versions, and any version with
+// --force, are set right away. A missing version is installed first if confirmInstall
+// agrees, and refused otherwise, since every shim would fail with it. A wildcard pin
+// (isPin) with no installed match can't be installed and is refused.
+func decidePin(installed, force, isPin bool, confirmInstall func() bool) pinDecision {
+	switch {
+	case installed || force:
+		return pinSet
+	case !isPin && confirmInstall():
+		return pinInstall
+	default:
+		return pinRefuse
+	}
+}
+
+// confirmInstall asks whether to install a missing version, following the
+// auto-install setting. Without a terminal to answer the prompt it only installs
+// when auto-install is set.
+func confirmInstall(displayName, version string) bool {
+	if config.Setting(config.SettingAutoInstall) == "" && !ui.IsInteractive() {
+		return false
+	}
+	return ui.PromptInstall(displayName, version)
+}
+
+// setRuntimeVersion is a helper function for setting runtime versions (global or local).
+// A version that isn't installed is installed first if the user agrees, or set anyway with force.
+// It reports whether the version was set; the caller exits with status 1 when it wasn't.
+func setRuntimeVersion(runtimeName, version, scope string, force bool, setter func(string) error) bool {
 	provider, err := runtime.Get(runtimeName)
 	if err != nil {
-		ui.Error("%v", err)
-		ui.Info("Available runtimes: %v", runtime.List())
-		return
+		reportError(err)
+		ui.Info("Available runtimes: %s", availableRuntimes())
+		os.Exit(1)
+	}
+
+	// The system version is never installed by dtvem, it only needs an installation in PATH
+	if runtime.IsSystemVersion(version) {
+		version = runtime.SystemVersion
+		if !hasSystemInstallation(provider) {
+			ui.Warning("No system %s installation was found in PATH; its shims will fail until one is installed", provider.DisplayName())
+		}
+		ui.Info("Setting %s %s version to the system installation...", scope, provider.DisplayName())
+		if err := setter(version); err != nil {
+			ui.Error("%v", err)
+			return false
+		}
+		ui.Success("Successfully set %s %s version to %s", scope, provider.DisplayName(), version)
+		return true
 	}
 
 	// Validate that the version is installed
-	installed, err := provider.IsInstalled(version)
+	installed, err := isVersionOrPinInstalled(provider, version)
 	if err != nil {
 		ui.Error("Failed to check if version is installed: %v", err)
-		return
+		return false
 	}
+
 	if !installed {
-		ui.Error("%s %s is not installed", provider.DisplayName(), version)
+		ui.Warning("%s %s is not installed", provider.DisplayName(), version)
+	}
+
+	switch decidePin(installed, force, runtime.IsVersionPin(version), func() bool {
+		return confirmInstall(provider.DisplayName(), version)
+	}) {
+	case pinRefuse:
+		ui.Error("The %s %s version was not changed", scope, provider.DisplayName())
 		ui.Info("Run 'dtvem list %s' to see installed versions", runtimeName)
 		ui.Info("Run 'dtvem install %s %s' to install it first", runtimeName, version)
-		return
+		ui.Info("Or use --force to set it anyway")
+		return false
+	case pinInstall:
+		if err := installVersion(provider, version); err != nil {
+			ui.Error("Failed to install %s %s: %v", provider.DisplayName(), version, err)
+			return false
+		}
+		ui.Success("%s %s installed successfully", provider.DisplayName(), version)
+	case pinSet:
+		if !installed {
+			ui.Info("Setting it anyway; its shims will fail until you run 'dtvem install %s %s'", runtimeName, version)
+		}
 	}
 
 	ui.Info("Setting %s %s version to %s...", scope, provider.DisplayName(), version)
 
 	if err := setter(version); err != nil {
 		ui.Error("%v", err)
-		return
+		return false
 	}
 
 	ui.Success("Successfully set %s %s version to %s", scope, provider.DisplayName(), version)
+	return true
 }
 
+// hasSystemInstallation reports whether any of a runtime's commands is installed on
+// the system PATH, outside dtvem's shims
+func hasSystemInstallation(provider runtime.Provider) bool {
+	for _, name := range provider.Shims() {
+		if path.LookPathExcludingShims(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isVersionOrPinInstalled checks that a version is installed, or for a wildcard pin
+// such as "20.x", that at least one matching version is installed
+func isVersionOrPinInstalled(provider runtime.Provider, version string) (bool, error) {
+	if !runtime.IsVersionPin(version) {
+		return provider.IsInstalled(version)
+	}
+
+	installedVersions, err := provider.ListInstalled()
+	if err != nil {
+		return false, err
+	}
+
+	versions := make([]string, 0, len(installedVersions))
+	for _, iv := range installedVersions {
+		versions = append(versions, iv.Version.Raw)
+	}
+
+	_, ok := runtime.MatchVersionPin(version, versions)
+	return ok, nil
+}
+
+var (
+	globalPinMinorFlag bool
+	globalForceFlag    bool
+)
+
 var globalCmd = &cobra.Command{
 	Use:   "global <runtime> <version>",
 	Short: "Set the global default version of a runtime",
 	Long: `Set the global default version for a runtime.
 This version will be used when no local version is specified.
 
+A wildcard such as 20.x or 20.11.x always uses the newest installed
+matching version. --pin-minor pins the given version's minor release.
+
+A version that isn't installed is offered for install first (or installed
+without asking when auto-install is on); --force sets it anyway.
+
+The version "system" uses the runtime installed on the system PATH instead of
+a dtvem-managed version.
+
 Examples:
   dtvem global python 3.11.0
-  dtvem global node 18.16.0`,
+  dtvem global node 18.16.0
+  dtvem global node 20.x
+  dtvem global node 20.11.1 --pin-minor   # Tracks the latest installed 20.11.x
+  dtvem global node 22.0.0 --force        # Set it before installing it
+  dtvem global ruby system                # Use the system Ruby`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		runtimeName := args[0]
-		version := args[1]
+		version := resolveVersionArg(runtimeName, args[1])
 
 		provider, err := runtime.Get(runtimeName)
 		if err != nil {
-			ui.Error("%v", err)
-			ui.Info("Available runtimes: %v", runtime.List())
-			return
+			reportError(err)
+			ui.Info("Available runtimes: %s", availableRuntimes())
+			os.Exit(1)
 		}
 
-		setRuntimeVersion(runtimeName, version, "global", provider.SetGlobalVersion)
+		if globalPinMinorFlag && !runtime.IsSystemVersion(version) {
+			version = runtime.MinorPin(version)
+		}
+
+		if !setRuntimeVersion(runtimeName, version, "global", globalForceFlag, provider.SetGlobalVersion) {
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(globalCmd)
+	globalCmd.Flags().BoolVar(&globalPinMinorFlag, "pin-minor", false, "Track the latest installed patch of the version's minor release")
+	globalCmd.Flags().BoolVar(&globalForceFlag, "force", false, "Set the version even if it isn't installed")
 }
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
 )
 
 func TestVersionValidation_InstalledVersion(t *testing.T) {
@@ -119,3 +121,110 @@ func containsPathComponent(path, component string) bool {
 		filepath.Base(filepath.Dir(path)) == component ||
 		filepath.Base(filepath.Dir(filepath.Dir(path))) == component
 }
+
+func TestDecidePin(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed bool
+		force     bool
+		isPin     bool
+		confirm   bool
+		want      pinDecision
+	}{
+		{name: "installed", installed: true, want: pinSet},
+		{name: "installed pin", installed: true, isPin: true, want: pinSet},
+		{name: "missing and install confirmed", confirm: true, want: pinInstall},
+		{name: "missing and install declined", want: pinRefuse},
+		{name: "missing with force", force: true, want: pinSet},
+		{name: "unmatched pin can't be installed", isPin: true, confirm: true, want: pinRefuse},
+		{name: "unmatched pin with force", isPin: true, force: true, want: pinSet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asked := false
+			got := decidePin(tt.installed, tt.force, tt.isPin, func() bool {
+				asked = true
+				return tt.confirm
+			})
+			if got != tt.want {
+				t.Errorf("decidePin() = %v, want %v", got, tt.want)
+			}
+			if wantAsked := !tt.installed && !tt.force && !tt.isPin; asked != wantAsked {
+				t.Errorf("decidePin() asked to install = %v, want %v", asked, wantAsked)
+			}
+		})
+	}
+}
+
+func TestSetRuntimeVersion_NotInstalled(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoInstall string
+		force       bool
+		installErr  error
+		wantInstall bool
+		wantSet     bool
+	}{
+		{name: "refused when not installing", autoInstall: "false"},
+		{name: "installed first with auto-install", autoInstall: "true", wantInstall: true, wantSet: true},
+		{name: "not set when the install fails", autoInstall: "true", installErr: errors.New("download failed"), wantInstall: true},
+		{name: "set anyway with force", autoInstall: "false", force: true, wantSet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupDebugEnv(t)
+			useTestRegistry(t)
+			t.Setenv(config.AutoInstallEnvVar, tt.autoInstall)
+
+			provider := &mockProvider{name: "pinrt", displayName: "Pin Runtime", installError: tt.installErr}
+			if err := runtime.Register(provider); err != nil {
+				t.Fatalf("Failed to register provider: %v", err)
+			}
+
+			var setCalls []string
+			ok := setRuntimeVersion("pinrt", "2.0.0", "global", tt.force, func(version string) error {
+				setCalls = append(setCalls, version)
+				return nil
+			})
+			if ok != tt.wantSet {
+				t.Errorf("setRuntimeVersion() = %v, want %v", ok, tt.wantSet)
+			}
+
+			if installed := len(provider.installCalls) > 0; installed != tt.wantInstall {
+				t.Errorf("installed = %v (calls %v), want %v", installed, provider.installCalls, tt.wantInstall)
+			}
+			if set := len(setCalls) > 0; set != tt.wantSet {
+				t.Errorf("version set = %v, want %v", set, tt.wantSet)
+			}
+		})
+	}
+}
+
+func TestSetRuntimeVersion_System(t *testing.T) {
+	setupDebugEnv(t)
+	useTestRegistry(t)
+	t.Setenv(config.AutoInstallEnvVar, "true")
+
+	provider := &mockProvider{name: "pinrt", displayName: "Pin Runtime"}
+	if err := runtime.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	// Set without installing anything, and stored in its canonical spelling
+	var setCalls []string
+	if !setRuntimeVersion("pinrt", "System", "local", false, func(version string) error {
+		setCalls = append(setCalls, version)
+		return nil
+	}) {
+		t.Error("setRuntimeVersion() = false, want the system version set")
+	}
+
+	if len(provider.installCalls) != 0 {
+		t.Errorf("installed %v, want nothing installed for the system version", provider.installCalls)
+	}
+	if len(setCalls) != 1 || setCalls[0] != runtime.SystemVersion {
+		t.Errorf("set %v, want [%s]", setCalls, runtime.SystemVersion)
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// toolVersionsFileName is the file asdf reads a project's versions from
+const toolVersionsFileName = ".tool-versions"
+
+// asdfPluginAliases maps asdf plugin names to runtime names where they differ
+var asdfPluginAliases = map[string]string{
+	"nodejs": "node",
+}
+
+// toolVersionsLine is a runtime and its versions from a .tool-versions file. asdf
+// tries the versions in order, so the first one is the preferred version.
+type toolVersionsLine struct {
+	Line     int
+	Plugin   string
+	Versions []string
+}
+
+// importedVersion is a runtime version written to .dtvem/runtimes.json by an import
+type importedVersion struct {
+	RuntimeName string
+	Version     string
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import runtime versions from other version managers",
+	Long: `Import a project's runtime versions from the files of other version managers
+into .dtvem/runtimes.json.
+
+Examples:
+  dtvem import tool-versions`,
+	Args: cobra.NoArgs,
+}
+
+var importToolVersionsCmd = &cobra.Command{
+	Use:   "tool-versions [path]",
+	Short: "Import versions from an asdf .tool-versions file",
+	Long: `Read an asdf .tool-versions file (by default the one in the current directory)
+and set its versions as the local versions in .dtvem/runtimes.json.
+
+asdf plugin names are mapped to runtimes (nodejs becomes node). When a line lists
+several versions, the first one is used. "system" is imported as dtvem's system
+version. Lines for runtimes dtvem doesn't manage, and versions such as "ref:..."
+that aren't version numbers, are skipped with a warning.
+
+Examples:
+  dtvem import tool-versions
+  dtvem import tool-versions ../other-project/.tool-versions`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := toolVersionsFileName
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		imported, err := importToolVersions(path)
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+		if len(imported) == 0 {
+			ui.Warning("No versions imported from %s", path)
+			return
+		}
+
+		for _, v := range imported {
+			ui.Success("Set local %s version to %s", v.RuntimeName, v.Version)
+		}
+		ui.Info("Run 'dtvem install' to install them")
+	},
+}
+
+// importToolVersions reads a .tool-versions file and sets the first version of each
+// known runtime as its local version, warning about the lines it skips
+func importToolVersions(path string) ([]importedVersion, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	lines, err := parseToolVersions(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var imported []importedVersion
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		runtimeName := line.Plugin
+		if alias, ok := asdfPluginAliases[runtimeName]; ok {
+			runtimeName = alias
+		}
+		if _, err := runtime.Get(runtimeName); err != nil {
+			ui.Warning("%s:%d: skipping %s, which dtvem doesn't manage", path, line.Line, line.Plugin)
+			continue
+		}
+		if seen[runtimeName] {
+			ui.Warning("%s:%d: skipping %s, which is already listed", path, line.Line, line.Plugin)
+			continue
+		}
+		if len(line.Versions) == 0 {
+			ui.Warning("%s:%d: skipping %s, which has no version", path, line.Line, line.Plugin)
+			continue
+		}
+		version := line.Versions[0]
+		if runtime.IsSystemVersion(version) {
+			version = runtime.SystemVersion
+		} else if !runtime.LooksLikeVersion(version) {
+			ui.Warning("%s:%d: skipping %s %s, which isn't a version number", path, line.Line, line.Plugin, version)
+			continue
+		}
+
+		if err := config.SetLocalVersion(runtimeName, version); err != nil {
+			return imported, fmt.Errorf("failed to set local %s version: %w", runtimeName, err)
+		}
+		seen[runtimeName] = true
+		imported = append(imported, importedVersion{RuntimeName: runtimeName, Version: version})
+	}
+
+	return imported, nil
+}
+
+// parseToolVersions parses asdf's .tool-versions format: a plugin name and one or
+// more versions per line, separated by whitespace, with # starting a comment
+func parseToolVersions(r io.Reader) ([]toolVersionsLine, error) {
+	var lines []toolVersionsLine
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		lines = append(lines, toolVersionsLine{Line: lineNumber, Plugin: fields[0], Versions: fields[1:]})
+	}
+	return lines, scanner.Err()
+}
+
+func init() {
+	importCmd.AddCommand(importToolVersionsCmd)
+	rootCmd.AddCommand(importCmd)
+}
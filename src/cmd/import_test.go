@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+func TestParseToolVersions(t *testing.T) {
+	input := `# asdf versions
+nodejs 20.11.0
+python   3.12.1 3.11.7  # fallback to 3.11
+
+ruby	3.3.0
+terraform
+`
+	got, err := parseToolVersions(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseToolVersions() error: %v", err)
+	}
+
+	want := []toolVersionsLine{
+		{Line: 2, Plugin: "nodejs", Versions: []string{"20.11.0"}},
+		{Line: 3, Plugin: "python", Versions: []string{"3.12.1", "3.11.7"}},
+		{Line: 5, Plugin: "ruby", Versions: []string{"3.3.0"}},
+		{Line: 6, Plugin: "terraform", Versions: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseToolVersions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestImportToolVersions(t *testing.T) {
+	setupDebugEnv(t)
+	useTestRegistry(t)
+	for _, name := range []string{"node", "python", "ruby"} {
+		if err := runtime.Register(&mockProvider{name: name, displayName: name}); err != nil {
+			t.Fatalf("Failed to register %s: %v", name, err)
+		}
+	}
+
+	contents := `nodejs 20.11.0
+python 3.12.1 3.11.7
+ruby system
+terraform 1.7.0
+node 18.16.0
+`
+	if err := os.WriteFile(toolVersionsFileName, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write .tool-versions: %v", err)
+	}
+
+	imported, err := importToolVersions(toolVersionsFileName)
+	if err != nil {
+		t.Fatalf("importToolVersions() error: %v", err)
+	}
+
+	want := []importedVersion{
+		{RuntimeName: "node", Version: "20.11.0"},
+		{RuntimeName: "python", Version: "3.12.1"},
+		{RuntimeName: "ruby", Version: "system"},
+	}
+	if !reflect.DeepEqual(imported, want) {
+		t.Errorf("importToolVersions() = %+v, want %+v", imported, want)
+	}
+
+	for _, v := range want {
+		resolved, err := config.ResolveVersionWithSource(v.RuntimeName)
+		if err != nil {
+			t.Fatalf("ResolveVersionWithSource(%s) error: %v", v.RuntimeName, err)
+		}
+		if resolved.Version != v.Version || resolved.Source != config.VersionSourceLocal {
+			t.Errorf("ResolveVersionWithSource(%s) = %+v, want %s from the local config", v.RuntimeName, resolved, v.Version)
+		}
+	}
+}
+
+func TestImportToolVersions_MissingFile(t *testing.T) {
+	setupDebugEnv(t)
+
+	if _, err := importToolVersions(toolVersionsFileName); err == nil {
+		t.Error("importToolVersions() should fail without a .tool-versions file")
+	}
+}
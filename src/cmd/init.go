@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/path"
 	"github.com/dtvem/dtvem/src/internal/ui"
@@ -31,8 +33,8 @@ Example:
 
 		if err := config.EnsureDirectories(); err != nil {
 			spinner.Error("Failed to create directories")
-			ui.Error("%v", err)
-			return
+			reportError(err)
+			os.Exit(1)
 		}
 
 		spinner.Success("Directories created")
@@ -43,7 +45,7 @@ Example:
 		if err := path.AddToPath(shimsDir, initYes); err != nil {
 			ui.Error("Failed to configure PATH: %v", err)
 			ui.Info("You can manually add %s to your PATH", shimsDir)
-			return
+			os.Exit(1)
 		}
 
 		ui.Success("dtvem initialized successfully!")
@@ -1,19 +1,38 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/download"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	installYesFlag bool
+	installYesFlag         bool
+	installTimeoutFlag     time.Duration
+	installKeepArchiveFlag string
+	installArchFlag        string
+	installFromFileFlag    string
+	installSHA256Flag      string
+	installAllowEOLFlag    bool
+	installDryRunFlag      bool
+	installFailFastFlag    bool
+	installContinueFlag    bool
+	installInteractiveFlag bool
+	installNoShimsFlag     bool
+	installShimOnlyFlag    bool
+	installNoVerifyFlag    bool
+	installChecksumOnly    bool
 )
 
 var installCmd = &cobra.Command{
@@ -24,18 +43,142 @@ var installCmd = &cobra.Command{
 Single install:
   dtvem install python 3.11.0
   dtvem install node 18.16.0
+  dtvem install python 3.12   # Newest 3.12.x available for this platform
+
+Pick a version from a list (type part of a version to narrow it down):
+  dtvem install node --interactive
 
 Bulk install (reads .dtvem/runtimes.json):
   dtvem install
-  dtvem install --yes    # Skip confirmation prompt`,
+  dtvem install --yes       # Skip confirmation prompt
+  dtvem install --jobs 2    # Download at most 2 archives at once
+  dtvem install --fail-fast # Stop at the first failure (default: --continue-on-error)
+
+Network timeout:
+  dtvem install node 22.0.0 --timeout 30m
+  DTVEM_NETWORK_TIMEOUT=2m dtvem install
+
+Keep a copy of the downloaded archive (e.g. to report a corrupt download):
+  dtvem install node 22.0.0 --keep-archive ./archives
+
+Install several architectures (e.g. to pre-build a cache for other machines):
+  dtvem install node 20.11.1 --arch arm64,amd64
+
+Install from a local archive instead of downloading (e.g. on an air-gapped machine):
+  dtvem install node 20.11.1 --from-file ./node-v20.11.1-linux-x64.tar.gz
+  dtvem install node 20.11.1 --from-file ./node.tar.gz --sha256 <checksum>
+
+Install without creating or updating shims, e.g. to use a version only through
+'dtvem exec' (the next 'dtvem reshim' adds its shims):
+  dtvem install node 22.0.0 --no-shims
+
+Recreate the shims of an installed version (e.g. after they were deleted),
+including those of its global packages, without downloading anything:
+  dtvem install node 22.0.0 --shim-only
+
+Show where archives would be downloaded from (platform, URL, checksum source)
+without installing; -v prints the same details during a real install:
+  dtvem install node 22.0.0 --dry-run
+  dtvem install --dry-run
+
+Re-verify an installed version without reinstalling it: its archive (from the
+download cache, or downloaded again) must match the manifest checksum, and its
+executable must exist and report the installed version:
+  dtvem install node 22.0.0 --checksum-only
+
+After installing, dtvem runs the new version's executable (e.g. node --version)
+and removes the install when it reports a different version, which catches
+mislabeled downloads. Pass --no-verify to keep such an install with a warning.
+
+Installing an end-of-life version prints a warning with its EOL date;
+pass --allow-eol to install it without the warning.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 || len(args) == 2 {
 			return nil
 		}
+		if len(args) == 1 && installInteractiveFlag {
+			return nil
+		}
 		return fmt.Errorf("accepts 0 or 2 arg(s), received %d", len(args))
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 2 {
+		if installTimeoutFlag > 0 {
+			download.SetTimeout(installTimeoutFlag)
+		}
+		if installKeepArchiveFlag != "" {
+			download.SetKeepArchiveDir(installKeepArchiveFlag)
+		}
+
+		if installInteractiveFlag && len(args) != 1 {
+			ui.Error("--interactive requires a runtime and no version")
+			os.Exit(1)
+		}
+		single := len(args) == 2 || installInteractiveFlag
+
+		if installArchFlag != "" && !single {
+			ui.Error("--arch requires a runtime and version")
+			os.Exit(1)
+		}
+		if installFromFileFlag != "" && !single {
+			ui.Error("--from-file requires a runtime and version")
+			os.Exit(1)
+		}
+		if installFromFileFlag != "" && installArchFlag != "" {
+			ui.Error("--from-file cannot be combined with --arch")
+			os.Exit(1)
+		}
+		if installSHA256Flag != "" && installFromFileFlag == "" {
+			ui.Error("--sha256 can only be used with --from-file")
+			os.Exit(1)
+		}
+		if (installFailFastFlag || cmd.Flags().Changed("continue-on-error")) && single {
+			ui.Error("--fail-fast and --continue-on-error only apply to bulk install")
+			os.Exit(1)
+		}
+		if installFailFastFlag && cmd.Flags().Changed("continue-on-error") && installContinueFlag {
+			ui.Error("--fail-fast cannot be combined with --continue-on-error")
+			os.Exit(1)
+		}
+		if installNoShimsFlag && installFromFileFlag != "" {
+			ui.Error("--no-shims cannot be combined with --from-file")
+			os.Exit(1)
+		}
+		if installDryRunFlag && (installFromFileFlag != "" || installArchFlag != "") {
+			ui.Error("--dry-run cannot be combined with --from-file or --arch")
+			os.Exit(1)
+		}
+		if installShimOnlyFlag && len(args) != 2 {
+			ui.Error("--shim-only requires a runtime and version")
+			os.Exit(1)
+		}
+		if installShimOnlyFlag && (installNoShimsFlag || installFromFileFlag != "" || installArchFlag != "" || installDryRunFlag) {
+			ui.Error("--shim-only cannot be combined with --no-shims, --from-file, --arch or --dry-run")
+			os.Exit(1)
+		}
+
+		if installChecksumOnly && len(args) != 2 {
+			ui.Error("--checksum-only requires a runtime and version")
+			os.Exit(1)
+		}
+		if installChecksumOnly && (installShimOnlyFlag || installNoShimsFlag || installFromFileFlag != "" || installArchFlag != "" || installDryRunFlag) {
+			ui.Error("--checksum-only cannot be combined with --shim-only, --no-shims, --from-file, --arch or --dry-run")
+			os.Exit(1)
+		}
+
+		if installShimOnlyFlag {
+			installShimsOnly(args[0], args[1])
+			return
+		}
+		if installChecksumOnly {
+			checkInstall(args[0], args[1])
+			return
+		}
+
+		if installInteractiveFlag {
+			args = append(args, pickInstallVersion(args[0]))
+		}
+
+		if single {
 			// Single install mode
 			installSingle(args[0], args[1])
 		} else {
@@ -48,6 +191,21 @@ Bulk install (reads .dtvem/runtimes.json):
 func init() {
 	rootCmd.AddCommand(installCmd)
 	installCmd.Flags().BoolVarP(&installYesFlag, "yes", "y", false, "Skip confirmation prompt")
+	addJobsFlag(installCmd)
+	installCmd.Flags().BoolVar(&installFailFastFlag, "fail-fast", false, "Bulk install: stop at the first runtime that fails to install")
+	installCmd.Flags().BoolVar(&installContinueFlag, "continue-on-error", true, "Bulk install: keep installing the remaining runtimes after a failure")
+	installCmd.Flags().StringVar(&installArchFlag, "arch", "", "Comma-separated architectures to install (e.g. arm64,amd64); only the native one gets shims")
+	installCmd.Flags().StringVar(&installFromFileFlag, "from-file", "", "Install from this local archive instead of downloading")
+	installCmd.Flags().StringVar(&installSHA256Flag, "sha256", "", "Expected SHA256 checksum of the --from-file archive")
+	installCmd.Flags().BoolVarP(&installInteractiveFlag, "interactive", "i", false, "Pick the version to install from a list")
+	installCmd.Flags().BoolVar(&installNoShimsFlag, "no-shims", false, "Install without creating or updating shims or setting a global version")
+	installCmd.Flags().BoolVar(&installShimOnlyFlag, "shim-only", false, "Recreate the shims of an installed version without reinstalling it")
+	installCmd.Flags().BoolVar(&installChecksumOnly, "checksum-only", false, "Verify an installed version against the manifest checksum without reinstalling it")
+	installCmd.Flags().BoolVar(&installDryRunFlag, "dry-run", false, "Show where archives would be downloaded from without installing")
+	installCmd.Flags().BoolVar(&installNoVerifyFlag, "no-verify", false, "Keep an install whose executable reports a different version, with a warning")
+	installCmd.Flags().BoolVar(&installAllowEOLFlag, "allow-eol", false, "Install end-of-life versions without a warning")
+	installCmd.Flags().StringVar(&installKeepArchiveFlag, "keep-archive", "", "Copy downloaded archives into this directory and print their checksums")
+	installCmd.Flags().DurationVar(&installTimeoutFlag, "timeout", 0, "Network timeout for downloads (e.g. 90s, 30m); overrides "+download.TimeoutEnvVar)
 }
 
 // installSingle installs a single runtime/version
@@ -57,16 +215,55 @@ func installSingle(runtimeName, version string) {
 	provider, err := runtime.Get(runtimeName)
 	if err != nil {
 		ui.Debug("Provider lookup failed: %v", err)
-		ui.Error("%v", err)
-		ui.Info("Available runtimes: %v", runtime.List())
+		reportError(err)
+		ui.Info("Available runtimes: %s", availableRuntimes())
 		os.Exit(1)
 	}
 
 	ui.Debug("Using provider: %s (%s)", provider.Name(), provider.DisplayName())
 
-	if err := provider.Install(version); err != nil {
+	version = resolveVersionArg(runtimeName, version)
+	if runtime.IsSystemVersion(version) {
+		reportError(systemVersionInstallError(provider))
+		os.Exit(1)
+	}
+	latest, err := resolvePartialVersion(provider, version)
+	if err != nil {
+		reportError(err)
+		os.Exit(1)
+	}
+	if latest != version {
+		ui.Info("Using %s %s, the newest %s release", provider.DisplayName(), ui.HighlightVersion(latest), version)
+		version = latest
+	}
+	if err := runtime.CheckSupportedVersion(provider, version); err != nil {
+		reportError(err)
+		os.Exit(1)
+	}
+	warnIfEOL(provider, version)
+
+	if installDryRunFlag {
+		if err := showResolvedDownload(provider, version, true); err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if installArchFlag != "" {
+		installSingleArches(provider, version)
+		return
+	}
+
+	if installFromFileFlag != "" {
+		err = installFromFile(provider, version, installFromFileFlag, installSHA256Flag)
+	} else {
+		_ = showResolvedDownload(provider, version, false)
+		err = installVersion(provider, version)
+	}
+	if err != nil {
 		ui.Debug("Installation failed: %v", err)
-		ui.Error("%v", err)
+		reportError(err)
 		os.Exit(1)
 	}
 
@@ -74,10 +271,98 @@ func installSingle(runtimeName, version string) {
 
 	// Auto-set global version if no global version is currently configured
 	autoSetGlobalIfNeeded(provider, version)
+
+	warnIfShimsIneffective(provider)
+}
+
+// installShimsOnly recreates the shims of an installed version (--shim-only)
+func installShimsOnly(runtimeName, version string) {
+	provider, err := runtime.Get(runtimeName)
+	if err != nil {
+		reportError(err)
+		ui.Info("Available runtimes: %s", availableRuntimes())
+		os.Exit(1)
+	}
+
+	version = resolveVersionArg(runtimeName, version)
+
+	shimNames, err := recreateVersionShims(provider, version)
+	if err != nil {
+		reportError(err)
+		os.Exit(1)
+	}
+
+	ui.Success("Recreated %d shims for %s %s: %s", len(shimNames), provider.DisplayName(), version, strings.Join(shimNames, ", "))
+	warnIfShimsIneffective(provider)
+}
+
+// recreateVersionShims verifies that a version is installed and recreates its shims
+// and the shims of its global packages, returning the shim names
+func recreateVersionShims(provider runtime.Provider, version string) ([]string, error) {
+	if installed, err := provider.IsInstalled(version); err != nil || !installed {
+		return nil, fmt.Errorf("%s %s is not installed; install it with 'dtvem install %s %s'",
+			provider.DisplayName(), version, provider.Name(), version)
+	}
+
+	manager, err := newShimManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.RehashVersion(provider.Name(), version)
+}
+
+// installSingleArches installs a version for each architecture given with --arch
+func installSingleArches(provider runtime.Provider, version string) {
+	targets, err := parseArchList(installArchFlag)
+	if err != nil {
+		ui.Error("Invalid --arch: %v", err)
+		os.Exit(1)
+	}
+
+	if err := installArches(provider, version, targets); err != nil {
+		ui.Debug("Installation failed: %v", err)
+		reportError(err)
+		os.Exit(1)
+	}
+
+	ui.Success("Successfully installed %s %s for %s", provider.DisplayName(), version, archNames(targets))
+	warnIfShimsIneffective(provider)
 }
 
-// autoSetGlobalIfNeeded sets the installed version as global if no global version exists
+// installVersion installs a version with the provider, without shims for --no-shims,
+// and verifies that the installed executable is that version. Versions older than the
+// provider's minimum are rejected before anything is downloaded.
+func installVersion(provider runtime.Provider, version string) error {
+	if runtime.IsSystemVersion(version) {
+		return systemVersionInstallError(provider)
+	}
+	if err := runtime.CheckSupportedVersion(provider, version); err != nil {
+		return err
+	}
+	if !installNoShimsFlag {
+		if err := provider.Install(version); err != nil {
+			return err
+		}
+		return verifyInstall(provider, version)
+	}
+
+	shimless, ok := provider.(runtime.ShimlessInstallProvider)
+	if !ok {
+		return fmt.Errorf("%s cannot be installed without shims", provider.DisplayName())
+	}
+	if err := shimless.InstallWithoutShims(version); err != nil {
+		return err
+	}
+	return verifyInstall(provider, version)
+}
+
+// autoSetGlobalIfNeeded sets the installed version as global if no global version exists.
+// Installs with --no-shims stay isolated and never become the global version.
 func autoSetGlobalIfNeeded(provider runtime.Provider, version string) {
+	if installNoShimsFlag {
+		return
+	}
+
 	currentGlobal, err := provider.GlobalVersion()
 	if err != nil || currentGlobal != "" {
 		// Either an error occurred or a global version is already set
@@ -95,49 +380,132 @@ func autoSetGlobalIfNeeded(provider runtime.Provider, version string) {
 	ui.Info("Set as global version (first install)")
 }
 
-// installBulk installs all runtimes from .dtvem/runtimes.json
 // installTask represents a runtime version to be installed
 type installTask struct {
 	runtimeName      string
 	version          string
 	provider         runtime.Provider
 	alreadyInstalled bool
+	previouslyFailed bool
+}
+
+// key identifies the task in the install progress file
+func (t installTask) key() string {
+	return t.runtimeName + "@" + t.version
 }
 
-// buildInstallTasks creates a list of install tasks from the config
-func buildInstallTasks(runtimes map[string]string) []installTask {
+// installProgressFileName is the file in the cache directory that records bulk install progress
+const installProgressFileName = "install-progress.json"
+
+// Task states recorded in the install progress file
+const (
+	taskStateInstalled = "installed"
+	taskStateFailed    = "failed"
+)
+
+// installProgress records the outcome of each task of a bulk install,
+// so that re-running the install resumes at the task that failed
+type installProgress struct {
+	ConfigPath string            `json:"config_path"`
+	Tasks      map[string]string `json:"tasks"`
+}
+
+// installProgressPath returns the path to the bulk install progress file
+func installProgressPath() string {
+	return filepath.Join(config.DefaultPaths().Cache, installProgressFileName)
+}
+
+// loadInstallProgress reads the recorded progress of a previous bulk install of configPath.
+// Progress recorded for a different config file is ignored.
+func loadInstallProgress(configPath string) *installProgress {
+	progress := &installProgress{ConfigPath: configPath, Tasks: map[string]string{}}
+
+	data, err := os.ReadFile(installProgressPath())
+	if err != nil {
+		return progress
+	}
+
+	var previous installProgress
+	if err := json.Unmarshal(data, &previous); err != nil || previous.ConfigPath != configPath || previous.Tasks == nil {
+		return progress
+	}
+
+	return &previous
+}
+
+// record stores the outcome of a task and saves the progress file
+func (p *installProgress) record(task installTask, state string) {
+	p.Tasks[task.key()] = state
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(installProgressPath()), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(installProgressPath(), data, 0644); err != nil {
+		ui.Debug("Failed to save install progress: %v", err)
+	}
+}
+
+// clear removes the progress file once every task has been installed
+func (p *installProgress) clear() {
+	_ = os.Remove(installProgressPath())
+}
+
+// buildInstallTasks creates a list of install tasks from the config, in a stable order
+func buildInstallTasks(runtimes map[string]string, progress *installProgress) []installTask {
 	var tasks []installTask
 
+	runtimeNames := make([]string, 0, len(runtimes))
+	for runtimeName := range runtimes {
+		runtimeNames = append(runtimeNames, runtimeName)
+	}
+	sort.Strings(runtimeNames)
+
 	ui.Info("Checking which versions need to be installed...")
-	for runtimeName, version := range runtimes {
+	for _, runtimeName := range runtimeNames {
+		version := runtime.NormalizeVersion(runtimes[runtimeName])
 		provider, err := runtime.Get(runtimeName)
 		if err != nil {
 			ui.Warning("Unknown runtime '%s', skipping", runtimeName)
 			continue
 		}
+		if runtime.IsSystemVersion(version) {
+			ui.Info("%s uses the system installation, skipping", provider.DisplayName())
+			continue
+		}
 
-		alreadyInstalled := isVersionInstalled(provider, version)
-
-		tasks = append(tasks, installTask{
+		task := installTask{
 			runtimeName:      runtimeName,
 			version:          version,
 			provider:         provider,
-			alreadyInstalled: alreadyInstalled,
-		})
+			alreadyInstalled: isVersionInstalled(provider, version),
+		}
+		task.previouslyFailed = !task.alreadyInstalled && progress.Tasks[task.key()] == taskStateFailed
+
+		tasks = append(tasks, task)
 	}
 
 	return tasks
 }
 
-// isVersionInstalled checks if a specific version is already installed
+// isVersionInstalled checks if a specific version is already installed, or for a
+// wildcard pin such as "20.x", that at least one matching version is installed
 func isVersionInstalled(provider runtime.Provider, version string) bool {
+	if runtime.IsVersionPin(version) {
+		installed, err := isVersionOrPinInstalled(provider, version)
+		return err == nil && installed
+	}
+
 	installedVersions, err := provider.ListInstalled()
 	if err != nil {
 		return false
 	}
 
 	for _, installed := range installedVersions {
-		if installed.Version.Raw == version {
+		if installed.Version.Equal(runtime.NewVersion(version)) {
 			return true
 		}
 	}
@@ -153,6 +521,9 @@ func showInstallationPlan(tasks []installTask) (toInstall, alreadyInstalled int)
 		if task.alreadyInstalled {
 			ui.Info("  ✓ %s %s (already installed)", task.provider.DisplayName(), task.version)
 			alreadyInstalled++
+		} else if task.previouslyFailed {
+			ui.Info("  → %s %s (will resume after previous failure)", task.provider.DisplayName(), task.version)
+			toInstall++
 		} else {
 			ui.Info("  → %s %s (will install)", task.provider.DisplayName(), task.version)
 			toInstall++
@@ -178,26 +549,82 @@ func promptInstallConfirmation(toInstallCount, alreadyInstalledCount int) bool {
 	return response == "" || response == constants.ResponseY || response == constants.ResponseYes
 }
 
-// executeInstalls installs all tasks and returns counts and failures
-func executeInstalls(tasks []installTask) (success, failures int, failureList []string) {
+// showBulkDryRun prints where each runtime that would be installed is downloaded from
+func showBulkDryRun(tasks []installTask) {
+	failed := false
+	for _, task := range tasks {
+		if task.alreadyInstalled {
+			continue
+		}
+		fmt.Println()
+		if err := showResolvedDownload(task.provider, task.version, true); err != nil {
+			ui.Error("%s %s: %v", task.provider.DisplayName(), task.version, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// executeInstalls installs all tasks and returns counts and failures.
+// With failFast it stops at the first failure and leaves the remaining tasks
+// for the next run; otherwise it installs every task.
+func executeInstalls(tasks []installTask, progress *installProgress, failFast bool) (success, failures int, failureList []string) {
 	ui.Header("\nInstalling runtimes...")
 
-	for _, task := range tasks {
+	// A wildcard pin such as "20.x" installs the newest patch release it matches
+	versions := make([]string, len(tasks))
+	resolveErrors := make([]error, len(tasks))
+	targets := make([]prefetchTarget, 0, len(tasks))
+	for i, task := range tasks {
 		if task.alreadyInstalled {
 			continue
 		}
+		versions[i], resolveErrors[i] = resolvePartialVersion(task.provider, task.version)
+		if resolveErrors[i] != nil {
+			versions[i] = task.version
+			continue
+		}
+		if runtime.CheckSupportedVersion(task.provider, versions[i]) == nil {
+			targets = append(targets, prefetchTarget{provider: task.provider, version: versions[i]})
+		}
+	}
+	prefetchArchives(targets, jobsFlag)
 
-		ui.Progress("Installing %s %s...", task.provider.DisplayName(), task.version)
+	explainedPermissions := false
+	for i, task := range tasks {
+		if task.alreadyInstalled {
+			continue
+		}
 
-		if err := task.provider.Install(task.version); err != nil {
-			ui.Error("Failed to install %s %s: %v", task.provider.DisplayName(), task.version, err)
+		version := versions[i]
+		ui.Progress("Installing %s %s...", task.provider.DisplayName(), version)
+		err := resolveErrors[i]
+		if err == nil {
+			warnIfEOL(task.provider, version)
+			_ = showResolvedDownload(task.provider, version, false)
+			err = installVersion(task.provider, version)
+		}
+
+		if err != nil {
+			ui.Error("Failed to install %s %s: %v", task.provider.DisplayName(), version, err)
+			if !explainedPermissions {
+				explainedPermissions = explainPermissionError(err)
+			}
 			failures++
-			failureList = append(failureList, fmt.Sprintf("%s %s", task.provider.DisplayName(), task.version))
+			failureList = append(failureList, fmt.Sprintf("%s %s", task.provider.DisplayName(), version))
+			progress.record(task, taskStateFailed)
+			if failFast {
+				ui.Warning("Stopping at the first failure (--fail-fast)")
+				break
+			}
 		} else {
-			ui.Success("Installed %s %s", task.provider.DisplayName(), task.version)
+			ui.Success("Installed %s %s", task.provider.DisplayName(), version)
 			success++
+			progress.record(task, taskStateInstalled)
 			// Auto-set global version if needed
-			autoSetGlobalIfNeeded(task.provider, task.version)
+			autoSetGlobalIfNeeded(task.provider, version)
 		}
 	}
 
@@ -205,7 +632,7 @@ func executeInstalls(tasks []installTask) (success, failures int, failureList []
 }
 
 // showInstallSummary displays the final installation summary
-func showInstallSummary(successCount, alreadyInstalledCount, failureCount int, failures []string) {
+func showInstallSummary(successCount, alreadyInstalledCount, failureCount int) {
 	ui.Header("\nInstallation Summary:")
 
 	if successCount > 0 {
@@ -216,9 +643,7 @@ func showInstallSummary(successCount, alreadyInstalledCount, failureCount int, f
 	}
 	if failureCount > 0 {
 		ui.Error("Failed to install: %d runtime(s)", failureCount)
-		for _, failure := range failures {
-			ui.Error("  - %s", failure)
-		}
+		ui.Info("Run 'dtvem install' again to resume; downloaded archives are reused")
 	}
 
 	if failureCount == 0 {
@@ -255,17 +680,24 @@ func installBulk() {
 		return
 	}
 
-	// Build install tasks
-	tasks := buildInstallTasks(runtimes)
+	// Build install tasks, picking up where a previous failed run left off
+	progress := loadInstallProgress(configPath)
+	tasks := buildInstallTasks(runtimes, progress)
 
 	// Show installation plan
 	toInstallCount, alreadyInstalledCount := showInstallationPlan(tasks)
 
 	if toInstallCount == 0 {
+		progress.clear()
 		ui.Success("\nAll runtimes are already installed!")
 		return
 	}
 
+	if installDryRunFlag {
+		showBulkDryRun(tasks)
+		return
+	}
+
 	// Prompt for confirmation
 	if !promptInstallConfirmation(toInstallCount, alreadyInstalledCount) {
 		ui.Info("Installation canceled")
@@ -273,13 +705,36 @@ func installBulk() {
 	}
 
 	// Execute installations
-	successCount, failureCount, failures := executeInstalls(tasks)
+	successCount, failureCount, failures := executeInstalls(tasks, progress, installFailFastFlag || !installContinueFlag)
+	if failureCount == 0 {
+		progress.clear()
+	}
 
 	// Show final summary
-	showInstallSummary(successCount, alreadyInstalledCount, failureCount, failures)
+	showInstallSummary(successCount, alreadyInstalledCount, failureCount)
 
-	// Exit with error if any installations failed
-	if failureCount > 0 {
+	if successCount > 0 {
+		seen := make(map[string]bool)
+		for _, task := range tasks {
+			if !task.alreadyInstalled && !seen[task.runtimeName] {
+				seen[task.runtimeName] = true
+				warnIfShimsIneffective(task.provider)
+			}
+		}
+	}
+
+	// Exit with an error if any installations failed, so CI provisioning fails too
+	if err := bulkInstallError(failures); err != nil {
+		ui.Error("%v", err)
 		os.Exit(1)
 	}
 }
+
+// bulkInstallError returns an error listing the runtimes a bulk install failed to
+// install, or nil when there were no failures
+func bulkInstallError(failures []string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to install %d runtime(s): %s", len(failures), strings.Join(failures, ", "))
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// archTarget is one architecture of a multi-architecture install
+type archTarget struct {
+	arch   string
+	native bool
+}
+
+// parseArchList turns a comma-separated --arch value into install targets,
+// normalizing spellings such as x64 and dropping duplicates. The architecture
+// dtvem would install by default is marked native.
+func parseArchList(value string) ([]archTarget, error) {
+	current := manifest.CurrentPlatform()
+
+	var targets []archTarget
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		platform := manifest.PlatformForArch(entry)
+		if !manifest.IsValidPlatform(platform) {
+			return nil, fmt.Errorf("unsupported architecture %q for %s", strings.TrimSpace(entry), manifest.PlatformArch(current))
+		}
+
+		arch := manifest.PlatformArch(platform)
+		if seen[arch] {
+			continue
+		}
+		seen[arch] = true
+		targets = append(targets, archTarget{arch: arch, native: platform == current})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no architectures given")
+	}
+	return targets, nil
+}
+
+// installArches installs a version for several architectures. The native architecture
+// is installed as usual and gets shims; the others are installed into arch-scoped
+// directories (see config.RuntimeArchVersionPath) without shims. Every architecture is
+// checked against the manifest before anything is installed.
+func installArches(provider runtime.Provider, version string, targets []archTarget) error {
+	archProvider, ok := provider.(runtime.ArchInstallProvider)
+	if !ok {
+		return fmt.Errorf("%s does not support multi-architecture installs", provider.DisplayName())
+	}
+
+	var unavailable []string
+	for _, target := range targets {
+		if err := archProvider.CheckArch(version, target.arch); err != nil {
+			unavailable = append(unavailable, err.Error())
+		}
+	}
+	if len(unavailable) > 0 {
+		return fmt.Errorf("not every requested architecture is available:\n  %s", strings.Join(unavailable, "\n  "))
+	}
+
+	for _, target := range targets {
+		if target.native {
+			if installed, _ := provider.IsInstalled(version); installed {
+				ui.Info("%s %s (%s) is already installed", provider.DisplayName(), version, target.arch)
+				continue
+			}
+			if err := installVersion(provider, version); err != nil {
+				return err
+			}
+			autoSetGlobalIfNeeded(provider, version)
+			continue
+		}
+
+		installPath := config.RuntimeArchVersionPath(provider.Name(), version, target.arch)
+		if _, err := os.Stat(installPath); err == nil {
+			ui.Info("%s %s (%s) is already installed in %s", provider.DisplayName(), version, target.arch, installPath)
+			continue
+		}
+		if err := archProvider.InstallArch(version, target.arch, installPath); err != nil {
+			return fmt.Errorf("failed to install %s: %w", target.arch, err)
+		}
+	}
+
+	return nil
+}
+
+// archNames returns the architectures of a multi-architecture install for display
+func archNames(targets []archTarget) string {
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = target.arch
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// mockArchProvider is a mockProvider that can install other architectures.
+// Install stands for the normal install, which creates shims.
+type mockArchProvider struct {
+	mockProvider
+	unavailable  map[string]bool
+	archInstalls []string
+}
+
+func (m *mockArchProvider) CheckArch(version, arch string) error {
+	if m.unavailable[arch] {
+		return fmt.Errorf("%s %s is not available for %s", m.displayName, version, arch)
+	}
+	return nil
+}
+
+func (m *mockArchProvider) InstallArch(version, arch, installPath string) error {
+	m.archInstalls = append(m.archInstalls, arch)
+	return os.MkdirAll(filepath.Join(installPath, "bin"), 0755)
+}
+
+// setupArchInstall points dtvem at a temporary root and pins the native architecture to amd64
+func setupArchInstall(t *testing.T) {
+	t.Helper()
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	t.Setenv(config.ArchEnvVar, "amd64")
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+}
+
+func TestParseArchList(t *testing.T) {
+	setupArchInstall(t)
+
+	tests := []struct {
+		value   string
+		want    []archTarget
+		wantErr bool
+	}{
+		{"arm64,amd64", []archTarget{{"arm64", false}, {"amd64", true}}, false},
+		{"x64, aarch64", []archTarget{{"amd64", true}, {"arm64", false}}, false},
+		{"arm64,aarch64", []archTarget{{"arm64", false}}, false},
+		{"sparc", nil, true},
+		{" , ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseArchList(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArchList(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseArchList(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallArches_OnlyNativeGetsShims(t *testing.T) {
+	setupArchInstall(t)
+	provider := &mockArchProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js", globalVersion: "18.0.0"}}
+
+	targets, err := parseArchList("arm64,amd64")
+	if err != nil {
+		t.Fatalf("parseArchList() error: %v", err)
+	}
+	if err := installArches(provider, "20.11.1", targets); err != nil {
+		t.Fatalf("installArches() error: %v", err)
+	}
+
+	if !reflect.DeepEqual(provider.installCalls, []string{"20.11.1"}) {
+		t.Errorf("Install (with shims) calls = %v, want only the native install", provider.installCalls)
+	}
+	if !reflect.DeepEqual(provider.archInstalls, []string{"arm64"}) {
+		t.Errorf("InstallArch calls = %v, want [arm64]", provider.archInstalls)
+	}
+
+	armPath := config.RuntimeArchVersionPath("node", "20.11.1", "arm64")
+	if _, err := os.Stat(filepath.Join(armPath, "bin")); err != nil {
+		t.Errorf("arm64 install directory not created: %v", err)
+	}
+	if _, err := os.Stat(config.RuntimeArchVersionPath("node", "20.11.1", "amd64")); !os.IsNotExist(err) {
+		t.Errorf("native install should not go to an arch-scoped directory: %v", err)
+	}
+
+	// Installing again skips the architectures that are already there
+	provider.installed = true
+	if err := installArches(provider, "20.11.1", targets); err != nil {
+		t.Fatalf("installArches() second run error: %v", err)
+	}
+	if len(provider.installCalls) != 1 || len(provider.archInstalls) != 1 {
+		t.Errorf("second run installed again: Install %v, InstallArch %v", provider.installCalls, provider.archInstalls)
+	}
+}
+
+func TestInstallArches_UnavailableArchInstallsNothing(t *testing.T) {
+	setupArchInstall(t)
+	provider := &mockArchProvider{
+		mockProvider: mockProvider{name: "node", displayName: "Node.js"},
+		unavailable:  map[string]bool{"arm64": true},
+	}
+
+	targets, err := parseArchList("amd64,arm64")
+	if err != nil {
+		t.Fatalf("parseArchList() error: %v", err)
+	}
+	if err := installArches(provider, "20.11.1", targets); err == nil {
+		t.Fatal("installArches() should fail when an architecture is not in the manifest")
+	}
+
+	if len(provider.installCalls) != 0 || len(provider.archInstalls) != 0 {
+		t.Errorf("nothing should be installed: Install %v, InstallArch %v", provider.installCalls, provider.archInstalls)
+	}
+}
+
+func TestInstallArches_UnsupportedProvider(t *testing.T) {
+	setupArchInstall(t)
+	provider := &mockProvider{name: "test", displayName: "Test"}
+
+	if err := installArches(provider, "1.0.0", []archTarget{{"amd64", true}}); err == nil {
+		t.Error("installArches() should fail for a provider without multi-architecture support")
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// installCheck is the outcome of one check of an installed version
+type installCheck struct {
+	Name string
+	// Err is why the check failed, nil when it passed
+	Err error
+	// Skipped explains why the check could not run; empty when it ran
+	Skipped string
+}
+
+// checkInstall re-verifies an installed version without reinstalling it and
+// exits non-zero when a check fails
+func checkInstall(runtimeName, version string) {
+	provider, err := runtime.Get(runtimeName)
+	if err != nil {
+		reportError(err)
+		ui.Info("Available runtimes: %s", availableRuntimes())
+		os.Exit(1)
+	}
+
+	version = resolveVersionArg(runtimeName, version)
+
+	ui.Header("Verifying %s v%s...", provider.DisplayName(), version)
+	checks, err := checkInstalledVersion(provider, version)
+	if err != nil {
+		reportError(err)
+		os.Exit(1)
+	}
+
+	if failed := reportInstallChecks(checks); failed > 0 {
+		ui.Error("%d of %d checks failed; reinstall with 'dtvem uninstall %s %s' and 'dtvem install %s %s'",
+			failed, len(checks), runtimeName, version, runtimeName, version)
+		os.Exit(1)
+	}
+	ui.Success("%s v%s is intact", provider.DisplayName(), version)
+}
+
+// checkInstalledVersion runs the checks of install --checksum-only: the version's
+// archive matches the manifest checksum, and its executable exists and reports the
+// installed version. The archive is taken from the archive cache, or downloaded
+// into it for the comparison and removed afterwards.
+func checkInstalledVersion(provider runtime.Provider, version string) ([]installCheck, error) {
+	if installed, err := provider.IsInstalled(version); err != nil || !installed {
+		return nil, fmt.Errorf("%s %s is not installed; install it with 'dtvem install %s %s'",
+			provider.DisplayName(), version, provider.Name(), version)
+	}
+
+	checks := []installCheck{checkArchiveChecksum(provider, version)}
+
+	executable := installCheck{Name: "Executable exists"}
+	if execPath, err := provider.ExecutablePath(version); err != nil {
+		executable.Err = err
+	} else if _, err := os.Stat(execPath); err != nil {
+		executable.Err = fmt.Errorf("%s is missing", execPath)
+	}
+	checks = append(checks, executable)
+
+	runs := installCheck{Name: "Executable reports v" + version}
+	switch {
+	case executable.Err != nil:
+		runs.Skipped = "no executable"
+	case !isVersionReporter(provider):
+		runs.Skipped = provider.DisplayName() + " cannot report its version"
+	default:
+		runs.Err = checkReportedVersion(provider, version)
+	}
+	checks = append(checks, runs)
+
+	return checks, nil
+}
+
+// checkArchiveChecksum compares the version's archive with the manifest checksum
+func checkArchiveChecksum(provider runtime.Provider, version string) installCheck {
+	check := installCheck{Name: "Archive matches the manifest checksum"}
+
+	resolver, ok := provider.(runtime.DownloadResolver)
+	if !ok {
+		check.Skipped = provider.DisplayName() + " cannot report its downloads"
+		return check
+	}
+	resolved, err := resolver.ResolveDownload(version)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	if resolved.SHA256 == "" {
+		check.Skipped = "no checksum in the manifest"
+		return check
+	}
+
+	archiveName := download.ArchiveName(resolved.URL, resolved.Format)
+	archivePath := download.ArchiveCachePath(provider.Name(), version, archiveName)
+	if !download.IsArchiveCached(archivePath) {
+		ui.Progress("Downloading %s to compare it...", archiveName)
+		if err := download.PrefetchArchive(provider.Name(), version, resolved.URL, archiveName, ""); err != nil {
+			check.Err = err
+			return check
+		}
+		defer download.RemoveCachedArchive(provider.Name(), version, archiveName)
+	}
+
+	check.Err = download.VerifyFile(archivePath, resolved.SHA256)
+	return check
+}
+
+// isVersionReporter reports whether a provider can read its executable's version
+func isVersionReporter(provider runtime.Provider) bool {
+	_, ok := provider.(runtime.VersionReporter)
+	return ok
+}
+
+// reportInstallChecks prints each check's outcome and returns how many failed
+func reportInstallChecks(checks []installCheck) int {
+	failed := 0
+	for _, check := range checks {
+		switch {
+		case check.Skipped != "":
+			ui.Info("%s: skipped (%s)", check.Name, check.Skipped)
+		case check.Err != nil:
+			failed++
+			var mismatch *download.ErrChecksumMismatch
+			if errors.As(check.Err, &mismatch) {
+				ui.Error("%s: expected %s, got %s", check.Name, mismatch.Expected, mismatch.Actual)
+			} else {
+				ui.Error("%s: %v", check.Name, check.Err)
+			}
+		default:
+			ui.Success("%s", check.Name)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// checksumCheckProvider is an installed mock version that also reports its download
+type checksumCheckProvider struct {
+	*versionReportingProvider
+	resolved *runtime.ResolvedDownload
+}
+
+func (p *checksumCheckProvider) ResolveDownload(version string) (*runtime.ResolvedDownload, error) {
+	return p.resolved, nil
+}
+
+func TestCheckInstalledVersion(t *testing.T) {
+	archive := []byte("archive contents")
+	sum := sha256.Sum256(archive)
+	archiveSHA256 := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name         string
+		sha256       string
+		reported     string
+		removeExec   bool
+		wantFailed   int
+		wantSkipped  int
+		wantMismatch bool
+	}{
+		{name: "intact install", sha256: archiveSHA256, reported: "20.11.1"},
+		{name: "checksum mismatch", sha256: "0000", reported: "20.11.1", wantFailed: 1, wantMismatch: true},
+		{name: "no checksum in the manifest", reported: "20.11.1", wantSkipped: 1},
+		{name: "wrong version reported", sha256: archiveSHA256, reported: "20.11.0", wantFailed: 1},
+		{name: "missing executable", sha256: archiveSHA256, reported: "20.11.1", removeExec: true, wantFailed: 1, wantSkipped: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupDebugEnv(t)
+			reporter := installFakeVersion(t, "20.11.1", tt.reported)
+			reporter.installed = true
+			provider := &checksumCheckProvider{
+				versionReportingProvider: reporter,
+				resolved:                 &runtime.ResolvedDownload{URL: server.URL + "/verifyrt-20.11.1.tar.gz", SHA256: tt.sha256},
+			}
+			if tt.removeExec {
+				if err := os.Remove(reporter.execPath); err != nil {
+					t.Fatalf("Failed to remove executable: %v", err)
+				}
+			}
+
+			checks, err := checkInstalledVersion(provider, "20.11.1")
+			if err != nil {
+				t.Fatalf("checkInstalledVersion() error: %v", err)
+			}
+
+			skipped := 0
+			for _, check := range checks {
+				if check.Skipped != "" {
+					skipped++
+				}
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("skipped checks = %d, want %d: %+v", skipped, tt.wantSkipped, checks)
+			}
+			if failed := reportInstallChecks(checks); failed != tt.wantFailed {
+				t.Errorf("reportInstallChecks() = %d failed, want %d: %+v", failed, tt.wantFailed, checks)
+			}
+
+			var mismatch *download.ErrChecksumMismatch
+			if got := errors.As(checks[0].Err, &mismatch); got != tt.wantMismatch {
+				t.Errorf("archive check error = %v, want a checksum mismatch: %v", checks[0].Err, tt.wantMismatch)
+			}
+
+			// An archive downloaded only for the comparison isn't left in the cache
+			if download.IsArchiveCached(download.ArchiveCachePath("verifyrt", "20.11.1", "verifyrt-20.11.1.tar.gz")) {
+				t.Error("downloaded archive left in the cache")
+			}
+		})
+	}
+}
+
+func TestCheckInstalledVersion_NotInstalled(t *testing.T) {
+	setupDebugEnv(t)
+	provider := &mockProvider{name: "verifyrt", displayName: "Verify Runtime"}
+
+	if _, err := checkInstalledVersion(provider, "20.11.1"); err == nil {
+		t.Error("checkInstalledVersion() of a version that is not installed should fail")
+	}
+}
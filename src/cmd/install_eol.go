@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// eolDateLayout is the layout of the dates in eolDates
+const eolDateLayout = "2006-01-02"
+
+// eolDates maps each runtime to the end-of-life dates of its release series.
+// Python, Ruby and PHP series are major.minor; Node.js series are majors.
+var eolDates = map[string]map[string]string{
+	"python": {
+		"2.7": "2020-01-01",
+		"3.5": "2020-09-30",
+		"3.6": "2021-12-23",
+		"3.7": "2023-06-27",
+		"3.8": "2024-10-07",
+		"3.9": "2025-10-31",
+	},
+	"ruby": {
+		"2.1": "2017-03-31",
+		"2.2": "2018-03-31",
+		"2.3": "2019-03-31",
+		"2.4": "2020-03-31",
+		"2.5": "2021-04-05",
+		"2.6": "2022-04-12",
+		"2.7": "2023-03-31",
+		"3.0": "2024-04-23",
+		"3.1": "2025-03-26",
+		"3.2": "2026-03-31",
+	},
+	"node": {
+		"10": "2021-04-30",
+		"11": "2019-06-01",
+		"12": "2022-04-30",
+		"13": "2020-06-01",
+		"14": "2023-04-30",
+		"15": "2021-06-01",
+		"16": "2023-09-11",
+		"17": "2022-06-01",
+		"18": "2025-04-30",
+		"19": "2023-06-01",
+		"20": "2026-04-30",
+		"21": "2024-06-01",
+		"23": "2025-06-01",
+		"25": "2026-06-01",
+	},
+	"php": {
+		"5.6": "2018-12-31",
+		"7.0": "2019-01-10",
+		"7.1": "2019-12-01",
+		"7.2": "2020-11-30",
+		"7.3": "2021-12-06",
+		"7.4": "2022-11-28",
+		"8.0": "2023-11-26",
+		"8.1": "2025-12-31",
+		"8.2": "2026-12-31",
+	},
+}
+
+// eolStatus reports whether version is past its end of life at now, and the EOL date
+// when it is known. The series table is checked first (major.minor, then major);
+// versions it doesn't cover fall back to the manifest's EOL flag, which has no date.
+func eolStatus(table map[string]string, m *manifest.Manifest, version string, now time.Time) (string, bool) {
+	base, _ := manifest.SplitBuild(version)
+	parts := strings.Split(base, ".")
+
+	var series []string
+	if len(parts) >= 2 {
+		series = append(series, parts[0]+"."+parts[1])
+	}
+	series = append(series, parts[0])
+
+	for _, s := range series {
+		date, ok := table[s]
+		if !ok {
+			continue
+		}
+		eol, err := time.Parse(eolDateLayout, date)
+		if err != nil {
+			continue
+		}
+		return date, !now.Before(eol)
+	}
+
+	if m != nil {
+		if release := m.Releases[base]; release != nil && release.EOL {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// warnIfEOL prints a warning when version has reached its end of life, unless
+// --allow-eol acknowledged it. EOL versions still install: projects may pin them.
+func warnIfEOL(provider runtime.Provider, version string) {
+	if installAllowEOLFlag {
+		return
+	}
+
+	// The manifest is only a fallback, so a failure to load it is not an error here
+	m, _ := manifest.DefaultSource().GetManifest(provider.Name())
+
+	date, eol := eolStatus(eolDates[provider.Name()], m, version, time.Now())
+	if !eol {
+		return
+	}
+
+	if date != "" {
+		ui.Warning("%s %s reached end of life on %s and no longer receives security fixes", provider.DisplayName(), version, date)
+	} else {
+		ui.Warning("%s %s has reached end of life and no longer receives security fixes", provider.DisplayName(), version)
+	}
+	ui.Info("Its package managers may also have dropped support for it; pass --allow-eol to hide this warning")
+}
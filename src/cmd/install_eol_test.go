@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/manifest"
+)
+
+func TestEOLStatus(t *testing.T) {
+	table := map[string]string{
+		"3.8": "2024-10-07",
+		"3.9": "2025-10-31",
+		"18":  "2025-04-30",
+	}
+	m := &manifest.Manifest{
+		Version: 1,
+		Releases: map[string]*manifest.Release{
+			"3.5.10": {EOL: true},
+			"3.13.1": {Date: "2024-12-03"},
+		},
+	}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		version  string
+		m        *manifest.Manifest
+		wantDate string
+		wantEOL  bool
+	}{
+		{"series past EOL", "3.8.20", m, "2024-10-07", true},
+		{"series not yet EOL", "3.9.21", m, "2025-10-31", false},
+		{"major series past EOL", "18.20.4", m, "2025-04-30", true},
+		{"build tag", "3.8.20+20241002", m, "2024-10-07", true},
+		{"manifest EOL flag", "3.5.10", m, "", true},
+		{"supported version", "3.13.1", m, "", false},
+		{"unknown version", "3.14.0", m, "", false},
+		{"no manifest", "3.5.10", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, eol := eolStatus(table, tt.m, tt.version, now)
+			if date != tt.wantDate || eol != tt.wantEOL {
+				t.Errorf("eolStatus(%q) = (%q, %v), want (%q, %v)", tt.version, date, eol, tt.wantDate, tt.wantEOL)
+			}
+		})
+	}
+}
+
+func TestEOLStatus_OnTheEOLDate(t *testing.T) {
+	table := map[string]string{"3.8": "2024-10-07"}
+	now := time.Date(2024, 10, 7, 12, 0, 0, 0, time.UTC)
+
+	if _, eol := eolStatus(table, nil, "3.8.20", now); !eol {
+		t.Error("eolStatus() should report EOL on the EOL date itself")
+	}
+}
+
+func TestEOLDatesAreValid(t *testing.T) {
+	for runtimeName, series := range eolDates {
+		for s, date := range series {
+			if _, err := time.Parse(eolDateLayout, date); err != nil {
+				t.Errorf("eolDates[%q][%q] = %q is not a valid date: %v", runtimeName, s, date, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// installFromFile installs a version from a local archive instead of downloading it.
+// The archive is verified against expectedSHA256, or the manifest's checksum when the
+// archive is the file the manifest lists for this version; without either, it is
+// installed unverified with a warning.
+func installFromFile(provider runtime.Provider, version, archivePath, expectedSHA256 string) error {
+	archiveProvider, ok := provider.(runtime.ArchiveInstallProvider)
+	if !ok {
+		return fmt.Errorf("%s does not support installing from a local archive", provider.DisplayName())
+	}
+
+	if info, err := os.Stat(archivePath); err != nil {
+		return fmt.Errorf("cannot read archive: %w", err)
+	} else if info.IsDir() {
+		return fmt.Errorf("%s is a directory, not an archive", archivePath)
+	}
+
+	if expectedSHA256 == "" {
+		if m, err := manifest.DefaultSource().GetManifest(provider.Name()); err == nil {
+			expectedSHA256 = manifestChecksum(m, version, manifest.CurrentPlatform(), archivePath)
+		}
+	}
+
+	if expectedSHA256 == "" {
+		ui.Warning("No checksum known for %s; installing it unverified (use --sha256 to verify it)", filepath.Base(archivePath))
+	} else {
+		if err := download.VerifyFile(archivePath, expectedSHA256); err != nil {
+			return fmt.Errorf("archive verification failed: %w", err)
+		}
+		ui.Success("Checksum verified")
+	}
+
+	if err := archiveProvider.InstallFromArchive(version, archivePath); err != nil {
+		return err
+	}
+	return verifyInstall(provider, version)
+}
+
+// manifestChecksum returns the manifest's checksum for an archive, provided the
+// archive has the same name as the download the manifest lists for version and platform
+func manifestChecksum(m *manifest.Manifest, version, platform, archivePath string) string {
+	dl := m.GetDownload(version, platform)
+	if dl == nil || filepath.Base(dl.URL) != filepath.Base(archivePath) {
+		return ""
+	}
+	return dl.SHA256
+}
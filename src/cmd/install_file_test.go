@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/manifest"
+)
+
+// mockArchiveProvider is a mockProvider that records installs from local archives
+type mockArchiveProvider struct {
+	mockProvider
+	archiveInstalls []string
+}
+
+func (m *mockArchiveProvider) InstallFromArchive(version, archivePath string) error {
+	m.archiveInstalls = append(m.archiveInstalls, version+" "+filepath.Base(archivePath))
+	return nil
+}
+
+// writeFixtureArchive writes a small archive and returns its path and checksum
+func writeFixtureArchive(t *testing.T) (string, string) {
+	t.Helper()
+	archivePath := filepath.Join(t.TempDir(), "node-v20.11.1-linux-x64.tar.gz")
+	if err := os.WriteFile(archivePath, []byte("archive contents"), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	checksum, err := download.ComputeSHA256(archivePath)
+	if err != nil {
+		t.Fatalf("ComputeSHA256() error: %v", err)
+	}
+	return archivePath, checksum
+}
+
+func TestInstallFromFile_VerifiesChecksum(t *testing.T) {
+	archivePath, checksum := writeFixtureArchive(t)
+	provider := &mockArchiveProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}}
+
+	if err := installFromFile(provider, "20.11.1", archivePath, checksum); err != nil {
+		t.Fatalf("installFromFile() error: %v", err)
+	}
+	want := []string{"20.11.1 node-v20.11.1-linux-x64.tar.gz"}
+	if !reflect.DeepEqual(provider.archiveInstalls, want) {
+		t.Errorf("archive installs = %v, want %v", provider.archiveInstalls, want)
+	}
+	if len(provider.installCalls) != 0 {
+		t.Errorf("Install (download) should not be called, got %v", provider.installCalls)
+	}
+}
+
+func TestInstallFromFile_ChecksumMismatch(t *testing.T) {
+	archivePath, _ := writeFixtureArchive(t)
+	provider := &mockArchiveProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}}
+
+	err := installFromFile(provider, "20.11.1", archivePath, "0000000000000000000000000000000000000000000000000000000000000000")
+	if err == nil {
+		t.Fatal("installFromFile() should fail when the checksum doesn't match")
+	}
+	if len(provider.archiveInstalls) != 0 {
+		t.Errorf("nothing should be installed, got %v", provider.archiveInstalls)
+	}
+}
+
+func TestInstallFromFile_Errors(t *testing.T) {
+	archivePath, checksum := writeFixtureArchive(t)
+
+	if err := installFromFile(&mockProvider{name: "test", displayName: "Test"}, "1.0.0", archivePath, checksum); err == nil {
+		t.Error("installFromFile() should fail for a provider that can't install from archives")
+	}
+
+	provider := &mockArchiveProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}}
+	if err := installFromFile(provider, "20.11.1", filepath.Join(t.TempDir(), "missing.tar.gz"), checksum); err == nil {
+		t.Error("installFromFile() should fail for a missing archive")
+	}
+	if err := installFromFile(provider, "20.11.1", t.TempDir(), checksum); err == nil {
+		t.Error("installFromFile() should fail for a directory")
+	}
+}
+
+func TestManifestChecksum(t *testing.T) {
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"20.11.1": {
+				"linux-amd64": {URL: "https://example.com/node-v20.11.1-linux-x64.tar.gz", SHA256: "abc123"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		version     string
+		platform    string
+		archivePath string
+		want        string
+	}{
+		{"matching archive", "20.11.1", "linux-amd64", "/tmp/node-v20.11.1-linux-x64.tar.gz", "abc123"},
+		{"renamed archive", "20.11.1", "linux-amd64", "/tmp/node.tar.gz", ""},
+		{"other platform", "20.11.1", "linux-arm64", "/tmp/node-v20.11.1-linux-x64.tar.gz", ""},
+		{"unknown version", "99.0.0", "linux-amd64", "/tmp/node-v20.11.1-linux-x64.tar.gz", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manifestChecksum(m, tt.version, tt.platform, tt.archivePath); got != tt.want {
+				t.Errorf("manifestChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
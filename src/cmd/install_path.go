@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	goruntime "runtime"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/path"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// shadowedShim is a shim that an executable earlier in PATH runs instead of
+type shadowedShim struct {
+	Name string
+	// Path is the executable that runs instead of the shim
+	Path string
+}
+
+// shadowedShims returns the shims that another executable shadows in pathEnv
+func shadowedShims(pathEnv, shimsDir string, shimNames []string) []shadowedShim {
+	var shadowed []shadowedShim
+	for _, name := range shimNames {
+		if exe := path.ShadowingExecutable(pathEnv, shimsDir, name); exe != "" {
+			shadowed = append(shadowed, shadowedShim{Name: name, Path: exe})
+		}
+	}
+	return shadowed
+}
+
+// warnIfShimsIneffective warns after an install when the runtime's commands won't
+// reach the dtvem shims: the shims directory is missing from PATH, or a system
+// install comes before it
+func warnIfShimsIneffective(provider runtime.Provider) {
+	if installNoShimsFlag {
+		return
+	}
+
+	shimsDir := path.ShimsDir()
+	if !path.IsInPath(shimsDir) {
+		ui.Warning("%s is not in your PATH, so %s commands won't use the versions dtvem installs", shimsDir, provider.DisplayName())
+		ui.Info("Run 'dtvem init' to add it, then restart your shell")
+		return
+	}
+
+	shadowed := shadowedShims(os.Getenv("PATH"), shimsDir, provider.Shims())
+	if len(shadowed) == 0 {
+		return
+	}
+
+	for _, s := range shadowed {
+		ui.Warning("'%s' runs %s, which comes before the dtvem shims in PATH", s.Name, s.Path)
+	}
+	ui.Info("%s", pathOrderRemedy(shimsDir, path.DetectShell()))
+	ui.Info("Or run 'dtvem repair-path' to do this for you")
+}
+
+// pathOrderRemedy tells the user how to move the shims directory to the front of PATH
+func pathOrderRemedy(shimsDir, shell string) string {
+	switch {
+	case goruntime.GOOS == constants.OSWindows:
+		return fmt.Sprintf("Move %s above the other entries of your PATH (System Properties > Environment Variables), then open a new terminal", shimsDir)
+	case shell == constants.ShellFish:
+		return fmt.Sprintf("Put the shims first in PATH by running: fish_add_path --move %s", shimsDir)
+	default:
+		return fmt.Sprintf("Put the shims first in PATH by adding this as the last PATH change in your shell config, then restart your shell:\n  export PATH=\"%s:$PATH\"", shimsDir)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+func TestShadowedShims(t *testing.T) {
+	tempDir := t.TempDir()
+	shimsDir := filepath.Join(tempDir, "shims")
+	systemDir := filepath.Join(tempDir, "bin")
+	for _, dir := range []string{shimsDir, systemDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	ext := ""
+	if goruntime.GOOS == constants.OSWindows {
+		ext = ".exe"
+	}
+	for _, name := range []string{"node", "npm", "npx"} {
+		if err := os.WriteFile(filepath.Join(shimsDir, name+ext), []byte("shim"), 0755); err != nil {
+			t.Fatalf("Failed to create shim: %v", err)
+		}
+	}
+	systemNode := filepath.Join(systemDir, "node"+ext)
+	if err := os.WriteFile(systemNode, []byte("node"), 0755); err != nil {
+		t.Fatalf("Failed to create system node: %v", err)
+	}
+
+	pathList := func(dirs ...string) string { return strings.Join(dirs, string(os.PathListSeparator)) }
+	shimNames := []string{"node", "npm", "npx"}
+
+	t.Run("system node before shims", func(t *testing.T) {
+		shadowed := shadowedShims(pathList(systemDir, shimsDir), shimsDir, shimNames)
+		if len(shadowed) != 1 || shadowed[0].Name != "node" || shadowed[0].Path != systemNode {
+			t.Errorf("shadowedShims() = %+v, want only node shadowed by %s", shadowed, systemNode)
+		}
+	})
+
+	t.Run("shims first", func(t *testing.T) {
+		if shadowed := shadowedShims(pathList(shimsDir, systemDir), shimsDir, shimNames); len(shadowed) != 0 {
+			t.Errorf("shadowedShims() = %+v, want none", shadowed)
+		}
+	})
+}
+
+func TestPathOrderRemedy(t *testing.T) {
+	shimsDir := filepath.Join("home", "user", ".dtvem", "shims")
+
+	remedy := pathOrderRemedy(shimsDir, constants.ShellBash)
+	if !strings.Contains(remedy, shimsDir) {
+		t.Errorf("pathOrderRemedy() = %q, want it to name the shims directory", remedy)
+	}
+
+	if goruntime.GOOS == constants.OSWindows {
+		return
+	}
+	if !strings.Contains(remedy, `export PATH="`+shimsDir+`:$PATH"`) {
+		t.Errorf("pathOrderRemedy(bash) = %q, want an export line", remedy)
+	}
+	if fish := pathOrderRemedy(shimsDir, constants.ShellFish); !strings.Contains(fish, "fish_add_path --move "+shimsDir) {
+		t.Errorf("pathOrderRemedy(fish) = %q, want a fish_add_path command", fish)
+	}
+}
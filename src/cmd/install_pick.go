@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/tui"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// pickerPageSize is the most versions the picker lists at once
+const pickerPageSize = 20
+
+// versionChoice is a version offered by the interactive picker
+type versionChoice struct {
+	Version string
+	Notes   string
+}
+
+// pickerInputKind is what a line typed at the picker asks for
+type pickerInputKind int
+
+const (
+	pickerQuit pickerInputKind = iota
+	pickerPick
+	pickerFilter
+	pickerInvalid
+)
+
+// pickerInput is a parsed line typed at the picker
+type pickerInput struct {
+	kind pickerInputKind
+	// index is the 0-based choice for pickerPick
+	index int
+	// filter narrows the list for pickerFilter; empty shows the release series again
+	filter string
+}
+
+// parsePickerInput parses a line typed at the picker: a number picks that choice,
+// q quits, and anything else filters the versions (an empty line clears the filter)
+func parsePickerInput(input string, count int) pickerInput {
+	input = strings.TrimSpace(input)
+
+	switch strings.ToLower(input) {
+	case "q", "quit":
+		return pickerInput{kind: pickerQuit}
+	}
+
+	if num, err := strconv.Atoi(input); err == nil {
+		if num < 1 || num > count {
+			return pickerInput{kind: pickerInvalid}
+		}
+		return pickerInput{kind: pickerPick, index: num - 1}
+	}
+
+	return pickerInput{kind: pickerFilter, filter: input}
+}
+
+// releaseSeries returns the release line a version belongs to: the major version
+// for Node.js, major.minor for the other runtimes
+func releaseSeries(runtimeName, version string) string {
+	parts := strings.Split(version, ".")
+	if runtimeName == "node" || len(parts) < 2 {
+		return parts[0]
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// versionPickerChoices returns the versions the picker offers, newest first. Without
+// a filter that is the newest version of each release series; with one it is every
+// version containing the filter text. The newest version overall is noted as latest.
+func versionPickerChoices(runtimeName string, available []runtime.AvailableVersion, filter string) []versionChoice {
+	sorted := make([]runtime.AvailableVersion, len(available))
+	copy(sorted, available)
+	runtime.SortVersionsDesc(sorted)
+
+	seen := make(map[string]bool)
+	var choices []versionChoice
+	for i, v := range sorted {
+		version := v.Version.Raw
+		if filter != "" {
+			if !strings.Contains(version, filter) {
+				continue
+			}
+		} else {
+			series := releaseSeries(runtimeName, version)
+			if seen[series] {
+				continue
+			}
+			seen[series] = true
+		}
+
+		notes := v.Notes
+		if i == 0 {
+			notes = strings.TrimSpace("latest " + notes)
+		}
+		choices = append(choices, versionChoice{Version: version, Notes: notes})
+	}
+	return choices
+}
+
+// pickInstallVersion runs the interactive picker for install --interactive and
+// exits if the runtime is unknown, no versions can be listed or the user quits
+func pickInstallVersion(runtimeName string) string {
+	provider, err := runtime.Get(runtimeName)
+	if err != nil {
+		reportError(err)
+		ui.Info("Available runtimes: %s", availableRuntimes())
+		os.Exit(1)
+	}
+
+	version, ok, err := pickVersion(provider, os.Stdin)
+	if err != nil {
+		ui.Error("%v", err)
+		os.Exit(1)
+	}
+	if !ok {
+		ui.Info("No version selected")
+		os.Exit(0)
+	}
+	return version
+}
+
+// pickVersion lets the user choose a version of the provider's runtime, reading
+// their answers from in. It returns false if the user quit without choosing.
+func pickVersion(provider runtime.Provider, in io.Reader) (string, bool, error) {
+	available, err := provider.ListAvailable()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to fetch available versions: %w", err)
+	}
+	if len(available) == 0 {
+		return "", false, fmt.Errorf("no %s versions are available for this platform", provider.DisplayName())
+	}
+
+	reader := bufio.NewReader(in)
+	filter := ""
+	for {
+		choices := versionPickerChoices(provider.Name(), available, filter)
+		if len(choices) == 0 {
+			ui.Warning("No versions match %q", filter)
+		} else {
+			showVersionChoices(provider, choices, filter)
+		}
+
+		ui.Printf("Enter a number to install, text to filter, or q to quit: ")
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && line == "" {
+			return "", false, nil
+		}
+
+		input := parsePickerInput(line, min(len(choices), pickerPageSize))
+		switch input.kind {
+		case pickerQuit:
+			return "", false, nil
+		case pickerPick:
+			return choices[input.index].Version, true, nil
+		case pickerFilter:
+			filter = input.filter
+		default:
+			ui.Warning("Enter a number from the list")
+		}
+	}
+}
+
+// showVersionChoices prints the numbered choices, up to pickerPageSize of them
+func showVersionChoices(provider runtime.Provider, choices []versionChoice, filter string) {
+	table := tui.NewTable("#", "Version", "Notes")
+	if filter == "" {
+		table.SetTitle(provider.DisplayName() + " release series")
+	} else {
+		table.SetTitle(fmt.Sprintf("%s versions matching %q", provider.DisplayName(), filter))
+	}
+
+	for i, choice := range choices {
+		if i == pickerPageSize {
+			break
+		}
+		table.AddRow(strconv.Itoa(i+1), choice.Version, choice.Notes)
+	}
+
+	fmt.Println()
+	fmt.Println(table.Render())
+	if len(choices) > pickerPageSize {
+		ui.Printf("%s\n", ui.DimText(fmt.Sprintf("%d more; type part of a version to narrow the list", len(choices)-pickerPageSize)))
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+func TestParsePickerInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		count     int
+		wantKind  pickerInputKind
+		wantIndex int
+		wantText  string
+	}{
+		{"first choice", "1\n", 5, pickerPick, 0, ""},
+		{"last choice", " 5 ", 5, pickerPick, 4, ""},
+		{"zero", "0", 5, pickerInvalid, 0, ""},
+		{"past the end", "6", 5, pickerInvalid, 0, ""},
+		{"negative", "-1", 5, pickerInvalid, 0, ""},
+		{"quit", "q", 5, pickerQuit, 0, ""},
+		{"quit word", "QUIT\n", 5, pickerQuit, 0, ""},
+		{"filter", "3.11\n", 5, pickerFilter, 0, "3.11"},
+		{"filter with spaces trimmed", "  20.1 ", 5, pickerFilter, 0, "20.1"},
+		{"empty line clears the filter", "\n", 5, pickerFilter, 0, ""},
+		{"number with no choices", "1", 0, pickerInvalid, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePickerInput(tt.input, tt.count)
+			if got.kind != tt.wantKind || got.index != tt.wantIndex || got.filter != tt.wantText {
+				t.Errorf("parsePickerInput(%q, %d) = %+v, want kind %d index %d filter %q",
+					tt.input, tt.count, got, tt.wantKind, tt.wantIndex, tt.wantText)
+			}
+		})
+	}
+}
+
+// availableVersionList builds AvailableVersions from version strings
+func availableVersionList(versions ...string) []runtime.AvailableVersion {
+	available := make([]runtime.AvailableVersion, 0, len(versions))
+	for _, v := range versions {
+		available = append(available, runtime.AvailableVersion{Version: runtime.NewVersion(v)})
+	}
+	return available
+}
+
+func TestVersionPickerChoices(t *testing.T) {
+	tests := []struct {
+		name    string
+		runtime string
+		filter  string
+		want    []string
+	}{
+		{"node groups by major", "node", "", []string{"22.3.0", "20.15.0", "18.20.3"}},
+		{"python groups by minor", "python", "", []string{"22.3.0", "20.15.0", "20.14.0", "20.1.0", "18.20.3"}},
+		{"filter lists every match", "node", "20.1", []string{"20.15.0", "20.14.0", "20.1.0"}},
+		{"filter matches anywhere in the version", "node", ".20.", []string{"18.20.3"}},
+		{"filter without matches", "node", "99", nil},
+	}
+
+	available := availableVersionList("18.20.3", "20.1.0", "22.3.0", "20.14.0", "20.15.0")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := versionPickerChoices(tt.runtime, available, tt.filter)
+			var got []string
+			for _, c := range choices {
+				got = append(got, c.Version)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("versionPickerChoices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionPickerChoices_Notes(t *testing.T) {
+	available := availableVersionList("3.12.4", "3.13.1")
+	available[0].Notes = "released 2024-06-06"
+	available[1].Notes = "released 2024-12-03"
+
+	choices := versionPickerChoices("python", available, "")
+	if len(choices) != 2 {
+		t.Fatalf("versionPickerChoices() = %+v, want 2 choices", choices)
+	}
+	if choices[0].Notes != "latest released 2024-12-03" {
+		t.Errorf("newest notes = %q, want it marked latest", choices[0].Notes)
+	}
+	if choices[1].Notes != "released 2024-06-06" {
+		t.Errorf("older notes = %q", choices[1].Notes)
+	}
+}
+
+// mockPickerProvider is a mockProvider with a list of available versions
+type mockPickerProvider struct {
+	mockProvider
+	available []runtime.AvailableVersion
+	err       error
+}
+
+func (m *mockPickerProvider) ListAvailable() ([]runtime.AvailableVersion, error) {
+	return m.available, m.err
+}
+
+func TestPickVersion(t *testing.T) {
+	provider := &mockPickerProvider{
+		mockProvider: mockProvider{name: "node", displayName: "Node.js"},
+		available:    availableVersionList("18.20.3", "20.1.0", "20.15.0", "22.3.0"),
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantOK  bool
+		wantErr bool
+	}{
+		{"pick a series", "2\n", "20.15.0", true, false},
+		{"filter then pick", "20.1\n2\n", "20.1.0", true, false},
+		{"invalid number then pick", "9\n1\n", "22.3.0", true, false},
+		{"quit", "q\n", "", false, false},
+		{"end of input", "", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, ok, err := pickVersion(provider, strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pickVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if version != tt.want || ok != tt.wantOK {
+				t.Errorf("pickVersion() = (%q, %v), want (%q, %v)", version, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("list failure", func(t *testing.T) {
+		failing := &mockPickerProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}, err: errors.New("offline")}
+		if _, _, err := pickVersion(failing, strings.NewReader("1\n")); err == nil {
+			t.Error("pickVersion() should fail when versions can't be listed")
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// describeResolvedDownload returns the lines that show where an archive comes from
+func describeResolvedDownload(resolved *runtime.ResolvedDownload) []string {
+	return []string{
+		"Platform: " + resolved.Platform,
+		"URL:      " + resolved.URL,
+		"Checksum: " + checksumDescription(resolved),
+	}
+}
+
+// checksumDescription describes the checksum a download is verified against
+func checksumDescription(resolved *runtime.ResolvedDownload) string {
+	switch {
+	case resolved.SHA256 == "":
+		return "none in the manifest"
+	case resolved.SHA256Source == "upstream":
+		return "SHA256 published upstream"
+	case resolved.SHA256Source == "dtvem":
+		return "SHA256 recorded by dtvem when mirroring"
+	default:
+		return "SHA256 (source not recorded)"
+	}
+}
+
+// showResolvedDownload prints where the archive for a version will be downloaded from.
+// In --dry-run the details are always printed and an unresolvable download is an
+// error; otherwise they are debug output for verbose installs.
+func showResolvedDownload(provider runtime.Provider, version string, dryRun bool) error {
+	if !dryRun && !ui.IsVerbose() {
+		return nil
+	}
+
+	resolver, ok := provider.(runtime.DownloadResolver)
+	if !ok {
+		if dryRun {
+			return fmt.Errorf("%s cannot report its downloads", provider.DisplayName())
+		}
+		return nil
+	}
+
+	resolved, err := resolver.ResolveDownload(version)
+	if err != nil {
+		if dryRun {
+			return err
+		}
+		ui.Debug("Could not resolve download: %v", err)
+		return nil
+	}
+
+	if dryRun {
+		ui.Info("%s %s would be downloaded from:", provider.DisplayName(), version)
+		for _, line := range describeResolvedDownload(resolved) {
+			ui.Info("  %s", line)
+		}
+		return nil
+	}
+
+	for _, line := range describeResolvedDownload(resolved) {
+		ui.Debug("%s", line)
+	}
+	return nil
+}
+
+// resolvePartialVersion turns a partial version such as "3.12" (or the pin "3.12.x")
+// into the newest patch release of it available for this platform
+func resolvePartialVersion(provider runtime.Provider, version string) (string, error) {
+	return runtime.ResolvePartialVersion(provider, version, manifest.CurrentPlatform())
+}
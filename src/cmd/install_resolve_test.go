@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// mockResolverProvider is a mockProvider that reports where its downloads come from
+type mockResolverProvider struct {
+	mockProvider
+	resolved *runtime.ResolvedDownload
+	err      error
+	calls    int
+}
+
+func (m *mockResolverProvider) ResolveDownload(version string) (*runtime.ResolvedDownload, error) {
+	m.calls++
+	return m.resolved, m.err
+}
+
+func TestDescribeResolvedDownload(t *testing.T) {
+	tests := []struct {
+		name         string
+		sha256       string
+		sha256Source string
+		wantChecksum string
+	}{
+		{"upstream checksum", "abc123", "upstream", "Checksum: SHA256 published upstream"},
+		{"mirrored checksum", "abc123", "dtvem", "Checksum: SHA256 recorded by dtvem when mirroring"},
+		{"legacy manifest", "abc123", "", "Checksum: SHA256 (source not recorded)"},
+		{"no checksum", "", "", "Checksum: none in the manifest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := describeResolvedDownload(&runtime.ResolvedDownload{
+				Platform:     "linux-amd64",
+				URL:          "https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz",
+				SHA256:       tt.sha256,
+				SHA256Source: tt.sha256Source,
+			})
+
+			want := []string{
+				"Platform: linux-amd64",
+				"URL:      https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz",
+				tt.wantChecksum,
+			}
+			if len(lines) != len(want) {
+				t.Fatalf("describeResolvedDownload() = %v, want %v", lines, want)
+			}
+			for i := range want {
+				if lines[i] != want[i] {
+					t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestShowResolvedDownload(t *testing.T) {
+	resolved := &runtime.ResolvedDownload{Platform: "linux-amd64", URL: "https://example.com/node.tar.gz"}
+
+	t.Run("dry run resolves the download", func(t *testing.T) {
+		provider := &mockResolverProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}, resolved: resolved}
+		if err := showResolvedDownload(provider, "22.0.0", true); err != nil {
+			t.Errorf("showResolvedDownload() error: %v", err)
+		}
+		if provider.calls != 1 {
+			t.Errorf("ResolveDownload calls = %d, want 1", provider.calls)
+		}
+	})
+
+	t.Run("dry run reports an unresolvable download", func(t *testing.T) {
+		provider := &mockResolverProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}, err: errors.New("not available")}
+		if err := showResolvedDownload(provider, "99.0.0", true); err == nil {
+			t.Error("showResolvedDownload() should fail when the download can't be resolved")
+		}
+	})
+
+	t.Run("dry run needs a resolver", func(t *testing.T) {
+		provider := &mockProvider{name: "test", displayName: "Test"}
+		if err := showResolvedDownload(provider, "1.0.0", true); err == nil {
+			t.Error("showResolvedDownload() should fail for a provider that can't report downloads")
+		}
+	})
+
+	t.Run("quiet install skips resolution", func(t *testing.T) {
+		provider := &mockResolverProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}, err: errors.New("not available")}
+		if err := showResolvedDownload(provider, "22.0.0", false); err != nil {
+			t.Errorf("showResolvedDownload() error: %v", err)
+		}
+		if provider.calls != 0 {
+			t.Errorf("ResolveDownload calls = %d, want none outside verbose mode", provider.calls)
+		}
+	})
+}
+
+func TestResolvePartialVersion(t *testing.T) {
+	provider := &mockPickerProvider{
+		mockProvider: mockProvider{name: "python", displayName: "Python"},
+		available:    availableVersionList("3.13.2", "3.13.0", "3.12.10", "3.12.8", "3.12.1"),
+	}
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "3.12", want: "3.12.10"},
+		{version: "3.13.x", want: "3.13.2"},
+		{version: "3", want: "3.13.2"},
+		{version: "3.12.1", want: "3.12.1"},
+		{version: "lts", want: "lts"},
+		{version: "3.11", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := resolvePartialVersion(provider, tt.version)
+			if tt.wantErr {
+				var notAvailable *runtime.VersionNotAvailableError
+				if !errors.As(err, &notAvailable) {
+					t.Errorf("resolvePartialVersion() error = %v, want a VersionNotAvailableError", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolvePartialVersion(%q) = %q, %v, want %q", tt.version, got, err, tt.want)
+			}
+		})
+	}
+}
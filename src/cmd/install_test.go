@@ -1,11 +1,26 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
 	"testing"
 
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
 	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
 )
 
+// useTestRegistry gives the test a fresh runtime registry, so the mock providers
+// it registers can't collide with those of other tests
+func useTestRegistry(t *testing.T) {
+	t.Helper()
+	t.Cleanup(runtime.UseRegistry(runtime.NewRegistry()))
+}
+
 // mockProvider implements runtime.Provider for testing
 type mockProvider struct {
 	name           string
@@ -13,16 +28,27 @@ type mockProvider struct {
 	globalVersion  string
 	globalSetError error
 	setGlobalCalls []string
+	installCalls   []string
+	packageCalls   []string
+	globalPackages []string
+	installed      bool
+	execPath       string
+	reshimAfter    bool
+	installError   error
+	env            map[string]string
 }
 
 func (m *mockProvider) Name() string                                          { return m.name }
 func (m *mockProvider) DisplayName() string                                   { return m.displayName }
 func (m *mockProvider) Shims() []string                                       { return []string{m.name} }
-func (m *mockProvider) ExecutablePath(version string) (string, error)         { return "", nil }
-func (m *mockProvider) IsInstalled(version string) (bool, error)              { return false, nil }
-func (m *mockProvider) ShouldReshimAfter(shimName string, args []string) bool { return false }
-func (m *mockProvider) Install(version string) error                          { return nil }
-func (m *mockProvider) Uninstall(version string) error                        { return nil }
+func (m *mockProvider) ExecutablePath(version string) (string, error)         { return m.execPath, nil }
+func (m *mockProvider) IsInstalled(version string) (bool, error)              { return m.installed, nil }
+func (m *mockProvider) ShouldReshimAfter(shimName string, args []string) bool { return m.reshimAfter }
+func (m *mockProvider) Install(version string) error {
+	m.installCalls = append(m.installCalls, version)
+	return m.installError
+}
+func (m *mockProvider) Uninstall(version string) error { return nil }
 func (m *mockProvider) ListInstalled() ([]runtime.InstalledVersion, error) {
 	return nil, nil
 }
@@ -37,9 +63,10 @@ func (m *mockProvider) DetectInstalled() ([]runtime.DetectedVersion, error) {
 	return nil, nil
 }
 func (m *mockProvider) GlobalPackages(installPath string) ([]string, error) {
-	return nil, nil
+	return m.globalPackages, nil
 }
 func (m *mockProvider) InstallGlobalPackages(version string, packages []string) error {
+	m.packageCalls = append(m.packageCalls, version)
 	return nil
 }
 func (m *mockProvider) ManualPackageInstallCommand(packages []string) string {
@@ -47,6 +74,9 @@ func (m *mockProvider) ManualPackageInstallCommand(packages []string) string {
 }
 
 func (m *mockProvider) GetEnvironment(_ string) (map[string]string, error) {
+	if m.env != nil {
+		return m.env, nil
+	}
 	return map[string]string{}, nil
 }
 
@@ -114,3 +144,307 @@ func TestAutoSetGlobalIfNeeded_MultipleInstalls(t *testing.T) {
 		t.Errorf("Expected second install to not change global, got %d calls total", len(provider.setGlobalCalls))
 	}
 }
+
+func TestBuildInstallTasks_ResumesAfterFailure(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+	useTestRegistry(t)
+
+	providers := []*mockProvider{
+		{name: "resumea", displayName: "ResumeA"},
+		{name: "resumeb", displayName: "ResumeB"},
+	}
+	for _, p := range providers {
+		if err := runtime.Register(p); err != nil {
+			t.Fatalf("Failed to register provider: %v", err)
+		}
+	}
+
+	configPath := filepath.Join(t.TempDir(), "runtimes.json")
+	runtimes := map[string]string{"resumeb": "2.0.0", "resumea": "1.0.0"}
+
+	// A first run where resumeb failed, e.g. during extraction
+	progress := loadInstallProgress(configPath)
+	tasks := buildInstallTasks(runtimes, progress)
+	if len(tasks) != 2 || tasks[0].runtimeName != "resumea" || tasks[1].runtimeName != "resumeb" {
+		t.Fatalf("buildInstallTasks() = %+v, want tasks sorted by runtime", tasks)
+	}
+	progress.record(tasks[0], taskStateInstalled)
+	progress.record(tasks[1], taskStateFailed)
+
+	// The re-run picks up the recorded failure
+	tasks = buildInstallTasks(runtimes, loadInstallProgress(configPath))
+	if tasks[0].previouslyFailed {
+		t.Error("resumea should not be marked as previously failed")
+	}
+	if !tasks[1].previouslyFailed {
+		t.Error("resumeb should be marked as previously failed")
+	}
+
+	// Progress recorded for another config file is ignored
+	other := loadInstallProgress(filepath.Join(t.TempDir(), "runtimes.json"))
+	if len(other.Tasks) != 0 {
+		t.Errorf("loadInstallProgress() for another config = %v, want empty", other.Tasks)
+	}
+
+	progress.clear()
+	if got := loadInstallProgress(configPath); len(got.Tasks) != 0 {
+		t.Errorf("loadInstallProgress() after clear = %v, want empty", got.Tasks)
+	}
+}
+
+func TestExecuteInstalls_FailureYieldsError(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	// Keep the EOL check from loading manifests for the mock runtimes
+	installAllowEOLFlag = true
+	t.Cleanup(func() { installAllowEOLFlag = false })
+
+	good := &mockProvider{name: "gooda", displayName: "GoodA", globalVersion: "1.0.0"}
+	bad := &mockProvider{name: "badb", displayName: "BadB", installError: errors.New("extraction failed")}
+	tasks := []installTask{
+		{runtimeName: "gooda", version: "1.0.0", provider: good},
+		{runtimeName: "badb", version: "2.0.0", provider: bad},
+	}
+
+	progress := loadInstallProgress(filepath.Join(t.TempDir(), "runtimes.json"))
+	success, failures, failureList := executeInstalls(tasks, progress, false)
+	if success != 1 || failures != 1 {
+		t.Fatalf("executeInstalls() = %d succeeded, %d failed, want 1 and 1", success, failures)
+	}
+
+	err := bulkInstallError(failureList)
+	if err == nil {
+		t.Fatal("bulkInstallError() should return an error when a task failed")
+	}
+	if !strings.Contains(err.Error(), "BadB 2.0.0") || strings.Contains(err.Error(), "GoodA") {
+		t.Errorf("bulkInstallError() = %q, want only the failed runtime listed", err)
+	}
+}
+
+func TestExecuteInstalls_PinInstallsNewestPatch(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	// Keep the EOL check from loading manifests for the mock runtime
+	installAllowEOLFlag = true
+	t.Cleanup(func() { installAllowEOLFlag = false })
+
+	provider := &mockStatusProvider{
+		mockProvider:      mockProvider{name: "pinrt", displayName: "Pin Runtime", globalVersion: "20.9.0"},
+		availableVersions: []string{"20.9.0", "20.11.1", "21.0.0"},
+	}
+	tasks := []installTask{{runtimeName: "pinrt", version: "20.x", provider: provider}}
+
+	progress := loadInstallProgress(filepath.Join(t.TempDir(), "runtimes.json"))
+	success, failures, _ := executeInstalls(tasks, progress, false)
+	if success != 1 || failures != 0 {
+		t.Fatalf("executeInstalls() = %d succeeded, %d failed, want 1 and 0", success, failures)
+	}
+	if len(provider.installCalls) != 1 || provider.installCalls[0] != "20.11.1" {
+		t.Errorf("Install calls = %v, want the newest patch 20.11.1", provider.installCalls)
+	}
+}
+
+func TestBulkInstallError_NoFailures(t *testing.T) {
+	if err := bulkInstallError(nil); err != nil {
+		t.Errorf("bulkInstallError(nil) = %v, want nil", err)
+	}
+}
+
+func TestExecuteInstalls_FailFastAndContinueOnError(t *testing.T) {
+	tests := []struct {
+		name        string
+		failFast    bool
+		wantSuccess int
+		wantLast    bool // whether the task after the failing one is installed
+	}{
+		{"continue on error", false, 2, true},
+		{"fail fast", true, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DTVEM_ROOT", t.TempDir())
+			config.ResetPathsCache()
+			t.Cleanup(config.ResetPathsCache)
+
+			installAllowEOLFlag = true
+			t.Cleanup(func() { installAllowEOLFlag = false })
+
+			first := &mockProvider{name: "firsta", displayName: "FirstA", globalVersion: "1.0.0"}
+			failing := &mockProvider{name: "failingb", displayName: "FailingB", installError: errors.New("download failed")}
+			last := &mockProvider{name: "lastc", displayName: "LastC", globalVersion: "3.0.0"}
+			tasks := []installTask{
+				{runtimeName: "firsta", version: "1.0.0", provider: first},
+				{runtimeName: "failingb", version: "2.0.0", provider: failing},
+				{runtimeName: "lastc", version: "3.0.0", provider: last},
+			}
+
+			progress := loadInstallProgress(filepath.Join(t.TempDir(), "runtimes.json"))
+			success, failures, failureList := executeInstalls(tasks, progress, tt.failFast)
+
+			if success != tt.wantSuccess || failures != 1 {
+				t.Errorf("executeInstalls() = %d succeeded, %d failed, want %d and 1", success, failures, tt.wantSuccess)
+			}
+			if bulkInstallError(failureList) == nil {
+				t.Error("bulkInstallError() should report the failure in both modes")
+			}
+			if installedLast := len(last.installCalls) == 1; installedLast != tt.wantLast {
+				t.Errorf("task after the failure installed = %v, want %v", installedLast, tt.wantLast)
+			}
+		})
+	}
+}
+
+// mockShimlessProvider is a mockProvider that can install without shims
+type mockShimlessProvider struct {
+	mockProvider
+	shimlessCalls []string
+}
+
+func (m *mockShimlessProvider) InstallWithoutShims(version string) error {
+	m.shimlessCalls = append(m.shimlessCalls, version)
+	return nil
+}
+
+// setNoShimsFlag sets --no-shims for the duration of a test
+func setNoShimsFlag(t *testing.T, value bool) {
+	t.Helper()
+	original := installNoShimsFlag
+	installNoShimsFlag = value
+	t.Cleanup(func() { installNoShimsFlag = original })
+}
+
+func TestInstallVersion_NoShims(t *testing.T) {
+	tests := []struct {
+		name         string
+		noShims      bool
+		wantInstall  int
+		wantShimless int
+	}{
+		{"default install creates shims", false, 1, 0},
+		{"--no-shims skips shims", true, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setNoShimsFlag(t, tt.noShims)
+			provider := &mockShimlessProvider{mockProvider: mockProvider{name: "test", displayName: "Test"}}
+
+			if err := installVersion(provider, "1.0.0"); err != nil {
+				t.Fatalf("installVersion() error: %v", err)
+			}
+			if len(provider.installCalls) != tt.wantInstall {
+				t.Errorf("Install calls = %v, want %d", provider.installCalls, tt.wantInstall)
+			}
+			if len(provider.shimlessCalls) != tt.wantShimless {
+				t.Errorf("InstallWithoutShims calls = %v, want %d", provider.shimlessCalls, tt.wantShimless)
+			}
+		})
+	}
+}
+
+func TestInstallVersion_NoShimsUnsupported(t *testing.T) {
+	setNoShimsFlag(t, true)
+	provider := &mockProvider{name: "test", displayName: "Test"}
+
+	if err := installVersion(provider, "1.0.0"); err == nil {
+		t.Error("installVersion() should fail for a provider that can't skip shims")
+	}
+	if len(provider.installCalls) != 0 {
+		t.Errorf("Install calls = %v, want none", provider.installCalls)
+	}
+}
+
+func TestAutoSetGlobalIfNeeded_NoShims(t *testing.T) {
+	setNoShimsFlag(t, true)
+	provider := &mockProvider{name: "test", displayName: "Test"}
+
+	autoSetGlobalIfNeeded(provider, "1.0.0")
+
+	if len(provider.setGlobalCalls) != 0 {
+		t.Errorf("SetGlobalVersion calls = %v, want none for --no-shims", provider.setGlobalCalls)
+	}
+}
+
+func TestIsVersionInstalled_BuildAndPrerelease(t *testing.T) {
+	provider := &mockStatusProvider{
+		mockProvider:      mockProvider{name: "ruby", displayName: "Ruby"},
+		installedVersions: []string{"4.0.0-preview2", "3.13.1+20251209"},
+	}
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"4.0.0-preview2", true},
+		{"3.13.1+20251209", true},
+		{"4.0.0", false},
+		{"3.13.1", false},
+		{"3.13.x", true},
+		{"3.12.x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := isVersionInstalled(provider, tt.version); got != tt.want {
+				t.Errorf("isVersionInstalled(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecreateVersionShims(t *testing.T) {
+	tempDir, provider := setupDoctorEnv(t)
+
+	// A global package executable in the installed version
+	toolName := "doctool"
+	if goruntime.GOOS == constants.OSWindows {
+		toolName += constants.ExtExe
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "versions", "docrt", "1.0.0", "bin", toolName), []byte("tool"), 0755); err != nil {
+		t.Fatalf("Failed to create package executable: %v", err)
+	}
+
+	shimNames, err := recreateVersionShims(provider, "1.0.0")
+	if err != nil {
+		t.Fatalf("recreateVersionShims() error: %v", err)
+	}
+	if strings.Join(shimNames, ",") != "docrt,doctool" {
+		t.Errorf("recreateVersionShims() = %v, want [docrt doctool]", shimNames)
+	}
+
+	for _, name := range []string{"docrt", "doctool"} {
+		if _, err := os.Stat(config.ShimPath(name)); err != nil {
+			t.Errorf("shim %s not recreated: %v", name, err)
+		}
+		if runtimeName, ok := shim.LookupRuntime(name); !ok || runtimeName != "docrt" {
+			t.Errorf("LookupRuntime(%s) = (%q, %v), want (docrt, true)", name, runtimeName, ok)
+		}
+	}
+
+	if len(provider.installCalls) != 0 {
+		t.Errorf("recreateVersionShims() reinstalled the version: %v", provider.installCalls)
+	}
+}
+
+func TestRecreateVersionShims_NotInstalled(t *testing.T) {
+	_, provider := setupDoctorEnv(t)
+	provider.installed = false
+
+	if _, err := recreateVersionShims(provider, "2.0.0"); err == nil {
+		t.Fatal("recreateVersionShims() for a version that is not installed should fail")
+	}
+
+	if _, err := os.Stat(config.ShimPath("docrt")); !os.IsNotExist(err) {
+		t.Errorf("shim created for a version that is not installed: %v", err)
+	}
+	if len(provider.installCalls) != 0 {
+		t.Errorf("recreateVersionShims() installed the version: %v", provider.installCalls)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// versionCheckTimeout bounds how long an installed executable may take to print its version
+const versionCheckTimeout = 30 * time.Second
+
+// verifyInstall checks that a new install's executable reports the version that was
+// installed. On a mismatch the install is removed and an error returned, unless
+// --no-verify turns the mismatch into a warning.
+func verifyInstall(provider runtime.Provider, version string) error {
+	err := checkReportedVersion(provider, version)
+	if err == nil {
+		return nil
+	}
+
+	if installNoVerifyFlag {
+		ui.Warning("%v", err)
+		return nil
+	}
+
+	if removeErr := os.RemoveAll(config.RuntimeVersionPath(provider.Name(), version)); removeErr != nil {
+		return fmt.Errorf("%w (removing the install failed: %v)", err, removeErr)
+	}
+	return fmt.Errorf("%w; the install was removed (use --no-verify to keep it)", err)
+}
+
+// checkReportedVersion runs an installed version's executable and compares the version
+// it reports with the installed one. Providers that can't read their executable's
+// version are not checked.
+func checkReportedVersion(provider runtime.Provider, version string) error {
+	reporter, ok := provider.(runtime.VersionReporter)
+	if !ok {
+		return nil
+	}
+
+	execPath, err := provider.ExecutablePath(version)
+	if err != nil {
+		return fmt.Errorf("cannot verify the installed %s %s: %w", provider.DisplayName(), version, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionCheckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, execPath, reporter.VersionArgs()...)
+	cmd.Env = os.Environ()
+	if env, err := provider.GetEnvironment(version); err == nil {
+		for key, value := range env {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("the installed %s %s does not run: %w", provider.DisplayName(), version, err)
+	}
+
+	reported, err := reporter.ParseVersionOutput(string(output))
+	if err != nil {
+		return fmt.Errorf("cannot verify the installed %s %s: %w", provider.DisplayName(), version, err)
+	}
+	if runtime.CompareVersions(reported, version) != 0 {
+		return fmt.Errorf("the installed %s reports version %s instead of %s, so the download may be mislabeled",
+			provider.DisplayName(), reported, version)
+	}
+
+	ui.Debug("Installed %s reports version %s", provider.DisplayName(), reported)
+	return nil
+}
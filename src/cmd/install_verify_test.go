@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+// versionReportingProvider is a mock provider whose executable prints "v<version>"
+type versionReportingProvider struct {
+	mockProvider
+}
+
+func (p *versionReportingProvider) VersionArgs() []string { return []string{"--version"} }
+func (p *versionReportingProvider) ParseVersionOutput(output string) (string, error) {
+	return strings.TrimPrefix(strings.TrimSpace(output), "v"), nil
+}
+
+// installFakeVersion creates an installed version whose executable reports reported
+func installFakeVersion(t *testing.T, version, reported string) *versionReportingProvider {
+	t.Helper()
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("fake executables are shell scripts")
+	}
+
+	binDir := filepath.Join(config.RuntimeVersionPath("verifyrt", version), "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	execPath := filepath.Join(binDir, "verifyrt")
+	script := "#!/bin/sh\necho v" + reported + "\n"
+	if err := os.WriteFile(execPath, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create fake executable: %v", err)
+	}
+
+	return &versionReportingProvider{mockProvider{name: "verifyrt", displayName: "Verify Runtime", execPath: execPath}}
+}
+
+func TestVerifyInstall(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		reported   string
+		noVerify   bool
+		wantErr    bool
+		wantRemove bool
+	}{
+		{name: "matching version", version: "20.11.1", reported: "20.11.1"},
+		{name: "build tag is ignored", version: "3.13.1+20251209", reported: "3.13.1"},
+		{name: "mislabeled download", version: "20.11.1", reported: "20.11.0", wantErr: true, wantRemove: true},
+		{name: "mislabeled download with --no-verify", version: "20.11.1", reported: "20.11.0", noVerify: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupDebugEnv(t)
+			original := installNoVerifyFlag
+			installNoVerifyFlag = tt.noVerify
+			t.Cleanup(func() { installNoVerifyFlag = original })
+
+			provider := installFakeVersion(t, tt.version, tt.reported)
+
+			err := verifyInstall(provider, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyInstall() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.reported) {
+				t.Errorf("verifyInstall() error = %q, want it to name the reported version %s", err, tt.reported)
+			}
+
+			_, statErr := os.Stat(config.RuntimeVersionPath("verifyrt", tt.version))
+			if removed := os.IsNotExist(statErr); removed != tt.wantRemove {
+				t.Errorf("install removed = %v, want %v", removed, tt.wantRemove)
+			}
+		})
+	}
+}
+
+func TestVerifyInstall_ExecutableDoesNotRun(t *testing.T) {
+	setupDebugEnv(t)
+	provider := installFakeVersion(t, "20.11.1", "20.11.1")
+	if err := os.WriteFile(provider.execPath, []byte("#!/bin/sh\nexit 127\n"), 0755); err != nil {
+		t.Fatalf("Failed to replace executable: %v", err)
+	}
+
+	if err := verifyInstall(provider, "20.11.1"); err == nil {
+		t.Error("verifyInstall() should fail when the installed executable does not run")
+	}
+}
+
+func TestVerifyInstall_ProviderWithoutVersionReporter(t *testing.T) {
+	setupDebugEnv(t)
+	provider := &mockProvider{name: "verifyrt", displayName: "Verify Runtime", execPath: "/nonexistent/verifyrt"}
+
+	if err := verifyInstall(provider, "20.11.1"); err != nil {
+		t.Errorf("verifyInstall() error = %v, want providers without VersionReporter to be trusted", err)
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+var (
+	interpreterVersionFlag string
+	interpreterJSONFlag    bool
+)
+
+// interpreterResult is the executable an editor should use as a runtime's interpreter
+type interpreterResult struct {
+	Runtime string `json:"runtime"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	// Env holds environment variables the executable needs to run correctly
+	Env map[string]string `json:"env,omitempty"`
+}
+
+var interpreterCmd = &cobra.Command{
+	Use:   "interpreter <runtime> [--version <version>]",
+	Short: "Print the absolute path to a runtime's executable for an editor",
+	Long: `Print the absolute path to the executable of a runtime version, for an editor's
+or IDE's interpreter setting (VS Code, JetBrains) where a shim doesn't work.
+
+The version is the configured one, unless it's given with --version. Unlike
+'dtvem which' and the shims, this never installs, prompts or reshims, so it's
+safe to call from editor integrations. Only the path is printed on stdout;
+environment variables the executable needs are noted on stderr.
+
+Exits with a non-zero status if no version is configured or it isn't installed.
+
+Examples:
+  dtvem interpreter python
+  dtvem interpreter node --version 22.0.0
+  dtvem interpreter python --json   # {"runtime","version","path","env"}`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider, err := runtime.Get(args[0])
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		result, err := resolveInterpreter(provider, interpreterVersionFlag)
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		if interpreterJSONFlag {
+			if err := writeInterpreterJSON(os.Stdout, result); err != nil {
+				reportError(fmt.Errorf("failed to encode result: %w", err))
+				os.Exit(1)
+			}
+			return
+		}
+
+		fmt.Println(result.Path)
+		writeInterpreterEnvNote(os.Stderr, result)
+	},
+}
+
+// resolveInterpreter finds the absolute executable path of a runtime version, the
+// configured one when version is empty. It only reads config and never installs.
+func resolveInterpreter(provider runtime.Provider, version string) (interpreterResult, error) {
+	result := interpreterResult{Runtime: provider.Name()}
+
+	if version != "" {
+		version = runtime.NormalizeVersion(config.ResolveAlias(provider.Name(), runtime.NormalizeVersion(version)))
+	}
+	version, execPath, err := resolveInstalledExecutable(provider, version, "give one with --version")
+	if err != nil {
+		return result, err
+	}
+	result.Version = version
+
+	if result.Path, err = filepath.Abs(execPath); err != nil {
+		return result, fmt.Errorf("could not make %s absolute: %w", execPath, err)
+	}
+
+	if runtime.IsSystemVersion(version) {
+		return result, nil
+	}
+	if env, err := provider.GetEnvironment(version); err == nil && len(env) > 0 {
+		result.Env = env
+	}
+
+	return result, nil
+}
+
+// writeInterpreterEnvNote tells the user which environment variables the interpreter
+// needs, since an editor running it directly won't get them from a shim
+func writeInterpreterEnvNote(w io.Writer, result interpreterResult) {
+	if len(result.Env) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(result.Env))
+	for name := range result.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintf(w, "Note: %s %s needs these environment variables:\n", result.Runtime, result.Version)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "  %s=%s\n", name, result.Env[name])
+	}
+}
+
+// writeInterpreterJSON writes the resolved interpreter to w as JSON
+func writeInterpreterJSON(w io.Writer, result interpreterResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func init() {
+	interpreterCmd.Flags().StringVar(&interpreterVersionFlag, "version", "", "Version to use instead of the configured one")
+	interpreterCmd.Flags().BoolVar(&interpreterJSONFlag, "json", false, "Print the runtime, version, path and environment as JSON")
+	rootCmd.AddCommand(interpreterCmd)
+}
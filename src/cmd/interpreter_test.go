@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// listFiles returns every path under root, to check that a command wrote nothing
+func listFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.Walk(root, func(path string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to list %s: %v", root, err)
+	}
+	return files
+}
+
+func TestResolveInterpreter(t *testing.T) {
+	tests := []struct {
+		name        string
+		global      string
+		version     string
+		installed   bool
+		wantVersion string
+		wantErr     error
+	}{
+		{"configured version", "1.0.0", "", true, "1.0.0", nil},
+		{"version flag wins", "1.0.0", "v2.0.0", true, "2.0.0", nil},
+		{"no version configured", "", "", true, "", errNoVersionConfigured},
+		{"version not installed", "1.0.0", "", false, "1.0.0", errVersionNotInstalled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := setupDebugEnv(t)
+			if tt.global != "" {
+				if err := config.SetGlobalVersion("interprt", tt.global); err != nil {
+					t.Fatalf("SetGlobalVersion() error: %v", err)
+				}
+			}
+
+			// A relative executable path comes back absolute
+			relPath := filepath.Join("versions", "interprt", "bin", "interprt")
+			provider := &mockProvider{name: "interprt", displayName: "Interp Runtime", installed: tt.installed, execPath: relPath}
+
+			before := listFiles(t, tempDir)
+			result, err := resolveInterpreter(provider, tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolveInterpreter() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Never installs or writes anything, even for a missing version
+			if len(provider.installCalls) != 0 {
+				t.Errorf("resolveInterpreter() installed %v", provider.installCalls)
+			}
+			if after := listFiles(t, tempDir); !reflect.DeepEqual(after, before) {
+				t.Errorf("resolveInterpreter() changed files: before %v, after %v", before, after)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			if result.Version != tt.wantVersion {
+				t.Errorf("Version = %q, want %q", result.Version, tt.wantVersion)
+			}
+			if !filepath.IsAbs(result.Path) {
+				t.Errorf("Path = %q, want an absolute path", result.Path)
+			}
+			if want, _ := filepath.Abs(relPath); result.Path != want {
+				t.Errorf("Path = %q, want %q", result.Path, want)
+			}
+		})
+	}
+}
+
+func TestWriteInterpreterEnvNote(t *testing.T) {
+	var buf bytes.Buffer
+	writeInterpreterEnvNote(&buf, interpreterResult{Runtime: "ruby", Version: "3.3.0"})
+	if buf.Len() != 0 {
+		t.Errorf("writeInterpreterEnvNote() without env wrote %q", buf.String())
+	}
+
+	writeInterpreterEnvNote(&buf, interpreterResult{
+		Runtime: "ruby",
+		Version: "3.3.0",
+		Env:     map[string]string{"RUBYLIB": "/lib", "LD_LIBRARY_PATH": "/opt/lib"},
+	})
+	got := buf.String()
+	if !strings.Contains(got, "ruby 3.3.0") {
+		t.Errorf("writeInterpreterEnvNote() = %q, want it to name the version", got)
+	}
+	if i, j := strings.Index(got, "LD_LIBRARY_PATH=/opt/lib"), strings.Index(got, "RUBYLIB=/lib"); i < 0 || j < 0 || i > j {
+		t.Errorf("writeInterpreterEnvNote() = %q, want both variables in sorted order", got)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// defaultJobs is how many archives bulk installs download at once
+const defaultJobs = 4
+
+// jobsFlag is the --jobs value shared by bulk install and migrate
+var jobsFlag int
+
+// addJobsFlag registers the --jobs flag on a command that installs several versions
+func addJobsFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&jobsFlag, "jobs", "j", defaultJobs, "Number of archives to download at once (1 disables parallel downloads)")
+}
+
+// prefetchTarget is a version whose archive can be downloaded ahead of its install
+type prefetchTarget struct {
+	provider runtime.Provider
+	version  string
+}
+
+// prefetchArchives downloads the archives of several versions concurrently, at most
+// jobs at a time, so that the installs that follow run from the archive cache.
+// Installs still run one at a time, keeping their output readable.
+// Failures are only logged: the install downloads the archive again and reports the error.
+func prefetchArchives(targets []prefetchTarget, jobs int) {
+	prefetchable := make([]prefetchTarget, 0, len(targets))
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		key := target.provider.Name() + "@" + target.version
+		if _, ok := target.provider.(runtime.PrefetchProvider); ok && !seen[key] {
+			seen[key] = true
+			prefetchable = append(prefetchable, target)
+		}
+	}
+
+	if jobs <= 1 || len(prefetchable) <= 1 {
+		return
+	}
+
+	spinner := ui.NewSpinner(fmt.Sprintf("Downloading %d archive(s), %d at a time...", len(prefetchable), jobs))
+	spinner.Start()
+
+	runConcurrently(len(prefetchable), jobs, func(i int) {
+		target := prefetchable[i]
+		prefetcher := target.provider.(runtime.PrefetchProvider)
+		if err := prefetcher.Prefetch(target.version); err != nil {
+			ui.Debug("Prefetch of %s %s failed: %v", target.provider.Name(), target.version, err)
+		}
+	})
+
+	spinner.Success(fmt.Sprintf("Downloaded %d archive(s)", len(prefetchable)))
+}
+
+// runConcurrently calls fn for each index in [0, n), with at most jobs calls running at once
+func runConcurrently(n, jobs int, fn func(i int)) {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	slots := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
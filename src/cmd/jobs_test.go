@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/migration"
+)
+
+// mockPrefetchProvider is a mockProvider that records prefetched versions
+type mockPrefetchProvider struct {
+	mockProvider
+	mu         sync.Mutex
+	prefetched []string
+}
+
+func (m *mockPrefetchProvider) Prefetch(version string) error {
+	time.Sleep(time.Millisecond)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prefetched = append(m.prefetched, version)
+	return nil
+}
+
+func TestRunConcurrently_BoundsConcurrency(t *testing.T) {
+	const jobs = 3
+	var running, maxRunning int32
+	var calls sync.Map
+
+	runConcurrently(10, jobs, func(i int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			current := atomic.LoadInt32(&maxRunning)
+			if n <= current || atomic.CompareAndSwapInt32(&maxRunning, current, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		calls.Store(i, true)
+	})
+
+	if maxRunning > jobs {
+		t.Errorf("max concurrent calls = %d, want at most %d", maxRunning, jobs)
+	}
+	for i := 0; i < 10; i++ {
+		if _, ok := calls.Load(i); !ok {
+			t.Errorf("fn not called for index %d", i)
+		}
+	}
+}
+
+func TestPrefetchArchives(t *testing.T) {
+	provider := &mockPrefetchProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}}
+	targets := []prefetchTarget{
+		{provider: provider, version: "18.19.0"},
+		{provider: provider, version: "20.11.1"},
+		{provider: provider, version: "20.11.1"},
+		{provider: provider, version: "22.0.0"},
+		{provider: &mockProvider{name: "other"}, version: "1.0.0"},
+	}
+
+	prefetchArchives(targets, 2)
+
+	sort.Strings(provider.prefetched)
+	want := []string{"18.19.0", "20.11.1", "22.0.0"}
+	if !reflect.DeepEqual(provider.prefetched, want) {
+		t.Errorf("prefetched = %v, want %v", provider.prefetched, want)
+	}
+}
+
+func TestPrefetchArchives_SingleJobSkipsPrefetch(t *testing.T) {
+	provider := &mockPrefetchProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}}
+	targets := []prefetchTarget{
+		{provider: provider, version: "18.19.0"},
+		{provider: provider, version: "20.11.1"},
+	}
+
+	prefetchArchives(targets, 1)
+
+	if len(provider.prefetched) != 0 {
+		t.Errorf("prefetched = %v with --jobs 1, want none", provider.prefetched)
+	}
+}
+
+func TestMigrateSelected_PrefetchesThenInstallsInOrder(t *testing.T) {
+	provider := &mockPrefetchProvider{mockProvider: mockProvider{name: "node", displayName: "Node.js"}}
+	nvm := &mockMigrationProvider{name: "nvm"}
+	versions := []string{"22.0.0", "20.11.1", "18.19.0", "16.20.2", "14.21.3"}
+
+	selected := make([]detectedVersionWithProvider, 0, len(versions))
+	for _, v := range versions {
+		selected = append(selected, detectedVersionWithProvider{
+			DetectedVersion:   migration.DetectedVersion{Version: v, Source: "nvm"},
+			MigrationProvider: nvm,
+		})
+	}
+
+	if got := migrateSelected(provider, selected, false, 3); got != len(versions) {
+		t.Errorf("migrateSelected() = %d, want %d", got, len(versions))
+	}
+
+	if len(provider.prefetched) != len(versions) {
+		t.Errorf("prefetched %d archive(s), want %d", len(provider.prefetched), len(versions))
+	}
+	if !reflect.DeepEqual(provider.installCalls, versions) {
+		t.Errorf("installCalls = %v, want %v in selection order", provider.installCalls, versions)
+	}
+}
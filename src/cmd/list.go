@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
 
 	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/runtime"
@@ -16,21 +18,35 @@ const (
 	localIndicator  = "📍"
 )
 
+var listRuntimeFlag string
+
 var listCmd = &cobra.Command{
 	Use:   "list [runtime]",
 	Short: "List installed versions",
 	Long: `List all installed versions of a specific runtime, or all runtimes if none specified.
 
+Versions are listed newest first. The version currently in use is highlighted,
+and the global and local versions are tagged.
+
 Examples:
-  dtvem list           # List all installed versions
-  dtvem list python    # List installed Python versions
-  dtvem list node      # List installed Node.js versions`,
+  dtvem list                    # List all installed versions
+  dtvem list python             # List installed Python versions
+  dtvem list --runtime node     # List installed Node.js versions`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
+		runtimeName := listRuntimeFlag
+		if len(args) == 1 {
+			if runtimeName != "" && runtimeName != args[0] {
+				reportError(fmt.Errorf("runtime given twice: %s and --runtime %s", args[0], runtimeName))
+				os.Exit(1)
+			}
+			runtimeName = args[0]
+		}
+
+		if runtimeName == "" {
 			listAllRuntimes()
 		} else {
-			listSingleRuntime(args[0])
+			listSingleRuntime(runtimeName)
 		}
 	},
 }
@@ -47,13 +63,14 @@ func listAllRuntimes() {
 		return
 	}
 
-	hasAny := false
+	hasAny, failed := false, false
 	for _, provider := range providers {
 		ui.Debug("Checking provider: %s", provider.Name())
 		versions, err := provider.ListInstalled()
 		if err != nil {
 			ui.Debug("Error listing versions for %s: %v", provider.Name(), err)
 			ui.Error("  %s: %v", provider.DisplayName(), err)
+			failed = true
 			continue
 		}
 		ui.Debug("Found %d installed versions for %s", len(versions), provider.Name())
@@ -63,31 +80,15 @@ func listAllRuntimes() {
 		}
 
 		hasAny = true
-		runtimeName := provider.Name()
-		globalVersion, _ := provider.GlobalVersion()
-		localVersion, _ := config.LocalVersion(runtimeName)
-
-		// Create table for this runtime with title
-		table := tui.NewTable("Version", "Status")
-		table.SetTitle(provider.DisplayName())
-
-		for _, v := range versions {
-			version := v.String()
-			status := getVersionStatus(version, globalVersion, localVersion)
-			isActive := isVersionActive(version, globalVersion, localVersion)
-
-			if isActive {
-				table.AddActiveRow(version, status)
-			} else {
-				table.AddRow(version, status)
-			}
-		}
-
-		fmt.Println(table.Render())
+		fmt.Println(installedVersionsTable(provider, versions))
 	}
 
 	if !hasAny {
 		ui.Info("No versions installed")
+		ui.Info("Install one with 'dtvem install <runtime> <version>' (see 'dtvem list-all <runtime>' for versions)")
+	}
+	if failed {
+		os.Exit(1)
 	}
 }
 
@@ -95,48 +96,80 @@ func listAllRuntimes() {
 func listSingleRuntime(runtimeName string) {
 	provider, err := runtime.Get(runtimeName)
 	if err != nil {
-		ui.Error("%v", err)
-		ui.Info("Available runtimes: %v", runtime.List())
-		return
+		reportError(err)
+		ui.Info("Available runtimes: %s", availableRuntimes())
+		os.Exit(1)
 	}
 
 	versions, err := provider.ListInstalled()
 	if err != nil {
 		ui.Error("%v", err)
-		return
+		os.Exit(1)
 	}
 
 	if len(versions) == 0 {
-		ui.Info("No versions installed")
+		ui.Info("No %s versions installed", provider.DisplayName())
+		ui.Info("Install one with 'dtvem install %s <version>' (see 'dtvem list-all %s' for versions)", runtimeName, runtimeName)
 		return
 	}
 
+	fmt.Println(installedVersionsTable(provider, versions))
+}
+
+// installedVersionsTable renders the installed versions of a runtime
+func installedVersionsTable(provider runtime.Provider, versions []runtime.InstalledVersion) string {
+	runtimeName := provider.Name()
+	currentVersion, _ := config.ResolveVersion(runtimeName)
 	globalVersion, _ := provider.GlobalVersion()
 	localVersion, _ := config.LocalVersion(runtimeName)
 
-	// Create table with title
 	table := tui.NewTable("Version", "Status")
 	table.SetTitle(provider.DisplayName())
 
-	for _, v := range versions {
-		version := v.String()
-		status := getVersionStatus(version, globalVersion, localVersion)
-		isActive := isVersionActive(version, globalVersion, localVersion)
-
-		if isActive {
-			table.AddActiveRow(version, status)
+	for _, row := range installedVersionRows(versions, currentVersion, globalVersion, localVersion) {
+		if row.active {
+			table.AddActiveRow(row.version, row.status)
 		} else {
-			table.AddRow(version, status)
+			table.AddRow(row.version, row.status)
 		}
 	}
 
-	fmt.Println(table.Render())
+	return table.Render()
+}
+
+// installedVersionRow is a row of the installed versions table
+type installedVersionRow struct {
+	version string
+	status  string
+	active  bool
+}
+
+// installedVersionRows returns the rows of the installed versions table, newest
+// first. The current version (the resolved one, which is the local version when
+// there is one) is active; the global and local versions are tagged.
+func installedVersionRows(versions []runtime.InstalledVersion, currentVersion, globalVersion, localVersion string) []installedVersionRow {
+	sorted := make([]runtime.InstalledVersion, len(versions))
+	copy(sorted, versions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return runtime.CompareVersions(sorted[i].Version.Raw, sorted[j].Version.Raw) > 0
+	})
+
+	rows := make([]installedVersionRow, 0, len(sorted))
+	for _, v := range sorted {
+		version := v.Version.Raw
+		rows = append(rows, installedVersionRow{
+			version: version,
+			status:  getVersionStatus(version, globalVersion, localVersion),
+			active:  sameVersion(version, currentVersion),
+		})
+	}
+	return rows
 }
 
 // getVersionStatus returns a status string for a version (global, local, or empty)
 func getVersionStatus(version, globalVersion, localVersion string) string {
-	isGlobal := version == globalVersion
-	isLocal := version == localVersion
+	isGlobal := sameVersion(version, globalVersion)
+	isLocal := sameVersion(version, localVersion)
 
 	var parts []string
 	if isLocal {
@@ -160,13 +193,13 @@ func getVersionStatus(version, globalVersion, localVersion string) string {
 	return status
 }
 
-// isVersionActive returns true if this version is the currently active one
-func isVersionActive(version, globalVersion, localVersion string) bool {
-	isGlobal := version == globalVersion
-	isLocal := version == localVersion
-	return isLocal || (isGlobal && localVersion == "")
+// sameVersion reports whether an installed version is a configured one, ignoring
+// a "v" prefix on either. An unset configured version matches nothing.
+func sameVersion(installed, configured string) bool {
+	return configured != "" && runtime.NewVersion(installed).Equal(runtime.NewVersion(configured))
 }
 
 func init() {
+	listCmd.Flags().StringVar(&listRuntimeFlag, "runtime", "", "Only list versions of this runtime")
 	rootCmd.AddCommand(listCmd)
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+func TestInstalledVersionRows(t *testing.T) {
+	installed := []runtime.InstalledVersion{
+		{Version: runtime.NewVersion("18.20.0")},
+		{Version: runtime.NewVersion("22.1.0")},
+		{Version: runtime.NewVersion("20.11.1")},
+		{Version: runtime.NewVersion("9.11.2")},
+	}
+
+	tests := []struct {
+		name                   string
+		current, global, local string
+		want                   []installedVersionRow
+	}{
+		{
+			name:    "global version in use",
+			current: "20.11.1", global: "20.11.1",
+			want: []installedVersionRow{
+				{version: "22.1.0"},
+				{version: "20.11.1", status: globalIndicator + " global", active: true},
+				{version: "18.20.0"},
+				{version: "9.11.2"},
+			},
+		},
+		{
+			name:    "local version overrides global",
+			current: "18.20.0", global: "v20.11.1", local: "18.20.0",
+			want: []installedVersionRow{
+				{version: "22.1.0"},
+				{version: "20.11.1", status: globalIndicator + " global"},
+				{version: "18.20.0", status: localIndicator + " local", active: true},
+				{version: "9.11.2"},
+			},
+		},
+		{
+			name: "nothing configured",
+			want: []installedVersionRow{
+				{version: "22.1.0"},
+				{version: "20.11.1"},
+				{version: "18.20.0"},
+				{version: "9.11.2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := installedVersionRows(installed, tt.current, tt.global, tt.local)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("installedVersionRows() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+
+	if installed[0].Version.Raw != "18.20.0" {
+		t.Error("installedVersionRows() reordered its input")
+	}
+}
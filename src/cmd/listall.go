@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/manifest"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/tui"
 	"github.com/dtvem/dtvem/src/internal/ui"
@@ -19,70 +20,124 @@ var listAllCmd = &cobra.Command{
 	Long: `Display all available versions of a runtime that can be installed.
 
 This command queries official sources to show all versions available for download.
-Installed versions are marked with a ✓ indicator.
+Installed versions are marked with a ✓ indicator. Versions without a pre-built
+binary for this platform are marked with ✗, and versions missing from the
+cached manifest with ?. A runtime that is in the manifests but has no dtvem
+provider yet is listed from its manifest, though it can't be installed.
 
 Examples:
   dtvem list-all python
   dtvem list-all node
-  dtvem list-all python --filter 3.11`,
+  dtvem list-all python --filter 3.11
+  dtvem list-all ruby --only-installed-platforms
+  dtvem list-all node --refresh   # Skip the cached version list`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		runtimeName := args[0]
 		filter, _ := cmd.Flags().GetString("filter")
 		limit, _ := cmd.Flags().GetInt("limit")
+		onlyInstallable, _ := cmd.Flags().GetBool("only-installed-platforms")
+		refresh, _ := cmd.Flags().GetBool("refresh")
 
-		// Get the provider
+		// Get the provider. A runtime without one can still be listed from its
+		// manifest, though it can't be installed yet.
 		provider, err := runtime.Get(runtimeName)
+		var m *manifest.Manifest
 		if err != nil {
-			ui.Error("Unknown runtime: %s", runtimeName)
-			ui.Info("Available runtimes: %v", runtime.List())
-			return
+			var manifestErr error
+			if m, manifestErr = manifest.DefaultSource().GetManifest(runtimeName); manifestErr != nil {
+				reportError(err)
+				ui.Info("Available runtimes: %s", availableRuntimes())
+				os.Exit(1)
+			}
 		}
 
 		ui.Info("Fetching available versions...")
 
-		// Get available versions
-		available, err := provider.ListAvailable()
-		if err != nil {
-			ui.Error("Failed to fetch available versions: %v", err)
-			return
+		var displayName, globalVersion string
+		var available []runtime.AvailableVersion
+		// installable holds the versions the provider can install on this platform
+		installable := make(map[string]bool)
+		var installed []runtime.InstalledVersion
+		if provider != nil {
+			displayName = provider.DisplayName()
+			if refresh {
+				if _, fromRemote, err := manifest.ForceRefreshRuntime(runtimeName); err != nil || !fromRemote {
+					ui.Debug("Could not refresh the %s manifest: %v", runtimeName, err)
+					ui.Warning("Could not fetch the latest %s versions; showing the cached list", displayName)
+				}
+			}
+
+			// Get available versions
+			available, err = provider.ListAvailable()
+			if err != nil {
+				ui.Error("Failed to fetch available versions: %v", err)
+				os.Exit(1)
+			}
+			for _, v := range available {
+				installable[v.Version.Raw] = true
+			}
+
+			// Annotate versions with whether they can be installed on this platform
+			m, err = manifest.DefaultSource().GetManifest(runtimeName)
+			if err != nil {
+				ui.Debug("Could not load manifest for availability hints: %v", err)
+				m = nil
+			}
+
+			// Get installed versions for comparison
+			installed, err = provider.ListInstalled()
+			if err != nil {
+				ui.Warning("Could not check installed versions: %v", err)
+				installed = []runtime.InstalledVersion{} // Continue without installed info
+			}
+			globalVersion, _ = provider.GlobalVersion()
+		} else {
+			displayName = m.RuntimeDisplayName(runtimeName)
+			if refresh {
+				if refreshed, fromRemote, err := manifest.ForceRefreshRuntime(runtimeName); err == nil && fromRemote {
+					m = refreshed
+				} else {
+					ui.Warning("Could not fetch the latest %s versions; showing the cached list", displayName)
+				}
+			}
+			globalVersion, _ = config.GlobalVersion(runtimeName)
 		}
+		available = withManifestVersions(available, m)
+		platform := manifest.CurrentPlatform()
 
 		if len(available) == 0 {
 			ui.Warning("No versions found")
 			return
 		}
 
-		// Get installed versions for comparison
-		installed, err := provider.ListInstalled()
-		if err != nil {
-			ui.Warning("Could not check installed versions: %v", err)
-			installed = []runtime.InstalledVersion{} // Continue without installed info
-		}
-
 		// Create a map of installed versions for quick lookup
 		installedMap := make(map[string]bool)
 		for _, v := range installed {
-			installedMap[v.Version.Raw] = true
+			installedMap[v.Version.Normalized()] = true
 		}
 
-		// Get global and local versions for indicators
-		globalVersion, _ := provider.GlobalVersion()
+		// Get local version for indicators
 		localVersion, _ := config.LocalVersion(runtimeName)
 
 		// Filter versions if requested
-		filteredVersions := available
-		if filter != "" {
-			filteredVersions = []runtime.AvailableVersion{}
-			for _, v := range available {
-				if strings.Contains(v.Version.Raw, filter) {
-					filteredVersions = append(filteredVersions, v)
-				}
+		filteredVersions := []runtime.AvailableVersion{}
+		for _, v := range available {
+			if filter != "" && !strings.Contains(v.Version.Raw, filter) {
+				continue
 			}
+			if onlyInstallable && versionAvailability(m, installable, v.Version.Raw, platform) != manifest.AvailabilityAvailable {
+				continue
+			}
+			filteredVersions = append(filteredVersions, v)
 		}
 
 		if len(filteredVersions) == 0 {
-			ui.Warning("No versions match filter: %s", filter)
+			if filter != "" {
+				ui.Warning("No versions match filter: %s", filter)
+			} else {
+				ui.Warning("No versions can be installed on %s", platform)
+			}
 			return
 		}
 
@@ -100,7 +155,8 @@ Examples:
 
 			// Create table for this page
 			table := tui.NewTable("", "Version", "Status", "Notes")
-			table.SetTitle(provider.DisplayName())
+			table.SetTitle(displayName)
+			showLegend := false
 
 			for i := 0; i < pageSize; i++ {
 				v := filteredVersions[offset+i]
@@ -108,18 +164,38 @@ Examples:
 
 				// Check if installed
 				marker := ""
-				if installedMap[version] {
+				if installedMap[v.Version.Normalized()] {
 					marker = tui.CheckMark
 				}
 
 				// Get status (global/local indicators)
 				status := getVersionStatus(version, globalVersion, localVersion)
 
-				table.AddRow(marker, version, status, v.Notes)
+				// Mark versions that can't be installed right now
+				availability := versionAvailability(m, installable, version, platform)
+				label := version
+				if hint := availabilityMarker(availability); hint != "" {
+					label = version + " " + hint
+					showLegend = true
+				}
+
+				notes := v.Notes
+				if notes == "" && provider == nil {
+					notes = m.ReleaseNotes(version)
+				}
+				if notes == "" {
+					notes = availabilityNote(availability, platform)
+				}
+
+				table.AddRow(marker, label, status, notes)
 			}
 
 			fmt.Println()
 			fmt.Println(table.Render())
+			if showLegend {
+				ui.Printf("%s\n", ui.DimText(fmt.Sprintf("%s no pre-built binary for %s   %s not in the cached manifest (run 'dtvem update %s')",
+					unavailableMarker, platform, unknownMarker, runtimeName)))
+			}
 
 			offset += pageSize
 			remaining = total - offset
@@ -141,11 +217,84 @@ Examples:
 		}
 
 		fmt.Println()
+		if provider == nil {
+			ui.Info("%s can't be installed with this version of dtvem yet; it provides: %s",
+				displayName, strings.Join(m.ShimNames(runtimeName), ", "))
+			return
+		}
 		ui.Info("Install a version with: dtvem install %s <version>", runtimeName)
 	},
 }
 
+// Markers shown next to versions in list-all that can't be installed right now
+const (
+	unavailableMarker = "✗"
+	unknownMarker     = "?"
+)
+
+// availabilityMarker returns the marker shown next to a version, or "" if it can be installed
+func availabilityMarker(availability manifest.Availability) string {
+	switch availability {
+	case manifest.AvailabilityAvailable:
+		return ""
+	case manifest.AvailabilityUnavailable:
+		return unavailableMarker
+	default:
+		return unknownMarker
+	}
+}
+
+// availabilityNote explains why a version can't be installed on the platform
+func availabilityNote(availability manifest.Availability, platform string) string {
+	switch availability {
+	case manifest.AvailabilityUnavailable:
+		return fmt.Sprintf("no pre-built binary for %s", platform)
+	case manifest.AvailabilityUnknown:
+		return "refresh manifests to install"
+	default:
+		return ""
+	}
+}
+
+// versionAvailability returns whether a version can be installed on the platform.
+// Versions the provider lists as installable are, even without a native build (such as
+// Ruby's x64 builds on Windows ARM64). Without a manifest, every version is assumed to be.
+func versionAvailability(m *manifest.Manifest, installable map[string]bool, version, platform string) manifest.Availability {
+	if m == nil || installable[version] {
+		return manifest.AvailabilityAvailable
+	}
+	return m.CheckAvailability(version, platform)
+}
+
+// withManifestVersions adds versions known to the manifest but not reported by the
+// provider (because they can't be installed on this platform), newest first
+func withManifestVersions(available []runtime.AvailableVersion, m *manifest.Manifest) []runtime.AvailableVersion {
+	if m == nil {
+		return available
+	}
+
+	seen := make(map[string]bool, len(available))
+	for _, v := range available {
+		seen[v.Version.Raw] = true
+	}
+
+	added := false
+	for _, version := range m.ListVersions() {
+		if !seen[version] {
+			available = append(available, runtime.AvailableVersion{Version: runtime.NewVersion(version)})
+			added = true
+		}
+	}
+
+	if added {
+		runtime.SortVersionsDesc(available)
+	}
+	return available
+}
+
 func init() {
+	listAllCmd.Flags().Bool("refresh", false, "Fetch the version list again instead of using the cached copy")
+	listAllCmd.Flags().Bool("only-installed-platforms", false, "Only show versions with a pre-built binary for this platform")
 	listAllCmd.Flags().StringP("filter", "f", "", "Filter versions by substring (e.g., '3.11' for Python 3.11.x)")
 	listAllCmd.Flags().IntP("limit", "l", 50, "Number of versions to show per page")
 	rootCmd.AddCommand(listAllCmd)
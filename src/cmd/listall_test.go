@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// testAvailabilityManifest has one installable version, one without a binary for
+// linux-amd64, and one that only has binaries for other platforms
+func testAvailabilityManifest(t *testing.T) *manifest.Manifest {
+	t.Helper()
+	m, err := manifest.ParseManifest([]byte(`{
+		"version": 1,
+		"versions": {
+			"3.0.0": {"linux-amd64": {"url": "https://example.com/3.0.0.tar.gz", "sha256": "abc"}},
+			"2.0.0": {"linux-amd64": null},
+			"1.0.0": {"darwin-arm64": {"url": "https://example.com/1.0.0.tar.gz", "sha256": "def"}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseManifest() error: %v", err)
+	}
+	return m
+}
+
+func TestAvailabilityMarker(t *testing.T) {
+	m := testAvailabilityManifest(t)
+
+	tests := []struct {
+		version          string
+		wantAvailability manifest.Availability
+		wantMarker       string
+	}{
+		{"3.0.0", manifest.AvailabilityAvailable, ""},
+		{"2.0.0", manifest.AvailabilityUnavailable, unavailableMarker},
+		{"1.0.0", manifest.AvailabilityUnknown, unknownMarker},
+		{"9.9.9", manifest.AvailabilityUnknown, unknownMarker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			availability := versionAvailability(m, nil, tt.version, "linux-amd64")
+			if availability != tt.wantAvailability {
+				t.Errorf("versionAvailability(%q) = %v, want %v", tt.version, availability, tt.wantAvailability)
+			}
+			if got := availabilityMarker(availability); got != tt.wantMarker {
+				t.Errorf("availabilityMarker(%v) = %q, want %q", availability, got, tt.wantMarker)
+			}
+			if got := availabilityNote(availability, "linux-amd64"); (got == "") != (tt.wantMarker == "") {
+				t.Errorf("availabilityNote(%v) = %q, want a note only for uninstallable versions", availability, got)
+			}
+		})
+	}
+}
+
+func TestVersionAvailability_NoManifest(t *testing.T) {
+	if got := versionAvailability(nil, nil, "3.0.0", "linux-amd64"); got != manifest.AvailabilityAvailable {
+		t.Errorf("versionAvailability() without a manifest = %v, want AvailabilityAvailable", got)
+	}
+}
+
+func TestVersionAvailability_ProviderInstallable(t *testing.T) {
+	m := testAvailabilityManifest(t)
+
+	// e.g. an x64 build the provider installs under emulation
+	installable := map[string]bool{"1.0.0": true}
+	if got := versionAvailability(m, installable, "1.0.0", "linux-amd64"); got != manifest.AvailabilityAvailable {
+		t.Errorf("versionAvailability() of a version the provider can install = %v, want AvailabilityAvailable", got)
+	}
+	if got := versionAvailability(m, installable, "2.0.0", "linux-amd64"); got != manifest.AvailabilityUnavailable {
+		t.Errorf("versionAvailability() of a version without a build = %v, want AvailabilityUnavailable", got)
+	}
+}
+
+func TestWithManifestVersions(t *testing.T) {
+	m := testAvailabilityManifest(t)
+	available := []runtime.AvailableVersion{{Version: runtime.NewVersion("3.0.0")}}
+
+	got := withManifestVersions(available, m)
+
+	want := []string{"3.0.0", "2.0.0", "1.0.0"}
+	if len(got) != len(want) {
+		t.Fatalf("withManifestVersions() returned %d versions, want %d", len(got), len(want))
+	}
+	for i, v := range want {
+		if got[i].Version.Raw != v {
+			t.Errorf("withManifestVersions()[%d] = %q, want %q", i, got[i].Version.Raw, v)
+		}
+	}
+}
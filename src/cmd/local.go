@@ -1,36 +1,73 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	localForceFlag bool
+	localRootFlag  bool
+)
+
 var localCmd = &cobra.Command{
 	Use:   "local <runtime> <version>",
 	Short: "Set the local version of a runtime for the current directory",
 	Long: `Set a runtime version for the current directory by creating a .dtvem/runtimes.json file.
 This version will be used when working in this directory or its subdirectories.
 
+A version that isn't installed is offered for install first (or installed
+without asking when auto-install is on); --force sets it anyway.
+
+The version "system" uses the runtime installed on the system PATH instead of
+a dtvem-managed version for this project.
+
+With --root the file is written at the root of the git repository instead of
+the current directory, so the version applies to the whole repository.
+
 Examples:
   dtvem local python 3.11.0
-  dtvem local node 18.16.0`,
+  dtvem local node 18.16.0
+  dtvem local node 22.0.0 --force   # Set it before installing it
+  dtvem local python 3.12.0 --root  # Set it for the whole repository
+  dtvem local node system           # Use the system Node.js here`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		runtimeName := args[0]
-		version := args[1]
+		version := resolveVersionArg(runtimeName, args[1])
 
 		provider, err := runtime.Get(runtimeName)
 		if err != nil {
-			ui.Error("%v", err)
-			ui.Info("Available runtimes: %v", runtime.List())
-			return
+			reportError(err)
+			ui.Info("Available runtimes: %s", availableRuntimes())
+			os.Exit(1)
+		}
+
+		setter := provider.SetLocalVersion
+		if localRootFlag {
+			root, ok := config.GitRoot()
+			if !ok {
+				reportError(fmt.Errorf("--root needs a git repository, but the current directory is not inside one"))
+				os.Exit(1)
+			}
+			setter = func(version string) error {
+				return config.SetLocalVersionIn(root, runtimeName, version)
+			}
 		}
 
-		setRuntimeVersion(runtimeName, version, "local", provider.SetLocalVersion)
+		if !setRuntimeVersion(runtimeName, version, "local", localForceFlag, setter) {
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
+	localCmd.Flags().BoolVar(&localForceFlag, "force", false, "Set the version even if it isn't installed")
+	localCmd.Flags().BoolVar(&localRootFlag, "root", false, "Write the version file at the git repository root")
 	rootCmd.AddCommand(localCmd)
 }
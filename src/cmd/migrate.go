@@ -2,18 +2,35 @@ package cmd
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/migration"
 	internalRuntime "github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	migrateDryRun  bool
+	migrateList    bool
+	migrateJSON    bool
+	migrateRefresh bool
+
+	migratePrintCleanup bool
+
+	migrateKeepPackagesFile string
+)
+
 var migrateCmd = &cobra.Command{
 	Use:   "migrate <runtime>",
 	Short: "Migrate existing runtime installations to dtvem",
@@ -23,9 +40,24 @@ This command scans your system for existing installations (from system packages,
 nvm, pyenv, etc.), lets you select which versions to migrate, and installs them
 via dtvem's normal installation process.
 
+Scan results are cached for a few minutes, until a version manager's directory
+changes, so running it again is quick; --refresh scans again anyway.
+
 Examples:
-  dtvem migrate node     # Detect and migrate Node.js installations
-  dtvem migrate python   # Detect and migrate Python installations`,
+  dtvem migrate node             # Detect and migrate Node.js installations
+  dtvem migrate python           # Detect and migrate Python installations
+  dtvem migrate node --dry-run   # Preview a migration without changing anything
+  dtvem migrate node --list      # Only list detected installations
+  dtvem migrate node --json      # List detected installations as JSON
+  dtvem migrate node --refresh   # Scan again instead of using cached results
+
+Print the commands that remove the old installations instead of running them,
+to review them first or run them elsewhere:
+  dtvem migrate node --print-cleanup
+
+Save the global packages of each version before reinstalling them, to recover
+the list if reinstalling fails:
+  dtvem migrate node --keep-packages-file packages.json`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		runtimeName := args[0]
@@ -34,33 +66,26 @@ Examples:
 		provider, err := internalRuntime.Get(runtimeName)
 		if err != nil {
 			ui.Error("%v", err)
-			ui.Info("Available runtimes: %v", internalRuntime.List())
+			ui.Info("Available runtimes: %s", availableRuntimes())
 			return
 		}
 
-		spinner := ui.NewSpinner(fmt.Sprintf("Scanning for %s installations...", provider.DisplayName()))
-		spinner.Start()
-
 		// Get migration providers for this runtime
 		migrationProviders := migration.GetByRuntime(runtimeName)
+		cache := migration.NewDetectionCache(filepath.Join(config.DefaultPaths().Cache, "migrate"), migration.DetectionCacheTTL)
 
-		// Collect all detected versions from all migration providers
-		detected := make([]detectedVersionWithProvider, 0)
-		for _, mp := range migrationProviders {
-			versions, err := mp.DetectVersions()
-			if err != nil {
-				continue // Skip providers that fail
-			}
-			for _, v := range versions {
-				detected = append(detected, detectedVersionWithProvider{
-					DetectedVersion:   v,
-					MigrationProvider: mp,
-				})
+		if migrateJSON {
+			if err := writeDetectedJSON(os.Stdout, runtimeName, detectVersions(migrationProviders, cache, migrateRefresh)); err != nil {
+				ui.Error("Failed to encode detection results: %v", err)
+				os.Exit(1)
 			}
+			return
 		}
 
-		// Deduplicate by path
-		detected = deduplicateByPath(detected)
+		spinner := ui.NewSpinner(fmt.Sprintf("Scanning for %s installations...", provider.DisplayName()))
+		spinner.Start()
+
+		detected := detectVersions(migrationProviders, cache, migrateRefresh)
 
 		if len(detected) == 0 {
 			spinner.Warning("No installations found")
@@ -85,6 +110,10 @@ Examples:
 				validatedMark)
 		}
 
+		if migrateList {
+			return
+		}
+
 		// Prompt user for selection
 		fmt.Printf("\nSelect versions to migrate:\n")
 		fmt.Printf("  Enter numbers separated by commas, or 'all' (e.g., 1,3 or all): ")
@@ -117,49 +146,21 @@ Examples:
 
 		fmt.Println()
 
+		if migrateDryRun {
+			ui.Header("Dry run: nothing will be installed or removed")
+		}
+
 		// Migrate each selected version
-		successCount := 0
 		fmt.Println()
-		for _, dv := range selectedVersions {
-			ui.Header("Migrating %s v%s...", provider.DisplayName(), dv.Version)
+		successCount := migrateSelected(provider, selectedVersions, migrateDryRun, jobsFlag)
 
-			// Detect global packages from the existing installation
-			var globalPackages []string
-			ui.Progress("Detecting global packages...")
-			packages, err := provider.GlobalPackages(dv.Path)
-			if err != nil {
-				ui.Warning("Could not detect global packages: %v", err)
-				globalPackages = []string{}
-			} else {
-				globalPackages = packages
-				if len(globalPackages) > 0 {
-					ui.Info("Found %d global package(s): %s", len(globalPackages), strings.Join(globalPackages, ", "))
-				} else {
-					ui.Info("No global packages found")
-				}
-			}
-
-			// Call the provider's Install method
-			if err := provider.Install(dv.Version); err != nil {
-				ui.Error("%v", err)
-			} else {
-				successCount++
-
-				// Reinstall global packages
-				if len(globalPackages) > 0 {
-					ui.Progress("Reinstalling %d global package(s)...", len(globalPackages))
-					if err := provider.InstallGlobalPackages(dv.Version, globalPackages); err != nil {
-						ui.Warning("Failed to reinstall some packages: %v", err)
-						if cmd := provider.ManualPackageInstallCommand(globalPackages); cmd != "" {
-							ui.Info("You can manually reinstall with:")
-							ui.Info("  %s", cmd)
-						}
-					} else {
-						ui.Success("Reinstalled %d global package(s)", len(globalPackages))
-					}
-				}
+		if migrateDryRun {
+			if choice := defaultGlobalChoice(selectedVersions); choice > 0 {
+				ui.Info("Would offer v%s as the global version", selectedVersions[choice-1].Version)
+				fmt.Println()
 			}
-			fmt.Println()
+			printCleanupPlan(selectedVersions)
+			return
 		}
 
 		if successCount == len(selectedVersions) {
@@ -178,23 +179,31 @@ Examples:
 		if successCount > 0 {
 			fmt.Println()
 			ui.Header("Set global version?")
+
+			// Pre-select the version the old version manager used as its default
+			defaultChoice := defaultGlobalChoice(selectedVersions)
 			for i, dv := range selectedVersions {
-				fmt.Printf("  [%d] %s\n", i+1, ui.HighlightVersion("v"+dv.Version))
+				note := ""
+				if i+1 == defaultChoice {
+					note = ui.DimText(fmt.Sprintf(" (default in %s)", dv.Source))
+				}
+				fmt.Printf("  [%d] %s%s\n", i+1, ui.HighlightVersion("v"+dv.Version), note)
 			}
 			fmt.Printf("  [0] None\n")
-			fmt.Printf("Select [0]: ")
+			fmt.Printf("Select [%d]: ", defaultChoice)
 
 			input, err = reader.ReadString('\n')
 			if err == nil {
-				input = strings.TrimSpace(input)
-				if input != "" && input != "0" {
-					if choice, err := strconv.Atoi(input); err == nil && choice > 0 && choice <= len(selectedVersions) {
-						version := selectedVersions[choice-1].Version
-						if err := provider.SetGlobalVersion(version); err != nil {
-							ui.Error("Error setting global version: %v", err)
-						} else {
-							ui.Success("Global version set to v%s", version)
-						}
+				choice := defaultChoice
+				if input = strings.TrimSpace(input); input != "" {
+					choice, _ = strconv.Atoi(input)
+				}
+				if choice > 0 && choice <= len(selectedVersions) {
+					version := selectedVersions[choice-1].Version
+					if err := provider.SetGlobalVersion(version); err != nil {
+						ui.Error("Error setting global version: %v", err)
+					} else {
+						ui.Success("Global version set to v%s", version)
 					}
 				}
 			}
@@ -203,7 +212,7 @@ Examples:
 		// Prompt to cleanup old installations
 		if successCount > 0 {
 			fmt.Println()
-			promptCleanupOldInstallations(selectedVersions, provider.DisplayName())
+			cleanupOldInstallations(os.Stdout, selectedVersions, provider.DisplayName())
 		}
 
 		// Show next steps
@@ -215,6 +224,251 @@ Examples:
 	},
 }
 
+// migrateSelected installs the selected versions with dtvem and reinstalls their
+// global packages. Archives are downloaded up to jobs at a time before the installs.
+// In dry-run mode it only reports what it would do.
+// Returns the number of versions migrated.
+func migrateSelected(provider internalRuntime.Provider, selected []detectedVersionWithProvider, dryRun bool, jobs int) int {
+	if !dryRun {
+		targets := make([]prefetchTarget, 0, len(selected))
+		for _, dv := range selected {
+			targets = append(targets, prefetchTarget{provider: provider, version: dv.Version})
+		}
+		prefetchArchives(targets, jobs)
+	}
+
+	successCount := 0
+	for _, dv := range selected {
+		ui.Header("Migrating %s v%s...", provider.DisplayName(), dv.Version)
+
+		// Detect global packages from the existing installation
+		globalPackages := []string{}
+		packagesDetected := false
+		if internalRuntime.CapabilitiesOf(provider).GlobalPackages {
+			ui.Progress("Detecting global packages...")
+			packages, err := provider.GlobalPackages(dv.Path)
+			if errors.Is(err, context.DeadlineExceeded) {
+				// A package manager that hangs must not block migrate; keep what it listed
+				ui.Warning("Detecting global packages timed out; some may be missing from the list")
+				err = nil
+			}
+			if err != nil {
+				ui.Warning("Could not detect global packages: %v", err)
+			} else {
+				globalPackages = packages
+				packagesDetected = true
+				if len(globalPackages) > 0 {
+					ui.Info("Found %d global package(s): %s", len(globalPackages), strings.Join(globalPackages, ", "))
+				} else {
+					ui.Info("No global packages found")
+				}
+			}
+		}
+
+		// Save the package list before reinstalling can fail or change it
+		if packagesDetected && migrateKeepPackagesFile != "" {
+			if dryRun {
+				ui.Info("Would save the package list to %s", migrateKeepPackagesFile)
+			} else if err := savePackagesSnapshot(migrateKeepPackagesFile, provider, dv, globalPackages); err != nil {
+				ui.Warning("Could not save the package list: %v", err)
+			} else {
+				ui.Info("Saved the package list to %s", migrateKeepPackagesFile)
+			}
+		}
+
+		if dryRun {
+			ui.Info("Would install %s v%s", provider.DisplayName(), dv.Version)
+			if len(globalPackages) > 0 {
+				ui.Info("Would reinstall %d global package(s)", len(globalPackages))
+			}
+			successCount++
+			fmt.Println()
+			continue
+		}
+
+		// Call the provider's Install method, unless dtvem can't install the version
+		err := internalRuntime.CheckSupportedVersion(provider, dv.Version)
+		if err == nil {
+			err = provider.Install(dv.Version)
+		}
+		if err != nil {
+			ui.Error("%v", err)
+		} else {
+			successCount++
+
+			// Reinstall global packages
+			if len(globalPackages) > 0 {
+				ui.Progress("Reinstalling %d global package(s)...", len(globalPackages))
+				if err := provider.InstallGlobalPackages(dv.Version, globalPackages); err != nil {
+					ui.Warning("Failed to reinstall some packages: %v", err)
+					if cmd := provider.ManualPackageInstallCommand(globalPackages); cmd != "" {
+						ui.Info("You can manually reinstall with:")
+						ui.Info("  %s", cmd)
+					}
+				} else {
+					ui.Success("Reinstalled %d global package(s)", len(globalPackages))
+				}
+			}
+		}
+		fmt.Println()
+	}
+	return successCount
+}
+
+// printCleanupPlan reports how each old installation would be removed after migrating
+func printCleanupPlan(versions []detectedVersionWithProvider) {
+	ui.Header("Cleanup Old Installations")
+	for _, dv := range versions {
+		fmt.Printf("Old installation: %s %s\n", ui.HighlightVersion("v"+dv.Version), ui.Highlight("("+dv.Source+")"))
+		fmt.Printf("  Location: %s\n", dv.Path)
+
+		mp := dv.MigrationProvider
+		if command := mp.UninstallCommand(dv.Version); mp.CanAutoUninstall() && command != "" {
+			ui.Info("Would offer to remove with: %s", command)
+		} else {
+			ui.Info("Would need manual removal")
+		}
+	}
+	fmt.Println()
+	ui.Info("Run without --dry-run to migrate")
+}
+
+// cleanupOldInstallations offers to remove the old installations after migrating.
+// With --print-cleanup it only writes the removal commands to w.
+func cleanupOldInstallations(w io.Writer, versions []detectedVersionWithProvider, runtimeDisplayName string) {
+	if !migratePrintCleanup {
+		promptCleanupOldInstallations(versions, runtimeDisplayName)
+		return
+	}
+
+	ui.Header("Cleanup Old Installations")
+	ui.Info("Review and run these commands to remove the old installations:")
+	printCleanupCommands(w, versions)
+}
+
+// printCleanupCommands writes the commands that remove the old installations to w
+// instead of running them. Installations that need manual removal are written as
+// comments, so the output can be reviewed and run as a script.
+func printCleanupCommands(w io.Writer, versions []detectedVersionWithProvider) {
+	for _, dv := range versions {
+		mp := dv.MigrationProvider
+		if command := mp.UninstallCommand(dv.Version); mp.CanAutoUninstall() && command != "" {
+			fmt.Fprintln(w, command)
+		} else {
+			fmt.Fprintf(w, "# v%s (%s) needs manual removal: %s\n", dv.Version, dv.Source, dv.Path)
+		}
+	}
+}
+
+// detectVersions collects the versions found by each migration provider,
+// without duplicates. Providers that fail are skipped. Results come from cache
+// when it's not nil, unless refresh is set.
+func detectVersions(migrationProviders []migration.Provider, cache *migration.DetectionCache, refresh bool) []detectedVersionWithProvider {
+	detected := make([]detectedVersionWithProvider, 0)
+	for _, mp := range migrationProviders {
+		var versions []migration.DetectedVersion
+		var err error
+		if cache != nil {
+			versions, err = cache.DetectVersions(mp, refresh)
+		} else {
+			versions, err = mp.DetectVersions()
+		}
+		if err != nil {
+			continue // Skip providers that fail
+		}
+		for _, v := range versions {
+			detected = append(detected, detectedVersionWithProvider{
+				DetectedVersion:   v,
+				MigrationProvider: mp,
+			})
+		}
+	}
+
+	return deduplicateByPath(detected)
+}
+
+// migrateListOutput is the JSON document printed by `dtvem migrate <runtime> --json`
+type migrateListOutput struct {
+	Runtime  string                 `json:"runtime"`
+	Versions []migrateListedVersion `json:"versions"`
+}
+
+// migrateListedVersion is a detected installation in the JSON output
+type migrateListedVersion struct {
+	Version   string `json:"version"`
+	Source    string `json:"source"`
+	Path      string `json:"path"`
+	Validated bool   `json:"validated"`
+}
+
+// writeDetectedJSON writes the detected installations to w as JSON
+func writeDetectedJSON(w io.Writer, runtimeName string, detected []detectedVersionWithProvider) error {
+	output := migrateListOutput{
+		Runtime:  runtimeName,
+		Versions: make([]migrateListedVersion, 0, len(detected)),
+	}
+	for _, dv := range detected {
+		output.Versions = append(output.Versions, migrateListedVersion{
+			Version:   dv.Version,
+			Source:    dv.Source,
+			Path:      dv.Path,
+			Validated: dv.Validated,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// packagesSnapshot is the file written by `dtvem migrate --keep-packages-file`: the
+// global packages of each migrated installation, by runtime and version, so the
+// list can be recovered when reinstalling them fails
+type packagesSnapshot struct {
+	Runtimes map[string]map[string]packagesSnapshotEntry `json:"runtimes"`
+}
+
+// packagesSnapshotEntry is the package list of one migrated installation
+type packagesSnapshotEntry struct {
+	Source   string   `json:"source"`
+	Path     string   `json:"path"`
+	Packages []string `json:"packages"`
+	// Command reinstalls the packages by hand
+	Command string `json:"command,omitempty"`
+}
+
+// savePackagesSnapshot records an installation's global packages in the snapshot
+// file at path, keeping the entries of other runtimes and versions already in it
+func savePackagesSnapshot(path string, provider internalRuntime.Provider, dv detectedVersionWithProvider, packages []string) error {
+	snapshot := packagesSnapshot{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return fmt.Errorf("%s is not a package snapshot: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if snapshot.Runtimes == nil {
+		snapshot.Runtimes = make(map[string]map[string]packagesSnapshotEntry)
+	}
+	if snapshot.Runtimes[provider.Name()] == nil {
+		snapshot.Runtimes[provider.Name()] = make(map[string]packagesSnapshotEntry)
+	}
+	snapshot.Runtimes[provider.Name()][dv.Version] = packagesSnapshotEntry{
+		Source:   dv.Source,
+		Path:     dv.Path,
+		Packages: packages,
+		Command:  provider.ManualPackageInstallCommand(packages),
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
 // detectedVersionWithProvider pairs a detected version with its migration provider.
 type detectedVersionWithProvider struct {
 	migration.DetectedVersion
@@ -236,6 +490,30 @@ func deduplicateByPath(versions []detectedVersionWithProvider) []detectedVersion
 	return result
 }
 
+// defaultGlobalChoice returns the 1-based index of the selected version that matches
+// its version manager's default version, or 0 if there is none. A partial default
+// (e.g. "20") matches the newest selected version in that line.
+func defaultGlobalChoice(selected []detectedVersionWithProvider) int {
+	choice := 0
+	for i, dv := range selected {
+		dp, ok := dv.MigrationProvider.(migration.DefaultVersionProvider)
+		if !ok {
+			continue
+		}
+		defaultVersion, ok := dp.DefaultVersion()
+		if !ok {
+			continue
+		}
+		if dv.Version != defaultVersion && !strings.HasPrefix(dv.Version, defaultVersion+".") {
+			continue
+		}
+		if choice == 0 || internalRuntime.CompareVersions(dv.Version, selected[choice-1].Version) > 0 {
+			choice = i + 1
+		}
+	}
+	return choice
+}
+
 // parseSelection parses user selection input like "1,3,5" or "all"
 func parseSelection(input string, maxCount int) []int {
 	indices := make([]int, 0, maxCount)
@@ -295,7 +573,7 @@ func promptCleanupOldInstallations(versions []detectedVersionWithProvider, runti
 
 			// Attempt to execute the uninstall command
 			ui.Progress("Removing %s v%s from %s...", runtimeDisplayName, dv.Version, dv.Source)
-			if err := executeUninstallCommand(command); err != nil {
+			if err := runUninstallCommand(command); err != nil {
 				ui.Error("Failed to remove: %v", err)
 				ui.Info("You can manually remove it with:")
 				ui.Info("  %s", command)
@@ -325,6 +603,9 @@ func promptCleanupOldInstallations(versions []detectedVersionWithProvider, runti
 	}
 }
 
+// runUninstallCommand runs an old installation's uninstall command (replaceable in tests)
+var runUninstallCommand = executeUninstallCommand
+
 // executeUninstallCommand executes the uninstall command for automated cleanup
 func executeUninstallCommand(command string) error {
 	// Parse the command into parts
@@ -342,5 +623,12 @@ func executeUninstallCommand(command string) error {
 }
 
 func init() {
+	addJobsFlag(migrateCmd)
+	migrateCmd.Flags().BoolVar(&migrateList, "list", false, "Only list detected installations, without migrating")
+	migrateCmd.Flags().BoolVar(&migrateJSON, "json", false, "List detected installations as JSON (implies --list)")
+	migrateCmd.Flags().BoolVar(&migrateRefresh, "refresh", false, "Scan for installations again instead of using cached results")
+	migrateCmd.Flags().StringVar(&migrateKeepPackagesFile, "keep-packages-file", "", "Save the detected global packages of each version to this JSON file before reinstalling them")
+	migrateCmd.Flags().BoolVar(&migratePrintCleanup, "print-cleanup", false, "Print the commands that remove old installations instead of running them")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show what would be migrated without installing or removing anything")
 	rootCmd.AddCommand(migrateCmd)
 }
@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/migration"
 )
 
 func TestParseSelection(t *testing.T) {
@@ -179,3 +186,355 @@ func TestParseSelection_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// mockMigrationProvider is a migration provider without a default version
+type mockMigrationProvider struct {
+	name             string
+	uninstallCommand string
+	versions         []migration.DetectedVersion
+	detectErr        error
+}
+
+func (m *mockMigrationProvider) Name() string        { return m.name }
+func (m *mockMigrationProvider) DisplayName() string { return m.name }
+func (m *mockMigrationProvider) Runtime() string     { return "node" }
+func (m *mockMigrationProvider) IsPresent() bool     { return true }
+func (m *mockMigrationProvider) DetectVersions() ([]migration.DetectedVersion, error) {
+	return m.versions, m.detectErr
+}
+func (m *mockMigrationProvider) CanAutoUninstall() bool         { return m.uninstallCommand != "" }
+func (m *mockMigrationProvider) UninstallCommand(string) string { return m.uninstallCommand }
+func (m *mockMigrationProvider) ManualInstructions() string     { return "" }
+
+// mockDefaultMigrationProvider is a migration provider that reports a default version
+type mockDefaultMigrationProvider struct {
+	mockMigrationProvider
+	defaultVersion string
+}
+
+func (m *mockDefaultMigrationProvider) DefaultVersion() (string, bool) {
+	return m.defaultVersion, m.defaultVersion != ""
+}
+
+func TestDefaultGlobalChoice(t *testing.T) {
+	withProvider := func(mp migration.Provider, versions ...string) []detectedVersionWithProvider {
+		result := make([]detectedVersionWithProvider, 0, len(versions))
+		for _, v := range versions {
+			result = append(result, detectedVersionWithProvider{
+				DetectedVersion:   migration.DetectedVersion{Version: v, Source: mp.Name()},
+				MigrationProvider: mp,
+			})
+		}
+		return result
+	}
+
+	nvm := &mockDefaultMigrationProvider{mockMigrationProvider{name: "nvm"}, "20.11.1"}
+	nvmPartial := &mockDefaultMigrationProvider{mockMigrationProvider{name: "nvm"}, "20"}
+	nvmUnset := &mockDefaultMigrationProvider{mockMigrationProvider{name: "nvm"}, ""}
+	system := &mockMigrationProvider{name: "system"}
+
+	tests := []struct {
+		name     string
+		selected []detectedVersionWithProvider
+		want     int
+	}{
+		{
+			name:     "exact default",
+			selected: withProvider(nvm, "18.19.0", "20.11.1", "22.0.0"),
+			want:     2,
+		},
+		{
+			name:     "partial default picks newest match",
+			selected: withProvider(nvmPartial, "20.9.0", "18.19.0", "20.11.1", "2.0.0"),
+			want:     3,
+		},
+		{
+			name:     "default not selected",
+			selected: withProvider(nvm, "18.19.0", "22.0.0"),
+			want:     0,
+		},
+		{
+			name:     "no default configured",
+			selected: withProvider(nvmUnset, "20.11.1"),
+			want:     0,
+		},
+		{
+			name:     "provider without default support",
+			selected: withProvider(system, "20.11.1"),
+			want:     0,
+		},
+		{
+			name:     "mixed providers",
+			selected: append(withProvider(system, "22.0.0"), withProvider(nvm, "20.11.1")...),
+			want:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultGlobalChoice(tt.selected); got != tt.want {
+				t.Errorf("defaultGlobalChoice() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateSelected_DryRun(t *testing.T) {
+	provider := &mockProvider{name: "node", displayName: "Node.js", globalPackages: []string{"typescript"}}
+	nvm := &mockMigrationProvider{name: "nvm", uninstallCommand: "nvm uninstall 20.11.1"}
+	selected := []detectedVersionWithProvider{
+		{DetectedVersion: migration.DetectedVersion{Version: "20.11.1", Source: "nvm"}, MigrationProvider: nvm},
+		{DetectedVersion: migration.DetectedVersion{Version: "18.19.0", Source: "nvm"}, MigrationProvider: nvm},
+	}
+
+	var uninstallCalls []string
+	originalRunner := runUninstallCommand
+	runUninstallCommand = func(command string) error {
+		uninstallCalls = append(uninstallCalls, command)
+		return nil
+	}
+	t.Cleanup(func() { runUninstallCommand = originalRunner })
+
+	if got := migrateSelected(provider, selected, true, defaultJobs); got != len(selected) {
+		t.Errorf("migrateSelected() = %d, want %d", got, len(selected))
+	}
+	printCleanupPlan(selected)
+
+	if len(provider.installCalls) != 0 {
+		t.Errorf("dry run installed versions: %v", provider.installCalls)
+	}
+	if len(provider.packageCalls) != 0 {
+		t.Errorf("dry run reinstalled packages for: %v", provider.packageCalls)
+	}
+	if len(uninstallCalls) != 0 {
+		t.Errorf("dry run ran uninstall commands: %v", uninstallCalls)
+	}
+}
+
+func TestMigrateSelected_Installs(t *testing.T) {
+	provider := &mockProvider{name: "node", displayName: "Node.js", globalPackages: []string{"typescript"}}
+	selected := []detectedVersionWithProvider{
+		{DetectedVersion: migration.DetectedVersion{Version: "20.11.1", Source: "nvm"}, MigrationProvider: &mockMigrationProvider{name: "nvm"}},
+	}
+
+	if got := migrateSelected(provider, selected, false, 1); got != 1 {
+		t.Errorf("migrateSelected() = %d, want 1", got)
+	}
+	if !reflect.DeepEqual(provider.installCalls, []string{"20.11.1"}) {
+		t.Errorf("installCalls = %v, want [20.11.1]", provider.installCalls)
+	}
+	if !reflect.DeepEqual(provider.packageCalls, []string{"20.11.1"}) {
+		t.Errorf("packageCalls = %v, want [20.11.1]", provider.packageCalls)
+	}
+}
+
+func TestDetectVersions(t *testing.T) {
+	nvm := &mockMigrationProvider{name: "nvm", versions: []migration.DetectedVersion{
+		{Version: "20.11.1", Path: "/home/user/.nvm/versions/node/v20.11.1/bin/node", Source: "nvm"},
+	}}
+	system := &mockMigrationProvider{name: "system", versions: []migration.DetectedVersion{
+		{Version: "18.19.0", Path: "/usr/bin/node", Source: "system", Validated: true},
+		{Version: "20.11.1", Path: "/home/user/.nvm/versions/node/v20.11.1/bin/node", Source: "system"},
+	}}
+	failing := &mockMigrationProvider{name: "fnm", detectErr: errors.New("broken")}
+
+	detected := detectVersions([]migration.Provider{nvm, failing, system}, nil, false)
+
+	if len(detected) != 2 {
+		t.Fatalf("detectVersions() returned %d versions, want 2 (duplicates removed)", len(detected))
+	}
+	if detected[0].MigrationProvider != nvm || detected[1].Path != "/usr/bin/node" {
+		t.Errorf("detectVersions() = %+v, want the nvm version then the system version", detected)
+	}
+}
+
+func TestWriteDetectedJSON(t *testing.T) {
+	nvm := &mockMigrationProvider{name: "nvm"}
+	detected := []detectedVersionWithProvider{
+		{
+			DetectedVersion:   migration.DetectedVersion{Version: "20.11.1", Path: "/home/user/.nvm/versions/node/v20.11.1/bin/node", Source: "nvm"},
+			MigrationProvider: nvm,
+		},
+		{
+			DetectedVersion:   migration.DetectedVersion{Version: "18.19.0", Path: "/usr/bin/node", Source: "system", Validated: true},
+			MigrationProvider: nvm,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDetectedJSON(&buf, "node", detected); err != nil {
+		t.Fatalf("writeDetectedJSON() error: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	want := map[string]interface{}{
+		"runtime": "node",
+		"versions": []interface{}{
+			map[string]interface{}{
+				"version":   "20.11.1",
+				"source":    "nvm",
+				"path":      "/home/user/.nvm/versions/node/v20.11.1/bin/node",
+				"validated": false,
+			},
+			map[string]interface{}{
+				"version":   "18.19.0",
+				"source":    "system",
+				"path":      "/usr/bin/node",
+				"validated": true,
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON = %v, want %v", got, want)
+	}
+}
+
+func TestWriteDetectedJSON_NoVersions(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeDetectedJSON(&buf, "python", nil); err != nil {
+		t.Fatalf("writeDetectedJSON() error: %v", err)
+	}
+
+	var got struct {
+		Runtime  string            `json:"runtime"`
+		Versions []json.RawMessage `json:"versions"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if got.Versions == nil {
+		t.Errorf("versions should be an empty array, got %s", buf.String())
+	}
+}
+
+// readPackagesSnapshot reads a --keep-packages-file snapshot
+func readPackagesSnapshot(t *testing.T, path string) packagesSnapshot {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	var snapshot packagesSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("Snapshot is not valid JSON: %v", err)
+	}
+	return snapshot
+}
+
+func TestSavePackagesSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packages.json")
+	node := &mockProvider{name: "node", displayName: "Node.js"}
+	python := &mockProvider{name: "python", displayName: "Python"}
+
+	saves := []struct {
+		provider *mockProvider
+		version  string
+		packages []string
+	}{
+		{node, "20.11.1", []string{"typescript", "eslint"}},
+		{node, "18.19.0", []string{}},
+		{python, "3.12.1", []string{"black"}},
+		// Saving a version again replaces its list
+		{node, "20.11.1", []string{"typescript"}},
+	}
+	for _, s := range saves {
+		dv := detectedVersionWithProvider{DetectedVersion: migration.DetectedVersion{
+			Version: s.version,
+			Source:  "nvm",
+			Path:    "/old/" + s.version,
+		}}
+		if err := savePackagesSnapshot(path, s.provider, dv, s.packages); err != nil {
+			t.Fatalf("savePackagesSnapshot(%s %s) error: %v", s.provider.name, s.version, err)
+		}
+	}
+
+	want := packagesSnapshot{Runtimes: map[string]map[string]packagesSnapshotEntry{
+		"node": {
+			"20.11.1": {Source: "nvm", Path: "/old/20.11.1", Packages: []string{"typescript"}},
+			"18.19.0": {Source: "nvm", Path: "/old/18.19.0", Packages: []string{}},
+		},
+		"python": {
+			"3.12.1": {Source: "nvm", Path: "/old/3.12.1", Packages: []string{"black"}},
+		},
+	}}
+	if got := readPackagesSnapshot(t, path); !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot = %+v, want %+v", got, want)
+	}
+}
+
+func TestSavePackagesSnapshot_NotASnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packages.json")
+	if err := os.WriteFile(path, []byte("typescript\neslint\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	dv := detectedVersionWithProvider{DetectedVersion: migration.DetectedVersion{Version: "20.11.1"}}
+	if err := savePackagesSnapshot(path, &mockProvider{name: "node"}, dv, []string{"typescript"}); err == nil {
+		t.Error("savePackagesSnapshot() should refuse to overwrite a file that is not a snapshot")
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "typescript\neslint\n" {
+		t.Errorf("file was changed to %q", data)
+	}
+}
+
+func TestMigrateSelected_KeepPackagesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packages.json")
+	originalFile := migrateKeepPackagesFile
+	migrateKeepPackagesFile = path
+	t.Cleanup(func() { migrateKeepPackagesFile = originalFile })
+
+	provider := &mockProvider{name: "node", displayName: "Node.js", globalPackages: []string{"typescript"}}
+	selected := []detectedVersionWithProvider{
+		{DetectedVersion: migration.DetectedVersion{Version: "20.11.1", Source: "nvm", Path: "/old/20.11.1"}, MigrationProvider: &mockMigrationProvider{name: "nvm"}},
+	}
+
+	// A dry run writes nothing
+	migrateSelected(provider, selected, true, 1)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote the snapshot: %v", err)
+	}
+
+	migrateSelected(provider, selected, false, 1)
+	entry := readPackagesSnapshot(t, path).Runtimes["node"]["20.11.1"]
+	if !reflect.DeepEqual(entry.Packages, []string{"typescript"}) || entry.Source != "nvm" || entry.Path != "/old/20.11.1" {
+		t.Errorf("snapshot entry = %+v, want the typescript package of the nvm install", entry)
+	}
+}
+
+func TestCleanupOldInstallations_PrintCleanup(t *testing.T) {
+	nvm := &mockMigrationProvider{name: "nvm", uninstallCommand: "nvm uninstall 20.11.1"}
+	system := &mockMigrationProvider{name: "system"}
+	selected := []detectedVersionWithProvider{
+		{DetectedVersion: migration.DetectedVersion{Version: "20.11.1", Source: "nvm", Path: "/home/user/.nvm/versions/node/v20.11.1"}, MigrationProvider: nvm},
+		{DetectedVersion: migration.DetectedVersion{Version: "18.19.0", Source: "system", Path: "/usr/bin/node"}, MigrationProvider: system},
+	}
+
+	var uninstallCalls []string
+	originalRunner := runUninstallCommand
+	runUninstallCommand = func(command string) error {
+		uninstallCalls = append(uninstallCalls, command)
+		return nil
+	}
+	t.Cleanup(func() { runUninstallCommand = originalRunner })
+
+	originalPrintCleanup := migratePrintCleanup
+	migratePrintCleanup = true
+	t.Cleanup(func() { migratePrintCleanup = originalPrintCleanup })
+
+	var out bytes.Buffer
+	cleanupOldInstallations(&out, selected, "Node.js")
+
+	want := "nvm uninstall 20.11.1\n# v18.19.0 (system) needs manual removal: /usr/bin/node\n"
+	if out.String() != want {
+		t.Errorf("cleanup output = %q, want %q", out.String(), want)
+	}
+	if len(uninstallCalls) != 0 {
+		t.Errorf("--print-cleanup ran uninstall commands: %v", uninstallCalls)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// permissionDeniedPath reports whether err is a permission error and, if it
+// names one, the path that couldn't be written
+func permissionDeniedPath(err error) (string, bool) {
+	if !errors.Is(err, fs.ErrPermission) {
+		return "", false
+	}
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Path, true
+	}
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		return linkErr.New, true
+	}
+	return "", true
+}
+
+// permissionGuidance explains how to fix a permission error on path. When path is
+// under the dtvem root, the fix is for the whole root: a root-owned ~/.dtvem left
+// by running dtvem with sudo is the usual cause.
+func permissionGuidance(path, root, goos string) []string {
+	target := root
+	if path != "" && !isWithinDir(path, root) {
+		target = filepath.Dir(path)
+	}
+
+	var lines []string
+	if path != "" {
+		lines = append(lines, fmt.Sprintf("dtvem isn't allowed to write to %s", path))
+	}
+
+	if goos == constants.OSWindows {
+		return append(lines,
+			"This usually happens after running dtvem as Administrator, or when another program holds the files open",
+			fmt.Sprintf("Check the permissions of %s (Properties > Security), or close the programs using it and try again", target))
+	}
+	return append(lines,
+		"This usually happens after running dtvem with sudo, which leaves files owned by root",
+		fmt.Sprintf("Take ownership back with: sudo chown -R \"$USER\" %s", target))
+}
+
+// explainPermissionError prints how to fix err if it is a permission error, and
+// reports whether it was one
+func explainPermissionError(err error) bool {
+	path, ok := permissionDeniedPath(err)
+	if !ok {
+		return false
+	}
+	for _, line := range permissionGuidance(path, config.DefaultPaths().Root, goruntime.GOOS) {
+		ui.Info("%s", line)
+	}
+	return true
+}
+
+// isWithinDir reports whether path is dir or inside it
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+func TestPermissionDeniedPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantPath string
+		wantOK   bool
+	}{
+		{
+			name:     "wrapped path error",
+			err:      fmt.Errorf("failed to create shims: %w", &fs.PathError{Op: "open", Path: "/home/me/.dtvem/shims/node", Err: fs.ErrPermission}),
+			wantPath: "/home/me/.dtvem/shims/node",
+			wantOK:   true,
+		},
+		{
+			name:     "link error",
+			err:      &os.LinkError{Op: "rename", Old: "/tmp/x", New: "/home/me/.dtvem/versions/node/20.0.0", Err: fs.ErrPermission},
+			wantPath: "/home/me/.dtvem/versions/node/20.0.0",
+			wantOK:   true,
+		},
+		{
+			name:   "permission error without a path",
+			err:    fmt.Errorf("write: %w", fs.ErrPermission),
+			wantOK: true,
+		},
+		{
+			name: "other error",
+			err:  &fs.PathError{Op: "open", Path: "/missing", Err: fs.ErrNotExist},
+		},
+		{
+			name: "untyped error",
+			err:  errors.New("permission denied"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := permissionDeniedPath(tt.err)
+			if path != tt.wantPath || ok != tt.wantOK {
+				t.Errorf("permissionDeniedPath() = (%q, %v), want (%q, %v)", path, ok, tt.wantPath, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPermissionGuidance(t *testing.T) {
+	root := filepath.Join("home", "me", ".dtvem")
+
+	t.Run("inside the root", func(t *testing.T) {
+		lines := permissionGuidance(filepath.Join(root, "shims", "node"), root, "linux")
+		text := strings.Join(lines, "\n")
+		if !strings.Contains(text, filepath.Join(root, "shims", "node")) {
+			t.Errorf("guidance = %q, want it to name the path", text)
+		}
+		if !strings.Contains(text, "sudo chown -R \"$USER\" "+root) {
+			t.Errorf("guidance = %q, want a chown of the dtvem root", text)
+		}
+	})
+
+	t.Run("outside the root", func(t *testing.T) {
+		path := filepath.Join("tmp", "dtvem-node-20.0.0", "node")
+		text := strings.Join(permissionGuidance(path, root, "darwin"), "\n")
+		if !strings.Contains(text, "chown -R \"$USER\" "+filepath.Dir(path)) {
+			t.Errorf("guidance = %q, want a chown of the path's directory", text)
+		}
+	})
+
+	t.Run("windows", func(t *testing.T) {
+		text := strings.Join(permissionGuidance(filepath.Join(root, "shims"), root, constants.OSWindows), "\n")
+		if strings.Contains(text, "chown") || !strings.Contains(text, "Properties > Security") {
+			t.Errorf("guidance = %q, want Windows advice", text)
+		}
+	})
+}
+
+func TestExplainPermissionError_NonWritableRoot(t *testing.T) {
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("directory permissions work differently on Windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+
+	readOnly := t.TempDir()
+	if err := os.Chmod(readOnly, 0555); err != nil {
+		t.Fatalf("Failed to make %s read-only: %v", readOnly, err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(readOnly, 0755) })
+
+	t.Setenv("DTVEM_ROOT", filepath.Join(readOnly, "dtvem"))
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	err := config.EnsureDirectories()
+	if err == nil {
+		t.Fatal("EnsureDirectories() in a read-only directory should fail")
+	}
+
+	path, ok := permissionDeniedPath(err)
+	if !ok {
+		t.Fatalf("permissionDeniedPath(%v) = false, want a permission error", err)
+	}
+	if !strings.HasPrefix(path, readOnly) {
+		t.Errorf("path = %q, want it inside %s", path, readOnly)
+	}
+	if !explainPermissionError(err) {
+		t.Error("explainPermissionError() = false, want guidance to be printed")
+	}
+	if code := errorDetail(err).Code; code != "permission_denied" {
+		t.Errorf("errorDetail().Code = %q, want permission_denied", code)
+	}
+}
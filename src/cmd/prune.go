@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// defaultPruneKeep is how many of the newest versions of each runtime prune keeps
+const defaultPruneKeep = 3
+
+var (
+	pruneKeepFlag   int
+	pruneDryRunFlag bool
+	pruneYesFlag    bool
+)
+
+// prunePlan is the installed versions of a runtime that prune removes
+type prunePlan struct {
+	Provider runtime.Provider
+	Remove   []string
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune [runtime]",
+	Short: "Uninstall all but the newest installed versions",
+	Long: `Uninstall old versions, keeping the newest few of each runtime (or only the
+given runtime).
+
+The global version and the version a local config resolves to in the current
+directory are always kept, on top of the newest --keep versions. Shims are
+regenerated once after all versions are removed.
+
+Examples:
+  dtvem prune
+  dtvem prune node --keep 1
+  dtvem prune --dry-run   # Show what would be removed
+  dtvem prune --yes       # Skip confirmation prompt`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if pruneKeepFlag < 0 {
+			reportError(fmt.Errorf("--keep must be 0 or more, got %d", pruneKeepFlag))
+			os.Exit(1)
+		}
+
+		providers := runtime.GetAll()
+		if len(args) == 1 {
+			provider, err := runtime.Get(args[0])
+			if err != nil {
+				reportError(err)
+				ui.Info("Available runtimes: %s", availableRuntimes())
+				os.Exit(1)
+			}
+			providers = []runtime.Provider{provider}
+		}
+
+		var plans []prunePlan
+		for _, provider := range providers {
+			plan, err := planPrune(provider, pruneKeepFlag)
+			if err != nil {
+				ui.Error("%s: %v", provider.DisplayName(), err)
+				continue
+			}
+			if len(plan.Remove) > 0 {
+				plans = append(plans, plan)
+			}
+		}
+
+		if len(plans) == 0 {
+			ui.Info("Nothing to prune")
+			return
+		}
+
+		total := 0
+		ui.Header("Versions to remove")
+		for _, plan := range plans {
+			ui.Info("%s: %s", plan.Provider.DisplayName(), strings.Join(plan.Remove, ", "))
+			total += len(plan.Remove)
+		}
+
+		if pruneDryRunFlag {
+			ui.Info("Dry run: %d version(s) would be removed", total)
+			return
+		}
+
+		if !pruneYesFlag && !confirmPrune(total) {
+			ui.Info("Prune canceled")
+			return
+		}
+
+		if failed := runPrune(plans); failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// planPrune picks the installed versions of a runtime to remove: all but the newest
+// keep versions, never removing the global version or the local one for this directory
+func planPrune(provider runtime.Provider, keep int) (prunePlan, error) {
+	plan := prunePlan{Provider: provider}
+
+	installed, err := provider.ListInstalled()
+	if err != nil {
+		return plan, err
+	}
+
+	versions := make([]runtime.AvailableVersion, 0, len(installed))
+	names := make([]string, 0, len(installed))
+	for _, v := range installed {
+		versions = append(versions, runtime.AvailableVersion{Version: v.Version})
+		names = append(names, v.Version.Raw)
+	}
+	runtime.SortVersionsDesc(versions)
+
+	protected := protectedVersions(provider, names)
+	for i, v := range versions {
+		if i < keep || protected[runtime.NormalizeVersion(v.Version.Raw)] {
+			continue
+		}
+		plan.Remove = append(plan.Remove, v.Version.Raw)
+	}
+	return plan, nil
+}
+
+// protectedVersions returns the installed versions prune must keep: the global version
+// and the one the current directory resolves to, with wildcard pins matched against installed.
+// Versions are keyed normalized, so a legacy "v"-prefixed install directory is still kept
+func protectedVersions(provider runtime.Provider, installed []string) map[string]bool {
+	protected := make(map[string]bool)
+
+	if global, err := provider.GlobalVersion(); err == nil && global != "" {
+		if runtime.IsVersionPin(global) {
+			if match, ok := runtime.MatchVersionPin(global, installed); ok {
+				protected[runtime.NormalizeVersion(match)] = true
+			}
+		} else {
+			protected[runtime.NormalizeVersion(global)] = true
+		}
+	}
+
+	if resolved, err := config.ResolveVersionWithSource(provider.Name()); err == nil {
+		protected[runtime.NormalizeVersion(resolved.Version)] = true
+	}
+
+	return protected
+}
+
+// runPrune removes the planned versions and regenerates shims once at the end.
+// It returns how many versions could not be removed.
+func runPrune(plans []prunePlan) int {
+	removed, failed := 0, 0
+	for _, plan := range plans {
+		for _, version := range plan.Remove {
+			versionPath := config.RuntimeVersionPath(plan.Provider.Name(), version)
+			if err := removeVersion(plan.Provider, version, versionPath); err != nil {
+				ui.Error("%s", uninstallFailureMessage(plan.Provider, versionPath, err))
+				failed++
+				continue
+			}
+			ui.Success("Removed %s v%s", plan.Provider.DisplayName(), version)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		manager, err := shim.NewManager()
+		if err == nil {
+			_, _, err = manager.RehashAndPrune()
+		}
+		if err != nil {
+			ui.Warning("Could not regenerate shims")
+			ui.Warning("You may need to run 'dtvem reshim' manually")
+		} else {
+			ui.Success("Shims regenerated")
+		}
+	}
+
+	if failed > 0 {
+		ui.Warning("Removed %d version(s); %d could not be removed", removed, failed)
+	} else {
+		ui.Success("Removed %d version(s)", removed)
+	}
+	return failed
+}
+
+// confirmPrune asks the user whether to remove the listed versions
+func confirmPrune(count int) bool {
+	ui.Printf("\nUninstall %d version(s)? [y/N]: ", count)
+
+	var response string
+	_, _ = fmt.Scanln(&response)
+	response = strings.ToLower(strings.TrimSpace(response))
+
+	return response == constants.ResponseY || response == constants.ResponseYes
+}
+
+func init() {
+	pruneCmd.Flags().IntVar(&pruneKeepFlag, "keep", defaultPruneKeep, "Number of the newest versions of each runtime to keep")
+	pruneCmd.Flags().BoolVar(&pruneDryRunFlag, "dry-run", false, "Show what would be removed without removing anything")
+	pruneCmd.Flags().BoolVarP(&pruneYesFlag, "yes", "y", false, "Skip confirmation prompt")
+	rootCmd.AddCommand(pruneCmd)
+}
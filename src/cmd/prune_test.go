@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// mockPruneProvider is a mock provider with a list of installed versions
+type mockPruneProvider struct {
+	mockUninstallProvider
+	versions []string
+}
+
+func (m *mockPruneProvider) ListInstalled() ([]runtime.InstalledVersion, error) {
+	installed := make([]runtime.InstalledVersion, 0, len(m.versions))
+	for _, v := range m.versions {
+		installed = append(installed, runtime.InstalledVersion{Version: runtime.NewVersion(v)})
+	}
+	return installed, nil
+}
+
+func TestPlanPrune(t *testing.T) {
+	installed := []string{"1.0.0", "2.0.0", "10.0.0", "3.0.0", "2.5.0"}
+
+	tests := []struct {
+		name       string
+		keep       int
+		global     string
+		local      string
+		wantRemove []string
+	}{
+		{"keeps the newest", 2, "", "", []string{"2.5.0", "2.0.0", "1.0.0"}},
+		{"keeps the global version", 2, "1.0.0", "", []string{"2.5.0", "2.0.0"}},
+		{"keeps the version a global pin matches", 2, "2.x", "", []string{"2.0.0", "1.0.0"}},
+		{"keeps the local version", 2, "", "2.0.0", []string{"2.5.0", "1.0.0"}},
+		{"keep zero leaves only protected versions", 0, "3.0.0", "", []string{"10.0.0", "2.5.0", "2.0.0", "1.0.0"}},
+		{"nothing beyond keep", 5, "", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupDebugEnv(t)
+			if tt.local != "" {
+				if err := config.SetLocalVersion("prunert", tt.local); err != nil {
+					t.Fatalf("SetLocalVersion() error: %v", err)
+				}
+			}
+
+			provider := &mockPruneProvider{versions: installed}
+			provider.name = "prunert"
+			provider.globalVersion = tt.global
+
+			plan, err := planPrune(provider, tt.keep)
+			if err != nil {
+				t.Fatalf("planPrune() error: %v", err)
+			}
+			if !reflect.DeepEqual(plan.Remove, tt.wantRemove) {
+				t.Errorf("planPrune() removes %v, want %v", plan.Remove, tt.wantRemove)
+			}
+		})
+	}
+}
+
+func TestPlanPrune_LegacyVersionDirectory(t *testing.T) {
+	installed := []string{"1.0.0", "v2.0.0", "3.0.0"}
+
+	tests := []struct {
+		name   string
+		global string
+		local  string
+	}{
+		{"global version", "2.0.0", ""},
+		{"global pin", "2.x", ""},
+		{"local version", "", "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupDebugEnv(t)
+			if tt.local != "" {
+				if err := config.SetLocalVersion("prunert", tt.local); err != nil {
+					t.Fatalf("SetLocalVersion() error: %v", err)
+				}
+			}
+
+			provider := &mockPruneProvider{versions: installed}
+			provider.name = "prunert"
+			provider.globalVersion = tt.global
+
+			plan, err := planPrune(provider, 1)
+			if err != nil {
+				t.Fatalf("planPrune() error: %v", err)
+			}
+			if want := []string{"1.0.0"}; !reflect.DeepEqual(plan.Remove, want) {
+				t.Errorf("planPrune() removes %v, want %v", plan.Remove, want)
+			}
+		})
+	}
+}
+
+func TestRunPrune(t *testing.T) {
+	setupDebugEnv(t)
+
+	provider := &mockPruneProvider{}
+	provider.name = "prunert"
+	provider.canUninstall = true
+
+	plans := []prunePlan{{Provider: provider, Remove: []string{"2.0.0", "1.0.0"}}}
+	if failed := runPrune(plans); failed != 0 {
+		t.Errorf("runPrune() failed = %d, want 0", failed)
+	}
+	if want := []string{"2.0.0", "1.0.0"}; !reflect.DeepEqual(provider.uninstallCalls, want) {
+		t.Errorf("Uninstall calls = %v, want %v", provider.uninstallCalls, want)
+	}
+
+	provider.uninstallCalls = nil
+	provider.uninstallErr = errors.New("in use")
+	if failed := runPrune(plans); failed != 2 {
+		t.Errorf("runPrune() failed = %d, want 2", failed)
+	}
+}
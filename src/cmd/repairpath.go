@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	goruntime "runtime"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/path"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	repairPathYes   bool
+	repairPathPrint bool
+	repairPathUser  bool
+)
+
+var repairPathCmd = &cobra.Command{
+	Use:   "repair-path",
+	Short: "Put the dtvem shims first in your PATH",
+	Long: `Add the dtvem shims directory to your PATH, or move it to the front when
+another directory comes before it.
+
+On Windows this edits the System PATH in the registry (administrator rights are
+requested when needed), or the user PATH with --user. On other platforms it makes
+the dtvem line the last PATH change in your shell config; running it again never
+adds a second line.
+
+Use --print to show the change without making it, e.g. to add the line to your
+shell config yourself.
+
+Examples:
+  dtvem repair-path
+  dtvem repair-path --print >> ~/.bashrc
+  dtvem repair-path --user      # Windows, no administrator rights needed`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if repairPathUser && goruntime.GOOS != constants.OSWindows {
+			ui.Error("--user only applies on Windows; shell configs are always per user")
+			os.Exit(1)
+		}
+
+		shimsDir := path.ShimsDir()
+		opts := path.RepairOptions{
+			SkipConfirmation: repairPathYes,
+			PrintOnly:        repairPathPrint,
+			UserScope:        repairPathUser,
+		}
+
+		if err := path.RepairPath(shimsDir, opts); err != nil {
+			ui.Error("Failed to repair PATH: %v", err)
+			ui.Info("You can manually add %s to the front of your PATH", shimsDir)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	repairPathCmd.Flags().BoolVarP(&repairPathYes, "yes", "y", false, "Skip confirmation prompts")
+	repairPathCmd.Flags().BoolVar(&repairPathPrint, "print", false, "Print the PATH change instead of making it")
+	repairPathCmd.Flags().BoolVar(&repairPathUser, "user", false, "Edit the user PATH instead of the System PATH (Windows only)")
+	rootCmd.AddCommand(repairPathCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"os/exec"
 	goruntime "runtime"
 	"strings"
@@ -31,14 +32,14 @@ Example:
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		runtimeName := args[0]
-		version := args[1]
+		version := runtime.NormalizeVersion(args[1])
 
 		// Verify the runtime exists
 		provider, err := runtime.Get(runtimeName)
 		if err != nil {
-			ui.Error("Unknown runtime: %s", runtimeName)
-			ui.Info("Available runtimes: node, python, ruby")
-			return
+			reportError(err)
+			ui.Info("Available runtimes: %s", availableRuntimes())
+			os.Exit(1)
 		}
 
 		// Get current platform
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var reshimRuntimeFlag string
+
 var reshimCmd = &cobra.Command{
 	Use:   "reshim",
 	Short: "Regenerate shim binaries",
@@ -21,36 +24,53 @@ var reshimCmd = &cobra.Command{
 This command scans all installed runtimes and creates shims for their executables.
 Run this command after installing new versions or if shims become corrupted.
 
-Example:
-  dtvem reshim`,
+With --runtime, only the installed versions of that runtime are scanned: its shims
+are recreated and the ones no longer provided by any of its versions are removed,
+leaving the shims of other runtimes alone.
+
+Examples:
+  dtvem reshim
+  dtvem reshim --runtime ruby   # After 'gem install'`,
 	Run: func(cmd *cobra.Command, args []string) {
+		// Validate the runtime before touching any shims
+		if reshimRuntimeFlag != "" {
+			if _, err := runtime.Get(reshimRuntimeFlag); err != nil {
+				reportError(err)
+				ui.Info("Available runtimes: %s", availableRuntimes())
+				os.Exit(1)
+			}
+		}
+
 		// Ensure directories exist
 		if err := config.EnsureDirectories(); err != nil {
-			ui.Error("Failed to create directories: %v", err)
-			return
+			reportError(fmt.Errorf("failed to create directories: %w", err))
+			os.Exit(1)
 		}
 
 		// Create shim manager
 		manager, err := shim.NewManager()
 		if err != nil {
-			ui.Error("%v", err)
+			reportError(err)
 			ui.Info("Note: Make sure dtvem-shim executable is built and available")
-			return
+			os.Exit(1)
 		}
 
-		// Collect display names as we process
-		displayNames := make(map[string]string)
-
 		// Regenerate shims with per-runtime progress
-		result, err := manager.RehashWithCallback(func(runtimeName, displayName string) {
-			displayNames[runtimeName] = displayName
+		progress := func(runtimeName, displayName string) {
 			ui.Info("Regenerating shims for %s...", displayName)
-		})
+		}
+		var result *shim.RehashResult
+		var removed []string
+		if reshimRuntimeFlag != "" {
+			result, removed, err = manager.RehashRuntime(reshimRuntimeFlag, progress)
+		} else {
+			result, err = manager.RehashWithCallback(progress)
+		}
 
 		if err != nil {
 			fmt.Println()
-			ui.Error("%v", err)
-			return
+			reportError(err)
+			os.Exit(1)
 		}
 
 		fmt.Println()
@@ -88,12 +108,22 @@ Example:
 			table.AddRow(displayName, shimList)
 		}
 
-		fmt.Println(table.Render())
-		fmt.Println()
+		if len(runtimeNames) > 0 {
+			fmt.Println(table.Render())
+			fmt.Println()
+		}
+		if len(removed) > 0 {
+			ui.Info("Removed %d stale shim(s): %s", len(removed), strings.Join(removed, ", "))
+		}
+		if reshimRuntimeFlag != "" && result.TotalShims == 0 {
+			ui.Warning("No %s versions installed - nothing to reshim", reshimRuntimeFlag)
+			return
+		}
 		ui.Success("Created %d shims for %d runtime(s)", result.TotalShims, len(result.ShimsByRuntime))
 	},
 }
 
 func init() {
+	reshimCmd.Flags().StringVar(&reshimRuntimeFlag, "runtime", "", "Only regenerate the shims of this runtime")
 	rootCmd.AddCommand(reshimCmd)
 }
@@ -4,37 +4,99 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/dtvem/dtvem/src/internal/tui"
 	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/dtvem/dtvem/src/internal/updatecheck"
 	"github.com/spf13/cobra"
 )
 
 var verbose bool
 
+// updateNotice yields the update notice from the background update check, if one was started
+var updateNotice func() string
+
 var rootCmd = &cobra.Command{
 	Use:   "dtvem",
 	Short: "Developer Tools Virtual Environment Manager",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		ui.SetVerbose(verbose)
+		if ui.IsInteractive() {
+			updateNotice = updatecheck.Start(Version)
+		}
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		printUpdateNotice()
 	},
 }
 
+// printUpdateNotice prints the result of the background update check to stderr
+func printUpdateNotice() {
+	if updateNotice == nil {
+		return
+	}
+	if notice := updateNotice(); notice != "" {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, ui.DimText(notice))
+		fmt.Fprintln(os.Stderr, ui.DimText(fmt.Sprintf("Set %s=1 to disable this check", updatecheck.DisableEnvVar)))
+	}
+}
+
 func Execute() {
 	// Check for --version or -v flag before Cobra parses
-	for _, arg := range os.Args[1:] {
-		if arg == "--version" || arg == "-v" {
-			versionCmd.Run(versionCmd, []string{})
-			return
-		}
+	if isVersionRequest(os.Args[1:]) {
+		versionCmd.Run(versionCmd, []string{})
+		return
 	}
 
+	// In JSON output mode failures are reported as JSON, not as Cobra's text
+	jsonOutput := ui.IsJSONOutput()
+	rootCmd.SilenceErrors = jsonOutput
+	rootCmd.SilenceUsage = jsonOutput
+
+	defer func() {
+		if r := recover(); r != nil {
+			reportPanic(r)
+		}
+	}()
+
 	if err := rootCmd.Execute(); err != nil {
-		// Error already printed by Cobra, just exit with error code
+		// Cobra already printed the error unless it was silenced for JSON output
+		if jsonOutput {
+			reportError(err)
+		}
 		os.Exit(1)
 	}
 }
 
+// isVersionRequest reports whether the command line asks for dtvem's version.
+// Arguments after a subcommand with its own --version flag belong to it, as in
+// 'dtvem interpreter node --version 22.0.0' and 'dtvem exec node --version'.
+func isVersionRequest(args []string) bool {
+	for _, arg := range args {
+		switch {
+		case arg == "--version" || arg == "-v":
+			return true
+		case arg == "--":
+			return false
+		case !strings.HasPrefix(arg, "-") && hasOwnVersionFlag(arg):
+			return false
+		}
+	}
+	return false
+}
+
+// hasOwnVersionFlag reports whether the named subcommand defines a --version flag
+func hasOwnVersionFlag(name string) bool {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return cmd.Flags().Lookup("version") != nil
+		}
+	}
+	return false
+}
+
 func init() {
 	// Hide the completion command until we implement it
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true
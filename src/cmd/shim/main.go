@@ -2,16 +2,15 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"syscall"
 
 	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/manifest"
 	"github.com/dtvem/dtvem/src/internal/path"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/shim"
@@ -35,12 +34,12 @@ func main() {
 
 func runShim() error {
 	// Get the name of this shim (e.g., "python", "node", "npm")
-	shimName := getShimName()
+	shimName := shim.NameFromPath(os.Args[0])
 	ui.Debug("Shim invoked: %s", shimName)
 	ui.Debug("Arguments: %v", os.Args[1:])
 
 	// Determine which runtime this shim belongs to
-	runtimeName := mapShimToRuntime(shimName)
+	runtimeName := shim.MapToRuntime(shimName)
 	ui.Debug("Mapped to runtime: %s", runtimeName)
 
 	// Get the runtime provider (using ShimProvider interface for minimal dependencies)
@@ -59,6 +58,11 @@ func runShim() error {
 	}
 	ui.Debug("Resolved version: %s", version)
 
+	// The reserved "system" version bypasses dtvem-managed versions
+	if runtime.IsSystemVersion(version) {
+		return runSystemVersion(shimName, provider)
+	}
+
 	// Check if the version is installed
 	installed, err := provider.IsInstalled(version)
 	if err != nil {
@@ -67,9 +71,9 @@ func runShim() error {
 
 	if !installed {
 		ui.Debug("Version %s is not installed", version)
-		ui.Error("%s %s is configured but not installed", provider.DisplayName(), version)
-		ui.Info("To install, run: dtvem install %s %s", runtimeName, version)
-		return fmt.Errorf("version not installed")
+		if version, err = installMissingVersion(provider, version); err != nil {
+			return err
+		}
 	}
 
 	// Get the path to the actual executable
@@ -77,12 +81,29 @@ func runShim() error {
 	if err != nil {
 		return fmt.Errorf("could not find %s %s executable: %w", runtimeName, version, err)
 	}
+	if resolver, ok := provider.(runtime.ShimExecutableResolver); ok {
+		execPath, err = resolver.ShimExecutablePath(version, shimName)
+		if err != nil {
+			return err
+		}
+	}
 	ui.Debug("Base executable path: %s", execPath)
 
 	// If the shim name differs from the base runtime name,
 	// we might need to adjust the executable path
 	// (e.g., python3 -> python3, pip -> pip, npm -> npm)
-	execPath = adjustExecutablePath(execPath, shimName, runtimeName)
+	resolution, err := shim.ResolveExecutable(execPath, shimName, runtimeName)
+	if err != nil {
+		ui.Debug("Executable resolution failed: %v", err)
+		ui.Error("%s was not found in %s %s", shimName, provider.DisplayName(), version)
+		ui.Info("The version that provided it may have been uninstalled")
+		ui.Info("Run 'dtvem reshim' to remove stale shims")
+		return fmt.Errorf("%s not found", shimName)
+	}
+	if resolution.SystemFallback {
+		return runSystemFallback(shimName, resolution.Path, provider.DisplayName(), version)
+	}
+	execPath = resolution.Path
 	ui.Debug("Final executable path: %s", execPath)
 
 	// Get provider-specific environment variables (e.g., LD_LIBRARY_PATH for Ruby)
@@ -101,17 +122,17 @@ func runShim() error {
 	// Execute the actual binary
 	if needsReshim {
 		// Need to run code after execution, so use exec.Command
-		exitCode := executeCommandWithWait(execPath, os.Args[1:], providerEnv)
+		exitCode := shim.ExecAndWait(execPath, os.Args[1:], providerEnv)
 
 		// If command succeeded, prompt for reshim
 		if exitCode == 0 {
-			promptReshim()
+			promptReshim(runtimeName)
 		}
 
 		os.Exit(exitCode)
 	} else {
 		// Normal execution - use syscall.Exec on Unix for efficiency
-		if err := executeCommand(execPath, os.Args[1:], providerEnv); err != nil {
+		if err := shim.Exec(execPath, os.Args[1:], providerEnv); err != nil {
 			return fmt.Errorf("failed to execute %s: %w", execPath, err)
 		}
 	}
@@ -119,11 +140,100 @@ func runShim() error {
 	return nil
 }
 
+// installMissingVersion installs a configured version that isn't installed yet, so a fresh
+// checkout works on the first command. It only installs when shouldAutoInstall agrees.
+// A wildcard pin such as "20.x" installs the newest patch release it matches, and the
+// installed version is returned.
+func installMissingVersion(provider runtime.ShimProvider, version string) (string, error) {
+	ui.Warning("%s %s is configured but not installed", provider.DisplayName(), version)
+
+	if err := runtime.CheckSupportedVersion(provider, version); err != nil {
+		return "", err
+	}
+
+	installer, ok := provider.(runtime.Provider)
+	if !ok {
+		ui.Info("To install, run: dtvem install %s %s", provider.Name(), version)
+		return "", fmt.Errorf("version not installed")
+	}
+
+	exact, err := runtime.ResolvePartialVersion(installer, version, manifest.CurrentPlatform())
+	if err != nil {
+		return "", err
+	}
+
+	if !shouldAutoInstall(provider.DisplayName(), exact, ui.IsInteractive()) {
+		ui.Info("To install, run: dtvem install %s %s", provider.Name(), version)
+		return "", fmt.Errorf("version not installed")
+	}
+
+	if err := installer.Install(exact); err != nil {
+		return "", fmt.Errorf("failed to install %s %s: %w", provider.DisplayName(), exact, err)
+	}
+	fmt.Fprintln(os.Stderr) // Empty line for spacing
+	return exact, nil
+}
+
+// shouldAutoInstall decides whether the shim installs a missing configured version.
+// DTVEM_AUTO_INSTALL=true (or the auto-install setting) installs without asking and false
+// never installs. When it is unset, the user is only asked if stdin is a terminal, so scripts never block on a prompt.
+func shouldAutoInstall(displayName, version string, interactive bool) bool {
+	if config.Setting(config.SettingAutoInstall) == "" && !interactive {
+		return false
+	}
+	return ui.PromptInstall(displayName, version)
+}
+
+// findInSystemPath finds a command on PATH, skipping dtvem's shims directory
+var findInSystemPath = path.LookPathExcludingShims
+
+// systemVersionPath finds the system installation of a command for a runtime whose
+// configured version is "system"
+func systemVersionPath(shimName string, provider runtime.ShimProvider) (string, error) {
+	systemPath := findInSystemPath(shimName)
+	if systemPath == "" {
+		return "", fmt.Errorf("%s is set to the system version, but no system %s was found in PATH", provider.DisplayName(), shimName)
+	}
+	return systemPath, nil
+}
+
+// runSystemVersion runs the system installation of a command for a runtime whose
+// configured version is "system"
+func runSystemVersion(shimName string, provider runtime.ShimProvider) error {
+	systemPath, err := systemVersionPath(shimName, provider)
+	if err != nil {
+		ui.Info("Install %s on the system, or choose a dtvem version: dtvem local %s <version>", provider.DisplayName(), provider.Name())
+		return err
+	}
+	ui.Debug("Using system installation: %s", systemPath)
+
+	// Execute the system version (no provider env needed for system installations)
+	if err := shim.Exec(systemPath, os.Args[1:], nil); err != nil {
+		return fmt.Errorf("failed to execute system %s: %w", shimName, err)
+	}
+	return nil
+}
+
+// runSystemFallback runs a system installation of a command that is missing from the
+// configured version, e.g. a package shim left behind after its version was uninstalled.
+func runSystemFallback(shimName, systemPath, displayName, version string) error {
+	ui.Warning("%s was not found in %s %s", shimName, displayName, version)
+	ui.Info("Using system installation: %s", systemPath)
+	ui.Info("Run 'dtvem reshim' to remove stale shims")
+	fmt.Fprintln(os.Stderr) // Empty line for spacing
+
+	// Execute the system version (no provider env needed for system installations)
+	if err := shim.Exec(systemPath, os.Args[1:], nil); err != nil {
+		return fmt.Errorf("failed to execute system %s: %w", shimName, err)
+	}
+	return nil
+}
+
 // handleNoConfiguredVersion handles the case when no dtvem version is configured
 // It attempts to fallback to system PATH or prompts for installation
 func handleNoConfiguredVersion(shimName, runtimeName string, provider runtime.ShimProvider) error {
 	// Try to find the executable deeper in PATH (system installation)
-	systemPath := path.LookPathExcludingShims(shimName)
+	systemPath := findInSystemPath(shimName)
 
 	if systemPath != "" {
 		// Found system installation - use it
@@ -134,7 +244,7 @@ func handleNoConfiguredVersion(shimName, runtimeName string, provider runtime.Sh
 		fmt.Fprintln(os.Stderr) // Empty line for spacing
 
 		// Execute the system version (no provider env needed for system installations)
-		if err := executeCommand(systemPath, os.Args[1:], nil); err != nil {
+		if err := shim.Exec(systemPath, os.Args[1:], nil); err != nil {
 			return fmt.Errorf("failed to execute system %s: %w", shimName, err)
 		}
 		return nil
@@ -155,242 +265,75 @@ func handleNoConfiguredVersion(shimName, runtimeName string, provider runtime.Sh
 	return fmt.Errorf("no version configured")
 }
 
-// getShimName returns the name of this shim binary
-func getShimName() string {
-	shimPath := os.Args[0]
-	shimName := filepath.Base(shimPath)
-
-	// Remove .exe extension on Windows
-	shimName = strings.TrimSuffix(shimName, ".exe")
+// reshimAction is what the shim does about shims after global packages changed
+type reshimAction int
 
-	return shimName
-}
-
-// mapShimToRuntime maps a shim name to its runtime
-// For example: python3 -> python, pip -> python, npm -> node, tsc -> node
-// First checks the shim map cache (generated by reshim), then falls back
-// to querying registered providers.
-func mapShimToRuntime(shimName string) string {
-	// First, try the shim map cache for O(1) lookup
-	// This handles both core shims and dynamically installed packages (tsc, eslint, black, etc.)
-	if runtimeName, ok := shim.LookupRuntime(shimName); ok {
-		return runtimeName
-	}
-
-	// Fall back to provider-based lookup if cache is missing or doesn't have the shim
-	// Get all registered providers (using ShimProvider interface)
-	providers := runtime.GetAllShimProviders()
-
-	// Check each provider's shims for an exact match first
-	for _, provider := range providers {
-		for _, s := range provider.Shims() {
-			if s == shimName {
-				return provider.Name()
-			}
-		}
-	}
-
-	// Check for prefix match (e.g., python3 -> python)
-	for _, provider := range providers {
-		for _, s := range provider.Shims() {
-			if strings.HasPrefix(shimName, s) {
-				return provider.Name()
-			}
-		}
-	}
-
-	// Default: use shim name as runtime name
-	return shimName
-}
-
-// adjustExecutablePath adjusts the executable path based on the shim name
-// For example, if shim is "pip" but base executable is "python",
-// we need to find "pip" in the same directory or Scripts subdirectory
-func adjustExecutablePath(execPath, shimName, runtimeName string) string {
-	// If shim name matches runtime name, use the path as-is
-	if shimName == runtimeName {
-		return execPath
-	}
-
-	// Otherwise, try to find the related executable
-	// For example: if execPath is /path/to/python and shimName is pip,
-	// look for /path/to/pip
-	dir := filepath.Dir(execPath)
+const (
+	reshimRun reshimAction = iota
+	reshimSkip
+	reshimPrompt
+)
 
-	// Directories to search (in order)
-	searchDirs := []string{
-		dir,                                 // Same directory as runtime executable
-		filepath.Join(dir, "Scripts"),       // Python Scripts directory (Windows)
-		filepath.Join(dir, "..", "Scripts"), // Alternative Python Scripts location
+// decideReshim decides what the shim does after global packages were installed or
+// removed. DTVEM_AUTO_RESHIM=true (or the auto-reshim setting) reshims without asking
+// and false skips it. When it is unset, the user is only asked if stdin is a terminal;
+// otherwise the shims are updated, since nobody is there to answer the prompt.
+func decideReshim(interactive bool) reshimAction {
+	switch config.Setting(config.SettingAutoReshim) {
+	case "true":
+		return reshimRun
+	case "false":
+		return reshimSkip
 	}
-
-	// On Windows, try multiple extensions
-	if os.PathSeparator == '\\' {
-		for _, searchDir := range searchDirs {
-			newExec := filepath.Join(searchDir, shimName)
-
-			// Try .cmd first (npm, npx use .cmd on Windows)
-			if _, err := os.Stat(newExec + ".cmd"); err == nil {
-				return newExec + ".cmd"
-			}
-			// Try .exe
-			if _, err := os.Stat(newExec + ".exe"); err == nil {
-				return newExec + ".exe"
-			}
-		}
-	} else {
-		// On Unix, check if the file exists as-is
-		for _, searchDir := range searchDirs {
-			newExec := filepath.Join(searchDir, shimName)
-			if _, err := os.Stat(newExec); err == nil {
-				return newExec
-			}
-		}
+	if !interactive {
+		return reshimRun
 	}
-
-	// If not found, return original path
-	// The runtime provider should have returned the correct path
-	return execPath
+	return reshimPrompt
 }
 
-// executeCommand executes a command with the given arguments and provider environment
-func executeCommand(execPath string, args []string, providerEnv map[string]string) error {
-	// Build full args (executable name + arguments)
-	fullArgs := append([]string{execPath}, args...)
-
-	// Get current environment and apply provider overrides
-	env := mergeEnvironment(os.Environ(), providerEnv)
-
-	// On Unix systems, use Exec to replace the current process
-	// On Windows, Exec is not available, so we use StartProcess
-	if err := syscall.Exec(execPath, fullArgs, env); err != nil {
-		// If Exec fails (e.g., on Windows), fall back to starting a new process
-		cmd := &exec.Cmd{
-			Path:   execPath,
-			Args:   fullArgs,
-			Env:    env,
-			Stdin:  os.Stdin,
-			Stdout: os.Stdout,
-			Stderr: os.Stderr,
-		}
-		if err := cmd.Run(); err != nil {
-			// Check if this is an exit error (command ran but returned non-zero)
-			var exitErr *exec.ExitError
-			if errors.As(err, &exitErr) {
-				// Command executed successfully but returned non-zero exit code
-				// This is not a shim error - propagate the exit code
-				os.Exit(exitErr.ExitCode())
-			}
-			// Other errors (couldn't start, etc.) are actual failures
-			return err
-		}
-	}
-
-	return nil
-}
-
-// executeCommandWithWait executes a command and waits for it to complete, returning the exit code
-func executeCommandWithWait(execPath string, args []string, providerEnv map[string]string) int {
-	// Build full args (executable name + arguments)
-	fullArgs := append([]string{execPath}, args...)
-
-	// Get current environment and apply provider overrides
-	env := mergeEnvironment(os.Environ(), providerEnv)
-
-	// Use exec.Command to run the command and wait for completion
-	cmd := &exec.Cmd{
-		Path:   execPath,
-		Args:   fullArgs,
-		Env:    env,
-		Stdin:  os.Stdin,
-		Stdout: os.Stdout,
-		Stderr: os.Stderr,
-	}
-
-	if err := cmd.Run(); err != nil {
-		// Check if this is an exit error
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			return exitErr.ExitCode()
-		}
-		// Other errors (couldn't start, etc.) return 1
-		return 1
-	}
-
-	return 0
-}
-
-// mergeEnvironment merges provider environment variables into the base environment.
-// Provider variables are prepended to existing values (for PATH-like variables) or set directly.
-func mergeEnvironment(baseEnv []string, providerEnv map[string]string) []string {
-	if len(providerEnv) == 0 {
-		return baseEnv
-	}
-
-	// Build a map of existing environment variables for easy lookup
-	envMap := make(map[string]string)
-	for _, e := range baseEnv {
-		if idx := strings.Index(e, "="); idx != -1 {
-			key := e[:idx]
-			value := e[idx+1:]
-			envMap[key] = value
-		}
-	}
+// promptReshim runs reshim for a runtime after its global packages were installed or
+// removed, asking first when decideReshim says so
+func promptReshim(runtimeName string) {
+	fmt.Fprintln(os.Stderr) // Empty line for spacing
+	ui.Info("Global packages were installed/removed")
 
-	// Apply provider environment variables
-	// For PATH-like variables (LD_LIBRARY_PATH, DYLD_LIBRARY_PATH), prepend the new value
-	for key, value := range providerEnv {
-		if existing, ok := envMap[key]; ok && existing != "" {
-			// Prepend new value to existing (for PATH-like variables)
-			envMap[key] = value + string(filepath.ListSeparator) + existing
-		} else {
-			envMap[key] = value
+	switch decideReshim(ui.IsInteractive()) {
+	case reshimSkip:
+		ui.Info("Skipping reshim (%s=false); run 'dtvem reshim' to use the new executables", config.AutoReshimEnvVar)
+		return
+	case reshimPrompt:
+		fmt.Fprintf(os.Stderr, "Run 'dtvem reshim' to update shims? [Y/n]: ")
+
+		var response string
+		_, _ = fmt.Scanln(&response)
+		response = strings.ToLower(strings.TrimSpace(response))
+
+		// Default to "yes" if empty response
+		if response != "" && response != constants.ResponseY && response != constants.ResponseYes {
+			ui.Info("Remember to run 'dtvem reshim' when you want to use the new executables")
+			return
 		}
 	}
 
-	// Convert back to slice format
-	result := make([]string, 0, len(envMap))
-	for key, value := range envMap {
-		result = append(result, key+"="+value)
-	}
-
-	return result
-}
-
-// promptReshim prompts the user to run reshim after installing global packages
-func promptReshim() {
-	fmt.Fprintln(os.Stderr) // Empty line for spacing
-	ui.Info("Global packages were installed/removed")
-	fmt.Fprintf(os.Stderr, "Run 'dtvem reshim' to update shims? [Y/n]: ")
-
-	var response string
-	_, _ = fmt.Scanln(&response)
-	response = strings.ToLower(strings.TrimSpace(response))
-
-	// Default to "yes" if empty response
-	if response == "" || response == constants.ResponseY || response == constants.ResponseYes {
-		// Run reshim
-		if err := runReshim(); err != nil {
-			ui.Error("Failed to run reshim: %v", err)
-			ui.Info("Please run manually: dtvem reshim")
-		} else {
-			ui.Success("Shims updated successfully")
-		}
+	if err := runReshim(runtimeName); err != nil {
+		ui.Error("Failed to run reshim: %v", err)
+		ui.Info("Please run manually: dtvem reshim")
 	} else {
-		ui.Info("Remember to run 'dtvem reshim' when you want to use the new executables")
+		ui.Success("Shims updated successfully")
 	}
 }
 
-// runReshim executes the reshim operation
-func runReshim() error {
+// runReshim executes the reshim operation, scoped to a runtime since only its
+// packages changed
+func runReshim(runtimeName string) error {
 	// Find dtvem executable
 	dtvemPath, err := findDtvemExecutable()
 	if err != nil {
 		return err
 	}
 
-	// Run: dtvem reshim
-	cmd := exec.Command(dtvemPath, "reshim")
+	// Run: dtvem reshim --runtime <runtime>
+	cmd := exec.Command(dtvemPath, "reshim", "--runtime", runtimeName)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
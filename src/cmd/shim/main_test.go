@@ -0,0 +1,185 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+func TestShouldAutoInstall(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		interactive bool
+		want        bool
+	}{
+		{name: "auto-install enabled", env: "true", interactive: false, want: true},
+		{name: "auto-install enabled in a terminal", env: "true", interactive: true, want: true},
+		{name: "auto-install disabled", env: "false", interactive: true, want: false},
+		{name: "unset and not a terminal", env: "", interactive: false, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ui.AutoInstallEnvVar, tt.env)
+
+			if got := shouldAutoInstall("Node.js", "20.0.0", tt.interactive); got != tt.want {
+				t.Errorf("shouldAutoInstall() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecideReshim(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		interactive bool
+		want        reshimAction
+	}{
+		{name: "auto-reshim enabled", env: "true", interactive: true, want: reshimRun},
+		{name: "auto-reshim disabled", env: "false", interactive: false, want: reshimSkip},
+		{name: "unset in a terminal", env: "", interactive: true, want: reshimPrompt},
+		{name: "unset and not a terminal", env: "", interactive: false, want: reshimRun},
+	}
+
+	// A developer's own config.json must not decide the unset cases
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.AutoReshimEnvVar, tt.env)
+			config.ResetSettingsCache()
+			t.Cleanup(config.ResetSettingsCache)
+
+			if got := decideReshim(tt.interactive); got != tt.want {
+				t.Errorf("decideReshim() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// systemShimProvider is a minimal ShimProvider for a runtime set to the system version
+type systemShimProvider struct{}
+
+func (systemShimProvider) Name() string                                     { return "sysrt" }
+func (systemShimProvider) DisplayName() string                              { return "System Runtime" }
+func (systemShimProvider) Shims() []string                                  { return []string{"sysrt"} }
+func (systemShimProvider) ExecutablePath(version string) (string, error)    { return "", nil }
+func (systemShimProvider) IsInstalled(version string) (bool, error)         { return false, nil }
+func (systemShimProvider) ShouldReshimAfter(string, []string) bool          { return false }
+func (systemShimProvider) GetEnvironment(string) (map[string]string, error) { return nil, nil }
+
+func TestSystemVersionPath(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("DTVEM_ROOT", root)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	execName := "sysrt"
+	if goruntime.GOOS == "windows" {
+		execName += ".exe"
+	}
+
+	// The shim comes first in PATH, the system installation after it
+	shimsDir := filepath.Join(root, "shims")
+	systemDir := filepath.Join(t.TempDir(), "system", "bin")
+	for _, dir := range []string{shimsDir, systemDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, execName), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatalf("Failed to create executable: %v", err)
+		}
+	}
+	t.Setenv("PATH", shimsDir+string(os.PathListSeparator)+systemDir)
+
+	// A project that pins the system version
+	projectDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	if err := config.SetLocalVersion("sysrt", runtime.SystemVersion); err != nil {
+		t.Fatalf("SetLocalVersion() error: %v", err)
+	}
+
+	version, err := config.ResolveVersion("sysrt")
+	if err != nil || !runtime.IsSystemVersion(version) {
+		t.Fatalf("ResolveVersion() = (%q, %v), want the system version", version, err)
+	}
+
+	got, err := systemVersionPath("sysrt", systemShimProvider{})
+	if err != nil {
+		t.Fatalf("systemVersionPath() error: %v", err)
+	}
+	if want := filepath.Join(systemDir, execName); got != want {
+		t.Errorf("systemVersionPath() = %q, want the system installation %q", got, want)
+	}
+
+	// Only the shim in PATH: no system installation
+	t.Setenv("PATH", shimsDir)
+	if _, err := systemVersionPath("sysrt", systemShimProvider{}); err == nil {
+		t.Error("systemVersionPath() should fail when only the shim is in PATH")
+	}
+}
+
+// pinInstallProvider is a Provider that records the versions the shim installs
+type pinInstallProvider struct {
+	runtime.Provider
+	available []runtime.AvailableVersion
+	installs  []string
+}
+
+func (p *pinInstallProvider) Name() string        { return "pinrt" }
+func (p *pinInstallProvider) DisplayName() string { return "Pin Runtime" }
+func (p *pinInstallProvider) ListAvailable() ([]runtime.AvailableVersion, error) {
+	return p.available, nil
+}
+func (p *pinInstallProvider) Install(version string) error {
+	p.installs = append(p.installs, version)
+	return nil
+}
+
+func TestInstallMissingVersion_Pin(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+	t.Setenv(ui.AutoInstallEnvVar, "true")
+
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	// A pin that no installed version matches is still the configured version
+	if err := config.SetGlobalVersion("pinrt", "20.x"); err != nil {
+		t.Fatalf("SetGlobalVersion() error: %v", err)
+	}
+	version, err := config.ResolveVersion("pinrt")
+	if err != nil || version != "20.x" {
+		t.Fatalf("ResolveVersion() = (%q, %v), want the pin 20.x", version, err)
+	}
+
+	provider := &pinInstallProvider{}
+	for _, v := range []string{"20.9.0", "20.11.1", "21.0.0"} {
+		provider.available = append(provider.available, runtime.AvailableVersion{Version: runtime.NewVersion(v)})
+	}
+
+	installed, err := installMissingVersion(provider, version)
+	if err != nil {
+		t.Fatalf("installMissingVersion() error: %v", err)
+	}
+	if installed != "20.11.1" || len(provider.installs) != 1 || provider.installs[0] != "20.11.1" {
+		t.Errorf("installMissingVersion() = %q, installs %v, want the newest patch 20.11.1", installed, provider.installs)
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dtvem/dtvem/src/internal/shim"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var shimsCmd = &cobra.Command{
+	Use:   "shims",
+	Short: "Inspect the shims directory",
+	Long: `Inspect the directory that holds dtvem's shims.
+
+Examples:
+  dtvem shims dir
+  export PATH="$(dtvem shims dir):$PATH"`,
+	Args: cobra.NoArgs,
+}
+
+var shimsDirCmd = &cobra.Command{
+	Use:   "dir",
+	Short: "Print the shims directory",
+	Long: `Print the path of the shims directory, creating it if it doesn't exist yet.
+
+The path is printed on its own, so it can be used in PATH setup scripts.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := shim.ShimsDir()
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		fmt.Println(dir)
+	},
+}
+
+func init() {
+	shimsCmd.AddCommand(shimsDirCmd)
+	rootCmd.AddCommand(shimsCmd)
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/tui"
+	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusJSONFlag  bool
+	statusCheckFlag bool
+)
+
+// statusReport is the aggregated state shown by the status command
+type statusReport struct {
+	Runtimes []runtimeSummary `json:"runtimes"`
+	Warnings []string         `json:"warnings"`
+}
+
+// runtimeSummary is the state of one runtime in the status report
+type runtimeSummary struct {
+	Runtime     string `json:"runtime"`
+	DisplayName string `json:"display_name"`
+	// Version is the active version, or empty if none is configured
+	Version string `json:"version,omitempty"`
+	// Source is where the active version was configured (local or global)
+	Source string `json:"source,omitempty"`
+	// File is the config file that set the active version
+	File              string `json:"file,omitempty"`
+	Installed         bool   `json:"installed"`
+	InstalledVersions int    `json:"installed_versions"`
+	// Latest is the newest available version; only filled in with --check
+	Latest string `json:"latest,omitempty"`
+	// UpToDate reports whether the active version is the latest; only set with --check
+	UpToDate *bool `json:"up_to_date,omitempty"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show an overview of all runtimes",
+	Long: `Show an at-a-glance overview of every runtime: the active version and where
+it was configured, how many versions are installed, and any problems that
+'dtvem doctor' would report.
+
+With --check, the newest available version of each runtime is fetched as well
+(this needs network access unless the manifests are cached).
+
+Examples:
+  dtvem status
+  dtvem status --check
+  dtvem status --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		report := collectStatus(runtime.GetAll(), doctorChecks(), statusCheckFlag)
+
+		if statusJSONFlag {
+			if err := writeStatusJSON(os.Stdout, report); err != nil {
+				ui.Error("Failed to encode status: %v", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		showStatus(report, statusCheckFlag)
+	},
+}
+
+// collectStatus gathers the state of each runtime and the problems found by the
+// doctor checks. The latest available versions are only looked up when check is set.
+func collectStatus(providers []runtime.Provider, checks []doctorCheck, check bool) statusReport {
+	report := statusReport{
+		Runtimes: make([]runtimeSummary, 0, len(providers)),
+		Warnings: []string{},
+	}
+
+	sorted := make([]runtime.Provider, len(providers))
+	copy(sorted, providers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+
+	for _, provider := range sorted {
+		summary := runtimeSummary{
+			Runtime:     provider.Name(),
+			DisplayName: provider.DisplayName(),
+		}
+
+		if resolved, err := config.ResolveVersionWithSource(provider.Name()); err == nil {
+			summary.Version = resolved.Version
+			summary.Source = string(resolved.Source)
+			summary.File = resolved.File
+			summary.Installed = currentStatus(provider, resolved.Version).installed
+		}
+
+		if installed, err := provider.ListInstalled(); err == nil {
+			summary.InstalledVersions = len(installed)
+		}
+
+		if check {
+			latest, err := latestAvailableVersion(provider)
+			switch {
+			case err != nil:
+				report.Warnings = append(report.Warnings, fmt.Sprintf("Could not check the latest %s version: %v", provider.DisplayName(), err))
+			case latest != "":
+				summary.Latest = latest
+				if summary.Version != "" && !runtime.IsSystemVersion(summary.Version) {
+					upToDate := runtime.CompareVersions(summary.Version, latest) >= 0
+					summary.UpToDate = &upToDate
+				}
+			}
+		}
+
+		report.Runtimes = append(report.Runtimes, summary)
+	}
+
+	for _, c := range checks {
+		for _, issue := range c.Run() {
+			report.Warnings = append(report.Warnings, issue.Problem)
+		}
+	}
+
+	return report
+}
+
+// latestAvailableVersion returns the newest version the provider can install
+func latestAvailableVersion(provider runtime.Provider) (string, error) {
+	available, err := provider.ListAvailable()
+	if err != nil {
+		return "", err
+	}
+
+	latest := ""
+	for _, v := range available {
+		if latest == "" || runtime.CompareVersions(v.Version.Raw, latest) > 0 {
+			latest = v.Version.Raw
+		}
+	}
+	return latest, nil
+}
+
+// showStatus prints the status report as a table followed by the warnings
+func showStatus(report statusReport, check bool) {
+	headers := []string{"Runtime", "Version", "Source", "Installed"}
+	if check {
+		headers = append(headers, "Latest")
+	}
+
+	table := tui.NewTable(headers...)
+	table.SetTitle("Status")
+
+	for _, s := range report.Runtimes {
+		version, source := "-", "-"
+		if s.Version != "" {
+			version = s.Version
+			source = s.Source
+			switch {
+			case s.Installed:
+			case runtime.IsSystemVersion(s.Version):
+				version += " " + tui.CrossMark + " not found in PATH"
+			default:
+				version += " " + tui.CrossMark + " not installed"
+			}
+		}
+
+		row := []string{s.DisplayName, version, source, fmt.Sprintf("%d", s.InstalledVersions)}
+		if check {
+			row = append(row, latestDescription(s))
+		}
+
+		if s.Version != "" && s.Installed {
+			table.AddActiveRow(row...)
+		} else {
+			table.AddRow(row...)
+		}
+	}
+
+	fmt.Println(table.Render())
+	fmt.Println()
+
+	if len(report.Warnings) == 0 {
+		ui.Success("No problems found")
+		return
+	}
+
+	for _, warning := range report.Warnings {
+		ui.Warning("%s", warning)
+	}
+	ui.Info("Run 'dtvem doctor' for details and fixes")
+}
+
+// latestDescription describes the latest version column for a runtime
+func latestDescription(s runtimeSummary) string {
+	switch {
+	case s.Latest == "":
+		return "-"
+	case s.UpToDate == nil:
+		return s.Latest
+	case *s.UpToDate:
+		return tui.CheckMark + " " + s.Latest
+	default:
+		return s.Latest + " available"
+	}
+}
+
+// writeStatusJSON writes the status report to w as JSON
+func writeStatusJSON(w io.Writer, report statusReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSONFlag, "json", false, "Print the status as JSON")
+	statusCmd.Flags().BoolVar(&statusCheckFlag, "check", false, "Also check for newer available versions (uses the network)")
+	rootCmd.AddCommand(statusCmd)
+}
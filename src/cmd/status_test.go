@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// mockStatusProvider is a mockProvider with installed and available versions
+type mockStatusProvider struct {
+	mockProvider
+	installedVersions []string
+	availableVersions []string
+	availableErr      error
+}
+
+func (m *mockStatusProvider) IsInstalled(version string) (bool, error) {
+	return containsString(m.installedVersions, version), nil
+}
+
+func (m *mockStatusProvider) ListInstalled() ([]runtime.InstalledVersion, error) {
+	var installed []runtime.InstalledVersion
+	for _, v := range m.installedVersions {
+		installed = append(installed, runtime.InstalledVersion{Version: runtime.NewVersion(v)})
+	}
+	return installed, nil
+}
+
+func (m *mockStatusProvider) ListAvailable() ([]runtime.AvailableVersion, error) {
+	var available []runtime.AvailableVersion
+	for _, v := range m.availableVersions {
+		available = append(available, runtime.AvailableVersion{Version: runtime.NewVersion(v)})
+	}
+	return available, m.availableErr
+}
+
+// setupStatusEnv creates a temp environment with node pinned locally, python set
+// globally and ruby not configured
+func setupStatusEnv(t *testing.T) []runtime.Provider {
+	t.Helper()
+	tempDir := setupDebugEnv(t)
+
+	if err := os.MkdirAll(filepath.Join(tempDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	if err := config.SetLocalVersion("node", "20.1.0"); err != nil {
+		t.Fatalf("SetLocalVersion() error: %v", err)
+	}
+	if err := config.SetGlobalVersion("python", "3.12.0"); err != nil {
+		t.Fatalf("SetGlobalVersion() error: %v", err)
+	}
+
+	return []runtime.Provider{
+		&mockStatusProvider{
+			mockProvider:      mockProvider{name: "ruby", displayName: "Ruby"},
+			installedVersions: []string{"3.3.0"},
+			availableVersions: []string{"3.3.0"},
+		},
+		&mockStatusProvider{
+			mockProvider:      mockProvider{name: "node", displayName: "Node.js"},
+			installedVersions: []string{"18.0.0", "20.1.0"},
+			availableVersions: []string{"20.1.0", "22.3.0", "18.0.0"},
+		},
+		&mockStatusProvider{
+			mockProvider:      mockProvider{name: "python", displayName: "Python"},
+			availableVersions: []string{"3.12.0", "3.11.9"},
+		},
+	}
+}
+
+func TestCollectStatus(t *testing.T) {
+	providers := setupStatusEnv(t)
+	checks := []doctorCheck{
+		{Name: "passing", Run: func() []doctorIssue { return nil }},
+		{Name: "failing", Run: func() []doctorIssue { return []doctorIssue{{Problem: "shims not in PATH"}} }},
+	}
+
+	report := collectStatus(providers, checks, false)
+
+	if len(report.Runtimes) != 3 {
+		t.Fatalf("collectStatus() = %d runtimes, want 3", len(report.Runtimes))
+	}
+
+	node, python, ruby := report.Runtimes[0], report.Runtimes[1], report.Runtimes[2]
+	if node.Runtime != "node" || python.Runtime != "python" || ruby.Runtime != "ruby" {
+		t.Fatalf("runtimes not sorted by name: %s, %s, %s", node.Runtime, python.Runtime, ruby.Runtime)
+	}
+
+	if node.Version != "20.1.0" || node.Source != "local" || !node.Installed || node.InstalledVersions != 2 {
+		t.Errorf("node = %+v, want local 20.1.0, installed, 2 versions", node)
+	}
+	if python.Version != "3.12.0" || python.Source != "global" || python.Installed || python.InstalledVersions != 0 {
+		t.Errorf("python = %+v, want global 3.12.0, not installed, 0 versions", python)
+	}
+	if ruby.Version != "" || ruby.Source != "" || ruby.InstalledVersions != 1 {
+		t.Errorf("ruby = %+v, want no active version and 1 installed version", ruby)
+	}
+
+	for _, s := range report.Runtimes {
+		if s.Latest != "" || s.UpToDate != nil {
+			t.Errorf("%s latest = %q without --check, want it unset", s.Runtime, s.Latest)
+		}
+	}
+
+	if len(report.Warnings) != 1 || report.Warnings[0] != "shims not in PATH" {
+		t.Errorf("warnings = %v, want the failing check's problem", report.Warnings)
+	}
+}
+
+func TestCollectStatus_Check(t *testing.T) {
+	providers := setupStatusEnv(t)
+	providers = append(providers, &mockStatusProvider{
+		mockProvider: mockProvider{name: "offline", displayName: "Offline"},
+		availableErr: errors.New("network unreachable"),
+	})
+
+	report := collectStatus(providers, nil, true)
+
+	tests := []struct {
+		runtime    string
+		wantLatest string
+		wantUpDate *bool
+	}{
+		{"node", "22.3.0", boolPtr(false)},
+		{"offline", "", nil},
+		{"python", "3.12.0", boolPtr(true)},
+		{"ruby", "3.3.0", nil},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.runtime, func(t *testing.T) {
+			s := report.Runtimes[i]
+			if s.Runtime != tt.runtime {
+				t.Fatalf("runtime %d = %q, want %q", i, s.Runtime, tt.runtime)
+			}
+			if s.Latest != tt.wantLatest {
+				t.Errorf("Latest = %q, want %q", s.Latest, tt.wantLatest)
+			}
+			if (s.UpToDate == nil) != (tt.wantUpDate == nil) || (s.UpToDate != nil && *s.UpToDate != *tt.wantUpDate) {
+				t.Errorf("UpToDate = %v, want %v", s.UpToDate, tt.wantUpDate)
+			}
+		})
+	}
+
+	if len(report.Warnings) != 1 {
+		t.Errorf("warnings = %v, want one for the failed check", report.Warnings)
+	}
+}
+
+func TestWriteStatusJSON(t *testing.T) {
+	providers := setupStatusEnv(t)
+	report := collectStatus(providers, nil, false)
+
+	var buf bytes.Buffer
+	if err := writeStatusJSON(&buf, report); err != nil {
+		t.Fatalf("writeStatusJSON() error: %v", err)
+	}
+
+	var decoded statusReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded.Runtimes) != 3 || decoded.Runtimes[0].Version != "20.1.0" {
+		t.Errorf("decoded runtimes = %+v", decoded.Runtimes)
+	}
+	if decoded.Warnings == nil {
+		t.Error("warnings should be an empty list, not null")
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
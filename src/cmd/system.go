@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/path"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// systemExecutable finds the system installation of a runtime whose version is set to
+// "system": the first of the runtime's own shims (e.g. python, then python3) found on
+// PATH outside dtvem's shims directory. Package shims such as pip are not considered.
+func systemExecutable(provider runtime.ShimProvider) (string, error) {
+	for _, name := range provider.Shims() {
+		if !strings.HasPrefix(name, provider.Name()) {
+			continue
+		}
+		if found := path.LookPathExcludingShims(name); found != "" {
+			return found, nil
+		}
+	}
+	return "", fmt.Errorf("%s is set to the system version, but no system %s was found in PATH",
+		provider.DisplayName(), provider.Name())
+}
+
+// systemVersionInstallError returns the error for trying to install the system version
+func systemVersionInstallError(provider runtime.ShimProvider) error {
+	return fmt.Errorf("%q can't be installed: it runs the %s installed on the system; install a version number instead",
+		runtime.SystemVersion, provider.DisplayName())
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// setupSystemRuntime sets a runtime to the system version, with a system installation
+// of it on PATH when installed is true
+func setupSystemRuntime(t *testing.T, installed bool) (*mockProvider, string) {
+	t.Helper()
+	setupDebugEnv(t)
+	if err := config.SetGlobalVersion("sysrt", runtime.SystemVersion); err != nil {
+		t.Fatalf("SetGlobalVersion() error: %v", err)
+	}
+
+	systemDir := t.TempDir()
+	t.Setenv("PATH", systemDir)
+	systemPath := ""
+	if installed {
+		name := "sysrt"
+		if goruntime.GOOS == constants.OSWindows {
+			name += constants.ExtExe
+		}
+		systemPath = filepath.Join(systemDir, name)
+		if err := os.WriteFile(systemPath, []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatalf("Failed to create system executable: %v", err)
+		}
+	}
+
+	return &mockProvider{name: "sysrt", displayName: "System Runtime"}, systemPath
+}
+
+func TestResolveInstalledExecutable_SystemVersion(t *testing.T) {
+	provider, systemPath := setupSystemRuntime(t, true)
+
+	result, err := resolveInterpreter(provider, "")
+	if err != nil {
+		t.Fatalf("resolveInterpreter() error: %v", err)
+	}
+	if result.Version != runtime.SystemVersion || result.Path != systemPath || result.Env != nil {
+		t.Errorf("resolveInterpreter() = %+v, want the system installation %s", result, systemPath)
+	}
+
+	target, err := resolveExecTarget(provider, execRequest{RuntimeName: "sysrt"})
+	if err != nil {
+		t.Fatalf("resolveExecTarget() error: %v", err)
+	}
+	if target.ExecPath != systemPath || len(target.Env) != 0 {
+		t.Errorf("resolveExecTarget() = %+v, want %s in the environment as it is", target, systemPath)
+	}
+}
+
+func TestResolveInstalledExecutable_SystemVersionMissing(t *testing.T) {
+	provider, _ := setupSystemRuntime(t, false)
+
+	_, err := resolveInterpreter(provider, "")
+	if err == nil || errors.Is(err, errVersionNotInstalled) {
+		t.Errorf("resolveInterpreter() error = %v, want one about the missing system installation", err)
+	}
+	if len(provider.installCalls) != 0 {
+		t.Errorf("resolveInterpreter() installed %v", provider.installCalls)
+	}
+}
+
+func TestCurrentStatus_SystemVersion(t *testing.T) {
+	provider, systemPath := setupSystemRuntime(t, true)
+	if rs := currentStatus(provider, runtime.SystemVersion); !rs.installed || rs.systemPath != systemPath {
+		t.Errorf("currentStatus() = %+v, want the system installation %s", rs, systemPath)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if rs := currentStatus(provider, runtime.SystemVersion); rs.installed {
+		t.Errorf("currentStatus() = %+v without a system installation, want not installed", rs)
+	}
+}
+
+func TestInstallVersion_SystemVersion(t *testing.T) {
+	provider, _ := setupSystemRuntime(t, false)
+
+	if err := installVersion(provider, "system"); err == nil {
+		t.Error("installVersion(system) succeeded, want an error")
+	}
+	if len(provider.installCalls) != 0 {
+		t.Errorf("installVersion(system) called Install(%v)", provider.installCalls)
+	}
+}
+
+func TestCheckGlobalVersions_SystemVersion(t *testing.T) {
+	provider, _ := setupSystemRuntime(t, true)
+	useTestRegistry(t)
+	if err := runtime.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	if issues := checkGlobalVersions(); len(issues) != 0 {
+		t.Errorf("checkGlobalVersions() = %+v, want none with a system installation", issues)
+	}
+
+	// Without a system installation the setting is reported, but not unset
+	t.Setenv("PATH", t.TempDir())
+	issues := checkGlobalVersions()
+	if len(issues) != 1 || issues[0].Fix != nil {
+		t.Errorf("checkGlobalVersions() = %+v, want one issue without a fix", issues)
+	}
+}
+
+func TestCollectStatus_SystemVersion(t *testing.T) {
+	provider, _ := setupSystemRuntime(t, true)
+
+	report := collectStatus([]runtime.Provider{provider}, nil, false)
+	if len(report.Runtimes) != 1 || !report.Runtimes[0].Installed {
+		t.Errorf("collectStatus() = %+v, want the system version installed", report.Runtimes)
+	}
+}
+
+func TestBuildShimReport_SystemVersion(t *testing.T) {
+	provider, systemPath := setupSystemRuntime(t, true)
+	useTestRegistry(t)
+	if err := runtime.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	report := buildShimReport("sysrt", nil)
+	if report.Problem != "" || !report.Installed || report.ExecutablePath != systemPath || !report.ExecutableExists {
+		t.Errorf("buildShimReport() = %+v, want the system installation %s", report, systemPath)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if report := buildShimReport("sysrt", nil); report.Problem == "" || report.Installed {
+		t.Errorf("buildShimReport() = %+v without a system installation, want a problem", report)
+	}
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/dtvem/dtvem/src/internal/config"
@@ -25,8 +27,10 @@ The version directory and all its contents will be deleted.
 
 Safety features:
   - Cannot uninstall the currently active global version
+  - Cannot uninstall the version a local config resolves to in this directory
   - Prompts for confirmation before deletion
-  - Automatically regenerates shims after uninstall
+  - Automatically regenerates shims after uninstall, removing shims that
+    only the uninstalled version provided
 
 Examples:
   dtvem uninstall python 3.11.0
@@ -35,17 +39,14 @@ Examples:
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		runtimeName := args[0]
-		version := args[1]
-
-		// Strip 'v' prefix if present
-		version = strings.TrimPrefix(version, "v")
+		version := runtime.NormalizeVersion(args[1])
 
 		// Get the runtime provider
 		provider, err := runtime.Get(runtimeName)
 		if err != nil {
-			ui.Error("%v", err)
-			ui.Info("Available runtimes: %v", runtime.List())
-			return
+			reportError(err)
+			ui.Info("Available runtimes: %s", availableRuntimes())
+			os.Exit(1)
 		}
 
 		ui.Header("Uninstalling %s v%s...", provider.DisplayName(), version)
@@ -55,24 +56,32 @@ Examples:
 		if _, err := os.Stat(versionPath); os.IsNotExist(err) {
 			ui.Error("Version %s is not installed", version)
 			ui.Info("Run 'dtvem list %s' to see installed versions", runtimeName)
-			return
+			os.Exit(1)
 		}
 
 		// Check if this is the currently active global version
 		globalVersion, err := provider.GlobalVersion()
-		if err == nil && globalVersion == version {
+		if err == nil && globalPins(runtimeName, globalVersion, version) {
 			ui.Error("Cannot uninstall the currently active global version")
-			ui.Info("Current global version: v%s", globalVersion)
+			if runtime.IsVersionPin(globalVersion) {
+				ui.Info("Current global version: %s (resolves to v%s)", globalVersion, version)
+			} else {
+				ui.Info("Current global version: v%s", globalVersion)
+			}
 			ui.Info("Set a different global version first: dtvem global %s <version>", runtimeName)
-			return
+			os.Exit(1)
 		}
 
-		// Check if this is the currently active local version
-		localVersion, err := provider.LocalVersion()
-		if err == nil && localVersion == version {
-			ui.Warning("This is the currently active local version in this directory")
-			ui.Info("Local version file: dtvem.config.json")
-			ui.Info("You may need to update or remove it after uninstalling")
+		// Check if a local config pins this directory to the version
+		if pinned, ok := localPin(runtimeName, version); ok {
+			ui.Error("Cannot uninstall the version used in this directory")
+			if pinned.Pin != "" {
+				ui.Info("Pinned by %s (%s resolves to v%s)", pinned.File, pinned.Pin, version)
+			} else {
+				ui.Info("Pinned by %s", pinned.File)
+			}
+			ui.Info("Change the local version first: dtvem local %s <version>", runtimeName)
+			os.Exit(1)
 		}
 
 		// Prompt for confirmation (unless --yes flag is provided)
@@ -96,10 +105,10 @@ Examples:
 		spinner := ui.NewSpinner(fmt.Sprintf("Removing %s v%s...", provider.DisplayName(), version))
 		spinner.Start()
 
-		if err := os.RemoveAll(versionPath); err != nil {
+		if err := removeVersion(provider, version, versionPath); err != nil {
 			spinner.Error("Failed to remove version")
-			ui.Error("Error: %v", err)
-			return
+			ui.Error("%s", uninstallFailureMessage(provider, versionPath, err))
+			os.Exit(1)
 		}
 
 		spinner.Success(fmt.Sprintf("%s v%s removed", provider.DisplayName(), version))
@@ -113,7 +122,7 @@ Examples:
 			shimSpinner.Warning("Could not regenerate shims")
 			ui.Warning("You may need to run 'dtvem reshim' manually")
 		} else {
-			if _, err := manager.Rehash(); err != nil {
+			if _, _, err := manager.RehashAndPrune(); err != nil {
 				shimSpinner.Warning("Could not regenerate shims")
 				ui.Warning("You may need to run 'dtvem reshim' manually")
 			} else {
@@ -125,6 +134,69 @@ Examples:
 	},
 }
 
+// localPin returns the resolution of runtimeName in the current directory when it
+// comes from a local config and lands on version. A wildcard pin that another
+// installed version still satisfies doesn't count.
+func localPin(runtimeName, version string) (config.ResolvedVersion, bool) {
+	resolved, err := config.ResolveVersionWithSource(runtimeName)
+	if err != nil || resolved.Source != config.VersionSourceLocal || resolved.Version != version {
+		return config.ResolvedVersion{}, false
+	}
+
+	if resolved.Pin != "" && otherInstallMatchesPin(runtimeName, resolved.Pin, version) {
+		return config.ResolvedVersion{}, false
+	}
+
+	return resolved, true
+}
+
+// globalPins reports whether the global version keeps version in use: it names version,
+// or it is a wildcard pin that version matches and no other installed version does
+func globalPins(runtimeName, globalVersion, version string) bool {
+	if !runtime.IsVersionPin(globalVersion) {
+		return globalVersion == version
+	}
+
+	if _, ok := runtime.MatchVersionPin(globalVersion, []string{version}); !ok {
+		return false
+	}
+	return !otherInstallMatchesPin(runtimeName, globalVersion, version)
+}
+
+// otherInstallMatchesPin reports whether an installed version other than version
+// satisfies a wildcard pin, so the pin still resolves once version is removed
+func otherInstallMatchesPin(runtimeName, pin, version string) bool {
+	var others []string
+	runtimeDir := filepath.Join(config.DefaultPaths().Versions, runtimeName)
+	entries, _ := os.ReadDir(runtimeDir)
+	for _, entry := range entries {
+		if entry.Name() != version && runtime.IsVersionDir(runtimeDir, entry) {
+			others = append(others, entry.Name())
+		}
+	}
+
+	_, ok := runtime.MatchVersionPin(pin, others)
+	return ok
+}
+
+// removeVersion removes an installed version, through the provider when it
+// implements Uninstall and by deleting its directory otherwise
+func removeVersion(provider runtime.Provider, version, versionPath string) error {
+	if runtime.CapabilitiesOf(provider).Uninstall {
+		return provider.Uninstall(version)
+	}
+	return os.RemoveAll(versionPath)
+}
+
+// uninstallFailureMessage explains why removing a version failed. A provider that
+// can't uninstall yet gets a pointer to the directory to delete by hand.
+func uninstallFailureMessage(provider runtime.Provider, versionPath string, err error) string {
+	if errors.Is(err, runtime.ErrNotImplemented) {
+		return fmt.Sprintf("Uninstall isn't supported for %s yet; remove manually: %s", provider.DisplayName(), versionPath)
+	}
+	return fmt.Sprintf("Error: %v", err)
+}
+
 func init() {
 	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "Skip confirmation prompt")
 	rootCmd.AddCommand(uninstallCmd)
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
 )
 
 func TestUninstallCommand_VersionStripping(t *testing.T) {
@@ -208,3 +211,163 @@ func TestUninstallCommand_ConfirmationResponse(t *testing.T) {
 		})
 	}
 }
+
+// mockUninstallProvider is a mockProvider that reports whether it implements Uninstall
+type mockUninstallProvider struct {
+	mockProvider
+	canUninstall   bool
+	uninstallCalls []string
+	uninstallErr   error
+}
+
+func (m *mockUninstallProvider) Capabilities() runtime.ProviderCapabilities {
+	return runtime.ProviderCapabilities{Uninstall: m.canUninstall}
+}
+
+func (m *mockUninstallProvider) Uninstall(version string) error {
+	m.uninstallCalls = append(m.uninstallCalls, version)
+	return m.uninstallErr
+}
+
+func TestRemoveVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		canUninstall  bool
+		wantCalls     int
+		wantDirExists bool
+	}{
+		{"provider uninstalls", true, 1, true},
+		{"directory removed for providers without uninstall", false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versionPath := filepath.Join(t.TempDir(), "1.0.0")
+			if err := os.MkdirAll(filepath.Join(versionPath, "bin"), 0755); err != nil {
+				t.Fatalf("Failed to create version directory: %v", err)
+			}
+
+			provider := &mockUninstallProvider{mockProvider: mockProvider{name: "test"}, canUninstall: tt.canUninstall}
+			if err := removeVersion(provider, "1.0.0", versionPath); err != nil {
+				t.Fatalf("removeVersion() error: %v", err)
+			}
+
+			if len(provider.uninstallCalls) != tt.wantCalls {
+				t.Errorf("Uninstall calls = %v, want %d", provider.uninstallCalls, tt.wantCalls)
+			}
+			_, err := os.Stat(versionPath)
+			if exists := err == nil; exists != tt.wantDirExists {
+				t.Errorf("version directory exists = %v, want %v", exists, tt.wantDirExists)
+			}
+		})
+	}
+}
+
+func TestUninstallFailureMessage(t *testing.T) {
+	provider := &mockUninstallProvider{
+		mockProvider: mockProvider{name: "test", displayName: "Test"},
+		canUninstall: true,
+		uninstallErr: fmt.Errorf("uninstalling 1.0.0: %w", runtime.ErrNotImplemented),
+	}
+	versionPath := filepath.Join(t.TempDir(), "1.0.0")
+
+	err := removeVersion(provider, "1.0.0", versionPath)
+	if err == nil {
+		t.Fatal("removeVersion() should return the provider's error")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"unimplemented", err, "Uninstall isn't supported for Test yet; remove manually: " + versionPath},
+		{"other error", errors.New("permission denied"), "Error: permission denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uninstallFailureMessage(provider, versionPath, tt.err); got != tt.want {
+				t.Errorf("uninstallFailureMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalPin(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed []string
+		local     string
+		uninstall string
+		wantBlock bool
+		wantPin   string
+	}{
+		{"exact local version", []string{"3.11.0", "3.12.1"}, "3.12.1", "3.12.1", true, ""},
+		{"other local version", []string{"3.11.0", "3.12.1"}, "3.11.0", "3.12.1", false, ""},
+		{"no local config", []string{"3.11.0", "3.12.1"}, "", "3.12.1", false, ""},
+		{"wildcard pin still satisfied", []string{"3.12.0", "3.12.1"}, "3.12.x", "3.12.1", false, ""},
+		{"wildcard pin on its last match", []string{"3.11.0", "3.12.1"}, "3.12.x", "3.12.1", true, "3.12.x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := setupDebugEnv(t)
+			for _, version := range tt.installed {
+				if err := os.MkdirAll(filepath.Join(tempDir, "versions", "python", version), 0755); err != nil {
+					t.Fatalf("Failed to create version directory: %v", err)
+				}
+			}
+			if tt.local != "" {
+				if err := config.SetLocalVersion("python", tt.local); err != nil {
+					t.Fatalf("SetLocalVersion() error: %v", err)
+				}
+			}
+
+			pinned, blocked := localPin("python", tt.uninstall)
+			if blocked != tt.wantBlock {
+				t.Fatalf("localPin() blocked = %v, want %v", blocked, tt.wantBlock)
+			}
+			if !blocked {
+				return
+			}
+			if pinned.Pin != tt.wantPin {
+				t.Errorf("localPin() pin = %q, want %q", pinned.Pin, tt.wantPin)
+			}
+			if want := filepath.Join(config.LocalConfigDirName, config.RuntimesFileName); !strings.HasSuffix(pinned.File, want) {
+				t.Errorf("localPin() file = %q, want the local %s", pinned.File, want)
+			}
+		})
+	}
+}
+
+func TestGlobalPins(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed []string
+		global    string
+		uninstall string
+		want      bool
+	}{
+		{"exact global version", []string{"20.11.0", "20.11.1"}, "20.11.1", "20.11.1", true},
+		{"other global version", []string{"20.11.0", "20.11.1"}, "20.11.0", "20.11.1", false},
+		{"wildcard pin on its last match", []string{"18.20.4", "20.11.1"}, "20.11.x", "20.11.1", true},
+		{"wildcard pin still satisfied", []string{"20.11.0", "20.11.1"}, "20.11.x", "20.11.1", false},
+		{"wildcard pin that doesn't match", []string{"18.20.4", "20.11.1"}, "18.x", "20.11.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := setupDebugEnv(t)
+			for _, version := range tt.installed {
+				if err := os.MkdirAll(filepath.Join(tempDir, "versions", "node", version), 0755); err != nil {
+					t.Fatalf("Failed to create version directory: %v", err)
+				}
+			}
+
+			if got := globalPins("node", tt.global, tt.uninstall); got != tt.want {
+				t.Errorf("globalPins(%q, %q) = %v, want %v", tt.global, tt.uninstall, got, tt.want)
+			}
+		})
+	}
+}
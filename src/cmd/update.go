@@ -64,7 +64,7 @@ Example:
 				source = "remote"
 			}
 
-			table.AddRow(runtime, fmt.Sprintf("%d versions", len(m.Versions)), source)
+			table.AddRow(m.RuntimeDisplayName(runtime), fmt.Sprintf("%d versions", len(m.Versions)), source)
 		}
 
 		fmt.Println(table.Render())
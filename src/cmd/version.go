@@ -2,7 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	goruntime "runtime"
+	"runtime/debug"
+	"strings"
 
+	"github.com/dtvem/dtvem/src/internal/manifest"
 	"github.com/dtvem/dtvem/src/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -10,16 +15,63 @@ import (
 // Version can be set at build time using ldflags
 var Version = "dev"
 
+// Commit is the git commit dtvem was built from, set at build time using ldflags.
+// Without it, the commit Go recorded in the build info is used, if any.
+var Commit = ""
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show the dtvem version",
-	Long:  `Display the current version of dtvem.`,
+	Long: `Display the current version of dtvem with its build information: the git
+commit, Go version and platform it was built for, and the manifests embedded in
+it with the number of versions each lists.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		content := fmt.Sprintf("dtvem %s", tui.RenderVersion(Version))
-		fmt.Println(tui.RenderInfoBox(content))
+		writeVersionInfo(cmd.OutOrStdout())
 	},
 }
 
+// writeVersionInfo writes the dtvem version and build information to w
+func writeVersionInfo(w io.Writer) {
+	lines := []string{
+		fmt.Sprintf("dtvem %s", tui.RenderVersion(Version)),
+		"",
+		fmt.Sprintf("Commit:    %s", buildCommit()),
+		fmt.Sprintf("Go:        %s", goruntime.Version()),
+		fmt.Sprintf("Platform:  %s/%s", goruntime.GOOS, goruntime.GOARCH),
+		fmt.Sprintf("Manifests: schema v%d", manifest.SchemaVersion),
+	}
+
+	summaries, err := manifest.NewEmbeddedSource().Summaries()
+	if err != nil {
+		lines = append(lines, fmt.Sprintf("  %s", tui.RenderMuted("unreadable: "+err.Error())))
+	}
+	for _, summary := range summaries {
+		line := fmt.Sprintf("  %-8s %d versions", summary.Runtime, summary.Versions)
+		if summary.SchemaVersion != manifest.SchemaVersion {
+			line += fmt.Sprintf(" (schema v%d)", summary.SchemaVersion)
+		}
+		lines = append(lines, line)
+	}
+
+	_, _ = fmt.Fprintln(w, tui.RenderInfoBox(strings.Join(lines, "\n")))
+}
+
+// buildCommit returns the git commit dtvem was built from: Commit when it was set
+// with ldflags, otherwise the revision in Go's build info, or "unknown"
+func buildCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && setting.Value != "" {
+				return setting.Value
+			}
+		}
+	}
+	return "unknown"
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVersionCmd_PrintsBuildInfo(t *testing.T) {
+	oldVersion, oldCommit := Version, Commit
+	t.Cleanup(func() { Version, Commit = oldVersion, oldCommit })
+	// As injected with -ldflags "-X github.com/dtvem/dtvem/src/cmd.Version=..."
+	Version, Commit = "1.2.3-test", "0123456789abcdef"
+
+	var out bytes.Buffer
+	versionCmd.SetOut(&out)
+	t.Cleanup(func() { versionCmd.SetOut(nil) })
+	versionCmd.Run(versionCmd, nil)
+
+	for _, want := range []string{"1.2.3-test", "0123456789abcdef", "schema v1", "node", "python", "ruby"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("version output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestBuildCommit(t *testing.T) {
+	oldCommit := Commit
+	t.Cleanup(func() { Commit = oldCommit })
+
+	Commit = "abc123"
+	if got := buildCommit(); got != "abc123" {
+		t.Errorf("buildCommit() = %q, want the injected commit", got)
+	}
+
+	// Test binaries have no VCS info to fall back to
+	Commit = ""
+	if got := buildCommit(); got == "" {
+		t.Error("buildCommit() is empty without an injected commit")
+	}
+}
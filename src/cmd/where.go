@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/runtime"
@@ -29,9 +30,9 @@ Examples:
 		// Get the provider
 		provider, err := runtime.Get(runtimeName)
 		if err != nil {
-			ui.Error("Unknown runtime: %s", runtimeName)
-			ui.Info("Available runtimes: %v", runtime.List())
-			return
+			reportError(err)
+			ui.Info("Available runtimes: %s", availableRuntimes())
+			os.Exit(1)
 		}
 
 		var version string
@@ -43,28 +44,37 @@ Examples:
 				ui.Error("No version configured for %s", runtimeName)
 				ui.Info("Set a version with: dtvem global %s <version>", runtimeName)
 				ui.Info("Or specify a version: dtvem where %s <version>", runtimeName)
-				return
+				os.Exit(1)
 			}
 			ui.Info("Using current version: %s", ui.HighlightVersion(version))
 			fmt.Println()
 		} else {
-			version = args[1]
-			// Strip 'v' prefix if present
-			if len(version) > 0 && (version[0] == 'v' || version[0] == 'V') {
-				version = version[1:]
+			version = runtime.NormalizeVersion(args[1])
+		}
+
+		// The system version is installed outside dtvem; show where its executable is
+		if runtime.IsSystemVersion(version) {
+			systemPath, err := systemExecutable(provider)
+			if err != nil {
+				reportError(err)
+				ui.Info("Install %s on the system, or choose a dtvem version: dtvem global %s <version>", provider.DisplayName(), runtimeName)
+				os.Exit(1)
 			}
+			fmt.Println(tui.RenderTitle(provider.DisplayName() + " " + version))
+			fmt.Println(tui.RenderInfoBox(filepath.Dir(systemPath)))
+			return
 		}
 
 		// Check if version is installed
 		installed, err := provider.IsInstalled(version)
 		if err != nil {
 			ui.Error("Failed to check if version is installed: %v", err)
-			return
+			os.Exit(1)
 		}
 		if !installed {
 			ui.Error("Version %s is not installed", version)
 			ui.Info("Install it with: dtvem install %s %s", runtimeName, version)
-			return
+			os.Exit(1)
 		}
 
 		// Get the installation path
@@ -74,7 +84,7 @@ Examples:
 		if _, err := os.Stat(installPath); os.IsNotExist(err) {
 			ui.Error("Installation directory not found: %s", installPath)
 			ui.Warning("Version may be corrupted or partially installed")
-			return
+			os.Exit(1)
 		}
 
 		// Display the information
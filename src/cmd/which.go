@@ -1,167 +1,200 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
-	goruntime "runtime"
 
 	"github.com/dtvem/dtvem/src/internal/config"
-	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/path"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/shim"
 	"github.com/dtvem/dtvem/src/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var whichJSONFlag bool
+
+var (
+	errCommandNotManaged   = errors.New("not managed by dtvem")
+	errShimNotFound        = errors.New("shim not found")
+	errNoVersionConfigured = errors.New("no version configured")
+	errVersionNotInstalled = errors.New("version not installed")
+)
+
+// whichResult is the executable a shim runs for a command
+type whichResult struct {
+	Runtime string `json:"runtime"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	// Source is where the version was configured (local or global)
+	Source string `json:"source"`
+	// SystemFallback is true when the shim runs the command found on the system PATH,
+	// because the version is "system" or doesn't provide the command
+	SystemFallback bool `json:"system_fallback,omitempty"`
+	// File is the config file that set the version
+	File string `json:"-"`
+	// Shim is the shim that intercepts the command
+	Shim string `json:"-"`
+}
+
 var whichCmd = &cobra.Command{
 	Use:   "which <command>",
 	Short: "Show the path to a command",
-	Long: `Display the full path to a command and which shim is being used.
+	Long: `Display the full path to the executable a shim runs for a command.
 
-This command shows:
+The command can be a runtime (python, node) or any command dtvem has a shim
+for (npm, pip, tsc). The version is resolved exactly like the shim does, and
+this command shows:
   - The shim path that intercepts the command
   - The actual executable that will be invoked
-  - The runtime and version being used
+  - The runtime and version being used, and the config file that set it
+
+Exits with a non-zero status if no version is configured or it isn't installed.
 
 Examples:
   dtvem which python
   dtvem which node
-  dtvem which npm`,
+  dtvem which npm
+  dtvem which python --json   # {"runtime","version","path","source"}`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		commandName := args[0]
 
-		// Find which runtime this command belongs to
-		runtimeName := mapCommandToRuntime(commandName)
-		if runtimeName == "" {
-			ui.Error("Unknown command: %s", commandName)
-			ui.Info("This command is not managed by dtvem")
-			return
-		}
-
-		// Get the provider for this runtime
-		provider, err := runtime.Get(runtimeName)
+		result, err := resolveWhich(commandName)
 		if err != nil {
-			ui.Error("Runtime provider not found: %s", runtimeName)
-			return
+			reportError(err)
+			whichHint(err, commandName, result)
+			os.Exit(1)
 		}
 
-		// Get the shim path
-		paths := config.DefaultPaths()
-		shimExt := ""
-		if goruntime.GOOS == constants.OSWindows {
-			shimExt = ".exe"
-		}
-		shimPath := filepath.Join(paths.Shims, commandName+shimExt)
-
-		// Check if shim exists
-		if _, err := os.Stat(shimPath); os.IsNotExist(err) {
-			ui.Error("Shim not found: %s", commandName)
-			ui.Info("Run 'dtvem reshim' to regenerate shims")
+		if whichJSONFlag {
+			if err := writeWhichJSON(os.Stdout, result); err != nil {
+				ui.Error("Failed to encode result: %v", err)
+				os.Exit(1)
+			}
 			return
 		}
 
-		// Get the current version
-		version, err := provider.CurrentVersion()
-		if err != nil {
-			ui.Error("No version configured for %s", runtimeName)
-			ui.Info("Set a version with: dtvem global %s <version>", runtimeName)
-			return
+		ui.Header("Command: %s", ui.Highlight(commandName))
+		fmt.Println()
+		ui.Info("Shim:       %s", result.Shim)
+		ui.Info("Executable: %s", result.Path)
+		ui.Info("Runtime:    %s", result.Runtime)
+		ui.Info("Version:    %s", ui.HighlightVersion(result.Version))
+		ui.Info("Set by:     %s (%s)", result.File, result.Source)
+		if runtime.IsSystemVersion(result.Version) {
+			ui.Info("The system version is set; the shim runs the system installation")
+		} else if result.SystemFallback {
+			ui.Warning("v%s doesn't provide %s; the shim runs the system installation", result.Version, commandName)
 		}
+	},
+}
 
-		// Get the base executable path
-		baseExecPath, err := provider.ExecutablePath(version)
-		if err != nil {
-			ui.Error("Failed to get executable path: %v", err)
-			return
-		}
+// resolveWhich finds the executable the shim for commandName runs, following the
+// shim's own steps: map the command to a runtime, resolve the configured version,
+// then locate the executable in that version (or on the system PATH). On failure
+// the result holds what was resolved so far.
+func resolveWhich(commandName string) (whichResult, error) {
+	result := whichResult{}
 
-		// Adjust path for secondary executables (pip, npm, etc.)
-		execPath := adjustExecutablePath(baseExecPath, commandName, runtimeName)
+	runtimeName := mapCommandToRuntime(commandName)
+	if runtimeName == "" {
+		return result, fmt.Errorf("%s is %w", commandName, errCommandNotManaged)
+	}
+	result.Runtime = runtimeName
 
-		// Check if the actual executable exists
-		if _, err := os.Stat(execPath); os.IsNotExist(err) {
-			ui.Error("Executable not found: %s", execPath)
-			ui.Warning("Version %s may not be properly installed", version)
-			return
-		}
+	provider, err := runtime.Get(runtimeName)
+	if err != nil {
+		return result, err
+	}
 
-		// Display the information
-		ui.Header("Command: %s", ui.Highlight(commandName))
-		fmt.Println()
-		ui.Info("Shim:       %s", shimPath)
-		ui.Info("Executable: %s", execPath)
-		ui.Info("Runtime:    %s", runtimeName)
-		ui.Info("Version:    %s", ui.HighlightVersion(version))
-	},
-}
+	result.Shim = config.ShimPath(commandName)
+	if _, err := os.Stat(result.Shim); os.IsNotExist(err) {
+		return result, fmt.Errorf("%w: %s", errShimNotFound, commandName)
+	}
 
-// mapCommandToRuntime maps a command name to its runtime
-func mapCommandToRuntime(commandName string) string {
-	// Get all registered runtimes
-	runtimes := runtime.List()
-
-	// Check each runtime's shims
-	for _, rt := range runtimes {
-		shims := shim.RuntimeShims(rt)
-		for _, shimName := range shims {
-			if shimName == commandName {
-				return rt
-			}
+	resolved, err := config.ResolveVersionWithSource(runtimeName)
+	if err != nil {
+		return result, fmt.Errorf("%w for %s: %v", errNoVersionConfigured, runtimeName, err)
+	}
+	result.Version = resolved.Version
+	result.Source = string(resolved.Source)
+	result.File = resolved.File
+
+	// The system version runs whatever is installed on the system PATH
+	if runtime.IsSystemVersion(resolved.Version) {
+		result.Path = path.LookPathExcludingShims(commandName)
+		if result.Path == "" {
+			return result, fmt.Errorf("%s is set to the system version, but no system %s was found in PATH",
+				runtimeName, commandName)
 		}
+		result.SystemFallback = true
+		return result, nil
 	}
 
-	return ""
-}
-
-// adjustExecutablePath adjusts the executable path based on the command name
-// For example, if command is "pip" but base executable is "python",
-// we need to find "pip" in the same directory or Scripts subdirectory
-func adjustExecutablePath(execPath, commandName, runtimeName string) string {
-	// If command name matches runtime name, use the path as-is
-	if commandName == runtimeName {
-		return execPath
+	installed, err := provider.IsInstalled(resolved.Version)
+	if err != nil {
+		return result, fmt.Errorf("could not check if %s %s is installed: %w", runtimeName, resolved.Version, err)
+	}
+	if !installed {
+		return result, fmt.Errorf("%w: %s %s", errVersionNotInstalled, runtimeName, resolved.Version)
 	}
 
-	// Otherwise, try to find the related executable
-	dir := filepath.Dir(execPath)
+	execPath, err := provider.ExecutablePath(resolved.Version)
+	if err != nil {
+		return result, fmt.Errorf("could not find %s %s executable: %w", runtimeName, resolved.Version, err)
+	}
 
-	// Directories to search (in order)
-	searchDirs := []string{
-		dir,                                 // Same directory as runtime executable
-		filepath.Join(dir, "Scripts"),       // Python Scripts directory (Windows)
-		filepath.Join(dir, "..", "Scripts"), // Alternative Python Scripts location
+	resolution, err := shim.ResolveExecutable(execPath, commandName, runtimeName)
+	if err != nil {
+		return result, err
 	}
+	result.Path = resolution.Path
+	result.SystemFallback = resolution.SystemFallback
 
-	// On Windows, try multiple extensions
-	if goruntime.GOOS == "windows" {
-		for _, searchDir := range searchDirs {
-			newExec := filepath.Join(searchDir, commandName)
+	return result, nil
+}
 
-			// Try .cmd first (npm, npx use .cmd on Windows)
-			if _, err := os.Stat(newExec + ".cmd"); err == nil {
-				return newExec + ".cmd"
-			}
-			// Try .exe
-			if _, err := os.Stat(newExec + ".exe"); err == nil {
-				return newExec + ".exe"
-			}
-		}
-	} else {
-		// On Unix, check if the file exists as-is
-		for _, searchDir := range searchDirs {
-			newExec := filepath.Join(searchDir, commandName)
-			if _, err := os.Stat(newExec); err == nil {
-				return newExec
-			}
-		}
+// whichHint suggests how to fix a failed resolveWhich
+func whichHint(err error, commandName string, result whichResult) {
+	switch {
+	case errors.Is(err, errCommandNotManaged):
+		ui.Info("Run 'dtvem reshim' if %s was installed by a runtime dtvem manages", commandName)
+	case errors.Is(err, errShimNotFound):
+		ui.Info("Run 'dtvem reshim' to regenerate shims")
+	case errors.Is(err, errNoVersionConfigured):
+		ui.Info("Install a version with: dtvem install %s <version>", result.Runtime)
+		ui.Info("Then select it with: dtvem global %s <version>", result.Runtime)
+	case errors.Is(err, errVersionNotInstalled):
+		ui.Info("Set by %s", result.File)
+		ui.Info("Install it with: dtvem install %s %s", result.Runtime, result.Version)
+	case errors.Is(err, shim.ErrExecutableNotFound):
+		ui.Warning("Version %s may not be properly installed", result.Version)
+		ui.Info("Run 'dtvem reshim' to remove stale shims")
 	}
+}
+
+// writeWhichJSON writes the resolved executable to w as JSON
+func writeWhichJSON(w io.Writer, result whichResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
 
-	// If not found, return original path
-	return execPath
+// mapCommandToRuntime maps a command name to its runtime, using the same
+// resolution as the shim. Returns an empty string if no runtime claims the command.
+func mapCommandToRuntime(commandName string) string {
+	runtimeName, source := shim.ResolveRuntime(commandName)
+	if source == shim.MappingFromName {
+		return ""
+	}
+	return runtimeName
 }
 
 func init() {
+	whichCmd.Flags().BoolVar(&whichJSONFlag, "json", false, "Print the runtime, version, path and config source as JSON")
 	rootCmd.AddCommand(whichCmd)
 }
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+func TestResolveWhich(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		global     string
+		local      string
+		installed  bool
+		noShim     bool
+		wantErr    error
+		wantSource string
+	}{
+		{"global version", "docrt", "1.0.0", "", true, false, nil, "global"},
+		{"local version wins", "docrt", "2.0.0", "1.0.0", true, false, nil, "local"},
+		{"unknown command", "nosuchcmd", "1.0.0", "", true, false, errCommandNotManaged, ""},
+		{"missing shim", "docrt", "1.0.0", "", true, true, errShimNotFound, ""},
+		{"no version configured", "docrt", "", "", true, false, errNoVersionConfigured, ""},
+		{"version not installed", "docrt", "1.0.0", "", false, false, errVersionNotInstalled, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, provider := setupDoctorEnv(t)
+			provider.installed = tt.installed
+			provider.execPath = filepath.Join(tempDir, "versions", "docrt", "1.0.0", "bin", "docrt")
+			if err := os.WriteFile(provider.execPath, []byte("binary"), 0755); err != nil {
+				t.Fatalf("Failed to create executable: %v", err)
+			}
+
+			if !tt.noShim {
+				if err := os.MkdirAll(filepath.Dir(config.ShimPath("docrt")), 0755); err != nil {
+					t.Fatalf("Failed to create shims directory: %v", err)
+				}
+				if err := os.WriteFile(config.ShimPath("docrt"), []byte("shim"), 0755); err != nil {
+					t.Fatalf("Failed to create shim: %v", err)
+				}
+			}
+			if tt.global != "" {
+				if err := config.SetGlobalVersion("docrt", tt.global); err != nil {
+					t.Fatalf("SetGlobalVersion() error: %v", err)
+				}
+			}
+			if tt.local != "" {
+				if err := config.SetLocalVersion("docrt", tt.local); err != nil {
+					t.Fatalf("SetLocalVersion() error: %v", err)
+				}
+			}
+
+			result, err := resolveWhich(tt.command)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("resolveWhich() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveWhich() error: %v", err)
+			}
+
+			if result.Runtime != "docrt" || result.Version != "1.0.0" || result.Source != tt.wantSource {
+				t.Errorf("resolveWhich() = %+v, want docrt 1.0.0 from %s", result, tt.wantSource)
+			}
+			if result.Path != provider.execPath {
+				t.Errorf("resolveWhich() path = %q, want %q", result.Path, provider.execPath)
+			}
+			if result.File == "" {
+				t.Error("resolveWhich() should report the config file that set the version")
+			}
+		})
+	}
+}
+
+func TestWriteWhichJSON(t *testing.T) {
+	result := whichResult{
+		Runtime: "python",
+		Version: "3.12.1",
+		Path:    "/root/.dtvem/versions/python/3.12.1/bin/python3",
+		Source:  "local",
+		File:    "/project/.dtvem/runtimes.json",
+		Shim:    "/root/.dtvem/shims/python",
+	}
+
+	var buf bytes.Buffer
+	if err := writeWhichJSON(&buf, result); err != nil {
+		t.Fatalf("writeWhichJSON() error: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"runtime": "python",
+		"version": "3.12.1",
+		"path":    "/root/.dtvem/versions/python/3.12.1/bin/python3",
+		"source":  "local",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("writeWhichJSON() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// AliasesFileName is the name of the version aliases file in the config directory
+const AliasesFileName = "aliases.json"
+
+// Aliases maps runtime names to their named versions
+// Format: {"node": {"work": "18.16.0", "stable": "20.11.1"}}
+type Aliases map[string]map[string]string
+
+// reservedAliases are names with built-in meaning that user aliases cannot override
+var reservedAliases = []string{"latest", "lts", runtime.SystemVersion}
+
+// AliasesPath returns the path to the version aliases file
+func AliasesPath() string {
+	paths := DefaultPaths()
+	return filepath.Join(paths.Config, AliasesFileName)
+}
+
+// IsReservedAlias reports whether name is a built-in alias that cannot be redefined
+func IsReservedAlias(name string) bool {
+	for _, reserved := range reservedAliases {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadAliases reads the aliases file. A missing file yields an empty set of aliases.
+func LoadAliases() (Aliases, error) {
+	data, err := os.ReadFile(AliasesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Aliases{}, nil
+		}
+		return nil, err
+	}
+
+	aliases := Aliases{}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse aliases file: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// ResolveAlias returns the version an alias points to for a runtime.
+// If name isn't a user-defined alias (or is a reserved built-in name),
+// it is returned unchanged so it can be treated as a literal version.
+func ResolveAlias(runtimeName, name string) string {
+	if IsReservedAlias(name) {
+		return name
+	}
+
+	aliases, err := LoadAliases()
+	if err != nil {
+		return name
+	}
+
+	if version, ok := aliases[runtimeName][name]; ok && version != "" {
+		return version
+	}
+
+	return name
+}
+
+// SetAlias defines or replaces an alias for a runtime version
+func SetAlias(runtimeName, name, version string) error {
+	if err := validateAliasName(name); err != nil {
+		return err
+	}
+
+	aliases, err := LoadAliases()
+	if err != nil {
+		return err
+	}
+
+	if aliases[runtimeName] == nil {
+		aliases[runtimeName] = map[string]string{}
+	}
+	aliases[runtimeName][name] = version
+
+	return saveAliases(aliases)
+}
+
+// RemoveAlias deletes an alias. Removing an alias that doesn't exist is an error.
+func RemoveAlias(runtimeName, name string) error {
+	aliases, err := LoadAliases()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := aliases[runtimeName][name]; !ok {
+		return fmt.Errorf("no alias %q defined for %s", name, runtimeName)
+	}
+
+	delete(aliases[runtimeName], name)
+	if len(aliases[runtimeName]) == 0 {
+		delete(aliases, runtimeName)
+	}
+
+	return saveAliases(aliases)
+}
+
+// validateAliasName rejects reserved names and names that would be mistaken for versions
+func validateAliasName(name string) error {
+	if name == "" {
+		return fmt.Errorf("alias name cannot be empty")
+	}
+	if IsReservedAlias(name) {
+		return fmt.Errorf("%q is a built-in alias and cannot be redefined", name)
+	}
+
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(name, "v"), "V")
+	if trimmed != "" && unicode.IsDigit(rune(trimmed[0])) {
+		return fmt.Errorf("alias %q looks like a version number", name)
+	}
+
+	return nil
+}
+
+// saveAliases writes the aliases file
+func saveAliases(aliases Aliases) error {
+	aliasesPath := AliasesPath()
+
+	if err := os.MkdirAll(filepath.Dir(aliasesPath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(aliasesPath, data, 0644)
+}
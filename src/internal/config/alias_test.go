@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// setupAliasRoot points the config at a temporary dtvem root
+func setupAliasRoot(t *testing.T) {
+	t.Helper()
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+}
+
+func TestResolveAlias(t *testing.T) {
+	setupAliasRoot(t)
+
+	if err := SetAlias("node", "work", "18.16.0"); err != nil {
+		t.Fatalf("SetAlias() error: %v", err)
+	}
+	if err := SetAlias("python", "stable", "3.12.1"); err != nil {
+		t.Fatalf("SetAlias() error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		runtimeName string
+		input       string
+		want        string
+	}{
+		{"alias resolves", "node", "work", "18.16.0"},
+		{"alias for other runtime", "python", "stable", "3.12.1"},
+		{"alias is per runtime", "python", "work", "work"},
+		{"literal version unchanged", "node", "20.11.1", "20.11.1"},
+		{"unknown name unchanged", "node", "unknown", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveAlias(tt.runtimeName, tt.input); got != tt.want {
+				t.Errorf("ResolveAlias(%q, %q) = %q, want %q", tt.runtimeName, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveAlias_NoFile(t *testing.T) {
+	setupAliasRoot(t)
+
+	if got := ResolveAlias("node", "work"); got != "work" {
+		t.Errorf("ResolveAlias() without aliases file = %q, want %q", got, "work")
+	}
+}
+
+func TestSetAlias_RejectsReservedAndVersionNames(t *testing.T) {
+	setupAliasRoot(t)
+
+	for _, name := range []string{"latest", "lts", "LTS", "system", "20", "v18.0.0", ""} {
+		if err := SetAlias("node", name, "18.16.0"); err == nil {
+			t.Errorf("SetAlias(%q) should have failed", name)
+		}
+	}
+}
+
+func TestResolveAlias_BuiltinsNotOverridable(t *testing.T) {
+	setupAliasRoot(t)
+
+	// Even a hand-edited aliases file cannot redefine the built-in names
+	if err := os.MkdirAll(DefaultPaths().Config, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	data := []byte(`{"node": {"latest": "0.10.0", "lts": "0.12.0"}}`)
+	if err := os.WriteFile(AliasesPath(), data, 0644); err != nil {
+		t.Fatalf("Failed to write aliases file: %v", err)
+	}
+
+	for _, name := range []string{"latest", "lts"} {
+		if got := ResolveAlias("node", name); got != name {
+			t.Errorf("ResolveAlias(node, %q) = %q, want built-in %q", name, got, name)
+		}
+	}
+}
+
+func TestRemoveAlias(t *testing.T) {
+	setupAliasRoot(t)
+
+	if err := SetAlias("node", "work", "18.16.0"); err != nil {
+		t.Fatalf("SetAlias() error: %v", err)
+	}
+	if err := RemoveAlias("node", "work"); err != nil {
+		t.Fatalf("RemoveAlias() error: %v", err)
+	}
+	if got := ResolveAlias("node", "work"); got != "work" {
+		t.Errorf("ResolveAlias() after removal = %q, want %q", got, "work")
+	}
+	if err := RemoveAlias("node", "work"); err == nil {
+		t.Error("RemoveAlias() of a missing alias should fail")
+	}
+}
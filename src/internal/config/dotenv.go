@@ -0,0 +1,71 @@
+package config
+
+import "strings"
+
+// DotEnvFileName is the name of the project environment file read for versions
+const DotEnvFileName = ".env"
+
+// dotEnvKeyPrefix and dotEnvKeySuffix surround the runtime name in a .env version key,
+// as in DTVEM_NODE_VERSION
+const (
+	dotEnvKeyPrefix = "DTVEM_"
+	dotEnvKeySuffix = "_VERSION"
+)
+
+// DotEnvEnabled reports whether versions are read from .env files. It is opt-in
+// (the dotenv setting), because a .env file is usually there for other tools.
+func DotEnvEnabled() bool {
+	return Setting(SettingDotEnv) == "true"
+}
+
+// ParseDotEnvVersions extracts runtime versions from the contents of a .env file.
+// Only DTVEM_<RUNTIME>_VERSION keys are read, e.g. DTVEM_NODE_VERSION=20.11.1 sets node.
+// Blank lines, # comments and an "export " prefix are allowed. Values may be quoted
+// with single or double quotes; unquoted values end at a " #" comment. A leading
+// "v" is stripped, as in .node-version files.
+func ParseDotEnvVersions(data []byte) RuntimesConfig {
+	versions := make(RuntimesConfig)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		if len(key) <= len(dotEnvKeyPrefix)+len(dotEnvKeySuffix) ||
+			!strings.HasPrefix(key, dotEnvKeyPrefix) || !strings.HasSuffix(key, dotEnvKeySuffix) {
+			continue
+		}
+		runtimeName := key[len(dotEnvKeyPrefix) : len(key)-len(dotEnvKeySuffix)]
+
+		if value = strings.TrimPrefix(dotEnvValue(value), "v"); value != "" {
+			versions[strings.ToLower(runtimeName)] = value
+		}
+	}
+
+	return versions
+}
+
+// dotEnvValue returns the value of a .env assignment without quotes or a trailing comment
+func dotEnvValue(value string) string {
+	value = strings.TrimSpace(value)
+
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+		return ""
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseDotEnvVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want RuntimesConfig
+	}{
+		{
+			name: "plain values",
+			data: "DTVEM_NODE_VERSION=20.11.1\nDTVEM_PYTHON_VERSION=3.12.1\n",
+			want: RuntimesConfig{"node": "20.11.1", "python": "3.12.1"},
+		},
+		{
+			name: "quoted values",
+			data: "DTVEM_NODE_VERSION=\"20.11.1\"\nDTVEM_RUBY_VERSION='3.3.0'\n",
+			want: RuntimesConfig{"node": "20.11.1", "ruby": "3.3.0"},
+		},
+		{
+			name: "comments",
+			data: "# versions for CI\nDTVEM_NODE_VERSION=20.11.1 # LTS\nDTVEM_PYTHON_VERSION=\"3.12.1\" # latest\n",
+			want: RuntimesConfig{"node": "20.11.1", "python": "3.12.1"},
+		},
+		{
+			name: "hash inside quotes is kept",
+			data: "DTVEM_NODE_VERSION=\"20.11.1#build\"\n",
+			want: RuntimesConfig{"node": "20.11.1#build"},
+		},
+		{
+			name: "export prefix, spaces and leading v",
+			data: "export DTVEM_NODE_VERSION = v20.11.1\r\n",
+			want: RuntimesConfig{"node": "20.11.1"},
+		},
+		{
+			name: "other keys and malformed lines are ignored",
+			data: "NODE_VERSION=18.0.0\nDATABASE_URL=postgres://localhost\nDTVEM_VERSION=1\nDTVEM_NODE_VERSION\nDTVEM_RUBY_VERSION=\nDTVEM_PYTHON_VERSION=\"3.12\n",
+			want: RuntimesConfig{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseDotEnvVersions([]byte(tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDotEnvVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveVersion_DotEnv(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+
+	projectDir := t.TempDir()
+	subDir := filepath.Join(projectDir, "app")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(subDir, DotEnvFileName), []byte("DTVEM_NODE_VERSION=20.11.1\nDTVEM_RUBY_VERSION=3.3.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	writeLocalRuntimes(t, projectDir, `{"node": "18.16.0"}`)
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	// Opt-in: without the setting, .env is not read
+	if _, err := LocalVersion("ruby"); err == nil {
+		t.Error("LocalVersion(ruby) should not read .env unless enabled")
+	}
+
+	t.Setenv(DotEnvEnvVar, "true")
+
+	// runtimes.json takes precedence, even from a parent directory
+	if got, err := LocalVersion("node"); err != nil || got != "18.16.0" {
+		t.Errorf("LocalVersion(node) = %q, %v, want 18.16.0 from runtimes.json", got, err)
+	}
+
+	resolved, err := ResolveVersionWithSource("ruby")
+	if err != nil {
+		t.Fatalf("ResolveVersionWithSource(ruby) error: %v", err)
+	}
+	if resolved.Version != "3.3.0" || resolved.File != filepath.Join(subDir, DotEnvFileName) {
+		t.Errorf("ResolveVersionWithSource(ruby) = %+v, want 3.3.0 from .env", resolved)
+	}
+}
+
+// writeLocalRuntimes writes a .dtvem/runtimes.json into dir
+func writeLocalRuntimes(t *testing.T, dir, content string) {
+	t.Helper()
+	configDir := filepath.Join(dir, LocalConfigDirName)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", configDir, err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, RuntimesFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write runtimes.json: %v", err)
+	}
+}
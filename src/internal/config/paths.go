@@ -4,10 +4,11 @@ package config
 import (
 	"os"
 	"path/filepath"
-	"runtime"
+	goruntime "runtime"
 	"sync"
 
 	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/runtime"
 )
 
 // Paths holds all important dtvem directory paths
@@ -80,7 +81,7 @@ func getRootDir() string {
 	}
 
 	// On Linux, respect XDG Base Directory specification
-	if runtime.GOOS == constants.OSLinux {
+	if goruntime.GOOS == constants.OSLinux {
 		return getXDGDataPath(home)
 	}
 
@@ -98,10 +99,42 @@ func getXDGDataPath(home string) string {
 	return filepath.Join(home, ".local", "share", "dtvem")
 }
 
-// RuntimeVersionPath returns the path to a specific runtime version
+// RuntimeVersionPath returns the path to a specific runtime version. The directory
+// is named by the normalized version, so "v20.11.1" and "20.11.1" share an install;
+// a "v"-prefixed directory left by an older install is used if it is the only one.
 func RuntimeVersionPath(runtimeName, version string) string {
 	paths := DefaultPaths()
-	return filepath.Join(paths.Versions, runtimeName, version)
+	runtimeDir := filepath.Join(paths.Versions, runtimeName)
+	dirName := runtime.NormalizeVersion(version)
+
+	versionPath := filepath.Join(runtimeDir, dirName)
+	if _, err := os.Stat(versionPath); os.IsNotExist(err) {
+		legacyPath := filepath.Join(runtimeDir, "v"+dirName)
+		if _, err := os.Stat(legacyPath); err == nil {
+			return legacyPath
+		}
+	}
+	return versionPath
+}
+
+// ArchVersionsDirName is the directory under the dtvem root that holds installs for other architectures
+const ArchVersionsDirName = "arch"
+
+// RuntimeArchVersionPath returns where a runtime version built for arch is installed
+// by a multi-architecture install (e.g. ~/.dtvem/arch/arm64/node/20.11.1)
+func RuntimeArchVersionPath(runtimeName, version, arch string) string {
+	paths := DefaultPaths()
+	return filepath.Join(paths.Root, ArchVersionsDirName, arch, runtimeName, runtime.NormalizeVersion(version))
+}
+
+// LocksDirName is the directory under the dtvem root that holds inter-process lock files
+const LocksDirName = "locks"
+
+// InstallLockPath returns the lock file that guards installing a runtime version
+// (e.g. ~/.dtvem/locks/node-20.11.1.lock)
+func InstallLockPath(runtimeName, version string) string {
+	paths := DefaultPaths()
+	return filepath.Join(paths.Root, LocksDirName, runtimeName+"-"+runtime.NormalizeVersion(version)+".lock")
 }
 
 // GlobalConfigPath returns the path to the global config file
@@ -114,7 +147,7 @@ func GlobalConfigPath() string {
 func ShimPath(shimName string) string {
 	paths := DefaultPaths()
 	// Add .exe extension on Windows
-	if runtime.GOOS == constants.OSWindows {
+	if goruntime.GOOS == constants.OSWindows {
 		shimName = shimName + constants.ExtExe
 	}
 	return filepath.Join(paths.Shims, shimName)
@@ -174,4 +207,5 @@ func ShimMapPath() string {
 func ResetPathsCache() {
 	pathsOnce = sync.Once{}
 	defaultPaths = nil
+	ResetSettingsCache()
 }
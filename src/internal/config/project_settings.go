@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// projectSettingsCache holds the project settings read for a working directory
+var projectSettingsCache struct {
+	sync.Mutex
+	dir      string
+	settings *Settings
+	path     string
+	err      error
+}
+
+// LoadProjectSettings reads the settings of the project in the current directory
+// from the closest .dtvem/config.json, and returns them with the file's path.
+// Settings that aren't ProjectSafe are dropped unless the user trusts project
+// settings (the trust-project-settings setting), so a cloned repository can't
+// change what dtvem downloads or runs. Outside a project it returns empty settings
+// and an empty path.
+func LoadProjectSettings() (*Settings, string, error) {
+	raw, path, err := readProjectSettingsCached()
+	if err != nil {
+		return &Settings{}, path, err
+	}
+
+	trusted := ProjectSettingsTrusted()
+	settings := &Settings{}
+	for _, def := range settingDefinitions {
+		if def.ProjectSafe || trusted {
+			*def.field(settings) = *def.field(raw)
+		}
+	}
+	return settings, path, nil
+}
+
+// ProjectSettingsTrusted reports whether project settings may set every setting.
+// Only the environment and the user's own config.json can grant this.
+func ProjectSettingsTrusted() bool {
+	if value := os.Getenv(TrustProjectEnvVar); value != "" {
+		return value == "true"
+	}
+	settings, _ := LoadSettings()
+	return settings.TrustProject == "true"
+}
+
+// readProjectSettingsCached reads the project settings once per working directory
+func readProjectSettingsCached() (*Settings, string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return &Settings{}, "", err
+	}
+
+	projectSettingsCache.Lock()
+	defer projectSettingsCache.Unlock()
+
+	if projectSettingsCache.settings == nil || projectSettingsCache.dir != cwd {
+		projectSettingsCache.dir = cwd
+		projectSettingsCache.settings, projectSettingsCache.path, projectSettingsCache.err = readProjectSettings(cwd)
+	}
+	return projectSettingsCache.settings, projectSettingsCache.path, projectSettingsCache.err
+}
+
+// resetProjectSettingsCache forces the project settings to be read again on next access
+func resetProjectSettingsCache() {
+	projectSettingsCache.Lock()
+	defer projectSettingsCache.Unlock()
+	projectSettingsCache.dir = ""
+	projectSettingsCache.settings = nil
+	projectSettingsCache.path = ""
+	projectSettingsCache.err = nil
+}
+
+// readProjectSettings walks up from dir to the repository root looking for
+// .dtvem/config.json and reads the first one found
+func readProjectSettings(dir string) (*Settings, string, error) {
+	var data []byte
+	path := ""
+	walkUpToRepoRoot(dir, func(dir string) bool {
+		candidate := filepath.Join(dir, LocalConfigDirName, SettingsFileName)
+		contents, err := os.ReadFile(candidate)
+		if err != nil {
+			return false
+		}
+		data, path = contents, candidate
+		return true
+	})
+	if path == "" {
+		return &Settings{}, "", nil
+	}
+
+	settings := &Settings{}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return &Settings{}, path, fmt.Errorf("failed to parse project settings file %s: %w", path, err)
+	}
+	return settings, path, nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupProjectSettings creates a git project with .dtvem/config.json at its root,
+// changes into a subdirectory of it and returns the settings file's path
+func setupProjectSettings(t *testing.T, contents string) string {
+	t.Helper()
+	setupSettingsRoot(t)
+	ResetSettingsCache()
+	t.Cleanup(ResetSettingsCache)
+
+	projectDir := t.TempDir()
+	subDir := filepath.Join(projectDir, "app")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	settingsPath := filepath.Join(projectDir, LocalConfigDirName, SettingsFileName)
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatalf("Failed to create .dtvem: %v", err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write project settings: %v", err)
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	return settingsPath
+}
+
+func TestGetSetting_ProjectPrecedence(t *testing.T) {
+	setupProjectSettings(t, `{"network-timeout": "2m"}`)
+
+	if err := SetSetting(SettingNetworkTimeout, "5m"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+	if err := SetSetting(SettingArch, "arm64"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		envValue   string
+		wantValue  string
+		wantSource SettingSource
+	}{
+		{"project overrides config.json", SettingNetworkTimeout, "", "2m", SettingFromProject},
+		{"environment overrides project", SettingNetworkTimeout, "30s", "30s", SettingFromEnv},
+		{"config.json used when the project doesn't set the key", SettingArch, "", "arm64", SettingFromConfig},
+		{"default when neither sets the key", SettingNode7z, "", "", SettingFromDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, _ := LookupSetting(tt.key)
+			t.Setenv(def.EnvVar, tt.envValue)
+
+			value, source := GetSetting(tt.key)
+			if value != tt.wantValue || source != tt.wantSource {
+				t.Errorf("GetSetting(%s) = (%q, %q), want (%q, %q)", tt.key, value, source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestLoadProjectSettings_SafetyAllowlist(t *testing.T) {
+	wantPath := setupProjectSettings(t, `{
+  "arch": "amd64",
+  "auto-install": "true",
+  "no-update-check": "1",
+  "trust-project-settings": "true"
+}`)
+
+	settings, path, err := LoadProjectSettings()
+	if err != nil {
+		t.Fatalf("LoadProjectSettings() error: %v", err)
+	}
+	if path != wantPath {
+		t.Errorf("LoadProjectSettings() path = %q, want %q", path, wantPath)
+	}
+	if settings.Arch != "amd64" {
+		t.Errorf("Arch = %q, want amd64 (project safe)", settings.Arch)
+	}
+	if settings.AutoInstall != "" || settings.NoUpdateCheck != "" || settings.TrustProject != "" {
+		t.Errorf("LoadProjectSettings() = %+v, want settings that aren't project safe dropped", settings)
+	}
+	if value, source := GetSetting(SettingAutoInstall); value != "" || source != SettingFromDefault {
+		t.Errorf("GetSetting(auto-install) = (%q, %q), want the project value ignored", value, source)
+	}
+	if ProjectSettingsTrusted() {
+		t.Error("ProjectSettingsTrusted() should not be granted by the project itself")
+	}
+
+	// Opting in lets the project set every setting
+	if err := SetSetting(SettingTrustProject, "true"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+	if value, source := GetSetting(SettingAutoInstall); value != "true" || source != SettingFromProject {
+		t.Errorf("GetSetting(auto-install) when trusted = (%q, %q), want (true, project)", value, source)
+	}
+
+	// The environment can withdraw the trust
+	t.Setenv(TrustProjectEnvVar, "false")
+	if value, source := GetSetting(SettingAutoInstall); value != "" || source != SettingFromDefault {
+		t.Errorf("GetSetting(auto-install) with %s=false = (%q, %q), want default", TrustProjectEnvVar, value, source)
+	}
+}
+
+func TestLoadProjectSettings_NoProject(t *testing.T) {
+	setupSettingsRoot(t)
+	ResetSettingsCache()
+	t.Cleanup(ResetSettingsCache)
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	settings, path, err := LoadProjectSettings()
+	if err != nil || path != "" || *settings != (Settings{}) {
+		t.Errorf("LoadProjectSettings() = (%+v, %q, %v), want empty settings", settings, path, err)
+	}
+}
+
+func TestLoadProjectSettings_InvalidFile(t *testing.T) {
+	setupProjectSettings(t, "{not json")
+
+	if _, _, err := LoadProjectSettings(); err == nil {
+		t.Error("LoadProjectSettings() should fail for an invalid file")
+	}
+	if err := SetSetting(SettingArch, "arm64"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+	if value, source := GetSetting(SettingArch); value != "arm64" || source != SettingFromConfig {
+		t.Errorf("GetSetting() with an invalid project file = (%q, %q), want (arm64, config)", value, source)
+	}
+}
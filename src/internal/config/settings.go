@@ -0,0 +1,299 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// SettingsFileName is the name of the persistent settings file in the config directory
+const SettingsFileName = "config.json"
+
+// Environment variables that override persistent settings
+const (
+	AutoInstallEnvVar    = "DTVEM_AUTO_INSTALL"
+	NetworkTimeoutEnvVar = "DTVEM_NETWORK_TIMEOUT"
+	ArchEnvVar           = "DTVEM_ARCH"
+	NoUpdateCheckEnvVar  = "DTVEM_NO_UPDATE_CHECK"
+	DotEnvEnvVar         = "DTVEM_DOTENV"
+	Node7zEnvVar         = "DTVEM_NODE_7Z"
+	NodeCorepackEnvVar   = "DTVEM_NODE_COREPACK"
+	TrustProjectEnvVar   = "DTVEM_TRUST_PROJECT_SETTINGS"
+	ExtractWorkersEnvVar = "DTVEM_EXTRACT_WORKERS"
+	MirrorEnvVar         = "DTVEM_MIRROR"
+	AutoReshimEnvVar     = "DTVEM_AUTO_RESHIM"
+)
+
+// Setting keys accepted by `dtvem config`
+const (
+	SettingAutoInstall    = "auto-install"
+	SettingNetworkTimeout = "network-timeout"
+	SettingArch           = "arch"
+	SettingNoUpdateCheck  = "no-update-check"
+	SettingDotEnv         = "dotenv"
+	SettingNode7z         = "node-7z"
+	SettingNodeCorepack   = "node-corepack"
+	SettingTrustProject   = "trust-project-settings"
+	SettingExtractWorkers = "extract-workers"
+	SettingMirror         = "mirror"
+	SettingAutoReshim     = "auto-reshim"
+)
+
+// Settings are the persistent user settings stored in config.json.
+// Empty fields are unset and fall back to their defaults.
+type Settings struct {
+	AutoInstall    string `json:"auto-install,omitempty"`
+	NetworkTimeout string `json:"network-timeout,omitempty"`
+	Arch           string `json:"arch,omitempty"`
+	NoUpdateCheck  string `json:"no-update-check,omitempty"`
+	DotEnv         string `json:"dotenv,omitempty"`
+	Node7z         string `json:"node-7z,omitempty"`
+	NodeCorepack   string `json:"node-corepack,omitempty"`
+	TrustProject   string `json:"trust-project-settings,omitempty"`
+	ExtractWorkers string `json:"extract-workers,omitempty"`
+	Mirror         string `json:"mirror,omitempty"`
+	AutoReshim     string `json:"auto-reshim,omitempty"`
+}
+
+// SettingSource describes where a setting's effective value came from
+type SettingSource string
+
+const (
+	// SettingFromEnv means the value came from the setting's environment variable
+	SettingFromEnv SettingSource = "environment"
+	// SettingFromProject means the value came from the project's .dtvem/config.json
+	SettingFromProject SettingSource = "project"
+	// SettingFromConfig means the value came from config.json
+	SettingFromConfig SettingSource = "config"
+	// SettingFromDefault means the setting is unset and uses its default
+	SettingFromDefault SettingSource = "default"
+)
+
+// SettingDefinition describes a persistent setting
+type SettingDefinition struct {
+	Key         string
+	EnvVar      string
+	Description string
+	// ProjectSafe settings can be set by a project's .dtvem/config.json. Other
+	// settings change what is downloaded or run without asking, so a project only
+	// sets them when the user trusts project settings.
+	ProjectSafe bool
+	// field returns the Settings field that stores the setting
+	field func(s *Settings) *string
+}
+
+// settingDefinitions lists every persistent setting
+var settingDefinitions = []SettingDefinition{
+	{
+		Key:         SettingAutoInstall,
+		EnvVar:      AutoInstallEnvVar,
+		Description: "Install missing versions without asking (true/false)",
+		field:       func(s *Settings) *string { return &s.AutoInstall },
+	},
+	{
+		Key:         SettingNetworkTimeout,
+		EnvVar:      NetworkTimeoutEnvVar,
+		Description: "How long a network request may stall (e.g. 90s, 10m)",
+		ProjectSafe: true,
+		field:       func(s *Settings) *string { return &s.NetworkTimeout },
+	},
+	{
+		Key:         SettingArch,
+		EnvVar:      ArchEnvVar,
+		Description: "Architecture of the binaries to install (e.g. amd64, arm64)",
+		ProjectSafe: true,
+		field:       func(s *Settings) *string { return &s.Arch },
+	},
+	{
+		Key:         SettingNoUpdateCheck,
+		EnvVar:      NoUpdateCheckEnvVar,
+		Description: "Disable the daily check for new dtvem releases (true/false)",
+		field:       func(s *Settings) *string { return &s.NoUpdateCheck },
+	},
+	{
+		Key:         SettingDotEnv,
+		EnvVar:      DotEnvEnvVar,
+		Description: "Read DTVEM_<RUNTIME>_VERSION keys from a project's .env file (true/false)",
+		ProjectSafe: true,
+		field:       func(s *Settings) *string { return &s.DotEnv },
+	},
+	{
+		Key:         SettingNode7z,
+		EnvVar:      Node7zEnvVar,
+		Description: "Download the smaller .7z Node.js archives on Windows, falling back to .zip (true/false, default true)",
+		ProjectSafe: true,
+		field:       func(s *Settings) *string { return &s.Node7z },
+	},
+	{
+		Key:         SettingNodeCorepack,
+		EnvVar:      NodeCorepackEnvVar,
+		Description: "Run 'corepack enable' after installing Node.js, for yarn and pnpm (true/false)",
+		field:       func(s *Settings) *string { return &s.NodeCorepack },
+	},
+	{
+		Key:         SettingTrustProject,
+		EnvVar:      TrustProjectEnvVar,
+		Description: "Let a project's .dtvem/config.json set every setting, not just the safe ones (true/false)",
+		field:       func(s *Settings) *string { return &s.TrustProject },
+	},
+	{
+		Key:         SettingExtractWorkers,
+		EnvVar:      ExtractWorkersEnvVar,
+		Description: "Number of files extracted in parallel from .zip archives (1 extracts one at a time)",
+		ProjectSafe: true,
+		field:       func(s *Settings) *string { return &s.ExtractWorkers },
+	},
+	{
+		Key:         SettingMirror,
+		EnvVar:      MirrorEnvVar,
+		Description: "Base URL of a mirror of builds.dtvem.io to download runtime binaries from",
+		field:       func(s *Settings) *string { return &s.Mirror },
+	},
+	{
+		Key:         SettingAutoReshim,
+		EnvVar:      AutoReshimEnvVar,
+		Description: "Update shims without asking after global packages are installed (true/false)",
+		field:       func(s *Settings) *string { return &s.AutoReshim },
+	},
+}
+
+var (
+	settingsCache     *Settings
+	settingsCacheErr  error
+	settingsCacheOnce sync.Once
+)
+
+// SettingDefinitions returns all persistent settings, sorted by key
+func SettingDefinitions() []SettingDefinition {
+	defs := make([]SettingDefinition, len(settingDefinitions))
+	copy(defs, settingDefinitions)
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Key < defs[j].Key })
+	return defs
+}
+
+// LookupSetting returns the definition of a setting
+func LookupSetting(key string) (SettingDefinition, bool) {
+	for _, def := range settingDefinitions {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return SettingDefinition{}, false
+}
+
+// SettingsPath returns the path to the persistent settings file
+func SettingsPath() string {
+	paths := DefaultPaths()
+	return filepath.Join(paths.Config, SettingsFileName)
+}
+
+// LoadSettings reads the settings file. It is read once per process;
+// a missing file yields empty settings.
+func LoadSettings() (*Settings, error) {
+	settingsCacheOnce.Do(func() {
+		settingsCache, settingsCacheErr = readSettings()
+	})
+	return settingsCache, settingsCacheErr
+}
+
+// readSettings reads the settings file from disk
+func readSettings() (*Settings, error) {
+	data, err := os.ReadFile(SettingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Settings{}, nil
+		}
+		return &Settings{}, err
+	}
+
+	settings := &Settings{}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return &Settings{}, fmt.Errorf("failed to parse settings file: %w", err)
+	}
+	return settings, nil
+}
+
+// ResetSettingsCache forces the settings file to be read again on next access.
+// This is primarily useful for testing.
+func ResetSettingsCache() {
+	settingsCacheOnce = sync.Once{}
+	settingsCache = nil
+	settingsCacheErr = nil
+	resetProjectSettingsCache()
+}
+
+// GetSetting returns the effective value of a setting and where it came from.
+// Precedence: environment variable > project .dtvem/config.json > config.json >
+// default (empty).
+func GetSetting(key string) (string, SettingSource) {
+	def, ok := LookupSetting(key)
+	if !ok {
+		return "", SettingFromDefault
+	}
+
+	if value := os.Getenv(def.EnvVar); value != "" {
+		return value, SettingFromEnv
+	}
+
+	if project, _, err := LoadProjectSettings(); err == nil {
+		if value := *def.field(project); value != "" {
+			return value, SettingFromProject
+		}
+	}
+
+	settings, _ := LoadSettings()
+	if value := *def.field(settings); value != "" {
+		return value, SettingFromConfig
+	}
+
+	return "", SettingFromDefault
+}
+
+// Setting returns the effective value of a setting, or "" if it is unset
+func Setting(key string) string {
+	value, _ := GetSetting(key)
+	return value
+}
+
+// SetSetting stores a setting in config.json
+func SetSetting(key, value string) error {
+	return updateSetting(key, value)
+}
+
+// UnsetSetting removes a setting from config.json
+func UnsetSetting(key string) error {
+	return updateSetting(key, "")
+}
+
+// updateSetting writes a single setting to config.json, preserving the others
+func updateSetting(key, value string) error {
+	def, ok := LookupSetting(key)
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	settings, err := readSettings()
+	if err != nil {
+		return err
+	}
+	*def.field(settings) = value
+
+	settingsPath := SettingsPath()
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
+		return err
+	}
+
+	ResetSettingsCache()
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// setupSettingsRoot points the config at a temporary dtvem root with no settings in the environment
+func setupSettingsRoot(t *testing.T) {
+	t.Helper()
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	for _, def := range settingDefinitions {
+		t.Setenv(def.EnvVar, "")
+	}
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+}
+
+func TestSettings_RoundTrip(t *testing.T) {
+	setupSettingsRoot(t)
+
+	if err := SetSetting(SettingNetworkTimeout, "5m"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+	if err := SetSetting(SettingAutoInstall, "true"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+
+	// Read back from disk, as a new process would
+	ResetSettingsCache()
+	settings, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings() error: %v", err)
+	}
+	if settings.NetworkTimeout != "5m" || settings.AutoInstall != "true" {
+		t.Errorf("LoadSettings() = %+v, want network-timeout 5m and auto-install true", settings)
+	}
+
+	if err := UnsetSetting(SettingAutoInstall); err != nil {
+		t.Fatalf("UnsetSetting() error: %v", err)
+	}
+	if value, source := GetSetting(SettingAutoInstall); value != "" || source != SettingFromDefault {
+		t.Errorf("GetSetting() after unset = (%q, %q), want default", value, source)
+	}
+	if value := Setting(SettingNetworkTimeout); value != "5m" {
+		t.Errorf("Setting(network-timeout) after unsetting another key = %q, want 5m", value)
+	}
+}
+
+func TestGetSetting_Precedence(t *testing.T) {
+	setupSettingsRoot(t)
+
+	if value, source := GetSetting(SettingNetworkTimeout); value != "" || source != SettingFromDefault {
+		t.Errorf("GetSetting() with nothing set = (%q, %q), want default", value, source)
+	}
+
+	if err := SetSetting(SettingNetworkTimeout, "5m"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+	if value, source := GetSetting(SettingNetworkTimeout); value != "5m" || source != SettingFromConfig {
+		t.Errorf("GetSetting() from config = (%q, %q), want (5m, config)", value, source)
+	}
+
+	t.Setenv(NetworkTimeoutEnvVar, "30s")
+	if value, source := GetSetting(SettingNetworkTimeout); value != "30s" || source != SettingFromEnv {
+		t.Errorf("GetSetting() with env set = (%q, %q), want (30s, environment)", value, source)
+	}
+}
+
+func TestSetSetting_UnknownKey(t *testing.T) {
+	setupSettingsRoot(t)
+
+	if err := SetSetting("no-such-setting", "value"); err == nil {
+		t.Error("SetSetting() with an unknown key should fail")
+	}
+	if _, err := os.Stat(SettingsPath()); !os.IsNotExist(err) {
+		t.Errorf("settings file should not be created for an unknown key: %v", err)
+	}
+}
+
+func TestLoadSettings_InvalidFile(t *testing.T) {
+	setupSettingsRoot(t)
+
+	if err := os.MkdirAll(DefaultPaths().Config, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(SettingsPath(), []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write settings file: %v", err)
+	}
+
+	if _, err := LoadSettings(); err == nil {
+		t.Error("LoadSettings() should fail for an invalid file")
+	}
+	if value, source := GetSetting(SettingArch); value != "" || source != SettingFromDefault {
+		t.Errorf("GetSetting() with an invalid file = (%q, %q), want default", value, source)
+	}
+}
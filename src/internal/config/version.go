@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
 )
 
 // RuntimesConfig represents the flat structure of runtimes.json
@@ -14,65 +17,232 @@ type RuntimesConfig map[string]string
 // SchemaURL is the URL to the runtimes.json schema
 const SchemaURL = "https://raw.githubusercontent.com/dtvem/dtvem/main/schemas/runtimes.schema.json"
 
+// VersionSource describes where a resolved version was configured
+type VersionSource string
+
+const (
+	// VersionSourceLocal means the version came from a project's .dtvem/runtimes.json
+	VersionSourceLocal VersionSource = "local"
+	// VersionSourceGlobal means the version came from the global config
+	VersionSourceGlobal VersionSource = "global"
+)
+
+// ResolvedVersion is a resolved version along with where it was configured
+type ResolvedVersion struct {
+	Version string
+	Source  VersionSource
+	// File is the config file that set the version
+	File string
+	// Pin is the configured wildcard pin (e.g., "20.x") that Version was resolved from, if any.
+	// When no installed version matches the pin, Version is the pin itself.
+	Pin string
+}
+
 // ResolveVersion finds the version to use for a runtime
 // Priority: local dtvem.config.json file (walking up directory tree) > global config
 func ResolveVersion(runtimeName string) (string, error) {
+	resolved, err := ResolveVersionWithSource(runtimeName)
+	if err != nil {
+		return "", err
+	}
+	return resolved.Version, nil
+}
+
+// ResolveVersionWithSource finds the version to use for a runtime, like ResolveVersion,
+// and also reports which config file it came from.
+func ResolveVersionWithSource(runtimeName string) (ResolvedVersion, error) {
 	// First, try to find local version
-	localVersion, err := findLocalVersion(runtimeName)
+	localVersion, localFile, err := findLocalVersionFile(runtimeName)
 	if err == nil && localVersion != "" {
-		return localVersion, nil
+		return resolvePin(runtimeName, ResolvedVersion{Version: localVersion, Source: VersionSourceLocal, File: localFile}), nil
 	}
 
 	// Fall back to global version
 	globalVersion, err := GlobalVersion(runtimeName)
 	if err == nil && globalVersion != "" {
-		return globalVersion, nil
+		return resolvePin(runtimeName, ResolvedVersion{Version: globalVersion, Source: VersionSourceGlobal, File: GlobalConfigPath()}), nil
+	}
+
+	return ResolvedVersion{}, fmt.Errorf("no version configured for %s", runtimeName)
+}
+
+// resolvePin resolves a wildcard pin such as "20.x" to the newest installed matching version.
+// When nothing installed matches, the pin itself is returned as the version, which is
+// configured but not installed.
+func resolvePin(runtimeName string, resolved ResolvedVersion) ResolvedVersion {
+	if !runtime.IsVersionPin(resolved.Version) {
+		return resolved
 	}
 
-	return "", fmt.Errorf("no version configured for %s", runtimeName)
+	resolved.Pin = resolved.Version
+	if version, ok := runtime.MatchVersionPin(resolved.Version, installedVersionDirs(runtimeName)); ok {
+		resolved.Version = version
+	}
+	return resolved
+}
+
+// installedVersionDirs returns the names of the installed version directories for a runtime
+func installedVersionDirs(runtimeName string) []string {
+	runtimeDir := filepath.Join(DefaultPaths().Versions, runtimeName)
+	entries, err := os.ReadDir(runtimeDir)
+	if err != nil {
+		return nil
+	}
+
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if runtime.IsVersionDir(runtimeDir, entry) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions
 }
 
 // findLocalVersion walks up the directory tree looking for .dtvem/runtimes.json file
 // Stops at git repository root or filesystem root
 func findLocalVersion(runtimeName string) (string, error) {
+	version, _, err := findLocalVersionFile(runtimeName)
+	return version, err
+}
+
+// GitRoot returns the root of the git repository containing the current directory,
+// the same boundary the local version lookup stops at. It returns false outside a repository.
+func GitRoot() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	return walkUpToRepoRoot(dir, func(string) bool { return false })
+}
+
+// walkUpToRepoRoot calls visit for dir and then each of its parents, until visit
+// returns true, the repository root (the directory containing .git) has been
+// visited, or the filesystem root is reached. It returns the repository root when
+// the walk ended there.
+func walkUpToRepoRoot(dir string, visit func(dir string) (stop bool)) (string, bool) {
+	for {
+		if visit(dir) {
+			return "", false
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// findLocalVersionFile is like findLocalVersion but also returns the file the version was read from
+func findLocalVersionFile(runtimeName string) (string, string, error) {
 	// Start from current working directory
 	currentDir, err := os.Getwd()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	// Walk up the directory tree
-	for {
-		configDir := filepath.Join(currentDir, LocalConfigDirName)
-		versionFile := filepath.Join(configDir, RuntimesFileName)
+	// The closest .env version, used only if no other file sets one
+	dotEnvVersion, dotEnvFile := "", ""
+	readDotEnv := DotEnvEnabled()
 
+	foundVersion, foundFile := "", ""
+	walkUpToRepoRoot(currentDir, func(dir string) bool {
 		// Check if .dtvem/runtimes.json exists
+		versionFile := filepath.Join(dir, LocalConfigDirName, RuntimesFileName)
 		if _, err := os.Stat(versionFile); err == nil {
 			version, err := readVersionFile(versionFile, runtimeName)
 			if err == nil && version != "" {
-				return version, nil
+				foundVersion, foundFile = version, versionFile
+				return true
 			}
 		}
 
-		// Check if this directory contains a .git directory (repository root)
-		gitDir := filepath.Join(currentDir, ".git")
-		if _, err := os.Stat(gitDir); err == nil {
-			// We've reached the git repository root, stop here
-			break
+		if readDotEnv && dotEnvFile == "" {
+			envFile := filepath.Join(dir, DotEnvFileName)
+			if data, err := os.ReadFile(envFile); err == nil {
+				if version := ParseDotEnvVersions(data)[runtimeName]; version != "" {
+					dotEnvVersion, dotEnvFile = version, envFile
+				}
+			}
 		}
 
-		// Move up one directory
-		parent := filepath.Dir(currentDir)
-
-		// Stop if we've reached the filesystem root
-		if parent == currentDir {
-			break
+		// Check runtime-specific version files used by other version managers
+		for _, name := range runtimeVersionFiles[runtimeName] {
+			versionFile := filepath.Join(dir, name)
+			if version, err := readPlainVersionFile(versionFile); err == nil {
+				if version, ok := ecosystemVersion(runtimeName, version); ok {
+					foundVersion, foundFile = version, versionFile
+					return true
+				}
+			}
 		}
+		return false
+	})
 
-		currentDir = parent
+	if foundFile != "" {
+		return foundVersion, foundFile, nil
+	}
+	if dotEnvFile != "" {
+		return dotEnvVersion, dotEnvFile, nil
+	}
+
+	return "", "", fmt.Errorf("no local version file found")
+}
+
+// runtimeVersionFiles lists the version files of other version managers that are honored
+// for each runtime, in priority order. .dtvem/runtimes.json in the same directory takes precedence.
+var runtimeVersionFiles = map[string][]string{
+	"node":   {".node-version", ".nvmrc"},
+	"python": {".python-version"},
+	"ruby":   {".ruby-version"},
+}
+
+// ecosystemVersion turns the version read from another version manager's file into
+// a dtvem version. A "ruby-" prefix (rbenv, chruby) is stripped, and a partial version
+// such as "20" or "3.11" becomes the wildcard pin "20.x" or "3.11.x", which resolves to
+// the newest matching install as those managers do. "system" (nvm, pyenv, rbenv) is the
+// reserved system version. Other values that aren't versions, such as nvm's "lts/*",
+// are not usable and return false.
+func ecosystemVersion(runtimeName, version string) (string, bool) {
+	if runtime.IsSystemVersion(version) {
+		return runtime.SystemVersion, true
+	}
+	if runtimeName == "ruby" {
+		version = strings.TrimPrefix(version, "ruby-")
+	}
+	if !runtime.LooksLikeVersion(version) || strings.ContainsAny(version, "/\\") {
+		return "", false
+	}
+	if runtime.IsVersionPin(version) {
+		return version, true
+	}
+
+	if components, numeric := runtime.NumericComponents(version); numeric && components < 3 {
+		return version + ".x", true
+	}
+	return version, true
+}
+
+// readPlainVersionFile reads a version file containing just a version string (e.g. .node-version).
+// Blank lines and # comments are ignored, and a leading "v" is stripped.
+func readPlainVersionFile(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.TrimPrefix(line, "v"), nil
 	}
 
-	return "", fmt.Errorf("no local version file found")
+	return "", fmt.Errorf("no version found in %s", filePath)
 }
 
 // readVersionFile reads a JSON config file and extracts the version for a runtime
@@ -93,7 +263,7 @@ func readVersionFile(filePath, runtimeName string) (string, error) {
 		return "", fmt.Errorf("runtime %s not found in config file", runtimeName)
 	}
 
-	return version, nil
+	return runtime.NormalizeVersion(version), nil
 }
 
 // ReadAllRuntimes reads all runtime/version pairs from a config file
@@ -199,10 +369,50 @@ func SetGlobalVersion(runtimeName, version string) error {
 	return os.WriteFile(configPath, data, 0644)
 }
 
+// UnsetGlobalVersion removes the global version for a runtime.
+// It is not an error if no global version is set.
+func UnsetGlobalVersion(runtimeName string) error {
+	configPath := GlobalConfigPath()
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	config := make(RuntimesConfig)
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if _, ok := config[runtimeName]; !ok {
+		return nil
+	}
+	delete(config, runtimeName)
+
+	data, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, data, 0644)
+}
+
 // SetLocalVersion sets the local version for a runtime in the current directory
 func SetLocalVersion(runtimeName, version string) error {
-	configDir := LocalConfigDir()
-	configPath := LocalConfigPath()
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	return SetLocalVersionIn(cwd, runtimeName, version)
+}
+
+// SetLocalVersionIn sets the local version for a runtime in dir's .dtvem/runtimes.json
+func SetLocalVersionIn(dir, runtimeName, version string) error {
+	configDir := filepath.Join(dir, LocalConfigDirName)
+	configPath := filepath.Join(configDir, RuntimesFileName)
 
 	// Ensure .dtvem directory exists
 	if err := os.MkdirAll(configDir, 0755); err != nil {
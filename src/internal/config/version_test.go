@@ -465,3 +465,431 @@ func TestSetLocalVersion_CreatesDirectoryAndFile(t *testing.T) {
 		t.Errorf("Config python version = %q, want %q", config["python"], "3.11.0")
 	}
 }
+
+func TestResolveVersion_WildcardPin(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+
+	// Run from a directory without a local config
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(tmpRoot); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	for _, v := range []string{"18.16.0", "20.2.0", "20.11.1", "20.9.0", "21.0.0"} {
+		if err := os.MkdirAll(filepath.Join(tmpRoot, "versions", "node", v), 0755); err != nil {
+			t.Fatalf("Failed to create version dir: %v", err)
+		}
+	}
+
+	tests := []struct {
+		pin     string
+		want    string
+		wantPin string
+	}{
+		{pin: "20.x", want: "20.11.1", wantPin: "20.x"},
+		{pin: "20.9.x", want: "20.9.0", wantPin: "20.9.x"},
+		{pin: "18.x", want: "18.16.0", wantPin: "18.x"},
+		// A pin nothing installed matches is configured but not installed
+		{pin: "19.x", want: "19.x", wantPin: "19.x"},
+		{pin: "20.2.0", want: "20.2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pin, func(t *testing.T) {
+			if err := SetGlobalVersion("node", tt.pin); err != nil {
+				t.Fatalf("SetGlobalVersion() error: %v", err)
+			}
+
+			resolved, err := ResolveVersionWithSource("node")
+			if err != nil {
+				t.Fatalf("ResolveVersionWithSource() error: %v", err)
+			}
+			if resolved.Version != tt.want {
+				t.Errorf("ResolveVersionWithSource() = %q, want %q", resolved.Version, tt.want)
+			}
+			if resolved.Pin != tt.wantPin {
+				t.Errorf("ResolvedVersion.Pin = %q, want %q", resolved.Pin, tt.wantPin)
+			}
+		})
+	}
+}
+
+func TestResolveVersion_VPrefixMatchesInstall(t *testing.T) {
+	tests := []struct {
+		name       string
+		installed  string
+		configured string
+	}{
+		{name: "installed with v, pinned without", installed: "v20.11.1", configured: "20.11.1"},
+		{name: "installed without v, pinned with", installed: "20.11.1", configured: "v20.11.1"},
+		{name: "same form", installed: "20.11.1", configured: "20.11.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpRoot := t.TempDir()
+			t.Setenv("DTVEM_ROOT", tmpRoot)
+			ResetPathsCache()
+			t.Cleanup(ResetPathsCache)
+
+			originalDir, err := os.Getwd()
+			if err != nil {
+				t.Fatalf("Failed to get working directory: %v", err)
+			}
+			if err := os.Chdir(tmpRoot); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+			t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+			installPath := RuntimeVersionPath("node", tt.installed)
+			if filepath.Base(installPath) != "20.11.1" {
+				t.Errorf("RuntimeVersionPath(node, %q) = %q, want a 20.11.1 directory", tt.installed, installPath)
+			}
+			if err := os.MkdirAll(installPath, 0755); err != nil {
+				t.Fatalf("Failed to create version dir: %v", err)
+			}
+			if err := SetGlobalVersion("node", tt.configured); err != nil {
+				t.Fatalf("SetGlobalVersion() error: %v", err)
+			}
+
+			version, err := ResolveVersion("node")
+			if err != nil {
+				t.Fatalf("ResolveVersion() error: %v", err)
+			}
+			if version != "20.11.1" {
+				t.Errorf("ResolveVersion() = %q, want 20.11.1", version)
+			}
+			if got := RuntimeVersionPath("node", version); got != installPath {
+				t.Errorf("RuntimeVersionPath(node, %q) = %q, want the install at %q", version, got, installPath)
+			}
+		})
+	}
+}
+
+func TestRuntimeVersionPath_LegacyVPrefixDir(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+
+	legacyPath := filepath.Join(tmpRoot, "versions", "node", "v18.16.0")
+	if err := os.MkdirAll(legacyPath, 0755); err != nil {
+		t.Fatalf("Failed to create version dir: %v", err)
+	}
+
+	for _, version := range []string{"18.16.0", "v18.16.0"} {
+		if got := RuntimeVersionPath("node", version); got != legacyPath {
+			t.Errorf("RuntimeVersionPath(node, %q) = %q, want the existing %q", version, got, legacyPath)
+		}
+	}
+	if got := RuntimeVersionPath("node", "v20.11.1"); filepath.Base(got) != "20.11.1" {
+		t.Errorf("RuntimeVersionPath(node, v20.11.1) = %q, want a 20.11.1 directory", got)
+	}
+}
+
+func TestResolveVersion_NodeVersionFile(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+
+	projectDir := t.TempDir()
+	subDir := filepath.Join(projectDir, "src")
+	for _, dir := range []string{filepath.Join(projectDir, ".git"), subDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	nodeVersionFile := filepath.Join(projectDir, ".node-version")
+	if err := os.WriteFile(nodeVersionFile, []byte("# pinned for CI\nv20.11.1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .node-version: %v", err)
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	resolved, err := ResolveVersionWithSource("node")
+	if err != nil {
+		t.Fatalf("ResolveVersionWithSource() error: %v", err)
+	}
+	if resolved.Version != "20.11.1" || resolved.Source != VersionSourceLocal || resolved.File != nodeVersionFile {
+		t.Errorf("ResolveVersionWithSource() = %+v, want 20.11.1 from %s", resolved, nodeVersionFile)
+	}
+
+	// .node-version only applies to Node.js
+	if _, err := ResolveVersion("python"); err == nil {
+		t.Error("ResolveVersion(python) should not read .node-version")
+	}
+
+	// .dtvem/runtimes.json in the same directory takes precedence
+	if err := os.MkdirAll(filepath.Join(projectDir, LocalConfigDirName), 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	runtimesFile := filepath.Join(projectDir, LocalConfigDirName, RuntimesFileName)
+	if err := os.WriteFile(runtimesFile, []byte(`{"node": "18.16.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write runtimes.json: %v", err)
+	}
+	if version, err := ResolveVersion("node"); err != nil || version != "18.16.0" {
+		t.Errorf("ResolveVersion() = %q, %v, want 18.16.0 from runtimes.json", version, err)
+	}
+}
+
+func TestReadPlainVersionFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"bare version", "20.11.1", "20.11.1", false},
+		{"v prefix and newline", "v18.16.0\n", "18.16.0", false},
+		{"comments and blank lines", "# comment\n\n  22.0.0  \n", "22.0.0", false},
+		{"empty file", "\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".node-version")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			got, err := readPlainVersionFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readPlainVersionFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readPlainVersionFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEcosystemVersion(t *testing.T) {
+	tests := []struct {
+		runtimeName string
+		version     string
+		want        string
+		wantOK      bool
+	}{
+		{"node", "20.11.1", "20.11.1", true},
+		{"node", "20", "20.x", true},
+		{"node", "20.11", "20.11.x", true},
+		{"node", "20.x", "20.x", true},
+		{"node", "lts/iron", "", false},
+		{"node", "lts/*", "", false},
+		{"node", "node", "", false},
+		{"python", "3.12.1", "3.12.1", true},
+		{"python", "3.11", "3.11.x", true},
+		{"python", "3.13.0rc1", "3.13.0rc1", true},
+		{"python", "system", "system", true},
+		{"node", "system", "system", true},
+		{"python", "3.11.4/envs/venv", "", false},
+		{"ruby", "ruby-3.3.0", "3.3.0", true},
+		{"ruby", "3.2", "3.2.x", true},
+		{"ruby", "jruby-9.4.5.0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.runtimeName+" "+tt.version, func(t *testing.T) {
+			got, ok := ecosystemVersion(tt.runtimeName, tt.version)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ecosystemVersion(%q, %q) = (%q, %v), want (%q, %v)",
+					tt.runtimeName, tt.version, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestResolveVersion_EcosystemVersionFiles(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+
+	for _, dir := range []string{"node/18.16.0", "node/18.20.4", "python/3.12.1"} {
+		if err := os.MkdirAll(filepath.Join(tmpRoot, "versions", dir), 0755); err != nil {
+			t.Fatalf("Failed to create version dir: %v", err)
+		}
+	}
+
+	// A file above the repository root must not be read
+	outerDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outerDir, ".ruby-version"), []byte("3.3.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .ruby-version: %v", err)
+	}
+	projectDir := filepath.Join(outerDir, "project")
+	subDir := filepath.Join(projectDir, "src")
+	for _, dir := range []string{filepath.Join(projectDir, ".git"), subDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	files := map[string]string{
+		".nvmrc":          "v18\n",
+		".python-version": "3.12.1\n3.11.9\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	tests := []struct {
+		runtimeName string
+		want        string
+		wantFile    string
+		wantPin     string
+	}{
+		{"node", "18.20.4", ".nvmrc", "18.x"},
+		{"python", "3.12.1", ".python-version", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.runtimeName, func(t *testing.T) {
+			resolved, err := ResolveVersionWithSource(tt.runtimeName)
+			if err != nil {
+				t.Fatalf("ResolveVersionWithSource() error: %v", err)
+			}
+			wantFile := filepath.Join(projectDir, tt.wantFile)
+			if resolved.Version != tt.want || resolved.File != wantFile || resolved.Pin != tt.wantPin {
+				t.Errorf("ResolveVersionWithSource() = %+v, want %s (pin %q) from %s", resolved, tt.want, tt.wantPin, wantFile)
+			}
+		})
+	}
+
+	if version, err := ResolveVersion("ruby"); err == nil {
+		t.Errorf("ResolveVersion(ruby) = %q, should stop at the repository root", version)
+	}
+}
+
+func TestGitRoot(t *testing.T) {
+	tmpRoot := t.TempDir()
+	repoDir := filepath.Join(tmpRoot, "repo")
+	subDir := filepath.Join(repoDir, "a", "b")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory structure: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git directory: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	root, ok := GitRoot()
+	if !ok {
+		t.Fatal("GitRoot() found no repository from a nested subdirectory")
+	}
+	rootResolved, _ := filepath.EvalSymlinks(root)
+	repoResolved, _ := filepath.EvalSymlinks(repoDir)
+	if rootResolved != repoResolved {
+		t.Errorf("GitRoot() = %q, want %q", root, repoDir)
+	}
+}
+
+func TestSetLocalVersionIn_GitRootFromSubdir(t *testing.T) {
+	tmpRoot := t.TempDir()
+	repoDir := filepath.Join(tmpRoot, "repo")
+	subDir := filepath.Join(repoDir, "packages", "web")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory structure: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git directory: %v", err)
+	}
+
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	root, ok := GitRoot()
+	if !ok {
+		t.Fatal("GitRoot() found no repository")
+	}
+	if err := SetLocalVersionIn(root, "node", "22.0.0"); err != nil {
+		t.Fatalf("SetLocalVersionIn() error: %v", err)
+	}
+
+	// The file is written at the repository root, not the current directory
+	if _, err := os.Stat(filepath.Join(subDir, ".dtvem", "runtimes.json")); !os.IsNotExist(err) {
+		t.Error("SetLocalVersionIn() wrote a config file in the current directory")
+	}
+	data, err := os.ReadFile(filepath.Join(repoDir, ".dtvem", "runtimes.json"))
+	if err != nil {
+		t.Fatalf("Failed to read config at the repository root: %v", err)
+	}
+	var config RuntimesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if config["node"] != "22.0.0" {
+		t.Errorf("Config node version = %q, want %q", config["node"], "22.0.0")
+	}
+
+	// And it is found from the subdirectory
+	version, err := findLocalVersion("node")
+	if err != nil {
+		t.Fatalf("findLocalVersion() error: %v", err)
+	}
+	if version != "22.0.0" {
+		t.Errorf("findLocalVersion() = %q, want %q", version, "22.0.0")
+	}
+}
+
+func TestResolveVersion_SystemVersion(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	ResetPathsCache()
+	t.Cleanup(ResetPathsCache)
+
+	projectDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalDir) }()
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	// An .nvmrc that defers to the system Node.js
+	if err := os.WriteFile(filepath.Join(projectDir, ".nvmrc"), []byte("system\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .nvmrc: %v", err)
+	}
+	if version, err := ResolveVersion("node"); err != nil || version != "system" {
+		t.Errorf("ResolveVersion(node) from .nvmrc = (%q, %v), want system", version, err)
+	}
+
+	// runtimes.json accepts it too, without any version installed
+	if err := SetLocalVersion("python", "system"); err != nil {
+		t.Fatalf("SetLocalVersion() error: %v", err)
+	}
+	if version, err := ResolveVersion("python"); err != nil || version != "system" {
+		t.Errorf("ResolveVersion(python) = (%q, %v), want system", version, err)
+	}
+}
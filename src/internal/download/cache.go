@@ -0,0 +1,299 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// ArchiveCacheDirName is the name of the directory under the cache dir that holds downloaded archives
+const ArchiveCacheDirName = "archives"
+
+// checksumSuffix is appended to an archive's path to name its checksum file
+const checksumSuffix = ".sha256"
+
+// ArchiveCachePath returns where the archive of a runtime version is kept while it is
+// being installed. Archives are keyed by version as well as name, since mirrored
+// archives are named after the platform alone (e.g. linux-amd64.tar.gz).
+func ArchiveCachePath(runtimeName, version, archiveName string) string {
+	paths := config.DefaultPaths()
+	return filepath.Join(paths.Cache, ArchiveCacheDirName, runtimeName, version, archiveName)
+}
+
+// keepArchiveDir is where archives are copied for the user to keep; empty disables copying
+var keepArchiveDir string
+
+// SetKeepArchiveDir makes CachedArchive copy every archive it provides into dir,
+// so it survives the cleanup after the install. An empty dir disables copying.
+func SetKeepArchiveDir(dir string) {
+	keepArchiveDir = dir
+}
+
+// CachedArchive downloads an archive into the archive cache and returns its path.
+// The download is verified against expectedSHA256, the manifest's checksum; when
+// that is empty the archive is used unverified, with a warning.
+// If a previous download of the same archive is still cached and its checksum
+// matches the one recorded when it was downloaded, the download is skipped.
+// This lets an install that failed after downloading (e.g. during extraction)
+// be retried without downloading the archive again.
+func CachedArchive(runtimeName, version, url, archiveName, expectedSHA256 string) (string, error) {
+	return cachedArchive(runtimeName, version, url, archiveName, expectedSHA256, func(url, destPath string) error {
+		if expectedSHA256 == "" {
+			return File(url, destPath)
+		}
+		return FileVerified(url, destPath, expectedSHA256)
+	})
+}
+
+// CachedArchiveWithProgress is CachedArchive, but reports download progress to
+// progress instead of drawing a progress bar. progress is not called when the
+// archive is already cached.
+func CachedArchiveWithProgress(runtimeName, version, url, archiveName, expectedSHA256 string, progress func(current, total int64)) (string, error) {
+	return cachedArchive(runtimeName, version, url, archiveName, expectedSHA256, func(url, destPath string) error {
+		return FileWithChecksumProgress(url, destPath, expectedSHA256, progress)
+	})
+}
+
+// cachedArchive provides an archive from the cache, downloading it with fetch if needed
+func cachedArchive(runtimeName, version, url, archiveName, expectedSHA256 string, fetch func(url, destPath string) error) (string, error) {
+	archivePath := ArchiveCachePath(runtimeName, version, archiveName)
+
+	if expectedSHA256 == "" {
+		ui.Warning("No checksum is known for %s; the download is unverified", archiveName)
+	}
+
+	if isArchiveCachedWithChecksum(archivePath, expectedSHA256) {
+		ui.Info("Using previously downloaded %s", archiveName)
+	} else if err := cacheArchive(archivePath, url, fetch); err != nil {
+		return "", err
+	}
+
+	if keepArchiveDir != "" {
+		if err := keepArchive(archivePath, keepArchiveDir); err != nil {
+			ui.Warning("Could not keep a copy of %s: %v", archiveName, err)
+		}
+	}
+
+	return archivePath, nil
+}
+
+// keepArchive copies an archive into dir and prints where it is and its checksum
+func keepArchive(archivePath, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	keptPath := filepath.Join(dir, filepath.Base(archivePath))
+	if err := copyFile(archivePath, keptPath); err != nil {
+		return err
+	}
+
+	checksum, err := ComputeSHA256(keptPath)
+	if err != nil {
+		return err
+	}
+
+	ui.Info("Kept archive: %s", keptPath)
+	ui.Info("SHA256: %s", checksum)
+	return nil
+}
+
+// copyFile copies the file at src to dst, replacing dst if it exists
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// PrefetchArchive downloads an archive into the archive cache without printing
+// progress, so several archives can be fetched at once. It is verified against
+// expectedSHA256 unless that is empty. A later CachedArchive call for the same
+// archive uses the prefetched file.
+func PrefetchArchive(runtimeName, version, url, archiveName, expectedSHA256 string) error {
+	archivePath := ArchiveCachePath(runtimeName, version, archiveName)
+	if isArchiveCachedWithChecksum(archivePath, expectedSHA256) {
+		return nil
+	}
+
+	return cacheArchive(archivePath, url, func(url, destPath string) error {
+		return FileWithChecksum(url, destPath, expectedSHA256)
+	})
+}
+
+// cacheArchive downloads url to archivePath with fetch and records its checksum
+func cacheArchive(archivePath, url string, fetch func(url, destPath string) error) error {
+	// Clear any stale or partial download before starting over
+	_ = os.Remove(archivePath + checksumSuffix)
+
+	if err := fetch(url, archivePath); err != nil {
+		_ = os.Remove(archivePath)
+		return err
+	}
+
+	checksum, err := ComputeSHA256(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to checksum download: %w", err)
+	}
+	if err := os.WriteFile(archivePath+checksumSuffix, []byte(checksum+"\n"), 0644); err != nil {
+		ui.Debug("Failed to record checksum for %s: %v", archivePath, err)
+	}
+
+	return nil
+}
+
+// IsArchiveCached reports whether a complete, uncorrupted archive exists at archivePath.
+// An archive without a recorded checksum is treated as incomplete.
+func IsArchiveCached(archivePath string) bool {
+	data, err := os.ReadFile(archivePath + checksumSuffix)
+	if err != nil {
+		return false
+	}
+
+	expected := strings.TrimSpace(string(data))
+	if expected == "" {
+		return false
+	}
+
+	if err := VerifyFile(archivePath, expected); err != nil {
+		ui.Debug("Cached archive %s is invalid: %v", archivePath, err)
+		return false
+	}
+
+	return true
+}
+
+// isArchiveCachedWithChecksum is IsArchiveCached, also requiring that the checksum
+// recorded for the archive is expectedSHA256 when that is set. An archive cached
+// from a different source than the manifest describes is then downloaded again.
+func isArchiveCachedWithChecksum(archivePath, expectedSHA256 string) bool {
+	if !IsArchiveCached(archivePath) {
+		return false
+	}
+	if expectedSHA256 == "" {
+		return true
+	}
+
+	recorded, err := os.ReadFile(archivePath + checksumSuffix)
+	return err == nil && strings.EqualFold(strings.TrimSpace(string(recorded)), strings.TrimSpace(expectedSHA256))
+}
+
+// Cached archive states reported by VerifyCache
+const (
+	CachedArchiveOK         = "ok"         // the archive matches its recorded checksum
+	CachedArchiveCorrupt    = "corrupt"    // the archive no longer matches its recorded checksum
+	CachedArchiveIncomplete = "incomplete" // no checksum was recorded, e.g. an interrupted download
+)
+
+// CachedArchiveStatus is the result of verifying one archive in the archive cache
+type CachedArchiveStatus struct {
+	Runtime string // Runtime the archive belongs to
+	Version string // Version the archive belongs to
+	Name    string // Archive file name
+	Path    string // Full path of the archive
+	State   string // One of the CachedArchive* states
+	Err     error  // Why the archive failed verification, nil when it is OK
+}
+
+// VerifyCache re-hashes every archive in the archive cache and compares it with the
+// checksum recorded when it was downloaded, to catch disk corruption or tampering
+// before a cached archive is installed. A missing cache directory has no archives.
+func VerifyCache() ([]CachedArchiveStatus, error) {
+	cacheDir := filepath.Join(config.DefaultPaths().Cache, ArchiveCacheDirName)
+
+	var statuses []CachedArchiveStatus
+	err := walkArchiveCache(func(path string) {
+		if strings.HasSuffix(path, checksumSuffix) {
+			return
+		}
+		rel, err := filepath.Rel(cacheDir, path)
+		if err != nil {
+			return
+		}
+		// Archives are cached as <runtime>/<version>/<name>
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 3 {
+			return
+		}
+		statuses = append(statuses, verifyCachedArchive(parts[0], parts[1], parts[2]))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// verifyCachedArchive checks one cached archive against its recorded checksum
+func verifyCachedArchive(runtimeName, version, archiveName string) CachedArchiveStatus {
+	status := CachedArchiveStatus{
+		Runtime: runtimeName,
+		Version: version,
+		Name:    archiveName,
+		Path:    ArchiveCachePath(runtimeName, version, archiveName),
+		State:   CachedArchiveOK,
+	}
+
+	data, err := os.ReadFile(status.Path + checksumSuffix)
+	expected := strings.TrimSpace(string(data))
+	if err != nil || expected == "" {
+		status.State = CachedArchiveIncomplete
+		status.Err = fmt.Errorf("no checksum recorded")
+		return status
+	}
+
+	if err := VerifyFile(status.Path, expected); err != nil {
+		status.State = CachedArchiveCorrupt
+		status.Err = err
+	}
+	return status
+}
+
+// IsCorruptArchive reports whether err means a downloaded archive is damaged:
+// its checksum doesn't match or it can't be extracted
+func IsCorruptArchive(err error) bool {
+	var corrupt *ErrCorruptArchive
+	var mismatch *ErrChecksumMismatch
+	return errors.As(err, &corrupt) || errors.As(err, &mismatch)
+}
+
+// RetryCorrupt runs attempt, which downloads a cached archive and installs from it.
+// If the archive turns out to be corrupt, it is discarded from the cache and
+// attempt is run once more, downloading it again; transient CDN corruption then
+// doesn't need a manual cleanup. There is only one retry, so a broken upstream
+// archive can't cause a loop.
+func RetryCorrupt(runtimeName, version, archiveName string, attempt func() error) error {
+	err := attempt()
+	if err == nil || !IsCorruptArchive(err) {
+		return err
+	}
+
+	ui.Warning("%s is corrupt (%v); downloading it again", archiveName, err)
+	RemoveCachedArchive(runtimeName, version, archiveName)
+	return attempt()
+}
+
+// RemoveCachedArchive deletes a cached archive once it has been installed
+func RemoveCachedArchive(runtimeName, version, archiveName string) {
+	archivePath := ArchiveCachePath(runtimeName, version, archiveName)
+	_ = os.Remove(archivePath)
+	_ = os.Remove(archivePath + checksumSuffix)
+}
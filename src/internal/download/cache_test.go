@@ -0,0 +1,449 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// setupArchiveCache points the cache at a temporary dtvem root and serves an archive,
+// counting how many times it is downloaded
+func setupArchiveCache(t *testing.T) (url string, downloads *int) {
+	t.Helper()
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		_, _ = w.Write([]byte("archive contents"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL + "/node-v20.0.0.tar.gz", &count
+}
+
+func TestCachedArchive_SecondRunSkipsDownload(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+
+	first, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+
+	// Simulate an extraction failure: the archive stays cached and the install is retried
+	second, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+
+	if first != second {
+		t.Errorf("CachedArchive() paths differ: %q vs %q", first, second)
+	}
+	if *downloads != 1 {
+		t.Errorf("archive downloaded %d times, want 1", *downloads)
+	}
+}
+
+func TestCachedArchive_CorruptArchiveIsDownloadedAgain(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+
+	archivePath, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+	if err := os.WriteFile(archivePath, []byte("truncated"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt archive: %v", err)
+	}
+
+	if _, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+	if *downloads != 2 {
+		t.Errorf("archive downloaded %d times, want 2", *downloads)
+	}
+}
+
+func TestCachedArchive_ArchiveWithoutChecksumIsDownloadedAgain(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+
+	// A partial download from an interrupted run has no checksum file
+	archivePath := ArchiveCachePath("node", "20.0.0", "node-v20.0.0.tar.gz")
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		t.Fatalf("Failed to create cache dir: %v", err)
+	}
+	if err := os.WriteFile(archivePath, []byte("partial"), 0644); err != nil {
+		t.Fatalf("Failed to write partial archive: %v", err)
+	}
+
+	if _, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+	if *downloads != 1 {
+		t.Errorf("archive downloaded %d times, want 1", *downloads)
+	}
+}
+
+func TestCachedArchive_KeyedByVersion(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+
+	// Mirrored archives share a name across versions
+	first, err := CachedArchive("node", "20.0.0", url, "linux-amd64.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+	second, err := CachedArchive("node", "22.0.0", url, "linux-amd64.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+
+	if first == second {
+		t.Errorf("CachedArchive() cached both versions at %s", first)
+	}
+	if *downloads != 2 {
+		t.Errorf("downloaded %d times, want once per version", *downloads)
+	}
+}
+
+func TestRemoveCachedArchive(t *testing.T) {
+	url, _ := setupArchiveCache(t)
+
+	archivePath, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+
+	RemoveCachedArchive("node", "20.0.0", "node-v20.0.0.tar.gz")
+
+	if IsArchiveCached(archivePath) {
+		t.Error("IsArchiveCached() = true after RemoveCachedArchive()")
+	}
+	if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+		t.Errorf("archive still exists after RemoveCachedArchive(): %v", err)
+	}
+}
+
+func TestPrefetchArchive_UsedByCachedArchive(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+
+	if err := PrefetchArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("PrefetchArchive() error: %v", err)
+	}
+	if err := PrefetchArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("PrefetchArchive() error: %v", err)
+	}
+	if _, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+
+	if *downloads != 1 {
+		t.Errorf("archive downloaded %d times, want 1", *downloads)
+	}
+}
+
+func TestCachedArchive_KeepArchive(t *testing.T) {
+	url, _ := setupArchiveCache(t)
+	keepDir := filepath.Join(t.TempDir(), "kept")
+	SetKeepArchiveDir(keepDir)
+	t.Cleanup(func() { SetKeepArchiveDir("") })
+
+	if _, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+
+	// The install removes the cached archive once it succeeds; the kept copy stays
+	RemoveCachedArchive("node", "20.0.0", "node-v20.0.0.tar.gz")
+
+	data, err := os.ReadFile(filepath.Join(keepDir, "node-v20.0.0.tar.gz"))
+	if err != nil {
+		t.Fatalf("kept archive missing after install cleanup: %v", err)
+	}
+	if string(data) != "archive contents" {
+		t.Errorf("kept archive contents = %q, want %q", data, "archive contents")
+	}
+}
+
+func TestCachedArchive_KeepArchiveFromCache(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+
+	if _, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+
+	// An archive reused from the cache is kept as well
+	keepDir := t.TempDir()
+	SetKeepArchiveDir(keepDir)
+	t.Cleanup(func() { SetKeepArchiveDir("") })
+
+	if _, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", ""); err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+	if *downloads != 1 {
+		t.Errorf("archive downloaded %d times, want 1", *downloads)
+	}
+	if _, err := os.Stat(filepath.Join(keepDir, "node-v20.0.0.tar.gz")); err != nil {
+		t.Errorf("kept archive missing: %v", err)
+	}
+}
+
+func TestCachedArchiveWithProgress(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+
+	var last, total int64
+	calls := 0
+	progress := func(current, size int64) {
+		calls++
+		last, total = current, size
+	}
+
+	if _, err := CachedArchiveWithProgress("node", "20.0.0", url, "node-v20.0.0.tar.gz", "", progress); err != nil {
+		t.Fatalf("CachedArchiveWithProgress() error: %v", err)
+	}
+	if calls == 0 || last != int64(len("archive contents")) || total != last {
+		t.Errorf("progress ended at (%d, %d) after %d call(s), want the full archive", last, total, calls)
+	}
+
+	// A cached archive is not downloaded again and reports no progress
+	calls = 0
+	if _, err := CachedArchiveWithProgress("node", "20.0.0", url, "node-v20.0.0.tar.gz", "", progress); err != nil {
+		t.Fatalf("CachedArchiveWithProgress() error: %v", err)
+	}
+	if calls != 0 || *downloads != 1 {
+		t.Errorf("cached archive: %d progress call(s), %d download(s), want 0 and 1", calls, *downloads)
+	}
+}
+
+// setupFlakyArchive serves a valid .tar.gz archive, except that the first corruptFirst
+// downloads get a damaged copy, and counts the downloads
+func setupFlakyArchive(t *testing.T, corruptFirst int) (url string, downloads *int) {
+	t.Helper()
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	archivePath := filepath.Join(t.TempDir(), "node.tar.gz")
+	writeTestTarGz(t, archivePath)
+	valid, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count <= corruptFirst {
+			_, _ = w.Write(valid[:len(valid)/2])
+			return
+		}
+		_, _ = w.Write(valid)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL + "/node-v20.0.0.tar.gz", &count
+}
+
+// installFromCache is a RetryCorrupt attempt that downloads the archive and extracts it
+func installFromCache(url, destDir string) func() error {
+	return func() error {
+		archivePath, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", "")
+		if err != nil {
+			return err
+		}
+		return ExtractWithProgress(archivePath, destDir, nil)
+	}
+}
+
+func TestRetryCorrupt_CorruptDownloadIsRetried(t *testing.T) {
+	url, downloads := setupFlakyArchive(t, 1)
+	destDir := filepath.Join(t.TempDir(), "out")
+
+	if err := RetryCorrupt("node", "20.0.0", "node-v20.0.0.tar.gz", installFromCache(url, destDir)); err != nil {
+		t.Fatalf("RetryCorrupt() error: %v", err)
+	}
+	if *downloads != 2 {
+		t.Errorf("archive downloaded %d times, want 2", *downloads)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, "node", "bin", "node")); err != nil {
+		t.Errorf("archive from the second download was not extracted: %v", err)
+	}
+}
+
+func TestRetryCorrupt_RetriesOnlyOnce(t *testing.T) {
+	url, downloads := setupFlakyArchive(t, 5)
+
+	err := RetryCorrupt("node", "20.0.0", "node-v20.0.0.tar.gz", installFromCache(url, filepath.Join(t.TempDir(), "out")))
+	if !IsCorruptArchive(err) {
+		t.Errorf("RetryCorrupt() error = %v, want the corrupt archive error", err)
+	}
+	if *downloads != 2 {
+		t.Errorf("archive downloaded %d times, want 2", *downloads)
+	}
+}
+
+func TestRetryCorrupt_OtherErrorsAreNotRetried(t *testing.T) {
+	attempts := 0
+	err := RetryCorrupt("node", "20.0.0", "node-v20.0.0.tar.gz", func() error {
+		attempts++
+		return errors.New("connection refused")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("RetryCorrupt() = %v after %d attempts, want the error after 1 attempt", err, attempts)
+	}
+}
+
+func TestIsCorruptArchive(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"corrupt archive", &ErrCorruptArchive{Err: errors.New("unexpected EOF")}, true},
+		{"wrapped checksum mismatch", fmt.Errorf("verify: %w", &ErrChecksumMismatch{Expected: "a", Actual: "b"}), true},
+		{"other error", errors.New("permission denied"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCorruptArchive(tt.err); got != tt.want {
+				t.Errorf("IsCorruptArchive(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCache(t *testing.T) {
+	url, _ := setupArchiveCache(t)
+
+	good, err := CachedArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+	bad, err := CachedArchive("python", "3.13.1", url, "cpython-3.13.1.tar.gz", "")
+	if err != nil {
+		t.Fatalf("CachedArchive() error: %v", err)
+	}
+	if err := os.WriteFile(bad, []byte("bit rot"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt archive: %v", err)
+	}
+	partial := ArchiveCachePath("ruby", "3.4.1", "ruby-3.4.1.tar.gz")
+	if err := os.MkdirAll(filepath.Dir(partial), 0755); err != nil {
+		t.Fatalf("Failed to create cache directory: %v", err)
+	}
+	if err := os.WriteFile(partial, []byte("half"), 0644); err != nil {
+		t.Fatalf("Failed to write partial archive: %v", err)
+	}
+
+	statuses, err := VerifyCache()
+	if err != nil {
+		t.Fatalf("VerifyCache() error: %v", err)
+	}
+
+	want := map[string]string{
+		good:    CachedArchiveOK,
+		bad:     CachedArchiveCorrupt,
+		partial: CachedArchiveIncomplete,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("VerifyCache() returned %d archives, want %d: %+v", len(statuses), len(want), statuses)
+	}
+	for _, status := range statuses {
+		if status.State != want[status.Path] {
+			t.Errorf("%s state = %q, want %q", status.Path, status.State, want[status.Path])
+		}
+		if (status.Err != nil) != (status.State != CachedArchiveOK) {
+			t.Errorf("%s Err = %v for state %q", status.Path, status.Err, status.State)
+		}
+	}
+}
+
+func TestVerifyCache_NoCache(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	statuses, err := VerifyCache()
+	if err != nil || len(statuses) != 0 {
+		t.Errorf("VerifyCache() = %v, %v, want no archives and no error", statuses, err)
+	}
+}
+
+func TestCachedArchive_ManifestChecksum(t *testing.T) {
+	// SHA256 of "archive contents", the body served by setupArchiveCache
+	const archiveSHA256 = "f69f4865f861193a91d1c5544a894167a7137b788d10bac8edbf5d095f45cb4d"
+
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{"matching checksum", archiveSHA256, false},
+		{"matching checksum in upper case", strings.ToUpper(archiveSHA256), false},
+		{"mismatched checksum", strings.Repeat("0", 64), true},
+		{"no checksum", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, _ := setupArchiveCache(t)
+
+			archivePath, err := CachedArchiveWithProgress("node", "20.0.0", url, "node-v20.0.0.tar.gz", tt.expected, nil)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("CachedArchiveWithProgress() error: %v", err)
+				}
+				if !IsArchiveCached(archivePath) {
+					t.Error("verified archive was not cached")
+				}
+				return
+			}
+
+			var mismatch *ErrChecksumMismatch
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("CachedArchiveWithProgress() error = %v, want a checksum mismatch", err)
+			}
+			if _, err := os.Stat(ArchiveCachePath("node", "20.0.0", "node-v20.0.0.tar.gz")); !os.IsNotExist(err) {
+				t.Error("archive with a mismatched checksum was kept")
+			}
+		})
+	}
+}
+
+func TestCachedArchive_CachedFromOtherSourceIsDownloadedAgain(t *testing.T) {
+	url, downloads := setupArchiveCache(t)
+	const archiveSHA256 = "f69f4865f861193a91d1c5544a894167a7137b788d10bac8edbf5d095f45cb4d"
+
+	// An archive cached intact, but not the one the manifest describes
+	archivePath := ArchiveCachePath("node", "20.0.0", "node-v20.0.0.tar.gz")
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		t.Fatalf("Failed to create cache directory: %v", err)
+	}
+	if err := os.WriteFile(archivePath, []byte("other contents"), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	checksum, err := ComputeSHA256(archivePath)
+	if err != nil {
+		t.Fatalf("ComputeSHA256() error: %v", err)
+	}
+	if err := os.WriteFile(archivePath+checksumSuffix, []byte(checksum+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to record checksum: %v", err)
+	}
+
+	if err := PrefetchArchive("node", "20.0.0", url, "node-v20.0.0.tar.gz", archiveSHA256); err != nil {
+		t.Fatalf("PrefetchArchive() error: %v", err)
+	}
+	if *downloads != 1 {
+		t.Errorf("downloads = %d, want the archive downloaded again", *downloads)
+	}
+	if err := VerifyFile(archivePath, archiveSHA256); err != nil {
+		t.Errorf("cached archive is not the manifest's: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// TempDirPrefix starts the name of every temporary directory dtvem creates, so
+// CleanTempDirs can remove them without touching anything else
+const TempDirPrefix = "dtvem-"
+
+// TempDir returns the temporary directory used while installing a version of a runtime
+func TempDir(runtimeName, version string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s%s-%s", TempDirPrefix, runtimeName, version))
+}
+
+// CleanedItem is a file or directory removed by a clean
+type CleanedItem struct {
+	Path string
+	Size int64 // Bytes freed
+}
+
+// CleanTempDirs removes the entries of tempRoot whose names start with TempDirPrefix,
+// left behind by installs that failed or were interrupted
+func CleanTempDirs(tempRoot string) ([]CleanedItem, error) {
+	entries, err := os.ReadDir(tempRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read temp directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), TempDirPrefix) {
+			paths = append(paths, filepath.Join(tempRoot, entry.Name()))
+		}
+	}
+	return removeAll(paths)
+}
+
+// CleanPartialArchives removes cached archives without a recorded checksum, i.e.
+// downloads that never finished, and checksum files whose archive is gone
+func CleanPartialArchives() ([]CleanedItem, error) {
+	var paths []string
+	err := walkArchiveCache(func(path string) {
+		if archive, isChecksum := strings.CutSuffix(path, checksumSuffix); isChecksum {
+			if _, err := os.Stat(archive); os.IsNotExist(err) {
+				paths = append(paths, path)
+			}
+			return
+		}
+		if _, err := os.Stat(path + checksumSuffix); os.IsNotExist(err) {
+			paths = append(paths, path)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removeAll(paths)
+}
+
+// CleanArchiveCache removes every file in the archive cache
+func CleanArchiveCache() ([]CleanedItem, error) {
+	var paths []string
+	if err := walkArchiveCache(func(path string) { paths = append(paths, path) }); err != nil {
+		return nil, err
+	}
+	return removeAll(paths)
+}
+
+// walkArchiveCache calls fn with the path of each file in the archive cache
+func walkArchiveCache(fn func(path string)) error {
+	cacheDir := filepath.Join(config.DefaultPaths().Cache, ArchiveCacheDirName)
+	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	err := filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			fn(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read archive cache: %w", err)
+	}
+	return nil
+}
+
+// removeAll removes each path and reports what was freed. It carries on past
+// failures and returns them together.
+func removeAll(paths []string) ([]CleanedItem, error) {
+	var removed []CleanedItem
+	var errs []error
+	for _, path := range paths {
+		size := diskUsage(path)
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, CleanedItem{Path: path, Size: size})
+	}
+	return removed, errors.Join(errs...)
+}
+
+// diskUsage returns the total size of the files at or under path
+func diskUsage(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, infoErr := d.Info(); infoErr == nil && !d.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
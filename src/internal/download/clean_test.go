@@ -0,0 +1,134 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// writeTestFile creates a file with content, and its parent directories
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create %s: %v", path, err)
+	}
+}
+
+// cleanedNames returns the base names of the cleaned items, sorted
+func cleanedNames(items []CleanedItem) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, filepath.Base(item.Path))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestTempDir(t *testing.T) {
+	dir := TempDir("node", "20.11.1")
+	if filepath.Dir(dir) != filepath.Clean(os.TempDir()) {
+		t.Errorf("TempDir() = %q, want it in %s", dir, os.TempDir())
+	}
+	if name := filepath.Base(dir); name != "dtvem-node-20.11.1" || !strings.HasPrefix(name, TempDirPrefix) {
+		t.Errorf("TempDir() name = %q, want dtvem-node-20.11.1", name)
+	}
+}
+
+func TestCleanTempDirs(t *testing.T) {
+	tempRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(tempRoot, "dtvem-node-20.11.1", "extracted", "bin", "node"), "12345")
+	writeTestFile(t, filepath.Join(tempRoot, "dtvem-python-3.12.1", "python.tar.gz"), "123")
+	writeTestFile(t, filepath.Join(tempRoot, "go-build123", "a.out"), "keep")
+	writeTestFile(t, filepath.Join(tempRoot, "my-dtvem-notes.txt"), "keep")
+	writeTestFile(t, filepath.Join(tempRoot, "dtvemfile"), "keep")
+
+	removed, err := CleanTempDirs(tempRoot)
+	if err != nil {
+		t.Fatalf("CleanTempDirs() error: %v", err)
+	}
+
+	want := []string{"dtvem-node-20.11.1", "dtvem-python-3.12.1"}
+	if got := cleanedNames(removed); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("CleanTempDirs() removed %v, want %v", got, want)
+	}
+
+	var freed int64
+	for _, item := range removed {
+		freed += item.Size
+	}
+	if freed != 8 {
+		t.Errorf("freed %d bytes, want 8", freed)
+	}
+
+	for _, name := range []string{"go-build123", "my-dtvem-notes.txt", "dtvemfile"} {
+		if _, err := os.Stat(filepath.Join(tempRoot, name)); err != nil {
+			t.Errorf("%s should not be removed: %v", name, err)
+		}
+	}
+	for _, name := range want {
+		if _, err := os.Stat(filepath.Join(tempRoot, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", name)
+		}
+	}
+}
+
+// setupCleanCache creates an archive cache with a complete archive, a partial
+// download and an orphaned checksum file
+func setupCleanCache(t *testing.T) {
+	t.Helper()
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	writeTestFile(t, ArchiveCachePath("node", "1.0.0", "complete.tar.gz"), "archive")
+	writeTestFile(t, ArchiveCachePath("node", "1.0.0", "complete.tar.gz")+checksumSuffix, "abc\n")
+	writeTestFile(t, ArchiveCachePath("python", "1.0.0", "partial.tar.gz"), "half")
+	writeTestFile(t, ArchiveCachePath("ruby", "1.0.0", "gone.tar.gz")+checksumSuffix, "abc\n")
+}
+
+func TestCleanPartialArchives(t *testing.T) {
+	setupCleanCache(t)
+
+	removed, err := CleanPartialArchives()
+	if err != nil {
+		t.Fatalf("CleanPartialArchives() error: %v", err)
+	}
+
+	want := []string{"gone.tar.gz.sha256", "partial.tar.gz"}
+	if got := cleanedNames(removed); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("CleanPartialArchives() removed %v, want %v", got, want)
+	}
+	if _, err := os.Stat(ArchiveCachePath("node", "1.0.0", "complete.tar.gz")); err != nil {
+		t.Errorf("complete archive should be kept: %v", err)
+	}
+}
+
+func TestCleanArchiveCache(t *testing.T) {
+	setupCleanCache(t)
+
+	removed, err := CleanArchiveCache()
+	if err != nil {
+		t.Fatalf("CleanArchiveCache() error: %v", err)
+	}
+	if len(removed) != 4 {
+		t.Errorf("CleanArchiveCache() removed %v, want all 4 files", cleanedNames(removed))
+	}
+}
+
+func TestCleanArchiveCache_NoCache(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	removed, err := CleanArchiveCache()
+	if err != nil || len(removed) != 0 {
+		t.Errorf("CleanArchiveCache() without a cache = (%v, %v), want nothing removed", removed, err)
+	}
+}
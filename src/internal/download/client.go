@@ -0,0 +1,166 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// TimeoutEnvVar sets the network timeout, as a duration ("90s", "5m") or a number of seconds.
+// It overrides the network-timeout setting.
+const TimeoutEnvVar = config.NetworkTimeoutEnvVar
+
+// DefaultTimeout is how long a request may wait to connect, for a response or for
+// more data when no timeout is configured. It is generous for slow mirrors.
+const DefaultTimeout = 10 * time.Minute
+
+// timeoutOverride is the timeout set on the command line, which takes precedence over the environment
+var timeoutOverride time.Duration
+
+// SetTimeout sets the network timeout for all subsequent requests.
+// A zero duration clears the override.
+func SetTimeout(timeout time.Duration) {
+	timeoutOverride = timeout
+}
+
+// ConfiguredTimeout returns the timeout set with SetTimeout, DTVEM_NETWORK_TIMEOUT or
+// the network-timeout setting, if any
+func ConfiguredTimeout() (time.Duration, bool) {
+	if timeoutOverride > 0 {
+		return timeoutOverride, true
+	}
+
+	value, source := config.GetSetting(config.SettingNetworkTimeout)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	timeout, err := ParseTimeout(value)
+	if err != nil {
+		ui.Debug("Ignoring invalid network timeout %q from %s: %v", value, source, err)
+		return 0, false
+	}
+	return timeout, true
+}
+
+// ParseTimeout parses a timeout given as a duration ("90s", "5m") or a number of seconds
+func ParseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0, strconv.ErrRange
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, strconv.ErrRange
+	}
+	return timeout, nil
+}
+
+// HTTPClient returns an HTTP client using the configured network timeout,
+// or DefaultTimeout when none is configured
+func HTTPClient() *http.Client {
+	return HTTPClientWithDefault(DefaultTimeout)
+}
+
+// HTTPClientWithDefault returns an HTTP client using the configured network timeout,
+// or fallback when none is configured.
+//
+// The timeout applies to each stage of a request rather than to the request as a
+// whole: connecting, the TLS handshake, waiting for the response headers, and each
+// wait for more of the response body. A large download on a slow but working
+// connection therefore never times out, while a stalled one does.
+func HTTPClientWithDefault(fallback time.Duration) *http.Client {
+	timeout := clientTimeout(fallback)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+	return &http.Client{Transport: &idleTimeoutTransport{base: transport, timeout: timeout}}
+}
+
+// TimeoutOf returns the network timeout of a client from HTTPClient or
+// HTTPClientWithDefault, or 0 for any other client
+func TimeoutOf(client *http.Client) time.Duration {
+	if transport, ok := client.Transport.(*idleTimeoutTransport); ok {
+		return transport.timeout
+	}
+	return 0
+}
+
+// clientTimeout returns the configured network timeout, or fallback when none is configured
+func clientTimeout(fallback time.Duration) time.Duration {
+	if configured, ok := ConfiguredTimeout(); ok {
+		return configured
+	}
+	return fallback
+}
+
+// idleTimeoutTransport cancels a request whose response body sends no data for timeout
+type idleTimeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// RoundTrip sends the request with the base transport and watches its response body
+func (t *idleTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	body := &idleTimeoutBody{ReadCloser: resp.Body, timeout: t.timeout, cancel: cancel}
+	body.timer = time.AfterFunc(t.timeout, func() {
+		body.expired.Store(true)
+		cancel()
+	})
+	resp.Body = body
+	return resp, nil
+}
+
+// idleTimeoutBody is a response body whose request is canceled when no data
+// arrives for timeout
+type idleTimeoutBody struct {
+	io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	expired atomic.Bool
+}
+
+// Read reads from the body, restarting the idle timeout whenever data arrives
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	if err != nil && err != io.EOF && b.expired.Load() {
+		err = fmt.Errorf("no data received for %v: %w", b.timeout, err)
+	}
+	return n, err
+}
+
+// Close stops the idle timeout and closes the body
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	b.cancel()
+	return b.ReadCloser.Close()
+}
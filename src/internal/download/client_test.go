@@ -0,0 +1,124 @@
+package download
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"90s", 90 * time.Second, false},
+		{"5m", 5 * time.Minute, false},
+		{"45", 45 * time.Second, false},
+		{"0", 0, true},
+		{"-10s", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTimeout(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimeout(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTimeout(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_Timeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		override time.Duration
+		fallback time.Duration
+		want     time.Duration
+	}{
+		{"default", "", 0, DefaultTimeout, DefaultTimeout},
+		{"caller fallback", "", 0, 30 * time.Second, 30 * time.Second},
+		{"environment", "2m", 0, DefaultTimeout, 2 * time.Minute},
+		{"environment seconds", "15", 0, 30 * time.Second, 15 * time.Second},
+		{"invalid environment ignored", "later", 0, DefaultTimeout, DefaultTimeout},
+		{"override beats environment", "2m", 45 * time.Second, DefaultTimeout, 45 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(TimeoutEnvVar, tt.env)
+			SetTimeout(tt.override)
+			t.Cleanup(func() { SetTimeout(0) })
+
+			if got := TimeoutOf(HTTPClientWithDefault(tt.fallback)); got != tt.want {
+				t.Errorf("HTTPClientWithDefault(%v) timeout = %v, want %v", tt.fallback, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_UsesDefaultTimeout(t *testing.T) {
+	t.Setenv(TimeoutEnvVar, "")
+	SetTimeout(0)
+
+	if got := TimeoutOf(HTTPClient()); got != DefaultTimeout {
+		t.Errorf("HTTPClient() timeout = %v, want %v", got, DefaultTimeout)
+	}
+	// The timeout must not cap a whole download
+	if got := HTTPClient().Timeout; got != 0 {
+		t.Errorf("HTTPClient().Timeout = %v, want no overall timeout", got)
+	}
+}
+
+func TestHTTPClient_IdleTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	tests := []struct {
+		name    string
+		pause   time.Duration
+		wantErr bool
+	}{
+		// Takes longer than the timeout in total, but data keeps arriving
+		{name: "slow but steady download", pause: timeout / 4, wantErr: false},
+		{name: "stalled download", pause: 2 * timeout, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				for i := 0; i < 8; i++ {
+					_, _ = w.Write([]byte("chunk"))
+					w.(http.Flusher).Flush()
+					select {
+					case <-time.After(tt.pause):
+					case <-r.Context().Done():
+						return
+					}
+				}
+			}))
+			defer server.Close()
+
+			t.Setenv(TimeoutEnvVar, "")
+			SetTimeout(timeout)
+			t.Cleanup(func() { SetTimeout(0) })
+
+			resp, err := HTTPClient().Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() error: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			_, err = io.ReadAll(resp.Body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reading the body error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package download
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -32,7 +33,7 @@ func File(url, destPath string) error {
 
 	// Make HTTP request
 	ui.Debug("Making HTTP GET request...")
-	resp, err := http.Get(url)
+	resp, err := HTTPClient().Get(url)
 	if err != nil {
 		ui.Debug("HTTP request failed: %v", err)
 		return fmt.Errorf("failed to connect: %w (URL: %s)", err, url)
@@ -57,8 +58,7 @@ func File(url, destPath string) error {
 	)
 
 	// Copy data with progress bar
-	_, err = io.Copy(io.MultiWriter(out, bar), resp.Body)
-	if err != nil {
+	if err := copyBody(io.MultiWriter(out, bar), resp.Body, size); err != nil {
 		ui.Debug("Download failed: %v", err)
 		return err
 	}
@@ -70,6 +70,11 @@ func File(url, destPath string) error {
 
 // FileWithProgress downloads a file and reports progress
 func FileWithProgress(url, destPath string, progress func(current, total int64)) error {
+	return fileWithProgress(url, destPath, progress, io.Discard)
+}
+
+// fileWithProgress downloads a file, reporting progress and also writing the body to tee
+func fileWithProgress(url, destPath string, progress func(current, total int64), tee io.Writer) error {
 	// Create destination directory if it doesn't exist
 	destDir := filepath.Dir(destPath)
 	if err := os.MkdirAll(destDir, 0755); err != nil {
@@ -84,7 +89,7 @@ func FileWithProgress(url, destPath string, progress func(current, total int64))
 	defer func() { _ = out.Close() }()
 
 	// Make HTTP request
-	resp, err := http.Get(url)
+	resp, err := HTTPClient().Get(url)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %w (URL: %s)", err, url)
 	}
@@ -106,12 +111,31 @@ func FileWithProgress(url, destPath string, progress func(current, total int64))
 	}
 
 	// Copy data
-	_, err = io.Copy(out, reader)
-	if err != nil {
-		return err
-	}
+	return copyBody(io.MultiWriter(out, tee), reader, totalSize)
+}
 
-	return nil
+// ErrIncompleteDownload is returned when a download ends before all the bytes
+// announced by the Content-Length header have arrived.
+type ErrIncompleteDownload struct {
+	Got      int64
+	Expected int64
+}
+
+func (e *ErrIncompleteDownload) Error() string {
+	return fmt.Sprintf("incomplete download (got %d of %d bytes)", e.Got, e.Expected)
+}
+
+// copyBody copies a response body to w and checks that it was complete.
+// contentLength is the response's Content-Length, or -1 when it is unknown.
+// A dropped connection can end the body early with no error or only
+// io.ErrUnexpectedEOF, which is reported as an ErrIncompleteDownload.
+func copyBody(w io.Writer, body io.Reader, contentLength int64) error {
+	written, err := io.Copy(w, body)
+	if contentLength >= 0 && written < contentLength && (err == nil || errors.Is(err, io.ErrUnexpectedEOF)) {
+		ui.Debug("Download ended after %d of %d bytes", written, contentLength)
+		return &ErrIncompleteDownload{Got: written, Expected: contentLength}
+	}
+	return err
 }
 
 // progressReader wraps an io.Reader and reports progress
@@ -0,0 +1,68 @@
+package download
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// newShortServer serves a body that is shorter than its advertised Content-Length,
+// as a connection that drops mid-transfer would
+func newShortServer(t *testing.T, advertised, sent int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(advertised))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(make([]byte, sent))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFile_IncompleteDownload(t *testing.T) {
+	tests := []struct {
+		name     string
+		download func(url, destPath string) error
+	}{
+		{"File", File},
+		{"FileWithProgress", func(url, destPath string) error { return FileWithProgress(url, destPath, nil) }},
+		{"FileVerified", func(url, destPath string) error { return FileVerified(url, destPath, "unused") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newShortServer(t, 1000, 400)
+
+			err := tt.download(server.URL, filepath.Join(t.TempDir(), "archive.tar.gz"))
+
+			var incomplete *ErrIncompleteDownload
+			if !errors.As(err, &incomplete) {
+				t.Fatalf("%s() error = %v, want ErrIncompleteDownload", tt.name, err)
+			}
+			if incomplete.Got != 400 || incomplete.Expected != 1000 {
+				t.Errorf("ErrIncompleteDownload = %+v, want 400 of 1000 bytes", incomplete)
+			}
+			if want := "incomplete download (got 400 of 1000 bytes)"; err.Error() != want {
+				t.Errorf("error message = %q, want %q", err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestFile_CompleteDownload(t *testing.T) {
+	server := newShortServer(t, 1000, 1000)
+	destPath := filepath.Join(t.TempDir(), "archive.tar.gz")
+
+	if err := File(server.URL, destPath); err != nil {
+		t.Fatalf("File() error: %v", err)
+	}
+
+	info, err := os.Stat(destPath)
+	if err != nil || info.Size() != 1000 {
+		t.Errorf("downloaded file = (%v, %v), want 1000 bytes", info, err)
+	}
+}
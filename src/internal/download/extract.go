@@ -4,16 +4,93 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/bodgit/sevenzip"
+	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/ui"
+	"github.com/ulikunitz/xz"
 )
 
+// maxDefaultExtractWorkers caps the default number of parallel extraction workers.
+// Beyond a few workers extraction is limited by the disk, not the CPU.
+const maxDefaultExtractWorkers = 8
+
+// ExtractWorkers returns how many files are extracted from a .zip archive in parallel:
+// the extract-workers setting, or the number of CPUs up to maxDefaultExtractWorkers
+func ExtractWorkers() int {
+	value, source := config.GetSetting(config.SettingExtractWorkers)
+	value = strings.TrimSpace(value)
+	if value != "" {
+		workers, err := ParseExtractWorkers(value)
+		if err == nil {
+			return workers
+		}
+		ui.Debug("Ignoring invalid extract workers %q from %s: %v", value, source, err)
+	}
+
+	return min(goruntime.NumCPU(), maxDefaultExtractWorkers)
+}
+
+// ParseExtractWorkers parses a number of extraction workers, which must be at least 1
+func ParseExtractWorkers(value string) (int, error) {
+	workers, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if workers < 1 {
+		return 0, fmt.Errorf("must be at least 1")
+	}
+	return workers, nil
+}
+
+// ErrCorruptArchive is returned when an archive can't be read, e.g. because of a
+// gzip error or a truncated zip. Errors writing the extracted files are not
+// reported as corruption.
+type ErrCorruptArchive struct {
+	Err error
+}
+
+func (e *ErrCorruptArchive) Error() string {
+	return fmt.Sprintf("corrupt archive: %v", e.Err)
+}
+
+func (e *ErrCorruptArchive) Unwrap() error {
+	return e.Err
+}
+
+// corrupt marks an error reading an archive as corruption. A missing archive
+// is not corrupt, and an error that is already marked is returned unchanged.
+func corrupt(err error) error {
+	var marked *ErrCorruptArchive
+	if err == nil || errors.Is(err, os.ErrNotExist) || errors.As(err, &marked) {
+		return err
+	}
+	return &ErrCorruptArchive{Err: err}
+}
+
+// archiveReader marks the errors of reading an archive entry as corruption, so
+// they can be told apart from the errors of writing the extracted file
+type archiveReader struct {
+	r io.Reader
+}
+
+func (a archiveReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = corrupt(err)
+	}
+	return n, err
+}
+
 // archiveFile is an interface for files within an archive (zip or 7z)
 type archiveFile interface {
 	Open() (io.ReadCloser, error)
@@ -36,12 +113,55 @@ func (s *sevenzipFileAdapter) Name() string      { return s.File.Name }
 func (s *sevenzipFileAdapter) Mode() os.FileMode { return s.File.Mode() }
 func (s *sevenzipFileAdapter) IsDir() bool       { return s.File.FileInfo().IsDir() }
 
+// archiveExtensions are the archive extensions ExtractWithProgress recognizes
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar.xz", ".txz", ".zip", ".7z"}
+
+// ArchiveName returns the name the archive downloaded from url is cached and extracted
+// under. A format declared by the manifest ("tar.gz", "tar.xz", "zip" or "7z")
+// replaces the URL's archive extension, or is added when the URL has none, so the
+// extractor is chosen by the declared format.
+func ArchiveName(url, format string) string {
+	name := filepath.Base(url)
+	if format == "" {
+		return name
+	}
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext)
+			break
+		}
+	}
+	return name + "." + format
+}
+
+// ExtractWithProgress extracts a .zip, .tar.gz/.tgz, .tar.xz/.txz or .7z archive to a destination
+// directory, choosing the format from the archive's name. onFile, if not nil, is
+// called after each entry is extracted.
+func ExtractWithProgress(archivePath, destDir string, onFile func()) error {
+	switch {
+	case strings.HasSuffix(archivePath, ".zip"):
+		return extractZip(archivePath, destDir, onFile)
+	case strings.HasSuffix(archivePath, ".tar.gz"), strings.HasSuffix(archivePath, ".tgz"):
+		return extractTarGz(archivePath, destDir, onFile)
+	case strings.HasSuffix(archivePath, ".tar.xz"), strings.HasSuffix(archivePath, ".txz"):
+		return extractTarXz(archivePath, destDir, onFile)
+	case strings.HasSuffix(archivePath, ".7z"):
+		return extract7z(archivePath, destDir, onFile)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", filepath.Base(archivePath))
+	}
+}
+
 // ExtractZip extracts a zip archive to a destination directory
 func ExtractZip(zipPath, destDir string) error {
+	return extractZip(zipPath, destDir, nil)
+}
+
+func extractZip(zipPath, destDir string, onFile func()) error {
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
 		ui.Debug("Failed to open ZIP: %v", err)
-		return fmt.Errorf("failed to open archive: %w (file: %s)", err, zipPath)
+		return fmt.Errorf("failed to open archive: %w (file: %s)", corrupt(err), zipPath)
 	}
 	defer func() { _ = reader.Close() }()
 
@@ -49,15 +169,19 @@ func ExtractZip(zipPath, destDir string) error {
 	for i, f := range reader.File {
 		files[i] = &zipFileAdapter{f}
 	}
-	return extractArchive("ZIP", zipPath, destDir, files)
+	return extractArchive("ZIP", zipPath, destDir, files, ExtractWorkers(), onFile)
 }
 
 // Extract7z extracts a 7z archive to a destination directory
 func Extract7z(szPath, destDir string) error {
+	return extract7z(szPath, destDir, nil)
+}
+
+func extract7z(szPath, destDir string, onFile func()) error {
 	reader, err := sevenzip.OpenReader(szPath)
 	if err != nil {
 		ui.Debug("Failed to open 7z: %v", err)
-		return fmt.Errorf("failed to open archive: %w (file: %s)", err, szPath)
+		return fmt.Errorf("failed to open archive: %w (file: %s)", corrupt(err), szPath)
 	}
 	defer func() { _ = reader.Close() }()
 
@@ -65,54 +189,152 @@ func Extract7z(szPath, destDir string) error {
 	for i, f := range reader.File {
 		files[i] = &sevenzipFileAdapter{f}
 	}
-	return extractArchive("7z", szPath, destDir, files)
+	// 7z entries share solid compression blocks, so opening them out of order would
+	// decompress the same blocks again for each file; they are extracted in order
+	return extractArchive("7z", szPath, destDir, files, 1, onFile)
 }
 
-// extractArchive is a generic extractor for zip-like archives
-func extractArchive(archiveType, archivePath, destDir string, files []archiveFile) error {
+// extractArchive is a generic extractor for zip-like archives. Files are written by
+// up to workers goroutines; paths are checked and directories created beforehand, so
+// the workers only write files into directories that already exist.
+func extractArchive(archiveType, archivePath, destDir string, files []archiveFile, workers int, onFile func()) error {
 	ui.Debug("Extracting %s: %s", archiveType, archivePath)
 	ui.Debug("Destination: %s", destDir)
-	ui.Debug("%s contains %d files", archiveType, len(files))
+	ui.Debug("%s contains %d files (%d workers)", archiveType, len(files), workers)
 
 	// Create destination directory
 	if err := os.MkdirAll(destDir, 0755); err != nil {
 		return err
 	}
 
-	// Extract each file
-	for _, file := range files {
-		if err := extractArchiveFile(file, destDir); err != nil {
+	// Check every path and create the directories first. When an archive holds a
+	// name twice, only the last entry is written, as a sequential extraction would leave it.
+	destPaths := make([]string, len(files))
+	var regular []int
+	last := make(map[string]int)
+	for i, file := range files {
+		destPath, err := archiveDestPath(file.Name(), destDir)
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", file.Name(), err)
+		}
+		destPaths[i] = destPath
+
+		if file.IsDir() {
+			if err := os.MkdirAll(destPath, file.Mode()); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", file.Name(), err)
+			}
+			if onFile != nil {
+				onFile()
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
 			return fmt.Errorf("failed to extract %s: %w", file.Name(), err)
 		}
+		regular = append(regular, i)
+		last[destPath] = i
+	}
+
+	var unique []int
+	for _, i := range regular {
+		if last[destPaths[i]] == i {
+			unique = append(unique, i)
+		} else if onFile != nil {
+			onFile()
+		}
+	}
+
+	extract := func(i int) error {
+		if err := extractArchiveFile(files[i], destPaths[i]); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", files[i].Name(), err)
+		}
+		return nil
+	}
+
+	if err := runExtractWorkers(unique, workers, extract, onFile); err != nil {
+		return err
 	}
 
 	ui.Debug("%s extraction complete", archiveType)
 	return nil
 }
 
-// extractArchiveFile extracts a single file from an archive (zip or 7z)
-func extractArchiveFile(file archiveFile, destDir string) error {
-	// Build destination path
-	destPath := filepath.Join(destDir, file.Name())
+// runExtractWorkers calls extract for each index using up to workers goroutines,
+// calling onFile (one call at a time) after each success. After the first error
+// no more files are started, and that error is returned.
+func runExtractWorkers(indexes []int, workers int, extract func(i int) error, onFile func()) error {
+	if workers > len(indexes) {
+		workers = len(indexes)
+	}
+	if workers <= 1 {
+		for _, i := range indexes {
+			if err := extract(i); err != nil {
+				return err
+			}
+			if onFile != nil {
+				onFile()
+			}
+		}
+		return nil
+	}
 
-	// Check for ZipSlip vulnerability
-	if !strings.HasPrefix(destPath, filepath.Clean(destDir)+string(os.PathSeparator)) {
-		return fmt.Errorf("illegal file path: %s", file.Name())
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := extract(i)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if err == nil && onFile != nil {
+					onFile()
+				}
+				mu.Unlock()
+			}
+		}()
 	}
 
-	if file.IsDir() {
-		return os.MkdirAll(destPath, file.Mode())
+	for _, i := range indexes {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- i
 	}
+	close(jobs)
+	wg.Wait()
 
-	// Create parent directory
-	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
-		return err
+	return firstErr
+}
+
+// archiveDestPath returns where an archive entry is extracted, rejecting names that
+// would land outside destDir (ZipSlip)
+func archiveDestPath(name, destDir string) (string, error) {
+	destPath := filepath.Join(destDir, name)
+	if !strings.HasPrefix(destPath, filepath.Clean(destDir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path: %s", name)
 	}
+	return destPath, nil
+}
 
+// extractArchiveFile writes a regular file from an archive (zip or 7z) to destPath,
+// whose directory already exists
+func extractArchiveFile(file archiveFile, destPath string) error {
 	// Open source file
 	srcFile, err := file.Open()
 	if err != nil {
-		return err
+		return corrupt(err)
 	}
 	defer func() { _ = srcFile.Close() }()
 
@@ -123,30 +345,63 @@ func extractArchiveFile(file archiveFile, destDir string) error {
 	}
 	defer func() { _ = destFile.Close() }()
 
-	_, err = io.Copy(destFile, srcFile)
+	_, err = io.Copy(destFile, archiveReader{srcFile})
 	return err
 }
 
 // ExtractTarGz extracts a tar.gz archive to a destination directory
 func ExtractTarGz(tarGzPath, destDir string) error {
-	ui.Debug("Extracting tar.gz: %s", tarGzPath)
+	return extractTarGz(tarGzPath, destDir, nil)
+}
+
+func extractTarGz(tarGzPath, destDir string, onFile func()) error {
+	return extractTar("tar.gz", tarGzPath, destDir, onFile, func(r io.Reader) (io.Reader, error) {
+		gzReader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip archive: %w", err)
+		}
+		return gzReader, nil
+	})
+}
+
+// ExtractTarXz extracts a tar.xz archive to a destination directory
+func ExtractTarXz(tarXzPath, destDir string) error {
+	return extractTarXz(tarXzPath, destDir, nil)
+}
+
+func extractTarXz(tarXzPath, destDir string, onFile func()) error {
+	return extractTar("tar.xz", tarXzPath, destDir, onFile, func(r io.Reader) (io.Reader, error) {
+		xzReader, err := xz.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xz archive: %w", err)
+		}
+		return xzReader, nil
+	})
+}
+
+// extractTar extracts a compressed tar archive, with decompress wrapping the
+// archive file in a reader for its compression format
+func extractTar(archiveType, archivePath, destDir string, onFile func(), decompress func(io.Reader) (io.Reader, error)) error {
+	ui.Debug("Extracting %s: %s", archiveType, archivePath)
 	ui.Debug("Destination: %s", destDir)
 
-	file, err := os.Open(tarGzPath)
+	file, err := os.Open(archivePath)
 	if err != nil {
-		ui.Debug("Failed to open tar.gz: %v", err)
-		return fmt.Errorf("failed to open archive: %w (file: %s)", err, tarGzPath)
+		ui.Debug("Failed to open %s: %v", archiveType, err)
+		return fmt.Errorf("failed to open archive: %w (file: %s)", err, archivePath)
 	}
 	defer func() { _ = file.Close() }()
 
-	gzReader, err := gzip.NewReader(file)
+	reader, err := decompress(file)
 	if err != nil {
-		ui.Debug("Failed to create gzip reader: %v", err)
-		return fmt.Errorf("invalid gzip archive: %w (file: %s)", err, tarGzPath)
+		ui.Debug("Failed to create %s reader: %v", archiveType, err)
+		return fmt.Errorf("%w (file: %s)", corrupt(err), archivePath)
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
 	}
-	defer func() { _ = gzReader.Close() }()
 
-	tarReader := tar.NewReader(gzReader)
+	tarReader := tar.NewReader(reader)
 
 	if err := os.MkdirAll(destDir, 0755); err != nil {
 		return err
@@ -159,16 +414,19 @@ func ExtractTarGz(tarGzPath, destDir string) error {
 			break
 		}
 		if err != nil {
-			return err
+			return corrupt(err)
 		}
 
 		if err := extractTarFile(header, tarReader, destDir); err != nil {
 			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
 		}
 		fileCount++
+		if onFile != nil {
+			onFile()
+		}
 	}
 
-	ui.Debug("tar.gz extraction complete: %d files extracted", fileCount)
+	ui.Debug("%s extraction complete: %d files extracted", archiveType, fileCount)
 	return nil
 }
 
@@ -195,7 +453,7 @@ func extractTarFile(header *tar.Header, reader io.Reader, destDir string) error
 		}
 		defer func() { _ = outFile.Close() }()
 
-		_, err = io.Copy(outFile, reader)
+		_, err = io.Copy(outFile, archiveReader{reader})
 		return err
 
 	case tar.TypeSymlink:
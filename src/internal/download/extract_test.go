@@ -0,0 +1,484 @@
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	goruntime "runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/ulikunitz/xz"
+)
+
+// testArchiveFiles are the entries written to test archives
+var testArchiveFiles = map[string]string{
+	"node/bin/node":     "binary",
+	"node/README.md":    "readme",
+	"node/lib/index.js": "module",
+}
+
+func writeTestZip(t *testing.T, path string) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	writer := zip.NewWriter(out)
+	for name, content := range testArchiveFiles {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		_, _ = w.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+}
+
+func writeTestTarGz(t *testing.T, path string) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create tar.gz: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	gz := gzip.NewWriter(out)
+	writer := tar.NewWriter(gz)
+	for name, content := range testArchiveFiles {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		_, _ = writer.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to write gzip: %v", err)
+	}
+}
+
+func writeTestTarXz(t *testing.T, path string) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create tar.xz: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	xzWriter, err := xz.NewWriter(out)
+	if err != nil {
+		t.Fatalf("Failed to create xz writer: %v", err)
+	}
+	writer := tar.NewWriter(xzWriter)
+	for name, content := range testArchiveFiles {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		_, _ = writer.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write tar: %v", err)
+	}
+	if err := xzWriter.Close(); err != nil {
+		t.Fatalf("Failed to write xz: %v", err)
+	}
+}
+
+func TestExtractWithProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(t *testing.T, path string)
+	}{
+		{"archive.zip", writeTestZip},
+		{"archive.tar.gz", writeTestTarGz},
+		{"archive.tgz", writeTestTarGz},
+		{"archive.tar.xz", writeTestTarXz},
+		{"archive.txz", writeTestTarXz},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			archivePath := filepath.Join(dir, tt.name)
+			tt.write(t, archivePath)
+
+			files := 0
+			destDir := filepath.Join(dir, "extracted")
+			if err := ExtractWithProgress(archivePath, destDir, func() { files++ }); err != nil {
+				t.Fatalf("ExtractWithProgress() error: %v", err)
+			}
+
+			if files != len(testArchiveFiles) {
+				t.Errorf("onFile called %d times, want %d", files, len(testArchiveFiles))
+			}
+			for name, content := range testArchiveFiles {
+				data, err := os.ReadFile(filepath.Join(destDir, filepath.FromSlash(name)))
+				if err != nil || string(data) != content {
+					t.Errorf("%s = (%q, %v), want %q", name, data, err, content)
+				}
+			}
+		})
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		format string
+		want   string
+	}{
+		{"no declared format", "https://example.com/node-v22.0.0-linux-x64.tar.gz", "", "node-v22.0.0-linux-x64.tar.gz"},
+		{"declared format matches", "https://example.com/node-v22.0.0-linux-x64.tar.gz", "tar.gz", "node-v22.0.0-linux-x64.tar.gz"},
+		{"mislabeled extension", "https://mirror.example.com/node-v22.0.0-linux-x64.tgz", "zip", "node-v22.0.0-linux-x64.zip"},
+		{"stripped extension", "https://mirror.example.com/node-v22.0.0-linux-x64", "tar.xz", "node-v22.0.0-linux-x64.tar.xz"},
+		{"other dots kept", "https://mirror.example.com/python-3.13.1.bin", "7z", "python-3.13.1.bin.7z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArchiveName(tt.url, tt.format); got != tt.want {
+				t.Errorf("ArchiveName(%q, %q) = %q, want %q", tt.url, tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractWithProgress_DeclaredFormat(t *testing.T) {
+	// A zip served at a .tar.gz URL extracts as a zip under its declared format
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, ArchiveName("https://mirror.example.com/node/windows-amd64.tar.gz", "zip"))
+	writeTestZip(t, archivePath)
+
+	destDir := filepath.Join(dir, "extracted")
+	if err := ExtractWithProgress(archivePath, destDir, nil); err != nil {
+		t.Fatalf("ExtractWithProgress() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, "node", "bin", "node")); err != nil {
+		t.Errorf("extracted file missing: %v", err)
+	}
+}
+
+func TestExtractWithProgress_UnsupportedFormat(t *testing.T) {
+	if err := ExtractWithProgress(filepath.Join(t.TempDir(), "archive.rar"), t.TempDir(), nil); err == nil {
+		t.Error("ExtractWithProgress() should fail for an unsupported format")
+	}
+}
+
+func TestExtractWithProgress_CorruptArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.tar.gz")
+	writeTestTarGz(t, valid)
+	data, err := os.ReadFile(valid)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+
+	archives := map[string][]byte{
+		"garbage.tar.gz":   []byte("not a gzip stream"),
+		"truncated.tar.gz": data[:len(data)/2],
+		"garbage.zip":      []byte("not a zip file"),
+		"garbage.tar.xz":   []byte("not an xz stream"),
+	}
+
+	for name, content := range archives {
+		t.Run(name, func(t *testing.T) {
+			archivePath := filepath.Join(dir, name)
+			if err := os.WriteFile(archivePath, content, 0644); err != nil {
+				t.Fatalf("Failed to write archive: %v", err)
+			}
+
+			err := ExtractWithProgress(archivePath, filepath.Join(t.TempDir(), "out"), nil)
+			if !IsCorruptArchive(err) {
+				t.Errorf("ExtractWithProgress() error = %v, want a corrupt archive error", err)
+			}
+		})
+	}
+}
+
+func TestExtractTarXz_PreservesModesAndSymlinks(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("file modes and symlinks are not preserved on Windows")
+	}
+
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "ruby.tar.xz")
+	out, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create tar.xz: %v", err)
+	}
+	xzWriter, err := xz.NewWriter(out)
+	if err != nil {
+		t.Fatalf("Failed to create xz writer: %v", err)
+	}
+	writer := tar.NewWriter(xzWriter)
+	entries := []*tar.Header{
+		{Name: "ruby/bin/", Mode: 0755, Typeflag: tar.TypeDir},
+		{Name: "ruby/bin/ruby", Mode: 0755, Size: int64(len("binary")), Typeflag: tar.TypeReg},
+		{Name: "ruby/lib/readme.txt", Mode: 0600, Size: int64(len("binary")), Typeflag: tar.TypeReg},
+		{Name: "ruby/bin/irb", Linkname: "ruby", Typeflag: tar.TypeSymlink},
+	}
+	for _, header := range entries {
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to add %s: %v", header.Name, err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			_, _ = writer.Write([]byte("binary"))
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write tar: %v", err)
+	}
+	if err := xzWriter.Close(); err != nil {
+		t.Fatalf("Failed to write xz: %v", err)
+	}
+	_ = out.Close()
+
+	destDir := filepath.Join(dir, "extracted")
+	if err := ExtractTarXz(archivePath, destDir); err != nil {
+		t.Fatalf("ExtractTarXz() error: %v", err)
+	}
+
+	for name, want := range map[string]os.FileMode{"ruby/bin/ruby": 0755, "ruby/lib/readme.txt": 0600} {
+		info, err := os.Stat(filepath.Join(destDir, name))
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", name, err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %v, want %v", name, got, want)
+		}
+	}
+
+	link, err := os.Readlink(filepath.Join(destDir, "ruby", "bin", "irb"))
+	if err != nil {
+		t.Fatalf("ruby/bin/irb is not a symlink: %v", err)
+	}
+	if link != "ruby" {
+		t.Errorf("ruby/bin/irb links to %q, want %q", link, "ruby")
+	}
+}
+
+func TestExtractWithProgress_WriteErrorIsNotCorruption(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "node.tar.gz")
+	writeTestTarGz(t, archivePath)
+
+	// The destination can't be created because a file is in the way
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, []byte("file"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	err := ExtractWithProgress(archivePath, filepath.Join(blocker, "out"), nil)
+	if err == nil {
+		t.Fatal("ExtractWithProgress() should fail when the destination can't be created")
+	}
+	if IsCorruptArchive(err) {
+		t.Errorf("ExtractWithProgress() error = %v, should not be reported as corruption", err)
+	}
+}
+
+func TestExtractWithProgress_MissingArchiveIsNotCorruption(t *testing.T) {
+	err := ExtractWithProgress(filepath.Join(t.TempDir(), "missing.tar.gz"), t.TempDir(), nil)
+	if err == nil || IsCorruptArchive(err) {
+		t.Errorf("ExtractWithProgress() error = %v, want a non-corruption error", err)
+	}
+}
+
+// writeLargeTestZip writes a zip with many small files in nested directories, some
+// executable, like the standard library of a runtime
+func writeLargeTestZip(t testing.TB, path string, count int) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	writer := zip.NewWriter(out)
+	if _, err := writer.CreateHeader(&zip.FileHeader{Name: "python/Lib/", Method: zip.Store}); err != nil {
+		t.Fatalf("Failed to add directory: %v", err)
+	}
+	for i := 0; i < count; i++ {
+		header := &zip.FileHeader{Name: fmt.Sprintf("python/Lib/pkg%d/module%d.py", i%17, i), Method: zip.Deflate}
+		mode := os.FileMode(0644)
+		if i%5 == 0 {
+			mode = 0755
+		}
+		header.SetMode(mode)
+		w, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", header.Name, err)
+		}
+		_, _ = w.Write([]byte(strings.Repeat(fmt.Sprintf("line %d\n", i), i%50+1)))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+}
+
+// treeSnapshot describes every file and directory under root by its mode and content
+func treeSnapshot(t *testing.T, root string) map[string]string {
+	t.Helper()
+	snapshot := make(map[string]string)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		if info.IsDir() {
+			snapshot[rel] = "dir"
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		snapshot[rel] = fmt.Sprintf("%v %s", info.Mode(), data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk %s: %v", root, err)
+	}
+	return snapshot
+}
+
+func TestExtractZip_ParallelMatchesSerial(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "python.zip")
+	writeLargeTestZip(t, archivePath, 500)
+
+	extract := func(workers string) (map[string]string, int) {
+		t.Setenv(config.ExtractWorkersEnvVar, workers)
+		destDir := filepath.Join(dir, "extracted-"+workers)
+		var files atomic.Int64
+		if err := ExtractWithProgress(archivePath, destDir, func() { files.Add(1) }); err != nil {
+			t.Fatalf("ExtractWithProgress() with %s workers error: %v", workers, err)
+		}
+		return treeSnapshot(t, destDir), int(files.Load())
+	}
+
+	serial, serialFiles := extract("1")
+	parallel, parallelFiles := extract("8")
+
+	if !reflect.DeepEqual(parallel, serial) {
+		t.Errorf("parallel extraction produced a different tree than serial extraction")
+	}
+	if parallelFiles != serialFiles || serialFiles != 501 {
+		t.Errorf("onFile called %d times in parallel and %d serially, want 501", parallelFiles, serialFiles)
+	}
+}
+
+func TestExtractZip_ParallelRejectsZipSlip(t *testing.T) {
+	t.Setenv(config.ExtractWorkersEnvVar, "4")
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "evil.zip")
+
+	out, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	writer := zip.NewWriter(out)
+	for _, name := range []string{"pkg/a.txt", "pkg/b.txt", "../evil.txt", "pkg/c.txt"} {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		_, _ = w.Write([]byte(name))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+	_ = out.Close()
+
+	destDir := filepath.Join(dir, "extracted")
+	if err := ExtractWithProgress(archivePath, destDir, nil); err == nil || !strings.Contains(err.Error(), "illegal file path") {
+		t.Fatalf("ExtractWithProgress() error = %v, want an illegal file path error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil.txt")); !os.IsNotExist(err) {
+		t.Error("ExtractWithProgress() wrote a file outside the destination")
+	}
+	// Paths are checked before any file is written
+	if _, err := os.Stat(filepath.Join(destDir, "pkg", "a.txt")); !os.IsNotExist(err) {
+		t.Error("ExtractWithProgress() wrote files before rejecting the archive")
+	}
+}
+
+func TestRunExtractWorkers_StopsAfterError(t *testing.T) {
+	indexes := make([]int, 100)
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	var started atomic.Int64
+	failure := errors.New("disk full")
+	err := runExtractWorkers(indexes, 4, func(i int) error {
+		started.Add(1)
+		if i == 3 {
+			return failure
+		}
+		return nil
+	}, nil)
+
+	if !errors.Is(err, failure) {
+		t.Errorf("runExtractWorkers() error = %v, want %v", err, failure)
+	}
+	if n := started.Load(); n == int64(len(indexes)) {
+		t.Errorf("runExtractWorkers() started all %d files after an error", n)
+	}
+}
+
+func TestParseExtractWorkers(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"1", 1, false},
+		{"8", 8, false},
+		{"0", 0, true},
+		{"-2", 0, true},
+		{"many", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseExtractWorkers(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseExtractWorkers(%q) = (%d, %v), want %d (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func BenchmarkExtractZip(b *testing.B) {
+	archivePath := filepath.Join(b.TempDir(), "python.zip")
+	writeLargeTestZip(b, archivePath, 2000)
+
+	for _, workers := range []string{"1", "4", "8"} {
+		b.Run("workers="+workers, func(b *testing.B) {
+			b.Setenv(config.ExtractWorkersEnvVar, workers)
+			for i := 0; i < b.N; i++ {
+				if err := ExtractWithProgress(archivePath, filepath.Join(b.TempDir(), "extracted"), nil); err != nil {
+					b.Fatalf("ExtractWithProgress() error: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package download
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// InstallLockStaleAfter is how long an install lock may go without being refreshed
+// before it is taken to be left behind by a dtvem process that died
+const InstallLockStaleAfter = 2 * time.Minute
+
+// lockPollInterval is how often a process waiting for a lock checks it again
+var lockPollInterval = 250 * time.Millisecond
+
+// Lock is an inter-process lock held through a lock file. While it is held the lock
+// file's modification time is refreshed, so waiting processes can tell a lock of a
+// live process from one left behind by a process that died.
+type Lock struct {
+	path   string
+	waited bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// LockInstall takes the install lock of a runtime version, so two dtvem processes
+// (e.g. parallel CI steps) never extract the same version into the same directory.
+// It waits while another process holds the lock; the caller should check whether
+// the version got installed in the meantime, and release the lock when done.
+func LockInstall(runtimeName, version string) (*Lock, error) {
+	return AcquireLock(config.InstallLockPath(runtimeName, version), InstallLockStaleAfter, func() {
+		ui.Info("Waiting for another dtvem process to finish installing %s %s...", runtimeName, version)
+	})
+}
+
+// AcquireLock takes the lock file at path, waiting while another process holds it.
+// A lock that hasn't been refreshed for staleAfter is removed and taken over.
+// onWait, if not nil, is called once when the lock is found taken.
+func AcquireLock(path string, staleAfter time.Duration, onWait func()) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	waiting := false
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			// The PID only helps someone looking at a lock that seems stuck
+			_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+			_ = file.Close()
+			lock := holdLock(path, staleAfter)
+			lock.waited = waiting
+			return lock, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file %s: %w", path, err)
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleAfter {
+			removeStaleLock(path, staleAfter)
+			continue
+		}
+
+		if !waiting && onWait != nil {
+			onWait()
+		}
+		waiting = true
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// removeStaleLock removes a lock file that has gone stale. It is renamed to a name of
+// its own first, which only one of several waiting processes can do, so none of them
+// deletes a fresh lock another has taken since it saw the stale one. A lock that turns
+// out to be fresh after the rename is put back.
+func removeStaleLock(path string, staleAfter time.Duration) {
+	claimed := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, claimed); err != nil {
+		// Another waiting process got to it first
+		return
+	}
+
+	if info, err := os.Stat(claimed); err == nil && time.Since(info.ModTime()) <= staleAfter {
+		// Another process took the lock between the check and the rename. Link
+		// doesn't replace an existing file, so a newer lock is never overwritten.
+		_ = os.Link(claimed, path)
+	} else {
+		ui.Debug("Removed stale lock %s", path)
+	}
+	_ = os.Remove(claimed)
+}
+
+// holdLock keeps refreshing a taken lock file until the lock is released
+func holdLock(path string, staleAfter time.Duration) *Lock {
+	lock := &Lock{path: path, stop: make(chan struct{}), done: make(chan struct{})}
+
+	go func() {
+		defer close(lock.done)
+		ticker := time.NewTicker(staleAfter / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lock.stop:
+				return
+			case now := <-ticker.C:
+				_ = os.Chtimes(path, now, now)
+			}
+		}
+	}()
+
+	return lock
+}
+
+// Waited reports whether another process held the lock when it was requested, i.e.
+// whatever the lock protects may have been done by that process in the meantime
+func (l *Lock) Waited() bool {
+	return l.waited
+}
+
+// Release gives up the lock, letting a waiting process take it
+func (l *Lock) Release() {
+	close(l.stop)
+	<-l.done
+	_ = os.Remove(l.path)
+}
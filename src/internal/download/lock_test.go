@@ -0,0 +1,271 @@
+package download
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// lockHelperEnvVar makes the test binary act as a second dtvem process holding a lock
+const lockHelperEnvVar = "DTVEM_TEST_LOCK_HELPER"
+
+// useFastLockPolling makes waiting for a lock poll quickly
+func useFastLockPolling(t *testing.T) {
+	t.Helper()
+	original := lockPollInterval
+	lockPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { lockPollInterval = original })
+}
+
+func TestAcquireLock_WaitsForRelease(t *testing.T) {
+	useFastLockPolling(t)
+	path := filepath.Join(t.TempDir(), "locks", "node-20.11.1.lock")
+
+	first, err := AcquireLock(path, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("AcquireLock() error: %v", err)
+	}
+
+	var waited atomic.Bool
+	acquired := make(chan *Lock)
+	go func() {
+		second, err := AcquireLock(path, time.Minute, func() { waited.Store(true) })
+		if err != nil {
+			t.Errorf("second AcquireLock() error: %v", err)
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second AcquireLock() returned while the lock was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	first.Release()
+	select {
+	case second := <-acquired:
+		if !second.Waited() {
+			t.Error("second lock Waited() = false, want true")
+		}
+		second.Release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second AcquireLock() did not return after the lock was released")
+	}
+	if first.Waited() {
+		t.Error("first lock Waited() = true, want false")
+	}
+
+	if !waited.Load() {
+		t.Error("onWait was not called while waiting for the lock")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind after release: %v", err)
+	}
+}
+
+func TestAcquireLock_TakesOverStaleLock(t *testing.T) {
+	useFastLockPolling(t)
+	path := filepath.Join(t.TempDir(), "node-20.11.1.lock")
+	writeTestFile(t, path, "99999\n")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lock, err := AcquireLock(path, time.Minute, func() { t.Error("waited for a stale lock") })
+		if err != nil {
+			t.Errorf("AcquireLock() error: %v", err)
+			return
+		}
+		lock.Release()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("AcquireLock() did not take over a stale lock")
+	}
+}
+
+func TestRemoveStaleLock(t *testing.T) {
+	staleAfter := time.Minute
+	tests := []struct {
+		name     string
+		age      time.Duration
+		wantKept bool
+	}{
+		{name: "stale lock is removed", age: 2 * staleAfter, wantKept: false},
+		// Another process took the lock after this one saw the stale one
+		{name: "fresh lock is kept", age: 0, wantKept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "node-20.11.1.lock")
+			if err := os.WriteFile(path, []byte("1234\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			modTime := time.Now().Add(-tt.age)
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+
+			removeStaleLock(path, staleAfter)
+
+			if _, err := os.Stat(path); (err == nil) != tt.wantKept {
+				t.Errorf("lock exists = %v, want %v", err == nil, tt.wantKept)
+			}
+			entries, _ := os.ReadDir(dir)
+			if want := map[bool]int{true: 1, false: 0}[tt.wantKept]; len(entries) != want {
+				t.Errorf("lock directory has %d entries, want %d", len(entries), want)
+			}
+		})
+	}
+}
+
+func TestAcquireLock_HeldLockIsRefreshed(t *testing.T) {
+	useFastLockPolling(t)
+	path := filepath.Join(t.TempDir(), "node-20.11.1.lock")
+	staleAfter := 200 * time.Millisecond
+
+	lock, err := AcquireLock(path, staleAfter, nil)
+	if err != nil {
+		t.Fatalf("AcquireLock() error: %v", err)
+	}
+
+	// Holding the lock for several stale periods must not let another process take it
+	acquired := make(chan *Lock)
+	go func() {
+		second, _ := AcquireLock(path, staleAfter, nil)
+		acquired <- second
+	}()
+	select {
+	case second := <-acquired:
+		second.Release()
+		t.Fatal("a held lock was taken over as stale")
+	case <-time.After(4 * staleAfter):
+	}
+
+	lock.Release()
+	(<-acquired).Release()
+}
+
+func TestLockInstall_OneInstallPerVersion(t *testing.T) {
+	useFastLockPolling(t)
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	// Several installers of the same version: each waits for the lock, then only
+	// installs if the version isn't installed yet
+	installPath := config.RuntimeVersionPath("node", "20.11.1")
+	var installs atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock, err := LockInstall("node", "20.11.1")
+			if err != nil {
+				t.Errorf("LockInstall() error: %v", err)
+				return
+			}
+			defer lock.Release()
+
+			if _, err := os.Stat(installPath); err == nil {
+				return
+			}
+			installs.Add(1)
+			time.Sleep(20 * time.Millisecond) // extracting
+			if err := os.MkdirAll(installPath, 0755); err != nil {
+				t.Errorf("Failed to install: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := installs.Load(); got != 1 {
+		t.Errorf("version installed %d times, want 1", got)
+	}
+}
+
+func TestLockInstall_AcrossProcesses(t *testing.T) {
+	useFastLockPolling(t)
+	root := t.TempDir()
+	t.Setenv("DTVEM_ROOT", root)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	// A second process takes the lock, installs slowly and exits
+	helper := exec.Command(os.Args[0], "-test.run=^TestLockInstallHelperProcess$")
+	helper.Env = append(os.Environ(), lockHelperEnvVar+"=1", "DTVEM_ROOT="+root)
+	stdout, err := helper.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Failed to connect to helper: %v", err)
+	}
+	if err := helper.Start(); err != nil {
+		t.Fatalf("Failed to start helper: %v", err)
+	}
+	defer func() { _ = helper.Wait() }()
+
+	// Wait until the helper holds the lock
+	scanner := bufio.NewScanner(stdout)
+	locked := false
+	for scanner.Scan() {
+		if scanner.Text() == "locked" {
+			locked = true
+			break
+		}
+	}
+	if !locked {
+		t.Fatal("helper process did not take the lock")
+	}
+	go func() {
+		for scanner.Scan() {
+		}
+	}()
+
+	lock, err := LockInstall("node", "20.11.1")
+	if err != nil {
+		t.Fatalf("LockInstall() error: %v", err)
+	}
+	defer lock.Release()
+
+	// By the time this process gets the lock, the other one has installed the version
+	if _, err := os.Stat(config.RuntimeVersionPath("node", "20.11.1")); err != nil {
+		t.Errorf("version not installed when the lock was released: %v", err)
+	}
+}
+
+// TestLockInstallHelperProcess is the second process of TestLockInstall_AcrossProcesses
+func TestLockInstallHelperProcess(t *testing.T) {
+	if os.Getenv(lockHelperEnvVar) != "1" {
+		t.Skip("only runs as a helper process")
+	}
+	config.ResetPathsCache()
+
+	lock, err := LockInstall("node", "20.11.1")
+	if err != nil {
+		t.Fatalf("LockInstall() error: %v", err)
+	}
+	fmt.Println("locked")
+
+	time.Sleep(300 * time.Millisecond) // extracting
+	if err := os.MkdirAll(config.RuntimeVersionPath("node", "20.11.1"), 0755); err != nil {
+		t.Fatalf("Failed to install: %v", err)
+	}
+	lock.Release()
+}
@@ -46,7 +46,7 @@ func FileVerified(url, destPath, expectedSHA256 string) error {
 
 	// Make HTTP request
 	ui.Debug("Making HTTP GET request...")
-	resp, err := http.Get(url)
+	resp, err := HTTPClient().Get(url)
 	if err != nil {
 		ui.Debug("HTTP request failed: %v", err)
 		return fmt.Errorf("failed to connect: %w (URL: %s)", err, url)
@@ -74,8 +74,7 @@ func FileVerified(url, destPath, expectedSHA256 string) error {
 	hasher := sha256.New()
 
 	// Copy data with progress bar and hashing
-	_, err = io.Copy(io.MultiWriter(out, bar, hasher), resp.Body)
-	if err != nil {
+	if err := copyBody(io.MultiWriter(out, bar, hasher), resp.Body, size); err != nil {
 		ui.Debug("Download failed: %v", err)
 		_ = os.Remove(destPath) // Clean up partial download
 		return err
@@ -105,6 +104,36 @@ func FileVerified(url, destPath, expectedSHA256 string) error {
 	return nil
 }
 
+// FileWithChecksum downloads a file and verifies its SHA256 checksum while writing it.
+// On a mismatch the file is deleted and an *ErrChecksumMismatch returned. An empty
+// expectedSHA256 skips the verification.
+func FileWithChecksum(url, destPath, expectedSHA256 string) error {
+	return FileWithChecksumProgress(url, destPath, expectedSHA256, nil)
+}
+
+// FileWithChecksumProgress is FileWithChecksum, reporting download progress to progress
+func FileWithChecksumProgress(url, destPath, expectedSHA256 string, progress func(current, total int64)) error {
+	hasher := sha256.New()
+	if err := fileWithProgress(url, destPath, progress, hasher); err != nil {
+		return err
+	}
+	if expectedSHA256 == "" {
+		return nil
+	}
+
+	actualSHA256 := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(actualSHA256, strings.TrimSpace(expectedSHA256)) {
+		_ = os.Remove(destPath)
+		return &ErrChecksumMismatch{
+			Expected: expectedSHA256,
+			Actual:   actualSHA256,
+		}
+	}
+
+	ui.Debug("Checksum verified: %s", actualSHA256)
+	return nil
+}
+
 // VerifyFile checks if an existing file matches the expected SHA256 checksum.
 func VerifyFile(filePath, expectedSHA256 string) error {
 	f, err := os.Open(filePath)
@@ -2,6 +2,8 @@ package download
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -103,3 +105,45 @@ func TestErrChecksumMismatch(t *testing.T) {
 		t.Errorf("Error() = %q, want %q", err.Error(), want)
 	}
 }
+
+func TestFileWithChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello world\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{"matching checksum", "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447", false},
+		{"mismatched checksum", "0000000000000000000000000000000000000000000000000000000000000000", true},
+		{"no checksum skips verification", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destPath := filepath.Join(t.TempDir(), "file.txt")
+			err := FileWithChecksum(server.URL+"/file.txt", destPath, tt.expected)
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("FileWithChecksum() error: %v", err)
+				}
+				if _, err := os.Stat(destPath); err != nil {
+					t.Errorf("downloaded file missing: %v", err)
+				}
+				return
+			}
+
+			var mismatch *ErrChecksumMismatch
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("FileWithChecksum() error = %v, want a checksum mismatch", err)
+			}
+			if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+				t.Error("file with a mismatched checksum was not deleted")
+			}
+		})
+	}
+}
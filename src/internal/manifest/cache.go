@@ -18,6 +18,16 @@ type CachedSource struct {
 	ttl      time.Duration
 }
 
+// runtimesCacheFile is the cache file for the list of runtimes.
+// Runtime names never start with an underscore, so it cannot clash with a manifest.
+const runtimesCacheFile = "_runtimes.cache.json"
+
+// runtimesCacheEntry stores the list of runtimes along with its cache timestamp.
+type runtimesCacheEntry struct {
+	CachedAt time.Time `json:"cached_at"`
+	Runtimes []string  `json:"runtimes"`
+}
+
 // cacheEntry stores a manifest along with its cache timestamp.
 type cacheEntry struct {
 	CachedAt time.Time `json:"cached_at"`
@@ -33,16 +43,22 @@ func NewCachedSource(source Source, cacheDir string, ttl time.Duration) *CachedS
 	}
 }
 
-// GetManifest returns a cached manifest if valid, otherwise fetches from the underlying source.
+// GetManifest returns a cached manifest if valid, otherwise fetches from the underlying
+// source. If the source fails (e.g. when offline), an expired cached manifest is
+// returned instead.
 func (s *CachedSource) GetManifest(runtime string) (*Manifest, error) {
 	// Try to load from cache first
-	if manifest, err := s.loadFromCache(runtime); err == nil {
-		return manifest, nil
+	entry, cacheErr := s.loadFromCache(runtime)
+	if cacheErr == nil && time.Since(entry.CachedAt) <= s.ttl {
+		return entry.Manifest, nil
 	}
 
 	// Fetch from underlying source
 	manifest, err := s.source.GetManifest(runtime)
 	if err != nil {
+		if cacheErr == nil {
+			return entry.Manifest, nil
+		}
 		return nil, err
 	}
 
@@ -52,17 +68,32 @@ func (s *CachedSource) GetManifest(runtime string) (*Manifest, error) {
 	return manifest, nil
 }
 
-// ListRuntimes delegates to the underlying source (not cached).
+// ListRuntimes returns the cached list of runtimes if valid, otherwise fetches it from
+// the underlying source. If the source fails (e.g. when offline), an expired cached
+// list is returned instead.
 func (s *CachedSource) ListRuntimes() ([]string, error) {
-	return s.source.ListRuntimes()
+	entry, cacheErr := s.loadRuntimesFromCache()
+	if cacheErr == nil && time.Since(entry.CachedAt) <= s.ttl {
+		return entry.Runtimes, nil
+	}
+
+	runtimes, err := s.source.ListRuntimes()
+	if err != nil {
+		if cacheErr == nil {
+			return entry.Runtimes, nil
+		}
+		return nil, err
+	}
+
+	// Save to cache (ignore errors, caching is best-effort)
+	_ = s.saveRuntimesToCache(runtimes)
+
+	return runtimes, nil
 }
 
-// ForceRefresh clears the cache and fetches fresh manifests.
+// ForceRefresh fetches a fresh manifest, bypassing the cache. The cached manifest is
+// only replaced when the fetch succeeds, so it is still there to fall back to offline.
 func (s *CachedSource) ForceRefresh(runtime string) (*Manifest, error) {
-	// Remove cached file
-	cachePath := s.cachePath(runtime)
-	_ = os.Remove(cachePath)
-
 	// Fetch fresh from source
 	manifest, err := s.source.GetManifest(runtime)
 	if err != nil {
@@ -102,7 +133,7 @@ func (s *CachedSource) cachePath(runtime string) string {
 	return filepath.Join(s.cacheDir, runtime+".cache.json")
 }
 
-func (s *CachedSource) loadFromCache(runtime string) (*Manifest, error) {
+func (s *CachedSource) loadFromCache(runtime string) (*cacheEntry, error) {
 	cachePath := s.cachePath(runtime)
 
 	data, err := os.ReadFile(cachePath)
@@ -114,13 +145,11 @@ func (s *CachedSource) loadFromCache(runtime string) (*Manifest, error) {
 	if err := json.Unmarshal(data, &entry); err != nil {
 		return nil, err
 	}
-
-	// Check if cache is still valid
-	if time.Since(entry.CachedAt) > s.ttl {
-		return nil, os.ErrNotExist // Treat expired cache as not found
+	if entry.Manifest == nil {
+		return nil, os.ErrNotExist
 	}
 
-	return entry.Manifest, nil
+	return &entry, nil
 }
 
 func (s *CachedSource) saveToCache(runtime string, manifest *Manifest) error {
@@ -140,3 +169,35 @@ func (s *CachedSource) saveToCache(runtime string, manifest *Manifest) error {
 
 	return os.WriteFile(s.cachePath(runtime), data, 0644)
 }
+
+func (s *CachedSource) loadRuntimesFromCache() (*runtimesCacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(s.cacheDir, runtimesCacheFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry runtimesCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (s *CachedSource) saveRuntimesToCache(runtimes []string) error {
+	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
+		return err
+	}
+
+	entry := runtimesCacheEntry{
+		CachedAt: time.Now(),
+		Runtimes: runtimes,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(s.cacheDir, runtimesCacheFile), data, 0644)
+}
@@ -10,10 +10,11 @@ import (
 
 // mockSource is a test source that tracks calls
 type mockSource struct {
-	manifests map[string]*Manifest
-	callCount map[string]int
-	runtimes  []string
-	returnErr error
+	manifests     map[string]*Manifest
+	callCount     map[string]int
+	runtimes      []string
+	runtimesCalls int
+	returnErr     error
 }
 
 func newMockSource() *mockSource {
@@ -36,6 +37,10 @@ func (s *mockSource) GetManifest(runtime string) (*Manifest, error) {
 }
 
 func (s *mockSource) ListRuntimes() ([]string, error) {
+	s.runtimesCalls++
+	if s.returnErr != nil {
+		return nil, s.returnErr
+	}
 	return s.runtimes, nil
 }
 
@@ -135,6 +140,96 @@ func TestCachedSourceExpiration(t *testing.T) {
 	}
 }
 
+func TestCachedSourceListRuntimes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mock := newMockSource()
+	mock.runtimes = []string{"node", "python"}
+
+	source := NewCachedSource(mock, tmpDir, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		runtimes, err := source.ListRuntimes()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(runtimes) != 2 {
+			t.Errorf("runtimes = %v, want [node python]", runtimes)
+		}
+	}
+	if mock.runtimesCalls != 1 {
+		t.Errorf("runtimesCalls = %d, want 1 (should use cache)", mock.runtimesCalls)
+	}
+
+	// A new source sharing the cache directory reuses the cached list
+	mock.runtimes = []string{"node", "python", "ruby"}
+	reopened := NewCachedSource(mock, tmpDir, time.Hour)
+	runtimes, err := reopened.ListRuntimes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(runtimes) != 2 || mock.runtimesCalls != 1 {
+		t.Errorf("runtimes = %v after %d calls, want cached [node python] after 1", runtimes, mock.runtimesCalls)
+	}
+}
+
+func TestCachedSourceListRuntimesExpiration(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mock := newMockSource()
+	mock.runtimes = []string{"node", "python"}
+
+	source := NewCachedSource(mock, tmpDir, time.Millisecond)
+	if _, err := source.ListRuntimes(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for cache to expire
+	time.Sleep(10 * time.Millisecond)
+
+	mock.runtimes = []string{"node", "python", "ruby"}
+	runtimes, err := source.ListRuntimes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mock.runtimesCalls != 2 {
+		t.Errorf("runtimesCalls = %d, want 2 (cache should have expired)", mock.runtimesCalls)
+	}
+	if len(runtimes) != 3 {
+		t.Errorf("runtimes = %v, want refreshed list with ruby", runtimes)
+	}
+}
+
+func TestCachedSourceListRuntimesOffline(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mock := newMockSource()
+	mock.runtimes = []string{"node", "python"}
+
+	source := NewCachedSource(mock, tmpDir, time.Millisecond)
+	if _, err := source.ListRuntimes(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expire the cache and take the source offline
+	time.Sleep(10 * time.Millisecond)
+	mock.returnErr = os.ErrDeadlineExceeded
+
+	runtimes, err := source.ListRuntimes()
+	if err != nil {
+		t.Fatalf("expected stale cached list when offline, got error: %v", err)
+	}
+	if len(runtimes) != 2 {
+		t.Errorf("runtimes = %v, want stale [node python]", runtimes)
+	}
+
+	// Without a cached list the error is returned
+	empty := NewCachedSource(mock, t.TempDir(), time.Hour)
+	if _, err := empty.ListRuntimes(); err == nil {
+		t.Error("expected error with no cache and an offline source")
+	}
+}
+
 func TestCachedSourceClearCache(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -246,3 +341,44 @@ func TestNewEmbeddedSourceFromFS(t *testing.T) {
 		t.Errorf("Version = %d, want 1", m.Version)
 	}
 }
+
+func TestCachedSourceGetManifestOffline(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mock := newMockSource()
+	mock.manifests["node"] = &Manifest{
+		Version:  1,
+		Versions: map[string]map[string]*Download{"22.0.0": {}},
+	}
+
+	source := NewCachedSource(mock, tmpDir, time.Millisecond)
+	if _, err := source.GetManifest("node"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expire the cache and take the source offline
+	time.Sleep(10 * time.Millisecond)
+	mock.returnErr = os.ErrDeadlineExceeded
+
+	m, err := source.GetManifest("node")
+	if err != nil {
+		t.Fatalf("expected stale cached manifest when offline, got error: %v", err)
+	}
+	if _, ok := m.Versions["22.0.0"]; !ok {
+		t.Errorf("manifest versions = %v, want the stale cached 22.0.0", m.Versions)
+	}
+
+	// A failed refresh keeps the cached manifest
+	if _, err := source.ForceRefresh("node"); err == nil {
+		t.Error("ForceRefresh() expected error with an offline source")
+	}
+	if _, err := source.GetManifest("node"); err != nil {
+		t.Errorf("GetManifest() after a failed refresh error: %v", err)
+	}
+
+	// Without a cached manifest the error is returned
+	empty := NewCachedSource(mock, t.TempDir(), time.Hour)
+	if _, err := empty.GetManifest("node"); err == nil {
+		t.Error("expected error with no cache and an offline source")
+	}
+}
@@ -2,6 +2,7 @@ package manifest
 
 import (
 	"embed"
+	"fmt"
 	"io/fs"
 	"strings"
 )
@@ -38,6 +39,36 @@ func (s *EmbeddedSource) GetManifest(runtime string) (*Manifest, error) {
 	return ParseManifest(data)
 }
 
+// ManifestSummary describes a manifest embedded in the binary
+type ManifestSummary struct {
+	Runtime       string
+	SchemaVersion int // Format version of the manifest
+	Versions      int // Number of versions the manifest lists
+}
+
+// Summaries returns a summary of each embedded manifest, sorted by runtime name.
+func (s *EmbeddedSource) Summaries() ([]ManifestSummary, error) {
+	runtimes, err := s.ListRuntimes()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]ManifestSummary, 0, len(runtimes))
+	for _, runtimeName := range runtimes {
+		m, err := s.GetManifest(runtimeName)
+		if err != nil {
+			return nil, fmt.Errorf("embedded %s manifest: %w", runtimeName, err)
+		}
+		summaries = append(summaries, ManifestSummary{
+			Runtime:       runtimeName,
+			SchemaVersion: m.Version,
+			Versions:      len(m.Versions),
+		})
+	}
+
+	return summaries, nil
+}
+
 // ListRuntimes returns all available runtime names by scanning embedded files.
 func (s *EmbeddedSource) ListRuntimes() ([]string, error) {
 	entries, err := fs.ReadDir(s.fs, ".")
@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// TestEmbeddedManifestsConsistent checks the manifests shipped in the binary, so a
+// generator regression is caught by go test before a release
+func TestEmbeddedManifestsConsistent(t *testing.T) {
+	source := NewEmbeddedSource()
+	runtimes, err := source.ListRuntimes()
+	if err != nil {
+		t.Fatalf("ListRuntimes() error: %v", err)
+	}
+	for _, want := range []string{"node", "python", "ruby"} {
+		if !containsString(runtimes, want) {
+			t.Errorf("embedded manifests %v are missing %s", runtimes, want)
+		}
+	}
+
+	for _, runtimeName := range runtimes {
+		t.Run(runtimeName, func(t *testing.T) {
+			m, err := source.GetManifest(runtimeName)
+			if err != nil {
+				t.Fatalf("GetManifest() error: %v", err)
+			}
+			if len(m.Versions) == 0 {
+				t.Fatal("manifest has no versions")
+			}
+
+			for version, platforms := range m.Versions {
+				if err := checkManifestVersion(version); err != nil {
+					t.Errorf("version %q: %v", version, err)
+				}
+
+				for platform, download := range platforms {
+					if _, _, err := SplitPlatformKey(platform); err != nil {
+						t.Errorf("%s: %v", version, err)
+					}
+					if download == nil {
+						continue
+					}
+					if u, err := url.Parse(download.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+						t.Errorf("%s %s: download URL %q is not an https URL", version, platform, download.URL)
+					}
+				}
+			}
+
+			for version, release := range m.Releases {
+				if _, ok := m.Versions[version]; !ok {
+					t.Errorf("release %q is not a version in the manifest", version)
+				}
+				if _, err := time.Parse(time.DateOnly, release.Date); release.Date != "" && err != nil {
+					t.Errorf("release %q: date %q is not YYYY-MM-DD", version, release.Date)
+				}
+			}
+		})
+	}
+}
+
+// checkManifestVersion checks that a manifest version is a plain version that
+// runtime.NewVersion parses back to the same release numbers
+func checkManifestVersion(version string) error {
+	if !runtime.LooksLikeVersion(version) {
+		return fmt.Errorf("does not look like a version")
+	}
+
+	v := runtime.NewVersion(version)
+	if v.Normalized() != version {
+		return fmt.Errorf("normalizes to %q", v.Normalized())
+	}
+	release := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if !strings.HasPrefix(version, release) {
+		return fmt.Errorf("parses as %s", release)
+	}
+	return nil
+}
+
+func TestCheckManifestVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{"20.11.1", false},
+		{"4.0.0-preview2", false},
+		{"3.13.1+20251209", false},
+		{"v20.11.1", true},
+		{"20.11", true},
+		{"latest", true},
+		{" 3.12.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if err := checkManifestVersion(tt.version); (err != nil) != tt.wantErr {
+				t.Errorf("checkManifestVersion(%q) = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/dtvem/dtvem/src/internal/download"
 )
 
 // DefaultRemoteURL is the default URL for fetching manifests.
@@ -20,12 +22,11 @@ type HTTPSource struct {
 }
 
 // NewHTTPSource creates a Source that fetches manifests from a remote URL.
+// It uses the network timeout configured for downloads, or DefaultHTTPTimeout.
 func NewHTTPSource(baseURL string) *HTTPSource {
 	return &HTTPSource{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: DefaultHTTPTimeout,
-		},
+		baseURL:    baseURL,
+		httpClient: download.HTTPClientWithDefault(DefaultHTTPTimeout),
 	}
 }
 
@@ -4,6 +4,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/download"
 )
 
 func TestHTTPSource(t *testing.T) {
@@ -118,3 +121,15 @@ func TestHTTPSourceNetworkError(t *testing.T) {
 		t.Fatal("expected error for unreachable server")
 	}
 }
+
+func TestNewHTTPSourceTimeout(t *testing.T) {
+	t.Setenv(download.TimeoutEnvVar, "")
+	if got := download.TimeoutOf(NewHTTPSource(DefaultRemoteURL).httpClient); got != DefaultHTTPTimeout {
+		t.Errorf("timeout = %v, want default %v", got, DefaultHTTPTimeout)
+	}
+
+	t.Setenv(download.TimeoutEnvVar, "5s")
+	if got := download.TimeoutOf(NewHTTPSource(DefaultRemoteURL).httpClient); got != 5*time.Second {
+		t.Errorf("timeout = %v, want configured 5s", got)
+	}
+}
@@ -5,17 +5,46 @@ package manifest
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/runtime"
 )
 
+// BuildSeparator separates a version from a build tag, as in "3.13.1+20251209".
+// python-build-standalone republishes the same Python version under new build dates.
+const BuildSeparator = "+"
+
 // Manifest represents a runtime's version manifest containing all available versions
 // and their download information per platform.
 type Manifest struct {
-	// Version is the manifest format version (currently 1)
+	// Version is the manifest format version (currently SchemaVersion)
 	Version int `json:"version"`
 
 	// Versions maps version strings to platform availability
 	// e.g., "3.13.1" -> {"windows-amd64": {URL, SHA256}, "darwin-arm64": false}
 	Versions map[string]map[string]*Download `json:"versions"`
+
+	// Releases maps version strings to release information. It is optional:
+	// manifests written before it was added simply have no release notes.
+	Releases map[string]*Release `json:"releases,omitempty"`
+
+	// DisplayName is the user-friendly runtime name, e.g. "Node.js". It is optional
+	// and lets runtimes without a compiled provider present nicely.
+	DisplayName string `json:"display_name,omitempty"`
+
+	// Shims lists the executables a runtime without a compiled provider gets shims
+	// for, e.g. ["deno"]. It is optional.
+	Shims []string `json:"shims,omitempty"`
+}
+
+// Release describes when a version was released and whether it is still supported.
+type Release struct {
+	// Date is the upstream release date in YYYY-MM-DD form, empty when unknown
+	Date string `json:"date,omitempty"`
+
+	// EOL is true when the version's release series no longer gets upstream fixes
+	EOL bool `json:"eol,omitempty"`
 }
 
 // Download contains the URL and checksum for a downloadable binary.
@@ -33,6 +62,15 @@ type Download struct {
 	// "dtvem" - checksum generated by dtvem during mirroring
 	// Empty string for legacy manifests without this field
 	SHA256Source string `json:"sha256_source,omitempty"`
+
+	// Build is the upstream build tag of the binary, e.g. the python-build-standalone
+	// release date "20251209". Empty when the upstream has no build tags.
+	Build string `json:"build,omitempty"`
+
+	// Format is the archive format ("tar.gz", "tar.xz", "zip" or "7z") when the URL's
+	// extension doesn't tell it, e.g. for mirrors that serve a .tar.gz at a .tgz URL
+	// or strip the extension. Empty when the URL's extension is right.
+	Format string `json:"format,omitempty"`
 }
 
 // Availability represents whether a version is available for a platform.
@@ -50,24 +88,81 @@ const (
 	AvailabilityUnavailable
 )
 
+// SplitBuild splits a version with a build tag ("3.13.1+20251209") into the
+// version and the build tag. The build tag is empty when there is none.
+func SplitBuild(version string) (string, string) {
+	base, build, _ := strings.Cut(version, BuildSeparator)
+	return base, build
+}
+
 // GetDownload returns the download info for a specific version and platform.
+// A version with a build tag ("3.13.1+20251209") matches a manifest entry of that
+// exact name, or the plain version's download when it has the same build tag.
 // Returns nil if the version doesn't exist or has no pre-built for the platform.
+// The URL points to the mirror when one is set (see RewriteURL).
 func (m *Manifest) GetDownload(version, platform string) *Download {
-	platforms, ok := m.Versions[version]
-	if !ok {
-		return nil
+	download, _ := m.lookup(version, platform)
+	return mirrored(download)
+}
+
+// lookup returns the download for a version and platform, and whether the
+// manifest has an entry (possibly nil) for them
+func (m *Manifest) lookup(version, platform string) (*Download, bool) {
+	if platforms, ok := m.Versions[version]; ok {
+		download, exists := platforms[platform]
+		return download, exists
 	}
-	return platforms[platform]
+
+	base, build := SplitBuild(version)
+	if build == "" {
+		return nil, false
+	}
+	download, exists := m.Versions[base][platform]
+	if !exists || (download != nil && download.Build != build) {
+		return nil, false
+	}
+	return download, true
 }
 
-// CheckAvailability returns the availability status for a version on a platform.
-func (m *Manifest) CheckAvailability(version, platform string) Availability {
-	platforms, ok := m.Versions[version]
-	if !ok {
-		return AvailabilityUnknown
+// Builds returns the build tags available for a version on a platform,
+// from both build-tagged entries and the plain version's download.
+func (m *Manifest) Builds(version, platform string) []string {
+	base, _ := SplitBuild(version)
+
+	var builds []string
+	for v, platforms := range m.Versions {
+		download := platforms[platform]
+		if download == nil {
+			continue
+		}
+		vBase, vBuild := SplitBuild(v)
+		if vBase != base {
+			continue
+		}
+		if vBuild == "" {
+			vBuild = download.Build
+		}
+		if vBuild != "" && !containsBuild(builds, vBuild) {
+			builds = append(builds, vBuild)
+		}
 	}
+	sort.Strings(builds)
+	return builds
+}
 
-	download, exists := platforms[platform]
+// containsBuild reports whether builds contains build
+func containsBuild(builds []string, build string) bool {
+	for _, b := range builds {
+		if b == build {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAvailability returns the availability status for a version on a platform.
+func (m *Manifest) CheckAvailability(version, platform string) Availability {
+	download, exists := m.lookup(version, platform)
 	if !exists {
 		return AvailabilityUnknown
 	}
@@ -79,6 +174,56 @@ func (m *Manifest) CheckAvailability(version, platform string) Availability {
 	return AvailabilityAvailable
 }
 
+// EmulationHint returns a note for an error message when version has no native
+// build for platform but an amd64 build exists that can run under emulation.
+// It returns an empty string when there is nothing to suggest.
+func (m *Manifest) EmulationHint(version, platform string) string {
+	emulated, ok := EmulatedPlatform(platform)
+	if !ok || m.GetDownload(version, emulated) == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (an x64 build is available and runs under emulation: set %s=amd64 to install it)", ArchEnvVar)
+}
+
+// ReleaseNotes returns a short note about a version's release for display,
+// such as "EOL" or "released 2024-01-15". A version with a build tag uses the
+// release of its plain version. Returns an empty string when nothing is known.
+func (m *Manifest) ReleaseNotes(version string) string {
+	release, ok := m.Releases[version]
+	if !ok {
+		base, _ := SplitBuild(version)
+		release = m.Releases[base]
+	}
+
+	switch {
+	case release == nil:
+		return ""
+	case release.EOL:
+		return "EOL"
+	case release.Date != "":
+		return "released " + release.Date
+	}
+	return ""
+}
+
+// RuntimeDisplayName returns the manifest's display name for the runtime, or
+// runtimeName itself when the manifest doesn't set one
+func (m *Manifest) RuntimeDisplayName(runtimeName string) string {
+	if m.DisplayName != "" {
+		return m.DisplayName
+	}
+	return runtimeName
+}
+
+// ShimNames returns the executables to create shims for, from the manifest's
+// shims metadata. Without metadata the runtime gets one shim named after it.
+func (m *Manifest) ShimNames(runtimeName string) []string {
+	if len(m.Shims) > 0 {
+		return m.Shims
+	}
+	return []string{runtimeName}
+}
+
 // ListVersions returns all version strings in the manifest.
 // The order is not guaranteed.
 func (m *Manifest) ListVersions() []string {
@@ -100,6 +245,27 @@ func (m *Manifest) ListAvailableVersions(platform string) []string {
 	return versions
 }
 
+// AvailableVersions returns the versions that have a build for platform, sorted newest
+// first, with their release notes (release date or EOL status). Versions without a build
+// for the platform's architecture (e.g. releases that predate arm64 support) are left out.
+func (m *Manifest) AvailableVersions(platform string) []runtime.AvailableVersion {
+	versionStrings := m.ListAvailableVersions(platform)
+
+	versions := make([]runtime.AvailableVersion, 0, len(versionStrings))
+	for _, v := range versionStrings {
+		versions = append(versions, runtime.AvailableVersion{
+			Version: runtime.NewVersion(v),
+			Notes:   m.ReleaseNotes(v),
+		})
+	}
+	runtime.SortVersionsDesc(versions)
+
+	return versions
+}
+
+// SchemaVersion is the manifest format version this build of dtvem reads
+const SchemaVersion = 1
+
 // ParseManifest parses JSON data into a Manifest.
 func ParseManifest(data []byte) (*Manifest, error) {
 	var m Manifest
@@ -107,7 +273,7 @@ func ParseManifest(data []byte) (*Manifest, error) {
 		return nil, fmt.Errorf("failed to parse manifest: %w", err)
 	}
 
-	if m.Version != 1 {
+	if m.Version != SchemaVersion {
 		return nil, fmt.Errorf("unsupported manifest version: %d", m.Version)
 	}
 
@@ -1,7 +1,9 @@
 package manifest
 
 import (
+	"reflect"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +76,28 @@ func TestParseManifest(t *testing.T) {
 	}
 }
 
+func TestParseManifest_Format(t *testing.T) {
+	m, err := ParseManifest([]byte(`{
+		"version": 1,
+		"versions": {
+			"22.0.0": {
+				"linux-amd64": {"url": "https://mirror.example.com/node-v22.0.0-linux-x64.tgz", "format": "tar.xz"},
+				"darwin-arm64": {"url": "https://example.com/node-v22.0.0-darwin-arm64.tar.gz"}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseManifest() error: %v", err)
+	}
+
+	if got := m.GetDownload("22.0.0", PlatformLinuxAMD64).Format; got != "tar.xz" {
+		t.Errorf("Format = %q, want tar.xz", got)
+	}
+	if got := m.GetDownload("22.0.0", PlatformDarwinARM64).Format; got != "" {
+		t.Errorf("Format without a declared format = %q, want empty", got)
+	}
+}
+
 func TestManifestGetDownload(t *testing.T) {
 	data := `{
 		"version": 1,
@@ -146,6 +170,31 @@ func TestManifestGetDownload(t *testing.T) {
 	}
 }
 
+func TestManifestEmulationHint(t *testing.T) {
+	m := &Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*Download{
+			"20.0.0": {
+				"windows-amd64": {URL: "https://example.com/win-x64.zip"},
+			},
+			"22.0.0": {
+				"windows-amd64": {URL: "https://example.com/win-x64.zip"},
+				"windows-arm64": {URL: "https://example.com/win-arm64.zip"},
+			},
+		},
+	}
+
+	if hint := m.EmulationHint("20.0.0", PlatformWindowsARM64); !strings.Contains(hint, ArchEnvVar) {
+		t.Errorf("EmulationHint() = %q, want a hint mentioning %s", hint, ArchEnvVar)
+	}
+	if hint := m.EmulationHint("20.0.0", PlatformLinuxARM64); hint != "" {
+		t.Errorf("EmulationHint() on linux = %q, want empty", hint)
+	}
+	if hint := m.EmulationHint("99.0.0", PlatformWindowsARM64); hint != "" {
+		t.Errorf("EmulationHint() for unknown version = %q, want empty", hint)
+	}
+}
+
 func TestManifestCheckAvailability(t *testing.T) {
 	data := `{
 		"version": 1,
@@ -297,3 +346,246 @@ func TestManifestListAvailableVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestManifestAvailableVersions(t *testing.T) {
+	m := &Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*Download{
+			"3.12.0":  {"linux-amd64": {URL: "https://example.com/312.tar.gz"}},
+			"3.13.1":  {"linux-amd64": {URL: "https://example.com/3131.tar.gz"}},
+			"3.11.5":  {"linux-amd64": nil},
+			"3.9.25":  {"linux-amd64": {URL: "https://example.com/3925.tar.gz"}},
+			"3.13.10": {"darwin-arm64": {URL: "https://example.com/31310.tar.gz"}},
+		},
+		Releases: map[string]*Release{
+			"3.13.1": {Date: "2024-12-03"},
+			"3.9.25": {Date: "2025-10-31", EOL: true},
+		},
+	}
+
+	got := m.AvailableVersions("linux-amd64")
+	want := []struct{ version, notes string }{
+		{"3.13.1", "released 2024-12-03"},
+		{"3.12.0", ""},
+		{"3.9.25", "EOL"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AvailableVersions() = %d versions, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Version.Raw != w.version || got[i].Notes != w.notes {
+			t.Errorf("AvailableVersions()[%d] = %s (%q), want %s (%q)", i, got[i].Version.Raw, got[i].Notes, w.version, w.notes)
+		}
+	}
+}
+
+func TestManifestGetDownload_BuildTag(t *testing.T) {
+	data := `{
+		"version": 1,
+		"versions": {
+			"3.13.1": {
+				"linux-amd64": {"url": "https://example.com/3.13.1.tar.gz", "sha256": "abc123", "build": "20251209"},
+				"darwin-arm64": null
+			},
+			"3.13.1+20250115": {
+				"linux-amd64": {"url": "https://example.com/3.13.1-20250115.tar.gz", "sha256": "def456", "build": "20250115"}
+			},
+			"3.12.0": {
+				"linux-amd64": {"url": "https://example.com/3.12.0.tar.gz", "sha256": "ghi789"}
+			}
+		}
+	}`
+
+	m, err := ParseManifest([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to parse manifest: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		version  string
+		platform string
+		wantURL  string
+		wantNil  bool
+	}{
+		{
+			name:     "plain version",
+			version:  "3.13.1",
+			platform: "linux-amd64",
+			wantURL:  "https://example.com/3.13.1.tar.gz",
+		},
+		{
+			name:     "build of the plain entry",
+			version:  "3.13.1+20251209",
+			platform: "linux-amd64",
+			wantURL:  "https://example.com/3.13.1.tar.gz",
+		},
+		{
+			name:     "build-tagged entry",
+			version:  "3.13.1+20250115",
+			platform: "linux-amd64",
+			wantURL:  "https://example.com/3.13.1-20250115.tar.gz",
+		},
+		{
+			name:     "unknown build",
+			version:  "3.13.1+20240101",
+			platform: "linux-amd64",
+			wantNil:  true,
+		},
+		{
+			name:     "build of a version without build tags",
+			version:  "3.12.0+20251209",
+			platform: "linux-amd64",
+			wantNil:  true,
+		},
+		{
+			name:     "build on a platform without a binary",
+			version:  "3.13.1+20251209",
+			platform: "darwin-arm64",
+			wantNil:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := m.GetDownload(tt.version, tt.platform)
+			if tt.wantNil {
+				if d != nil {
+					t.Errorf("expected nil, got %+v", d)
+				}
+				return
+			}
+			if d == nil {
+				t.Fatal("expected download, got nil")
+			}
+			if d.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", d.URL, tt.wantURL)
+			}
+		})
+	}
+
+	if got := m.CheckAvailability("3.13.1+20240101", "linux-amd64"); got != AvailabilityUnknown {
+		t.Errorf("CheckAvailability(unknown build) = %v, want AvailabilityUnknown", got)
+	}
+	if got := m.CheckAvailability("3.13.1+20251209", "darwin-arm64"); got != AvailabilityUnavailable {
+		t.Errorf("CheckAvailability(build without binary) = %v, want AvailabilityUnavailable", got)
+	}
+
+	builds := m.Builds("3.13.1+20240101", "linux-amd64")
+	if len(builds) != 2 || builds[0] != "20250115" || builds[1] != "20251209" {
+		t.Errorf("Builds() = %v, want [20250115 20251209]", builds)
+	}
+}
+
+func TestSplitBuild(t *testing.T) {
+	tests := []struct {
+		version   string
+		wantBase  string
+		wantBuild string
+	}{
+		{"3.13.1+20251209", "3.13.1", "20251209"},
+		{"3.13.1", "3.13.1", ""},
+		{"3.13.1+", "3.13.1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			base, build := SplitBuild(tt.version)
+			if base != tt.wantBase || build != tt.wantBuild {
+				t.Errorf("SplitBuild(%q) = (%q, %q), want (%q, %q)", tt.version, base, build, tt.wantBase, tt.wantBuild)
+			}
+		})
+	}
+}
+
+func TestManifestReleaseNotes(t *testing.T) {
+	data := `{
+		"version": 1,
+		"versions": {
+			"3.8.20": {"linux-amd64": {"url": "https://example.com/3.8.20.tar.gz", "sha256": "abc"}},
+			"3.12.8": {"linux-amd64": {"url": "https://example.com/3.12.8.tar.gz", "sha256": "def"}},
+			"3.13.1": {"linux-amd64": {"url": "https://example.com/3.13.1.tar.gz", "sha256": "ghi"}}
+		},
+		"releases": {
+			"3.8.20": {"date": "2024-09-06", "eol": true},
+			"3.12.8": {"date": "2024-12-03"},
+			"3.13.0": {}
+		}
+	}`
+
+	m, err := ParseManifest([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseManifest() error: %v", err)
+	}
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"3.8.20", "EOL"},
+		{"3.12.8", "released 2024-12-03"},
+		{"3.12.8+20241206", "released 2024-12-03"},
+		{"3.13.0", ""},
+		{"3.13.1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := m.ReleaseNotes(tt.version); got != tt.want {
+				t.Errorf("ReleaseNotes(%q) = %q, want %q", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestReleaseNotes_NoReleases(t *testing.T) {
+	m, err := ParseManifest([]byte(`{"version": 1, "versions": {"20.0.0": {}}}`))
+	if err != nil {
+		t.Fatalf("ParseManifest() error: %v", err)
+	}
+	if got := m.ReleaseNotes("20.0.0"); got != "" {
+		t.Errorf("ReleaseNotes() = %q, want empty for a manifest without releases", got)
+	}
+}
+
+func TestManifestRuntimeMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		wantDisplayName string
+		wantShims       []string
+	}{
+		{
+			name: "with metadata",
+			data: `{
+				"version": 1,
+				"display_name": "Deno",
+				"shims": ["deno", "denort"],
+				"versions": {"2.1.0": {}}
+			}`,
+			wantDisplayName: "Deno",
+			wantShims:       []string{"deno", "denort"},
+		},
+		{
+			name:            "without metadata",
+			data:            `{"version": 1, "versions": {"2.1.0": {}}}`,
+			wantDisplayName: "deno",
+			wantShims:       []string{"deno"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseManifest([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseManifest() error: %v", err)
+			}
+			if got := m.RuntimeDisplayName("deno"); got != tt.wantDisplayName {
+				t.Errorf("RuntimeDisplayName() = %q, want %q", got, tt.wantDisplayName)
+			}
+			if got := m.ShimNames("deno"); !reflect.DeepEqual(got, tt.wantShims) {
+				t.Errorf("ShimNames() = %v, want %v", got, tt.wantShims)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package manifest
+
+import (
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// DefaultBuildsURL is where the binaries listed in the manifests are hosted
+const DefaultBuildsURL = "https://builds.dtvem.io"
+
+// MirrorEnvVar is the environment variable that redirects downloads from
+// DefaultBuildsURL to a mirror, e.g. DTVEM_MIRROR=https://mirror.corp/dtvem
+const MirrorEnvVar = config.MirrorEnvVar
+
+// RewriteURL redirects a download URL to a mirror. The match is on a plain string
+// prefix: a URL that starts with DefaultBuildsURL followed by "/" has that
+// DefaultBuildsURL replaced with mirror, minus any trailing slashes. For the mirror
+// https://mirror.corp/dtvem,
+//
+//	https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz
+//
+// becomes
+//
+//	https://mirror.corp/dtvem/node/22.0.0/linux-amd64.tar.gz
+//
+// Other URLs, such as upstream downloads or hosts that merely start with
+// "builds.dtvem.io", are returned unchanged, as is every URL when mirror is empty.
+func RewriteURL(url, mirror string) string {
+	mirror = strings.TrimRight(strings.TrimSpace(mirror), "/")
+	if mirror == "" || !strings.HasPrefix(url, DefaultBuildsURL+"/") {
+		return url
+	}
+	return mirror + strings.TrimPrefix(url, DefaultBuildsURL)
+}
+
+// MirrorURL redirects a download URL to the mirror set with the mirror setting
+// (DTVEM_MIRROR), if any
+func MirrorURL(url string) string {
+	return RewriteURL(url, config.Setting(config.SettingMirror))
+}
+
+// mirrored returns the download with its URL redirected to the configured mirror.
+// The manifest's own entry is left unchanged.
+func mirrored(download *Download) *Download {
+	if download == nil {
+		return nil
+	}
+	url := MirrorURL(download.URL)
+	if url == download.URL {
+		return download
+	}
+	redirected := *download
+	redirected.URL = url
+	return &redirected
+}
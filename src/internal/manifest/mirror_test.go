@@ -0,0 +1,91 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+func TestRewriteURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		mirror string
+		want   string
+	}{
+		{
+			name:   "R2 build",
+			url:    "https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz",
+			mirror: "https://mirror.corp/dtvem",
+			want:   "https://mirror.corp/dtvem/node/22.0.0/linux-amd64.tar.gz",
+		},
+		{
+			name:   "R2 build with a build tag",
+			url:    "https://builds.dtvem.io/python/3.13.1+20251209/windows-amd64.zip",
+			mirror: "https://mirror.corp/dtvem/",
+			want:   "https://mirror.corp/dtvem/python/3.13.1+20251209/windows-amd64.zip",
+		},
+		{
+			name:   "mirror at the root of a host",
+			url:    "https://builds.dtvem.io/ruby/3.3.0/darwin-arm64.tar.gz",
+			mirror: "http://artifacts.internal:8080",
+			want:   "http://artifacts.internal:8080/ruby/3.3.0/darwin-arm64.tar.gz",
+		},
+		{
+			name:   "no mirror",
+			url:    "https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz",
+			mirror: "",
+			want:   "https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz",
+		},
+		{
+			name:   "upstream URL",
+			url:    "https://nodejs.org/dist/v22.0.0/node-v22.0.0-win-x64.7z",
+			mirror: "https://mirror.corp/dtvem",
+			want:   "https://nodejs.org/dist/v22.0.0/node-v22.0.0-win-x64.7z",
+		},
+		{
+			name:   "lookalike host",
+			url:    "https://builds.dtvem.io.example.com/node/22.0.0/linux-amd64.tar.gz",
+			mirror: "https://mirror.corp/dtvem",
+			want:   "https://builds.dtvem.io.example.com/node/22.0.0/linux-amd64.tar.gz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RewriteURL(tt.url, tt.mirror); got != tt.want {
+				t.Errorf("RewriteURL(%q, %q) = %q, want %q", tt.url, tt.mirror, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestGetDownload_Mirror(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	config.ResetSettingsCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		config.ResetSettingsCache()
+	})
+	t.Setenv(MirrorEnvVar, "https://mirror.corp/dtvem")
+
+	url := "https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz"
+	m := &Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*Download{
+			"22.0.0": {PlatformLinuxAMD64: {URL: url, SHA256: "abc123"}},
+		},
+	}
+
+	dl := m.GetDownload("22.0.0", PlatformLinuxAMD64)
+	if dl == nil {
+		t.Fatal("GetDownload() = nil")
+	}
+	if dl.URL != "https://mirror.corp/dtvem/node/22.0.0/linux-amd64.tar.gz" || dl.SHA256 != "abc123" {
+		t.Errorf("GetDownload() = %+v, want the mirrored URL with the manifest's checksum", dl)
+	}
+	if m.Versions["22.0.0"][PlatformLinuxAMD64].URL != url {
+		t.Error("GetDownload() changed the manifest's entry")
+	}
+}
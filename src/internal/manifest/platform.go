@@ -3,6 +3,9 @@ package manifest
 import (
 	"fmt"
 	"runtime"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
 )
 
 // Platform keys match Go's runtime.GOOS-GOARCH format.
@@ -18,9 +21,115 @@ const (
 	PlatformLinux386     = "linux-386"
 )
 
+// ArchEnvVar is the environment variable that overrides the architecture
+// used to select downloads (e.g. DTVEM_ARCH=amd64 on an ARM64 host).
+const ArchEnvVar = config.ArchEnvVar
+
 // CurrentPlatform returns the platform key for the current OS and architecture.
+// The architecture is the native architecture of the host, so an amd64 build of
+// dtvem running under emulation (Rosetta 2, Windows on ARM) still selects
+// native arm64 downloads. DTVEM_ARCH (or the arch setting) overrides the detection.
 func CurrentPlatform() string {
-	return fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+	return platformKey(runtime.GOOS, selectArch(runtime.GOARCH, nativeArch(), config.Setting(config.SettingArch)))
+}
+
+// PlatformForArch returns the platform key for an architecture on the current OS.
+// Common spellings such as x64 and aarch64 are accepted.
+func PlatformForArch(arch string) string {
+	return platformKey(runtime.GOOS, arch)
+}
+
+// PlatformKey returns the manifest platform key for an OS and architecture.
+// Besides Go's GOOS/GOARCH names it accepts the spellings used by upstream
+// release names (e.g. Node.js's "win" and "x64", uname's "x86_64" and "armv7l").
+// It fails for combinations that have no platform key.
+func PlatformKey(goos, goarch string) (string, error) {
+	key := platformKey(goos, goarch)
+	if !IsValidPlatform(key) {
+		return "", fmt.Errorf("unsupported platform %s (supported platforms: %s)", key, strings.Join(ValidPlatforms(), ", "))
+	}
+	return key, nil
+}
+
+// SplitPlatformKey is the inverse of PlatformKey: it returns the GOOS and GOARCH
+// of a platform key such as "linux-arm64".
+func SplitPlatformKey(platform string) (goos, goarch string, err error) {
+	if !IsValidPlatform(platform) {
+		return "", "", fmt.Errorf("unsupported platform %s (supported platforms: %s)", platform, strings.Join(ValidPlatforms(), ", "))
+	}
+	i := strings.LastIndex(platform, "-")
+	return platform[:i], platform[i+1:], nil
+}
+
+// platformKey builds a platform key without checking that it is supported, so
+// that unsupported hosts still get a key to show in error messages.
+func platformKey(goos, goarch string) string {
+	return normalizeOS(goos) + "-" + normalizeArch(goarch)
+}
+
+// PlatformArch returns the architecture part of a platform key (e.g. "arm64" for "darwin-arm64").
+func PlatformArch(platform string) string {
+	if i := strings.LastIndex(platform, "-"); i >= 0 {
+		return platform[i+1:]
+	}
+	return platform
+}
+
+// selectArch decides which architecture to install runtimes for.
+// An explicit override wins, then the detected native architecture,
+// and finally the architecture dtvem itself was built for.
+func selectArch(buildArch, native, override string) string {
+	if arch := normalizeArch(override); arch != "" {
+		return arch
+	}
+	if native != "" {
+		return native
+	}
+	return buildArch
+}
+
+// normalizeOS maps common operating system spellings to Go's GOOS names.
+func normalizeOS(goos string) string {
+	switch strings.ToLower(strings.TrimSpace(goos)) {
+	case "win", "win32", "win64", "windows":
+		return "windows"
+	case "mac", "macos", "osx", "darwin":
+		return "darwin"
+	default:
+		return strings.ToLower(strings.TrimSpace(goos))
+	}
+}
+
+// normalizeArch maps common architecture spellings to Go's GOARCH names.
+// 32-bit ARM is armv7l in Node.js release names and uname output.
+func normalizeArch(arch string) string {
+	switch strings.ToLower(strings.TrimSpace(arch)) {
+	case "":
+		return ""
+	case "x64", "x86_64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "x86", "i386", "i686", "386":
+		return "386"
+	case "armv7l", "armv7", "armhf", "arm":
+		return "arm"
+	default:
+		return strings.ToLower(strings.TrimSpace(arch))
+	}
+}
+
+// EmulatedPlatform returns the amd64 platform that can run under emulation on
+// the given platform (Rosetta 2 on macOS, x64 emulation on Windows ARM64).
+func EmulatedPlatform(platform string) (string, bool) {
+	switch platform {
+	case PlatformDarwinARM64:
+		return PlatformDarwinAMD64, true
+	case PlatformWindowsARM64:
+		return PlatformWindowsAMD64, true
+	default:
+		return "", false
+	}
 }
 
 // ValidPlatforms returns all supported platform keys.
@@ -0,0 +1,19 @@
+//go:build darwin
+
+package manifest
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// nativeArch returns arm64 when dtvem is an amd64 binary running under Rosetta 2.
+func nativeArch() string {
+	if runtime.GOARCH == "amd64" {
+		if translated, err := unix.SysctlUint32("sysctl.proc_translated"); err == nil && translated == 1 {
+			return "arm64"
+		}
+	}
+	return ""
+}
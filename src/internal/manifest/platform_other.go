@@ -0,0 +1,8 @@
+//go:build !windows && !darwin
+
+package manifest
+
+// nativeArch returns an empty string; no emulation detection is needed on this OS.
+func nativeArch() string {
+	return ""
+}
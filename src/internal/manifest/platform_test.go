@@ -6,14 +6,165 @@ import (
 )
 
 func TestCurrentPlatform(t *testing.T) {
+	t.Setenv(ArchEnvVar, "")
+
 	got := CurrentPlatform()
-	want := runtime.GOOS + "-" + runtime.GOARCH
+	want := runtime.GOOS + "-" + selectArch(runtime.GOARCH, nativeArch(), "")
 
 	if got != want {
 		t.Errorf("CurrentPlatform() = %q, want %q", got, want)
 	}
 }
 
+func TestCurrentPlatform_ArchOverride(t *testing.T) {
+	t.Setenv(ArchEnvVar, "x64")
+
+	got := CurrentPlatform()
+	want := runtime.GOOS + "-amd64"
+
+	if got != want {
+		t.Errorf("CurrentPlatform() with %s=x64 = %q, want %q", ArchEnvVar, got, want)
+	}
+}
+
+func TestPlatformForArch(t *testing.T) {
+	tests := []struct {
+		arch string
+		want string
+	}{
+		{"arm64", runtime.GOOS + "-arm64"},
+		{"x64", runtime.GOOS + "-amd64"},
+		{" AArch64 ", runtime.GOOS + "-arm64"},
+	}
+
+	for _, tt := range tests {
+		platform := PlatformForArch(tt.arch)
+		if platform != tt.want {
+			t.Errorf("PlatformForArch(%q) = %q, want %q", tt.arch, platform, tt.want)
+		}
+		if arch := PlatformArch(platform); arch != PlatformArch(tt.want) {
+			t.Errorf("PlatformArch(%q) = %q", platform, arch)
+		}
+	}
+}
+
+func TestPlatformKey(t *testing.T) {
+	tests := []struct {
+		goos    string
+		goarch  string
+		want    string
+		wantErr bool
+	}{
+		{"windows", "amd64", PlatformWindowsAMD64, false},
+		{"windows", "arm64", PlatformWindowsARM64, false},
+		{"windows", "386", PlatformWindows386, false},
+		{"windows", "arm", "", true},
+		{"darwin", "amd64", PlatformDarwinAMD64, false},
+		{"darwin", "arm64", PlatformDarwinARM64, false},
+		{"darwin", "386", "", true},
+		{"darwin", "arm", "", true},
+		{"linux", "amd64", PlatformLinuxAMD64, false},
+		{"linux", "arm64", PlatformLinuxARM64, false},
+		{"linux", "386", PlatformLinux386, false},
+		{"linux", "arm", PlatformLinuxARM, false},
+		{"linux", "ppc64le", "", true},
+		{"freebsd", "amd64", "", true},
+
+		// Upstream spellings
+		{"win", "x64", PlatformWindowsAMD64, false},
+		{"win32", "x86", PlatformWindows386, false},
+		{"macos", "aarch64", PlatformDarwinARM64, false},
+		{"osx", "x86_64", PlatformDarwinAMD64, false},
+		{"Linux", "armv7l", PlatformLinuxARM, false},
+		{"linux", "armhf", PlatformLinuxARM, false},
+		{"linux", "i686", PlatformLinux386, false},
+		{" linux ", " ARM64 ", PlatformLinuxARM64, false},
+		{"", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			got, err := PlatformKey(tt.goos, tt.goarch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlatformKey(%q, %q) error = %v, wantErr %v", tt.goos, tt.goarch, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PlatformKey(%q, %q) = %q, want %q", tt.goos, tt.goarch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPlatformKey(t *testing.T) {
+	for _, platform := range ValidPlatforms() {
+		goos, goarch, err := SplitPlatformKey(platform)
+		if err != nil {
+			t.Errorf("SplitPlatformKey(%q) error: %v", platform, err)
+			continue
+		}
+		if key, err := PlatformKey(goos, goarch); err != nil || key != platform {
+			t.Errorf("PlatformKey(SplitPlatformKey(%q)) = %q, %v; want a round trip", platform, key, err)
+		}
+	}
+
+	for _, platform := range []string{"", "linux", "freebsd-amd64", "linux-x64"} {
+		if _, _, err := SplitPlatformKey(platform); err == nil {
+			t.Errorf("SplitPlatformKey(%q) should fail", platform)
+		}
+	}
+}
+
+func TestSelectArch(t *testing.T) {
+	tests := []struct {
+		name      string
+		buildArch string
+		native    string
+		override  string
+		want      string
+	}{
+		{"no detection uses build arch", "amd64", "", "", "amd64"},
+		{"native arm64 under emulation", "amd64", "arm64", "", "arm64"},
+		{"override wins over native", "amd64", "arm64", "amd64", "amd64"},
+		{"override accepts x64", "arm64", "", "x64", "amd64"},
+		{"override accepts aarch64", "amd64", "", "AARCH64", "arm64"},
+		{"override accepts armv7l", "amd64", "", "armv7l", "arm"},
+		{"override accepts armhf", "amd64", "", "armhf", "arm"},
+		{"unknown arch passed through", "amd64", "", "ppc64le", "ppc64le"},
+		{"blank override ignored", "arm64", "", "  ", "arm64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectArch(tt.buildArch, tt.native, tt.override)
+			if got != tt.want {
+				t.Errorf("selectArch(%q, %q, %q) = %q, want %q", tt.buildArch, tt.native, tt.override, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmulatedPlatform(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     string
+		wantOK   bool
+	}{
+		{PlatformWindowsARM64, PlatformWindowsAMD64, true},
+		{PlatformDarwinARM64, PlatformDarwinAMD64, true},
+		{PlatformLinuxARM64, "", false},
+		{PlatformWindowsAMD64, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got, ok := EmulatedPlatform(tt.platform)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("EmulatedPlatform(%q) = (%q, %v), want (%q, %v)", tt.platform, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestValidPlatforms(t *testing.T) {
 	platforms := ValidPlatforms()
 
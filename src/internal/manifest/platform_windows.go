@@ -0,0 +1,47 @@
+//go:build windows
+
+package manifest
+
+import (
+	"debug/pe"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// nativeArch returns the architecture of the Windows host, which differs from
+// runtime.GOARCH when dtvem runs under x64 emulation on ARM64.
+func nativeArch() string {
+	var processMachine, nativeMachine uint16
+	if err := windows.IsWow64Process2(windows.CurrentProcess(), &processMachine, &nativeMachine); err == nil {
+		if arch := archFromMachine(nativeMachine); arch != "" {
+			return arch
+		}
+	}
+
+	// IsWow64Process2 is unavailable before Windows 10 1511, fall back to the
+	// environment. PROCESSOR_ARCHITEW6432 is only set for emulated processes.
+	return archFromEnv(os.Getenv)
+}
+
+// archFromMachine maps a PE machine type to a GOARCH name.
+func archFromMachine(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64"
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "amd64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "386"
+	default:
+		return ""
+	}
+}
+
+// archFromEnv reads the host architecture from the PROCESSOR_ARCHITECTURE variables.
+func archFromEnv(getenv func(string) string) string {
+	if arch := getenv("PROCESSOR_ARCHITEW6432"); arch != "" {
+		return normalizeArch(arch)
+	}
+	return normalizeArch(getenv("PROCESSOR_ARCHITECTURE"))
+}
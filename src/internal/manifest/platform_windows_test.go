@@ -0,0 +1,68 @@
+//go:build windows
+
+package manifest
+
+import (
+	"debug/pe"
+	"testing"
+)
+
+func TestArchFromMachine(t *testing.T) {
+	tests := []struct {
+		machine uint16
+		want    string
+	}{
+		{pe.IMAGE_FILE_MACHINE_ARM64, "arm64"},
+		{pe.IMAGE_FILE_MACHINE_AMD64, "amd64"},
+		{pe.IMAGE_FILE_MACHINE_I386, "386"},
+		{0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := archFromMachine(tt.machine); got != tt.want {
+			t.Errorf("archFromMachine(%#x) = %q, want %q", tt.machine, got, tt.want)
+		}
+	}
+}
+
+func TestArchFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "x64 process on ARM64 host",
+			env:  map[string]string{"PROCESSOR_ARCHITECTURE": "AMD64", "PROCESSOR_ARCHITEW6432": "ARM64"},
+			want: "arm64",
+		},
+		{
+			name: "native ARM64 process",
+			env:  map[string]string{"PROCESSOR_ARCHITECTURE": "ARM64"},
+			want: "arm64",
+		},
+		{
+			name: "native x64 process",
+			env:  map[string]string{"PROCESSOR_ARCHITECTURE": "AMD64"},
+			want: "amd64",
+		},
+		{
+			name: "nothing set",
+			env:  map[string]string{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := archFromEnv(func(key string) string { return tt.env[key] })
+			if got != tt.want {
+				t.Errorf("archFromEnv() = %q, want %q", got, tt.want)
+			}
+			// The decision should prefer the detected native arch over an emulated x64 build
+			if tt.want != "" && selectArch("amd64", got, "") != tt.want {
+				t.Errorf("selectArch() did not prefer native arch %q", tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package manifest
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"testing/fstest"
 )
@@ -143,6 +144,20 @@ func TestEmbeddedSourceFromFS(t *testing.T) {
 			t.Errorf("len(runtimes) = %d, want 2", len(runtimes))
 		}
 	})
+
+	t.Run("Summaries", func(t *testing.T) {
+		summaries, err := source.Summaries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []ManifestSummary{
+			{Runtime: "node", SchemaVersion: 1, Versions: 1},
+			{Runtime: "python", SchemaVersion: 1, Versions: 1},
+		}
+		if !reflect.DeepEqual(summaries, want) {
+			t.Errorf("Summaries() = %+v, want %+v", summaries, want)
+		}
+	})
 }
 
 func TestErrManifestNotFound(t *testing.T) {
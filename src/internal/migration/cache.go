@@ -0,0 +1,113 @@
+package migration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// DetectionCacheTTL is how long cached detection results are used, as long as the
+// version manager's directories haven't changed
+const DetectionCacheTTL = 15 * time.Minute
+
+// SourcePathsProvider is an optional interface for migration providers whose detection
+// results only depend on a few directories or files. Their results are cached until
+// one of these paths changes; providers without it are scanned on every run.
+type SourcePathsProvider interface {
+	// SourcePaths returns the directories and files the detected versions come from,
+	// whether they exist or not
+	SourcePaths() []string
+}
+
+// DetectionCache caches the results of DetectVersions on disk
+type DetectionCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// detectionCacheEntry is the cached detection result of one provider
+type detectionCacheEntry struct {
+	CachedAt time.Time `json:"cached_at"`
+	// Paths maps each source path to its modification time in nanoseconds, zero if
+	// it didn't exist
+	Paths    map[string]int64  `json:"paths"`
+	Versions []DetectedVersion `json:"versions"`
+}
+
+// NewDetectionCache creates a cache of detection results stored in dir
+func NewDetectionCache(dir string, ttl time.Duration) *DetectionCache {
+	return &DetectionCache{dir: dir, ttl: ttl}
+}
+
+// DetectVersions returns the cached versions of a provider if they haven't expired
+// and none of its source paths changed, otherwise it scans again and caches the
+// result. With refresh it always scans.
+func (c *DetectionCache) DetectVersions(provider Provider, refresh bool) ([]DetectedVersion, error) {
+	sourcePaths, ok := provider.(SourcePathsProvider)
+	if !ok {
+		return provider.DetectVersions()
+	}
+
+	paths := pathModTimes(sourcePaths.SourcePaths())
+	cachePath := c.cachePath(provider)
+	if !refresh {
+		if entry, err := loadDetectionCache(cachePath); err == nil &&
+			time.Since(entry.CachedAt) <= c.ttl && reflect.DeepEqual(entry.Paths, paths) {
+			return entry.Versions, nil
+		}
+	}
+
+	versions, err := provider.DetectVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Caching is best-effort
+	_ = saveDetectionCache(cachePath, detectionCacheEntry{CachedAt: time.Now(), Paths: paths, Versions: versions})
+	return versions, nil
+}
+
+// cachePath returns the cache file of a provider
+func (c *DetectionCache) cachePath(provider Provider) string {
+	return filepath.Join(c.dir, provider.Runtime()+"-"+provider.Name()+".json")
+}
+
+// pathModTimes returns the modification time of each path, zero for a missing one
+func pathModTimes(paths []string) map[string]int64 {
+	modTimes := make(map[string]int64, len(paths))
+	for _, path := range paths {
+		var modTime int64
+		if info, err := os.Stat(path); err == nil {
+			modTime = info.ModTime().UnixNano()
+		}
+		modTimes[path] = modTime
+	}
+	return modTimes
+}
+
+func loadDetectionCache(path string) (*detectionCacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry detectionCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func saveDetectionCache(path string, entry detectionCacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,131 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// countingProvider is a migration provider that counts its scans
+type countingProvider struct {
+	dir      string
+	versions []DetectedVersion
+	scans    int
+}
+
+func (p *countingProvider) Name() string                   { return "counting" }
+func (p *countingProvider) DisplayName() string            { return "Counting" }
+func (p *countingProvider) Runtime() string                { return "node" }
+func (p *countingProvider) IsPresent() bool                { return true }
+func (p *countingProvider) CanAutoUninstall() bool         { return false }
+func (p *countingProvider) UninstallCommand(string) string { return "" }
+func (p *countingProvider) ManualInstructions() string     { return "" }
+func (p *countingProvider) SourcePaths() []string          { return []string{p.dir} }
+
+func (p *countingProvider) DetectVersions() ([]DetectedVersion, error) {
+	p.scans++
+	return p.versions, nil
+}
+
+func newCountingProvider(t *testing.T) *countingProvider {
+	t.Helper()
+	return &countingProvider{
+		dir:      t.TempDir(),
+		versions: []DetectedVersion{{Version: "20.11.1", Path: "/nvm/v20.11.1/bin/node", Source: "nvm"}},
+	}
+}
+
+func TestDetectionCache_ReusesResults(t *testing.T) {
+	cache := NewDetectionCache(t.TempDir(), time.Hour)
+	provider := newCountingProvider(t)
+
+	first, err := cache.DetectVersions(provider, false)
+	if err != nil {
+		t.Fatalf("DetectVersions() error: %v", err)
+	}
+
+	// A new cache over the same directory, like a second run, doesn't scan again
+	reopened := NewDetectionCache(cache.dir, time.Hour)
+	second, err := reopened.DetectVersions(provider, false)
+	if err != nil {
+		t.Fatalf("DetectVersions() error: %v", err)
+	}
+
+	if provider.scans != 1 {
+		t.Errorf("scans = %d, want 1", provider.scans)
+	}
+	if !reflect.DeepEqual(second, first) {
+		t.Errorf("cached versions = %+v, want %+v", second, first)
+	}
+
+	if _, err := reopened.DetectVersions(provider, true); err != nil {
+		t.Fatalf("DetectVersions() error: %v", err)
+	}
+	if provider.scans != 2 {
+		t.Errorf("scans after refresh = %d, want 2", provider.scans)
+	}
+}
+
+func TestDetectionCache_InvalidatedOnDirectoryChange(t *testing.T) {
+	cache := NewDetectionCache(t.TempDir(), time.Hour)
+	provider := newCountingProvider(t)
+
+	if _, err := cache.DetectVersions(provider, false); err != nil {
+		t.Fatalf("DetectVersions() error: %v", err)
+	}
+
+	// Installing a version with the version manager changes its directory
+	if err := os.Mkdir(filepath.Join(provider.dir, "v22.0.0"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(provider.dir, future, future); err != nil {
+		t.Fatalf("Failed to touch directory: %v", err)
+	}
+	provider.versions = append(provider.versions, DetectedVersion{Version: "22.0.0", Source: "nvm"})
+
+	versions, err := cache.DetectVersions(provider, false)
+	if err != nil {
+		t.Fatalf("DetectVersions() error: %v", err)
+	}
+	if provider.scans != 2 || len(versions) != 2 {
+		t.Errorf("scans = %d with %d versions, want a new scan finding 2", provider.scans, len(versions))
+	}
+}
+
+func TestDetectionCache_Expires(t *testing.T) {
+	cache := NewDetectionCache(t.TempDir(), -time.Second)
+	provider := newCountingProvider(t)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.DetectVersions(provider, false); err != nil {
+			t.Fatalf("DetectVersions() error: %v", err)
+		}
+	}
+	if provider.scans != 2 {
+		t.Errorf("scans = %d, want 2 with expired results", provider.scans)
+	}
+}
+
+func TestDetectionCache_SkipsProvidersWithoutSourcePaths(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewDetectionCache(dir, time.Hour)
+	provider := newCountingProvider(t)
+
+	// Embedding only the Provider interface hides SourcePaths
+	scanner := struct{ Provider }{provider}
+	for i := 0; i < 2; i++ {
+		if _, err := cache.DetectVersions(scanner, false); err != nil {
+			t.Fatalf("DetectVersions() error: %v", err)
+		}
+	}
+
+	if provider.scans != 2 {
+		t.Errorf("scans = %d, want 2 without source paths", provider.scans)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("cache wrote %d file(s) for a provider without source paths", len(entries))
+	}
+}
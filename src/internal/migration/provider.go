@@ -2,6 +2,11 @@
 // other version managers (nvm, pyenv, rbenv, etc.) to dtvem.
 package migration
 
+import (
+	"os"
+	"strings"
+)
+
 // Provider defines the interface that all migration providers must implement.
 // Each provider handles detection and cleanup for a specific version manager.
 type Provider interface {
@@ -32,12 +37,45 @@ type Provider interface {
 	ManualInstructions() string
 }
 
+// DefaultVersionProvider is an optional interface for migration providers that can
+// report the version manager's default version (e.g. `nvm alias default`).
+// The migrate command pre-selects it as the new global version.
+type DefaultVersionProvider interface {
+	// DefaultVersion returns the version manager's default version, which may be
+	// partial (e.g. "20"). Returns false if no default is configured.
+	DefaultVersion() (string, bool)
+}
+
+// ReadVersionFile reads a version manager's version file (e.g. ~/.pyenv/version)
+// and returns the first version listed, without a "v" prefix. Returns false if the
+// file doesn't exist, is empty, or selects the system installation.
+func ReadVersionFile(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		// pyenv allows several versions on one line; the first one wins
+		version := strings.TrimPrefix(fields[0], "v")
+		if version == "system" {
+			return "", false
+		}
+		return version, true
+	}
+	return "", false
+}
+
 // DetectedVersion represents a runtime version found by a migration provider.
 type DetectedVersion struct {
-	Version   string // Version string (e.g., "22.0.0", "3.11.0")
-	Path      string // Path to the executable
-	Source    string // Source/version manager name (e.g., "nvm", "pyenv")
-	Validated bool   // Whether we've verified this version works
+	Version   string `json:"version"`   // Version string (e.g., "22.0.0", "3.11.0")
+	Path      string `json:"path"`      // Path to the executable
+	Source    string `json:"source"`    // Source/version manager name (e.g., "nvm", "pyenv")
+	Validated bool   `json:"validated"` // Whether we've verified this version works
 }
 
 // String returns a formatted string representation
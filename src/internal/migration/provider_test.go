@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadVersionFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantOK  bool
+	}{
+		{"plain version", "3.12.1\n", "3.12.1", true},
+		{"v prefix", "v20.11.1", "20.11.1", true},
+		{"multiple versions", "3.12.1 3.11.7\n", "3.12.1", true},
+		{"comments and blank lines", "# managed by pyenv\n\n3.11.7\n", "3.11.7", true},
+		{"system", "system\n", "", false},
+		{"empty", "\n", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "version")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write version file: %v", err)
+			}
+
+			got, ok := ReadVersionFile(path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ReadVersionFile() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := ReadVersionFile(filepath.Join(t.TempDir(), "missing")); ok {
+		t.Error("ReadVersionFile() for a missing file should return false")
+	}
+}
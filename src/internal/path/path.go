@@ -8,6 +8,16 @@ import (
 	"strings"
 )
 
+// RepairOptions controls how RepairPath puts the shims directory first in PATH
+type RepairOptions struct {
+	// SkipConfirmation makes the change without prompting
+	SkipConfirmation bool
+	// PrintOnly prints the change to make instead of making it
+	PrintOnly bool
+	// UserScope edits the user PATH instead of the System PATH (Windows only)
+	UserScope bool
+}
+
 // IsInPath checks if a directory is in the system PATH
 func IsInPath(dir string) bool {
 	pathEnv := os.Getenv("PATH")
@@ -34,6 +44,52 @@ func IsInPath(dir string) bool {
 	return false
 }
 
+// PathIndex returns the position of dir among the directories of the PATH
+// environment variable, or -1 if it is not in PATH
+func PathIndex(dir string) int {
+	return pathEntryIndex(os.Getenv("PATH"), dir, string(os.PathListSeparator), runtime.GOOS == "windows")
+}
+
+// pathEntryIndex returns the position of dir among the entries of pathValue, or -1.
+// Empty entries are skipped and foldCase compares the entries ignoring case.
+func pathEntryIndex(pathValue, dir, separator string, foldCase bool) int {
+	index := 0
+	for _, entry := range strings.Split(pathValue, separator) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if samePathEntry(entry, dir, foldCase) {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// prependPathEntry returns pathValue with dir as its first entry, dropping any
+// other occurrence of dir and any empty entries
+func prependPathEntry(pathValue, dir, separator string, foldCase bool) string {
+	entries := []string{dir}
+	for _, entry := range strings.Split(pathValue, separator) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || samePathEntry(entry, dir, foldCase) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, separator)
+}
+
+// samePathEntry compares two PATH entries, ignoring trailing separators
+func samePathEntry(a, b string, foldCase bool) bool {
+	a, b = strings.TrimRight(a, `/\`), strings.TrimRight(b, `/\`)
+	if foldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
 // ShimsDir returns the path to the shims directory
 // This replicates the root directory logic from config package to avoid circular dependencies.
 // Must stay in sync with config.getRootDir().
@@ -65,43 +121,102 @@ func ShimsDir() string {
 // This prevents detecting our own shims as "system" installations during migration detection.
 // Returns the full path to the executable, or empty string if not found.
 func LookPathExcludingShims(execName string) string {
-	// Get the shims directory to exclude it from search
-	shimsDir := ShimsDir()
-
-	// Get PATH environment variable
-	pathEnv := os.Getenv("PATH")
-	if pathEnv == "" {
-		return ""
+	// Search each directory
+	for _, dir := range PathDirsExcludingShims() {
+		// Try to find the executable in this directory
+		candidatePath := findExecutableInDir(dir, execName)
+		if candidatePath != "" {
+			return candidatePath
+		}
 	}
 
-	// Split PATH into directories
-	pathDirs := filepath.SplitList(pathEnv)
+	return ""
+}
 
-	// Search each directory
-	for _, dir := range pathDirs {
+// PathDirsExcludingShims returns the directories of PATH in order, without dtvem's
+// shims directory
+func PathDirsExcludingShims() []string {
+	// Get the shims directory to exclude it from search
+	shimsDir := ShimsDir()
+
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
 		// Skip the dtvem shims directory (case-insensitive on Windows)
 		if strings.EqualFold(dir, shimsDir) {
 			continue
 		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
 
-		// Try to find the executable in this directory
-		candidatePath := findExecutableInDir(dir, execName)
-		if candidatePath != "" {
-			return candidatePath
+// ShadowingExecutable returns the first executable named execName in the directories
+// of pathEnv that come before shimsDir, i.e. the one that runs instead of the dtvem
+// shim. It returns an empty string when the shim is found first. When shimsDir is not
+// in pathEnv at all, every directory counts as coming before it.
+func ShadowingExecutable(pathEnv, shimsDir, execName string) string {
+	shimsDir = filepath.Clean(shimsDir)
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		if samePath(filepath.Clean(dir), shimsDir) {
+			return ""
+		}
+		if candidate := findExecutableInDir(dir, execName); candidate != "" {
+			return candidate
 		}
 	}
 
 	return ""
 }
 
+// ExecutableDirs returns the directories of pathEnv, in PATH order and without
+// duplicates, that contain an executable with any of the given names
+func ExecutableDirs(pathEnv string, execNames ...string) []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		dir = filepath.Clean(dir)
+
+		seen := false
+		for _, d := range dirs {
+			if samePath(d, dir) {
+				seen = true
+				break
+			}
+		}
+		if seen {
+			continue
+		}
+
+		for _, name := range execNames {
+			if findExecutableInDir(dir, name) != "" {
+				dirs = append(dirs, dir)
+				break
+			}
+		}
+	}
+	return dirs
+}
+
+// samePath compares two cleaned paths, ignoring case on Windows
+func samePath(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
 // findExecutableInDir looks for an executable with the given name in a directory.
-// On Windows, it tries .exe, .cmd, .bat extensions.
+// On Windows, it tries the extensions in PATHEXT (see executableExtensions).
 // On Unix, it checks if the file exists and has execute permission.
 func findExecutableInDir(dir, execName string) string {
 	if runtime.GOOS == "windows" {
-		// Windows: try .exe, .cmd, .bat extensions
-		for _, ext := range []string{".exe", ".cmd", ".bat"} {
-			candidate := filepath.Join(dir, execName+ext)
+		for _, candidate := range windowsCandidates(dir, execName, executableExtensions(os.Getenv("PATHEXT"))) {
 			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
 				return candidate
 			}
@@ -118,3 +233,46 @@ func findExecutableInDir(dir, execName string) string {
 	}
 	return ""
 }
+
+// defaultExecutableExtensions are the extensions tried on Windows when PATHEXT is empty
+var defaultExecutableExtensions = []string{".exe", ".cmd", ".bat"}
+
+// executableExtensions returns the extensions Windows runs without being typed, from a
+// PATHEXT value such as ".COM;.EXE;.BAT;.CMD;.PS1". They are lowercased (Windows
+// compares them case-insensitively) and kept in order without duplicates. An empty
+// PATHEXT gives defaultExecutableExtensions.
+func executableExtensions(pathext string) []string {
+	var extensions []string
+	seen := make(map[string]bool)
+	for _, ext := range strings.Split(pathext, ";") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" || seen[ext] {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		seen[ext] = true
+		extensions = append(extensions, ext)
+	}
+	if len(extensions) == 0 {
+		return defaultExecutableExtensions
+	}
+	return extensions
+}
+
+// windowsCandidates returns the paths to try for execName in dir, in order. A name
+// that already ends in one of the extensions (e.g. "yarn.ps1") is tried as given first.
+func windowsCandidates(dir, execName string, extensions []string) []string {
+	var candidates []string
+	for _, ext := range extensions {
+		if strings.EqualFold(filepath.Ext(execName), ext) {
+			candidates = append(candidates, filepath.Join(dir, execName))
+			break
+		}
+	}
+	for _, ext := range extensions {
+		candidates = append(candidates, filepath.Join(dir, execName+ext))
+	}
+	return candidates
+}
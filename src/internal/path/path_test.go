@@ -3,6 +3,7 @@ package path
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -295,3 +296,221 @@ func TestFindExecutableInDir(t *testing.T) {
 		}
 	})
 }
+
+// writeTestExecutable creates an executable named name in dir and returns its path
+func writeTestExecutable(t *testing.T, dir, name string) string {
+	t.Helper()
+	if runtime.GOOS == constants.OSWindows {
+		name += ".exe"
+	}
+	execPath := filepath.Join(dir, name)
+	if err := os.WriteFile(execPath, []byte("exec"), 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", execPath, err)
+	}
+	return execPath
+}
+
+func TestShadowingExecutable(t *testing.T) {
+	tempDir := t.TempDir()
+	shimsDir := filepath.Join(tempDir, "shims")
+	systemDir := filepath.Join(tempDir, "usr", "bin")
+	emptyDir := filepath.Join(tempDir, "empty")
+	for _, dir := range []string{shimsDir, systemDir, emptyDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	writeTestExecutable(t, shimsDir, "python")
+	systemPython := writeTestExecutable(t, systemDir, "python")
+
+	pathOf := func(dirs ...string) string {
+		return strings.Join(dirs, string(os.PathListSeparator))
+	}
+
+	tests := []struct {
+		name    string
+		pathEnv string
+		want    string
+	}{
+		{"shims first", pathOf(shimsDir, systemDir), ""},
+		{"system dir first", pathOf(systemDir, shimsDir), systemPython},
+		{"unrelated dir first", pathOf(emptyDir, shimsDir, systemDir), ""},
+		{"shims dir with trailing separator", pathOf(shimsDir+string(filepath.Separator), systemDir), ""},
+		{"shims not in PATH", pathOf(emptyDir, systemDir), systemPython},
+		{"empty entries ignored", pathOf("", shimsDir, systemDir), ""},
+		{"not installed anywhere else", pathOf(emptyDir, shimsDir), ""},
+		{"empty PATH", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShadowingExecutable(tt.pathEnv, shimsDir, "python"); got != tt.want {
+				t.Errorf("ShadowingExecutable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathEntryIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathValue string
+		dir       string
+		sep       string
+		foldCase  bool
+		want      int
+	}{
+		{"first", `C:\dtvem\shims;C:\Windows`, `C:\dtvem\shims`, ";", true, 0},
+		{"later", `C:\Python312;C:\dtvem\shims`, `C:\dtvem\shims`, ";", true, 1},
+		{"case differs", `C:\Python312;c:\DTVEM\shims`, `C:\dtvem\shims`, ";", true, 1},
+		{"case differs on unix", "/usr/bin:/Home/shims", "/home/shims", ":", false, -1},
+		{"trailing separator", `C:\dtvem\shims\;C:\Windows`, `C:\dtvem\shims`, ";", true, 0},
+		{"empty entries skipped", ";;C:\\Windows;C:\\dtvem\\shims", `C:\dtvem\shims`, ";", true, 1},
+		{"missing", "/usr/bin:/bin", "/home/shims", ":", false, -1},
+		{"empty path", "", "/home/shims", ":", false, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathEntryIndex(tt.pathValue, tt.dir, tt.sep, tt.foldCase); got != tt.want {
+				t.Errorf("pathEntryIndex(%q, %q) = %d, want %d", tt.pathValue, tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrependPathEntry(t *testing.T) {
+	shims := `C:\Users\me\.dtvem\shims`
+
+	tests := []struct {
+		name      string
+		pathValue string
+		want      string
+	}{
+		{"add to empty", "", shims},
+		{"add", `C:\Windows;C:\Python312`, shims + `;C:\Windows;C:\Python312`},
+		{"move to front", `C:\Windows;` + shims + `;C:\Python312`, shims + `;C:\Windows;C:\Python312`},
+		{"already first", shims + `;C:\Windows`, shims + `;C:\Windows`},
+		{"drops duplicates in any case", `C:\USERS\ME\.DTVEM\SHIMS;C:\Windows;` + shims, shims + `;C:\Windows`},
+		{"drops empty entries and spaces", ` C:\Windows ;;C:\Python312;`, shims + `;C:\Windows;C:\Python312`},
+		{"keeps unexpanded variables", `%SystemRoot%\system32;%USERPROFILE%\bin`, shims + `;%SystemRoot%\system32;%USERPROFILE%\bin`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := prependPathEntry(tt.pathValue, shims, ";", true)
+			if got != tt.want {
+				t.Errorf("prependPathEntry(%q) = %q, want %q", tt.pathValue, got, tt.want)
+			}
+			if again := prependPathEntry(got, shims, ";", true); again != got {
+				t.Errorf("prependPathEntry() is not idempotent: %q then %q", got, again)
+			}
+		})
+	}
+}
+
+func TestExecutableDirs(t *testing.T) {
+	tempDir := t.TempDir()
+	manualDir := filepath.Join(tempDir, "src", "dtvem")
+	brewDir := filepath.Join(tempDir, "brew", "bin")
+	shimOnlyDir := filepath.Join(tempDir, "shim-only")
+	emptyDir := filepath.Join(tempDir, "empty")
+	for _, dir := range []string{manualDir, brewDir, shimOnlyDir, emptyDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	writeTestExecutable(t, manualDir, "dtvem")
+	writeTestExecutable(t, manualDir, "dtvem-shim")
+	writeTestExecutable(t, brewDir, "dtvem")
+	writeTestExecutable(t, shimOnlyDir, "dtvem-shim")
+
+	pathOf := func(dirs ...string) string {
+		return strings.Join(dirs, string(os.PathListSeparator))
+	}
+
+	tests := []struct {
+		name    string
+		pathEnv string
+		want    []string
+	}{
+		{"single installation", pathOf(emptyDir, manualDir), []string{manualDir}},
+		{"two installations in PATH order", pathOf(brewDir, emptyDir, manualDir), []string{brewDir, manualDir}},
+		{"shim executable alone counts", pathOf(manualDir, shimOnlyDir), []string{manualDir, shimOnlyDir}},
+		{"same directory twice", pathOf(manualDir, manualDir+string(filepath.Separator)), []string{manualDir}},
+		{"none", pathOf(emptyDir, ""), nil},
+		{"empty PATH", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExecutableDirs(tt.pathEnv, "dtvem", "dtvem-shim")
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("ExecutableDirs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutableExtensions(t *testing.T) {
+	tests := []struct {
+		name    string
+		pathext string
+		want    []string
+	}{
+		{"empty uses defaults", "", []string{".exe", ".cmd", ".bat"}},
+		{"lowercased in order", ".COM;.EXE;.BAT;.CMD;.PS1", []string{".com", ".exe", ".bat", ".cmd", ".ps1"}},
+		{"blank entries and duplicates skipped", ".EXE;;.exe; .Ps1 ;", []string{".exe", ".ps1"}},
+		{"missing dot added", "EXE;CMD", []string{".exe", ".cmd"}},
+		{"only separators uses defaults", ";;", []string{".exe", ".cmd", ".bat"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := executableExtensions(tt.pathext); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("executableExtensions(%q) = %v, want %v", tt.pathext, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsCandidates(t *testing.T) {
+	extensions := []string{".exe", ".ps1"}
+
+	got := windowsCandidates("dir", "yarn", extensions)
+	want := []string{filepath.Join("dir", "yarn.exe"), filepath.Join("dir", "yarn.ps1")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("windowsCandidates(yarn) = %v, want %v", got, want)
+	}
+
+	// A name with a known extension is tried as given first (case-insensitively)
+	got = windowsCandidates("dir", "yarn.PS1", extensions)
+	want = []string{filepath.Join("dir", "yarn.PS1"), filepath.Join("dir", "yarn.PS1.exe"), filepath.Join("dir", "yarn.PS1.ps1")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("windowsCandidates(yarn.PS1) = %v, want %v", got, want)
+	}
+}
+
+func TestFindExecutableInDir_PathExt(t *testing.T) {
+	if runtime.GOOS != constants.OSWindows {
+		t.Skip("PATHEXT only applies on Windows")
+	}
+
+	tempDir := t.TempDir()
+	scriptPath := filepath.Join(tempDir, "yarn.ps1")
+	if err := os.WriteFile(scriptPath, []byte("exit 0"), 0644); err != nil {
+		t.Fatalf("Failed to create script: %v", err)
+	}
+
+	t.Setenv("PATHEXT", ".COM;.EXE;.BAT;.CMD")
+	if result := findExecutableInDir(tempDir, "yarn"); result != "" {
+		t.Errorf("findExecutableInDir() without .PS1 in PATHEXT = %q, want empty", result)
+	}
+
+	t.Setenv("PATHEXT", ".COM;.EXE;.BAT;.CMD;.PS1")
+	if result := findExecutableInDir(tempDir, "yarn"); !strings.EqualFold(result, scriptPath) {
+		t.Errorf("findExecutableInDir() with .PS1 in PATHEXT = %q, want %q", result, scriptPath)
+	}
+}
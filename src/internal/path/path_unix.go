@@ -3,7 +3,6 @@
 package path
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -55,7 +54,26 @@ func GetShellConfigFile(shell string) string {
 // AddToPath adds the shims directory to the user's PATH by modifying their shell config.
 // If skipConfirmation is true, the function will proceed without prompting the user.
 func AddToPath(shimsDir string, skipConfirmation bool) error {
+	return RepairPath(shimsDir, RepairOptions{SkipConfirmation: skipConfirmation})
+}
+
+// RepairPath makes the shims directory the first entry of PATH by making the dtvem
+// line the last PATH change in the user's shell config. Running it again doesn't
+// duplicate the line. With PrintOnly it prints the line instead.
+func RepairPath(shimsDir string, opts RepairOptions) error {
 	shell := DetectShell()
+	pathLine := ShellPathLine(shell, shimsDir)
+
+	if opts.PrintOnly {
+		fmt.Println(pathLine)
+		return nil
+	}
+
+	if PathIndex(shimsDir) == 0 {
+		ui.Success("%s is already at the beginning of your PATH", shimsDir)
+		return nil
+	}
+
 	if shell == "unknown" {
 		return fmt.Errorf("could not detect shell - please add %s to your PATH manually", shimsDir)
 	}
@@ -65,34 +83,33 @@ func AddToPath(shimsDir string, skipConfirmation bool) error {
 		return fmt.Errorf("could not determine config file for shell %s", shell)
 	}
 
-	// Check if the directory is already in PATH
-	if IsInPath(shimsDir) {
-		ui.Info("%s is already in your PATH", shimsDir)
-		return nil
+	content, err := os.ReadFile(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Check if the config file already contains the PATH modification
-	if containsPathModification(configFile, shimsDir) {
+	updated, changed := EnsurePathLine(string(content), shell, shimsDir)
+	if !changed {
 		ui.Warning("PATH modification already exists in %s, but not active in current shell", configFile)
 		ui.Info("Please restart your terminal or run: source %s", configFile)
 		return nil
 	}
 
-	// Prepare the export statement
-	exportLine := ""
-	if shell == constants.ShellFish {
-		exportLine = fmt.Sprintf("\n# Added by dtvem\nset -gx PATH \"%s\" $PATH\n", shimsDir)
-	} else {
-		exportLine = fmt.Sprintf("\n# Added by dtvem\nexport PATH=\"%s:$PATH\"\n", shimsDir)
-	}
+	inPath := IsInPath(shimsDir)
 
 	// Prompt user for confirmation (unless skipConfirmation is true)
-	if !skipConfirmation {
-		ui.Header("PATH Setup Required")
-		ui.Info("dtvem needs to add the shims directory to your PATH")
+	if !opts.SkipConfirmation {
+		if inPath {
+			ui.Header("PATH Fix Required")
+			ui.Warning("%s is in your PATH but not at the beginning", shimsDir)
+			ui.Info("It needs to be first to take priority over other installations")
+		} else {
+			ui.Header("PATH Setup Required")
+			ui.Info("dtvem needs to add the shims directory to your PATH")
+		}
 		ui.Info("Shell: %s", ui.Highlight(shell))
 		ui.Info("Config file: %s", ui.Highlight(configFile))
-		ui.Info("Will append: %s", ui.Highlight(strings.TrimSpace(exportLine)))
+		ui.Info("Will add as the last PATH change: %s", ui.Highlight(pathLine))
 		fmt.Printf("\nProceed? [Y/n]: ")
 
 		var response string
@@ -100,8 +117,8 @@ func AddToPath(shimsDir string, skipConfirmation bool) error {
 		response = strings.ToLower(strings.TrimSpace(response))
 
 		if response != "" && response != constants.ResponseY && response != constants.ResponseYes {
-			ui.Warning("PATH not modified. Please add this manually to your %s:", configFile)
-			ui.Info("%s", strings.TrimSpace(exportLine))
+			ui.Warning("PATH not modified. Please add this as the last PATH change in your %s:", configFile)
+			ui.Info("%s", pathLine)
 			return nil
 		}
 	}
@@ -114,39 +131,20 @@ func AddToPath(shimsDir string, skipConfirmation bool) error {
 		}
 	}
 
-	// Append to the config file
-	f, err := os.OpenFile(configFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open config file: %w", err)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(configFile); err == nil {
+		mode = info.Mode().Perm()
 	}
-	defer func() { _ = f.Close() }()
-
-	if _, err := f.WriteString(exportLine); err != nil {
+	if err := os.WriteFile(configFile, []byte(updated), mode); err != nil {
 		return fmt.Errorf("failed to write to config file: %w", err)
 	}
 
-	ui.Success("Added %s to PATH in %s", shimsDir, configFile)
+	if inPath {
+		ui.Success("Moved %s to the beginning of PATH in %s", shimsDir, configFile)
+	} else {
+		ui.Success("Added %s to PATH in %s", shimsDir, configFile)
+	}
 	ui.Warning("Please restart your terminal or run: source %s", configFile)
 
 	return nil
 }
-
-// containsPathModification checks if the config file already has dtvem PATH modification
-func containsPathModification(configFile, shimsDir string) bool {
-	f, err := os.Open(configFile)
-	if err != nil {
-		return false
-	}
-	defer func() { _ = f.Close() }()
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		// Check if line mentions both dtvem/shims and PATH
-		if strings.Contains(line, shimsDir) && (strings.Contains(line, "PATH") || strings.Contains(line, "path")) {
-			return true
-		}
-	}
-
-	return false
-}
@@ -35,6 +35,25 @@ const (
 // This requires administrator privileges. If not elevated, it will prompt
 // the user to re-run with elevation (unless skipConfirmation is true).
 func AddToPath(shimsDir string, skipConfirmation bool) error {
+	return RepairPath(shimsDir, RepairOptions{SkipConfirmation: skipConfirmation})
+}
+
+// RepairPath makes the shims directory the first entry of the System PATH, or of
+// the user PATH with UserScope. With PrintOnly it prints how to make the change.
+func RepairPath(shimsDir string, opts RepairOptions) error {
+	if opts.PrintOnly {
+		scope := "System"
+		if opts.UserScope {
+			scope = "user"
+		}
+		fmt.Printf("Move or add %s as the first entry of your %s PATH (System Properties > Environment Variables)\n", shimsDir, scope)
+		return nil
+	}
+
+	if opts.UserScope {
+		return repairUserPath(shimsDir, opts.SkipConfirmation)
+	}
+
 	// Check current System PATH status
 	needsUpdate, action, err := checkSystemPath(shimsDir)
 	if err != nil {
@@ -48,7 +67,7 @@ func AddToPath(shimsDir string, skipConfirmation bool) error {
 
 	// Check if we have admin privileges
 	if !isAdmin() {
-		return promptForElevation(shimsDir, action, skipConfirmation)
+		return promptForElevation(shimsDir, action, opts.SkipConfirmation)
 	}
 
 	// We have admin privileges - proceed with modification
@@ -69,17 +88,7 @@ func checkSystemPath(shimsDir string) (bool, string, error) {
 		return false, "", fmt.Errorf("failed to read System PATH: %w", err)
 	}
 
-	paths := strings.Split(currentPath, ";")
-	foundAt := -1
-
-	for i, p := range paths {
-		trimmed := strings.TrimSpace(p)
-		if strings.EqualFold(trimmed, shimsDir) {
-			foundAt = i
-			break
-		}
-	}
-
+	foundAt := pathEntryIndex(currentPath, shimsDir, ";", true)
 	if foundAt == 0 {
 		return false, "", nil // Already at beginning
 	} else if foundAt > 0 {
@@ -171,27 +180,8 @@ func modifySystemPath(shimsDir, action string) error {
 		return fmt.Errorf("failed to read System PATH: %w", err)
 	}
 
-	// Parse and filter current PATH entries
-	paths := strings.Split(currentPath, ";")
-	var filteredPaths []string
-
-	for _, p := range paths {
-		trimmed := strings.TrimSpace(p)
-		if trimmed == "" {
-			continue
-		}
-		// Skip if it's the shims dir (we'll prepend it)
-		if strings.EqualFold(trimmed, shimsDir) {
-			continue
-		}
-		filteredPaths = append(filteredPaths, trimmed)
-	}
-
 	// Build new PATH with shimsDir at the beginning
-	newPath := shimsDir
-	if len(filteredPaths) > 0 {
-		newPath += ";" + strings.Join(filteredPaths, ";")
-	}
+	newPath := prependPathEntry(currentPath, shimsDir, ";", true)
 
 	// Write back to registry
 	err = key.SetStringValue("Path", newPath)
@@ -212,6 +202,71 @@ func modifySystemPath(shimsDir, action string) error {
 	return nil
 }
 
+// repairUserPath makes the shims directory the first entry of the user PATH, which
+// doesn't need administrator privileges
+func repairUserPath(shimsDir string, skipConfirmation bool) error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Environment`, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("failed to open user PATH registry key: %w", err)
+	}
+	defer func() { _ = key.Close() }()
+
+	currentPath, valueType, err := key.GetStringValue("Path")
+	if err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return fmt.Errorf("failed to read user PATH: %w", err)
+	}
+
+	foundAt := pathEntryIndex(currentPath, shimsDir, ";", true)
+	if foundAt == 0 {
+		ui.Success("%s is already at the beginning of your user PATH", shimsDir)
+		return nil
+	}
+
+	if !skipConfirmation {
+		if foundAt > 0 {
+			ui.Header("PATH Fix Required")
+			ui.Warning("%s is in your user PATH but not at the beginning", shimsDir)
+		} else {
+			ui.Header("PATH Setup Required")
+			ui.Info("dtvem needs to add the shims directory to your user PATH")
+		}
+		ui.Info("Directory: %s", ui.Highlight(shimsDir))
+		fmt.Printf("\nProceed? [Y/n]: ")
+
+		var response string
+		_, _ = fmt.Scanln(&response)
+		response = strings.ToLower(strings.TrimSpace(response))
+
+		if response != "" && response != constants.ResponseY && response != constants.ResponseYes {
+			ui.Warning("PATH not modified. You can run 'dtvem repair-path --user' again later.")
+			return nil
+		}
+	}
+
+	// Keep the value's type so entries like %USERPROFILE% still expand
+	newPath := prependPathEntry(currentPath, shimsDir, ";", true)
+	if valueType == registry.EXPAND_SZ {
+		err = key.SetExpandStringValue("Path", newPath)
+	} else {
+		err = key.SetStringValue("Path", newPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update user PATH in registry: %w", err)
+	}
+
+	broadcastSettingChange()
+
+	if foundAt > 0 {
+		ui.Success("Moved %s to the beginning of your user PATH", shimsDir)
+	} else {
+		ui.Success("Added %s to your user PATH", shimsDir)
+	}
+	ui.Warning("System PATH entries still come before the user PATH; run 'dtvem repair-path' without --user if a system install takes priority")
+	ui.Warning("Please restart your terminal for the changes to take effect")
+
+	return nil
+}
+
 // broadcastSettingChange broadcasts WM_SETTINGCHANGE to notify the system of environment changes
 func broadcastSettingChange() {
 	env := syscall.StringToUTF16Ptr("Environment")
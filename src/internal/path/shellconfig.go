@@ -0,0 +1,76 @@
+package path
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+// shellConfigComment marks the PATH line dtvem writes to a shell config file
+const shellConfigComment = "# Added by dtvem"
+
+// pathVarPattern matches PATH as a whole word, so MANPATH or PYTHONPATH don't count
+var pathVarPattern = regexp.MustCompile(`(^|[^A-Za-z0-9_])PATH([^A-Za-z0-9_]|$)`)
+
+// ShellPathLine returns the shell config line that puts shimsDir first in PATH
+func ShellPathLine(shell, shimsDir string) string {
+	if shell == constants.ShellFish {
+		return fmt.Sprintf("set -gx PATH \"%s\" $PATH", shimsDir)
+	}
+	return fmt.Sprintf("export PATH=\"%s:$PATH\"", shimsDir)
+}
+
+// EnsurePathLine returns the shell config content with the dtvem PATH line as its
+// last PATH change, so the shims directory ends up first in PATH. Content that
+// already ends its PATH changes with the line is returned unchanged; otherwise the
+// existing dtvem lines are removed and the line is appended once.
+func EnsurePathLine(content, shell, shimsDir string) (string, bool) {
+	lines := strings.Split(content, "\n")
+
+	lastPathChange, dtvemLines := -1, 0
+	for i, line := range lines {
+		if isShimsPathLine(line, shimsDir) {
+			dtvemLines++
+		}
+		if isPathChange(line) {
+			lastPathChange = i
+		}
+	}
+	if dtvemLines == 1 && lastPathChange >= 0 && isShimsPathLine(lines[lastPathChange], shimsDir) {
+		return content, false
+	}
+
+	var kept []string
+	for i, line := range lines {
+		if isShimsPathLine(line, shimsDir) {
+			continue
+		}
+		if strings.TrimSpace(line) == shellConfigComment && i+1 < len(lines) && isShimsPathLine(lines[i+1], shimsDir) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	updated := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if updated != "" {
+		updated += "\n\n"
+	}
+	updated += shellConfigComment + "\n" + ShellPathLine(shell, shimsDir) + "\n"
+	return updated, true
+}
+
+// isShimsPathLine reports whether a config line changes PATH to include shimsDir
+func isShimsPathLine(line, shimsDir string) bool {
+	return isPathChange(line) && strings.Contains(line, shimsDir)
+}
+
+// isPathChange reports whether a config line (other than a comment) changes PATH
+func isPathChange(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return false
+	}
+	return pathVarPattern.MatchString(trimmed) || strings.HasPrefix(trimmed, "fish_add_path")
+}
@@ -0,0 +1,101 @@
+package path
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+func TestShellPathLine(t *testing.T) {
+	if got := ShellPathLine("bash", "/home/me/shims"); got != `export PATH="/home/me/shims:$PATH"` {
+		t.Errorf("ShellPathLine(bash) = %q", got)
+	}
+	if got := ShellPathLine(constants.ShellFish, "/home/me/shims"); got != `set -gx PATH "/home/me/shims" $PATH` {
+		t.Errorf("ShellPathLine(fish) = %q", got)
+	}
+}
+
+func TestEnsurePathLine(t *testing.T) {
+	shims := "/home/me/.local/share/dtvem/shims"
+	block := "# Added by dtvem\nexport PATH=\"" + shims + ":$PATH\"\n"
+
+	tests := []struct {
+		name        string
+		content     string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "empty file",
+			content:     "",
+			want:        block,
+			wantChanged: true,
+		},
+		{
+			name:        "appends after existing content",
+			content:     "alias ll='ls -l'\n",
+			want:        "alias ll='ls -l'\n\n" + block,
+			wantChanged: true,
+		},
+		{
+			name:        "already the last PATH change",
+			content:     "export PATH=\"$HOME/bin:$PATH\"\n\n" + block + "alias ll='ls -l'\n",
+			want:        "export PATH=\"$HOME/bin:$PATH\"\n\n" + block + "alias ll='ls -l'\n",
+			wantChanged: false,
+		},
+		{
+			name:        "other variables ending in PATH don't count",
+			content:     block + "export MANPATH=\"/opt/man:$MANPATH\"\nexport PYTHONPATH=/src\n",
+			want:        block + "export MANPATH=\"/opt/man:$MANPATH\"\nexport PYTHONPATH=/src\n",
+			wantChanged: false,
+		},
+		{
+			name:        "moves the line after a later PATH change",
+			content:     block + "export PATH=\"$HOME/.pyenv/bin:$PATH\"\n",
+			want:        "export PATH=\"$HOME/.pyenv/bin:$PATH\"\n\n" + block,
+			wantChanged: true,
+		},
+		{
+			name:        "removes duplicate lines",
+			content:     block + "\n" + block,
+			want:        block,
+			wantChanged: true,
+		},
+		{
+			name:        "commented out lines are ignored",
+			content:     block + "# export PATH=\"/opt/old:$PATH\"\n",
+			want:        block + "# export PATH=\"/opt/old:$PATH\"\n",
+			wantChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := EnsurePathLine(tt.content, "bash", shims)
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("EnsurePathLine() = (%q, %v), want (%q, %v)", got, changed, tt.want, tt.wantChanged)
+			}
+
+			// A second run must leave the result alone
+			again, changedAgain := EnsurePathLine(got, "bash", shims)
+			if changedAgain || again != got {
+				t.Errorf("EnsurePathLine() not idempotent: second run = (%q, %v)", again, changedAgain)
+			}
+			if n := strings.Count(again, shims); n != 1 {
+				t.Errorf("config mentions the shims directory %d times, want 1", n)
+			}
+		})
+	}
+}
+
+func TestEnsurePathLine_Fish(t *testing.T) {
+	shims := "/home/me/.local/share/dtvem/shims"
+	content := "set -gx PATH \"" + shims + "\" $PATH\nfish_add_path /opt/homebrew/bin\n"
+
+	got, changed := EnsurePathLine(content, constants.ShellFish, shims)
+	want := "fish_add_path /opt/homebrew/bin\n\n# Added by dtvem\nset -gx PATH \"" + shims + "\" $PATH\n"
+	if !changed || got != want {
+		t.Errorf("EnsurePathLine(fish) = (%q, %v), want (%q, true)", got, changed, want)
+	}
+}
@@ -0,0 +1,42 @@
+package runtime
+
+import "fmt"
+
+// RuntimeNotFoundError is returned when no provider is registered for a runtime name
+type RuntimeNotFoundError struct {
+	Runtime string
+}
+
+func (e *RuntimeNotFoundError) Error() string {
+	return fmt.Sprintf("runtime provider '%s' not found", e.Runtime)
+}
+
+// VersionNotAvailableError is returned when a runtime has no download of a version
+// for a platform
+type VersionNotAvailableError struct {
+	// Runtime is the provider name (e.g. "node") and DisplayName its display name
+	Runtime     string
+	DisplayName string
+	Version     string
+	Platform    string
+	// Hint is appended to the message, e.g. to suggest running under emulation
+	Hint string
+}
+
+func (e *VersionNotAvailableError) Error() string {
+	return fmt.Sprintf("%s %s is not available for %s%s", e.DisplayName, e.Version, e.Platform, e.Hint)
+}
+
+// VersionNotSupportedError is returned when a version is older than the oldest
+// version a runtime's provider can install
+type VersionNotSupportedError struct {
+	// Runtime is the provider name (e.g. "python") and DisplayName its display name
+	Runtime     string
+	DisplayName string
+	Version     string
+	MinVersion  string
+}
+
+func (e *VersionNotSupportedError) Error() string {
+	return fmt.Sprintf("%s %s cannot be installed with dtvem; the oldest supported version is %s", e.DisplayName, e.Version, e.MinVersion)
+}
@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// PackageListTimeout bounds how long listing an installation's global packages may take
+	PackageListTimeout = 2 * time.Minute
+	// PackageInstallTimeout bounds how long reinstalling global packages may take
+	PackageInstallTimeout = 30 * time.Minute
+)
+
+// packageCommandWaitDelay is how long a killed package manager's children may keep
+// its output open before RunPackageCommand stops waiting for them
+var packageCommandWaitDelay = 5 * time.Second
+
+// ContextPackageProvider is an optional interface for providers whose package manager
+// commands can be bounded or cancelled with a context. Their GlobalPackages and
+// InstallGlobalPackages run these with PackageListTimeout and PackageInstallTimeout.
+type ContextPackageProvider interface {
+	// GlobalPackagesContext is GlobalPackages bounded by ctx. When ctx ends first it
+	// returns the packages found so far with an error wrapping ctx.Err().
+	GlobalPackagesContext(ctx context.Context, installPath string) ([]string, error)
+	// InstallGlobalPackagesContext is InstallGlobalPackages bounded by ctx, showing the
+	// package manager's output as it runs
+	InstallGlobalPackagesContext(ctx context.Context, version string, packages []string) error
+}
+
+// RunPackageCommand runs a package manager command until it exits or ctx ends, and
+// returns its stdout. When stream is not nil, stdout and stderr are also written to
+// it as the command runs (both at once, so stream must be safe for concurrent use);
+// otherwise a failure's error includes stderr. When ctx ends
+// first the command is killed, and the output so far is returned with an error
+// wrapping ctx.Err().
+func RunPackageCommand(ctx context.Context, stream io.Writer, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = packageCommandWaitDelay
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if stream != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, stream)
+		cmd.Stderr = io.MultiWriter(&stderr, stream)
+	}
+
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return stdout.Bytes(), fmt.Errorf("%s did not finish: %w", filepath.Base(name), ctxErr)
+	}
+	if err != nil && stream == nil && stderr.Len() > 0 {
+		err = fmt.Errorf("%w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), err
+}
@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+// writeFakeCommand writes a shell script standing in for a package manager
+func writeFakeCommand(t *testing.T, script string) string {
+	t.Helper()
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("fake package manager commands are shell scripts")
+	}
+	path := filepath.Join(t.TempDir(), "fakepm")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake command: %v", err)
+	}
+	return path
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent writes
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunPackageCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		stream     bool
+		wantOutput string
+		wantErr    string
+		wantStream []string
+	}{
+		{
+			name:       "success",
+			script:     "echo left-pad",
+			wantOutput: "left-pad\n",
+		},
+		{
+			name:    "failure includes stderr",
+			script:  "echo 'ERR! 404 Not Found' >&2; exit 1",
+			wantErr: "ERR! 404 Not Found",
+		},
+		{
+			name:       "streams stdout and stderr",
+			script:     "echo added 1 package; echo 'npm WARN deprecated' >&2",
+			stream:     true,
+			wantOutput: "added 1 package\n",
+			wantStream: []string{"added 1 package", "npm WARN deprecated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := writeFakeCommand(t, tt.script)
+
+			var stream *lockedBuffer
+			var output []byte
+			var err error
+			if tt.stream {
+				stream = &lockedBuffer{}
+				output, err = RunPackageCommand(context.Background(), stream, command)
+			} else {
+				output, err = RunPackageCommand(context.Background(), nil, command)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RunPackageCommand() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunPackageCommand() error: %v", err)
+			}
+			if string(output) != tt.wantOutput {
+				t.Errorf("RunPackageCommand() output = %q, want %q", output, tt.wantOutput)
+			}
+			for _, want := range tt.wantStream {
+				if !strings.Contains(stream.String(), want) {
+					t.Errorf("streamed output %q does not contain %q", stream.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunPackageCommand_Timeout(t *testing.T) {
+	// A package manager that lists one package and then hangs
+	command := writeFakeCommand(t, "echo left-pad; exec sleep 30")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	output, err := RunPackageCommand(ctx, nil, command)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("RunPackageCommand() took %v, want it to stop at the timeout", elapsed)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunPackageCommand() error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+	if string(output) != "left-pad\n" {
+		t.Errorf("RunPackageCommand() output = %q, want the output before the timeout", output)
+	}
+}
@@ -0,0 +1,132 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pinWildcard is the final component of a version pin (e.g., the "x" in "20.x")
+const pinWildcard = "x"
+
+// IsVersionPin reports whether version is a wildcard pin such as "20.x" or "20.11.x",
+// which tracks the newest installed version with the given prefix.
+func IsVersionPin(version string) bool {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 || !strings.EqualFold(parts[len(parts)-1], pinWildcard) {
+		return false
+	}
+	return allNumeric(parts[:len(parts)-1])
+}
+
+// MinorPin returns the pin that tracks the latest patch of version's minor release.
+// For example, "20.11.1" becomes "20.11.x" and "20" becomes "20.x".
+func MinorPin(version string) string {
+	if IsVersionPin(version) {
+		return version
+	}
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".") + "." + pinWildcard
+}
+
+// MatchVersionPin returns the newest version in versions that matches pin.
+// For example, "20.x" matches "20.9.0" and "20.11.1", and returns "20.11.1".
+func MatchVersionPin(pin string, versions []string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(pin, "v"), ".")
+	prefix := parts[:len(parts)-1]
+
+	best := ""
+	for _, candidate := range versions {
+		candidateParts := strings.Split(strings.TrimPrefix(candidate, "v"), ".")
+		if len(candidateParts) <= len(prefix) {
+			continue
+		}
+
+		matches := true
+		for i, part := range prefix {
+			if candidateParts[i] != part {
+				matches = false
+				break
+			}
+		}
+
+		if matches && (best == "" || compareVersionStrings(candidate, best) > 0) {
+			best = candidate
+		}
+	}
+
+	return best, best != ""
+}
+
+// SelectLatestMatching returns the newest stable version in available whose leading
+// components are those of prefix, so "3.12" (or the pin "3.12.x") selects the newest
+// 3.12.x. Prereleases are skipped, as is a version equal to the prefix itself.
+func SelectLatestMatching(available []AvailableVersion, prefix string) (AvailableVersion, bool) {
+	want, _, _ := splitVersion(prefix)
+	if len(want) == 0 {
+		return AvailableVersion{}, false
+	}
+
+	var best AvailableVersion
+	found := false
+	for _, candidate := range available {
+		parts, prerelease, _ := splitVersion(candidate.Version.Raw)
+		if prerelease != "" || len(parts) <= len(want) || !hasVersionPrefix(parts, want) {
+			continue
+		}
+		if !found || compareVersionStrings(candidate.Version.Raw, best.Version.Raw) > 0 {
+			best, found = candidate, true
+		}
+	}
+
+	return best, found
+}
+
+// ResolvePartialVersion turns a partial version such as "3.12" (or the pin "3.12.x")
+// into the newest patch release of it the provider has for platform, so the exact
+// version is installed and recorded. Other versions are returned unchanged.
+func ResolvePartialVersion(provider Provider, version, platform string) (string, error) {
+	if !isPartialVersion(version) {
+		return version, nil
+	}
+
+	available, err := provider.ListAvailable()
+	if err != nil {
+		return "", fmt.Errorf("failed to list available %s versions: %w", provider.DisplayName(), err)
+	}
+
+	latest, ok := SelectLatestMatching(available, version)
+	if !ok {
+		return "", &VersionNotAvailableError{
+			Runtime:     provider.Name(),
+			DisplayName: provider.DisplayName(),
+			Version:     version,
+			Platform:    platform,
+			Hint:        fmt.Sprintf("; see 'dtvem list-all %s'", provider.Name()),
+		}
+	}
+	return latest.Version.Raw, nil
+}
+
+// isPartialVersion reports whether version names a release series rather than a
+// release: one or two numeric components ("20", "3.12") or a wildcard pin ("3.12.x")
+func isPartialVersion(version string) bool {
+	if IsVersionPin(version) {
+		return true
+	}
+
+	components, numeric := NumericComponents(version)
+	return numeric && components <= 2
+}
+
+// hasVersionPrefix reports whether parts starts with the components of prefix
+func hasVersionPrefix(parts, prefix []int) bool {
+	for i, part := range prefix {
+		if parts[i] != part {
+			return false
+		}
+	}
+	return true
+}
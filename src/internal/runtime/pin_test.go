@@ -0,0 +1,106 @@
+package runtime
+
+import "testing"
+
+func TestIsVersionPin(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"20.x", true},
+		{"20.11.x", true},
+		{"v20.X", true},
+		{"20.11.1", false},
+		{"20", false},
+		{"x", false},
+		{".x", false},
+		{"lts.x", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := IsVersionPin(tt.version); got != tt.want {
+				t.Errorf("IsVersionPin(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinorPin(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"20.11.1", "20.11.x"},
+		{"3.12", "3.12.x"},
+		{"20", "20.x"},
+		{"v18.16.0", "18.16.x"},
+		{"20.x", "20.x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := MinorPin(tt.version); got != tt.want {
+				t.Errorf("MinorPin(%q) = %q, want %q", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchVersionPin(t *testing.T) {
+	installed := []string{"18.16.0", "20.9.0", "20.11.1", "20.2.0", "21.0.0", "2.0.0"}
+
+	tests := []struct {
+		pin    string
+		want   string
+		wantOK bool
+	}{
+		{"20.x", "20.11.1", true},
+		{"20.9.x", "20.9.0", true},
+		{"18.x", "18.16.0", true},
+		{"2.x", "2.0.0", true},
+		{"19.x", "", false},
+		{"20.10.x", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pin, func(t *testing.T) {
+			got, ok := MatchVersionPin(tt.pin, installed)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("MatchVersionPin(%q) = (%q, %v), want (%q, %v)", tt.pin, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSelectLatestMatching(t *testing.T) {
+	var available []AvailableVersion
+	for _, v := range []string{"3.12.1", "3.12.10", "3.12.9", "3.13.0", "3.13.1", "3.13.2rc1", "3.120.0", "20.11.1+build"} {
+		available = append(available, AvailableVersion{Version: NewVersion(v)})
+	}
+
+	tests := []struct {
+		prefix string
+		want   string
+		wantOK bool
+	}{
+		{"3.12", "3.12.10", true},
+		{"3.13", "3.13.1", true},
+		{"v3.13", "3.13.1", true},
+		{"3.12.x", "3.12.10", true},
+		{"20", "20.11.1+build", true},
+		{"3.12.10", "", false},
+		{"3.14", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, ok := SelectLatestMatching(available, tt.prefix)
+			if ok != tt.wantOK || got.Version.Raw != tt.want {
+				t.Errorf("SelectLatestMatching(%q) = (%q, %v), want (%q, %v)", tt.prefix, got.Version.Raw, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 // Package runtime defines the provider interface and registry for runtime managers
 package runtime
 
+import "errors"
+
+// ErrNotImplemented is returned by provider methods that a runtime doesn't support yet.
+// Commands check for it with errors.Is and tell the user what to do instead.
+var ErrNotImplemented = errors.New("not implemented")
+
 // ShimProvider defines the minimal interface needed by the shim executable.
 // This interface excludes heavy operations like Install() and ListAvailable()
 // that require net/http and other dependencies not needed for shim execution.
@@ -92,3 +98,196 @@ type Provider interface {
 	// Returns empty string if the runtime doesn't support global packages
 	ManualPackageInstallCommand(packages []string) string
 }
+
+// ExecutableDirsProvider is an optional interface for providers whose package
+// managers place executables outside the version's bin directory (for example
+// a GOBIN-style directory). Reshim scans these directories in addition to the defaults.
+type ExecutableDirsProvider interface {
+	// ExecutableDirs returns additional directories containing executables for a version
+	ExecutableDirs(version string) []string
+}
+
+// ShimExecutableResolver is an optional interface for providers whose shims don't
+// all run executables next to ExecutablePath, or that report a missing one with a
+// clearer error than the shim's fallback to the system PATH
+type ShimExecutableResolver interface {
+	// ShimExecutablePath returns the executable the shim named shimName runs for a version
+	ShimExecutablePath(version, shimName string) (string, error)
+}
+
+// PrefetchProvider is an optional interface for providers that can download a
+// version's archive ahead of Install. Bulk installs prefetch several archives
+// concurrently and then install one at a time from the archive cache.
+type PrefetchProvider interface {
+	// Prefetch downloads the archive for a version into the archive cache without printing progress
+	Prefetch(version string) error
+}
+
+// ArchInstallProvider is an optional interface for providers that can install the
+// build of a version for another architecture of the current OS, e.g. to pre-build
+// caches for both arm64 and amd64 machines. These installs get no shims.
+type ArchInstallProvider interface {
+	// CheckArch returns an error if the version has no download for arch on this OS
+	CheckArch(version, arch string) error
+	// InstallArch installs the build of version for arch into installPath without creating shims
+	InstallArch(version, arch, installPath string) error
+}
+
+// ArchiveInstallProvider is an optional interface for providers that can install a
+// version from an archive on disk instead of downloading it, for air-gapped machines
+// and mirrors. The install uses the normal layout and creates shims.
+type ArchiveInstallProvider interface {
+	// InstallFromArchive installs a version from the archive at archivePath
+	InstallFromArchive(version, archivePath string) error
+}
+
+// ShimlessInstallProvider is an optional interface for providers that can install a
+// version without creating or updating shims (`install --no-shims`). The version is
+// listed as installed and usable through exec and env; the next reshim adds its shims.
+type ShimlessInstallProvider interface {
+	// InstallWithoutShims downloads and installs a version without touching the shims
+	InstallWithoutShims(version string) error
+}
+
+// VersionReporter is an optional interface for providers that can read the version a
+// runtime's executable reports about itself (e.g. `node --version`). Installs use it to
+// check that the installed binary is the version that was asked for, which catches
+// mislabeled downloads.
+type VersionReporter interface {
+	// VersionArgs returns the arguments that make the executable print its version
+	VersionArgs() []string
+	// ParseVersionOutput extracts the version from what the executable printed
+	ParseVersionOutput(output string) (string, error)
+}
+
+// PostInstaller is an optional interface for providers that prepare a version after
+// its files and shims are in place, such as making sure pip works or enabling
+// Corepack. A failure leaves the version installed and usable, so installs report
+// it as a warning instead of failing.
+type PostInstaller interface {
+	PostInstall(version string) error
+}
+
+// RunPostInstall runs the PostInstall hook of provider for version, if it has one
+func RunPostInstall(provider any, version string) error {
+	installer, ok := provider.(PostInstaller)
+	if !ok {
+		return nil
+	}
+	return installer.PostInstall(version)
+}
+
+// MinVersionProvider is an optional interface for providers that cannot install
+// versions older than some release, e.g. because no binaries are built for them.
+// Installs of older versions are rejected before anything is downloaded.
+type MinVersionProvider interface {
+	// MinSupportedVersion returns the oldest installable version, and false if
+	// there is no minimum
+	MinSupportedVersion() (Version, bool)
+}
+
+// CheckSupportedVersion returns a *VersionNotSupportedError if version is older than
+// the minimum of provider. Providers without a minimum support every version.
+func CheckSupportedVersion(provider ShimProvider, version string) error {
+	minProvider, ok := provider.(MinVersionProvider)
+	if !ok || !LooksLikeVersion(version) {
+		return nil
+	}
+	minVersion, ok := minProvider.MinSupportedVersion()
+	if !ok || CompareVersions(version, minVersion.Raw) >= 0 {
+		return nil
+	}
+	return &VersionNotSupportedError{
+		Runtime:     provider.Name(),
+		DisplayName: provider.DisplayName(),
+		Version:     version,
+		MinVersion:  minVersion.Raw,
+	}
+}
+
+// HealthProblem is a problem a HealthChecker found in an installed version
+type HealthProblem struct {
+	// Problem describes what is wrong
+	Problem string
+	// FixDescription describes what Fix will do
+	FixDescription string
+	// Fix repairs the problem, or is nil if it must be fixed by hand
+	Fix func() error
+	// Hint tells the user how to fix the problem by hand
+	Hint string
+}
+
+// HealthChecker is an optional interface for providers that can check an installed
+// version for problems that leave it only partly usable, such as a Python without a
+// working pip. `dtvem doctor` runs it for every installed version.
+type HealthChecker interface {
+	// CheckHealth returns the problems found in an installed version
+	CheckHealth(version string) []HealthProblem
+}
+
+// ResolvedDownload describes where the archive for a version comes from
+type ResolvedDownload struct {
+	Platform     string // Platform key the download was resolved for, e.g. "linux-amd64"
+	URL          string // URL the archive is downloaded from
+	SHA256       string // Expected checksum of the archive, empty if the manifest has none
+	SHA256Source string // Origin of the checksum ("upstream" or "dtvem"), empty if not recorded
+	Format       string // Archive format declared by the manifest, empty to go by the URL's extension
+}
+
+// DownloadResolver is an optional interface for providers that can report where a
+// version's archive will be downloaded from without downloading it. It backs
+// `install --dry-run` and the download details printed by verbose installs.
+type DownloadResolver interface {
+	// ResolveDownload returns the download for a version on the current platform
+	ResolveDownload(version string) (*ResolvedDownload, error)
+}
+
+// ProviderCapabilities describes which optional parts of the Provider interface a
+// provider really implements, so commands can adapt instead of calling stubs
+type ProviderCapabilities struct {
+	// GlobalPackages is true when GlobalPackages and InstallGlobalPackages work,
+	// i.e. the runtime has a package manager whose packages migrate can carry over
+	GlobalPackages bool
+	// Uninstall is true when Uninstall removes a version. Otherwise the uninstall
+	// command deletes the version directory itself.
+	Uninstall bool
+}
+
+// FamilyProvider is an optional interface for providers whose language family is
+// shared with other runtimes, such as node, deno and bun all running JavaScript
+type FamilyProvider interface {
+	// Family returns the language family, e.g. "JavaScript"
+	Family() string
+}
+
+// FamilyOf returns the language family of a provider, which is its display name
+// unless it implements FamilyProvider
+func FamilyOf(provider ShimProvider) string {
+	if fp, ok := provider.(FamilyProvider); ok {
+		return fp.Family()
+	}
+	return provider.DisplayName()
+}
+
+// CapabilitiesProvider is an optional interface for providers that report their
+// capabilities. Providers that don't implement it get DefaultCapabilities.
+type CapabilitiesProvider interface {
+	// Capabilities returns the features the provider supports
+	Capabilities() ProviderCapabilities
+}
+
+// DefaultCapabilities are assumed for providers that don't report capabilities.
+// They match what commands expected before providers could report them.
+func DefaultCapabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		GlobalPackages: true,
+	}
+}
+
+// CapabilitiesOf returns the capabilities of a provider
+func CapabilitiesOf(provider ShimProvider) ProviderCapabilities {
+	if cp, ok := provider.(CapabilitiesProvider); ok {
+		return cp.Capabilities()
+	}
+	return DefaultCapabilities()
+}
@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+)
+
+// mockCapableProvider is a mockProvider that reports its capabilities
+type mockCapableProvider struct {
+	mockProvider
+	capabilities ProviderCapabilities
+}
+
+func (m *mockCapableProvider) Capabilities() ProviderCapabilities { return m.capabilities }
+
+func TestCapabilitiesOf(t *testing.T) {
+	t.Run("defaults for providers that don't report", func(t *testing.T) {
+		got := CapabilitiesOf(&mockProvider{name: "plain"})
+		if got != DefaultCapabilities() {
+			t.Errorf("CapabilitiesOf() = %+v, want defaults %+v", got, DefaultCapabilities())
+		}
+	})
+
+	t.Run("reported capabilities", func(t *testing.T) {
+		want := ProviderCapabilities{Uninstall: true}
+		got := CapabilitiesOf(&mockCapableProvider{mockProvider: mockProvider{name: "capable"}, capabilities: want})
+		if got != want {
+			t.Errorf("CapabilitiesOf() = %+v, want %+v", got, want)
+		}
+	})
+}
+
+// mockPostInstallProvider is a mockProvider with a PostInstall hook
+type mockPostInstallProvider struct {
+	mockProvider
+	err     error
+	version string
+}
+
+func (m *mockPostInstallProvider) PostInstall(version string) error {
+	m.version = version
+	return m.err
+}
+
+func TestRunPostInstall(t *testing.T) {
+	if err := RunPostInstall(&mockProvider{name: "plain"}, "1.0.0"); err != nil {
+		t.Errorf("RunPostInstall() without a hook = %v, want nil", err)
+	}
+
+	provider := &mockPostInstallProvider{mockProvider: mockProvider{name: "hooked"}}
+	if err := RunPostInstall(provider, "1.0.0"); err != nil || provider.version != "1.0.0" {
+		t.Errorf("RunPostInstall() = %v, hook ran for %q, want the hook run for 1.0.0", err, provider.version)
+	}
+
+	provider.err = errors.New("pip failed")
+	if err := RunPostInstall(provider, "1.0.0"); !errors.Is(err, provider.err) {
+		t.Errorf("RunPostInstall() = %v, want the hook's error", err)
+	}
+}
+
+// mockMinVersionProvider is a mockProvider with a minimum supported version
+type mockMinVersionProvider struct {
+	mockProvider
+	minVersion string
+}
+
+func (m *mockMinVersionProvider) MinSupportedVersion() (Version, bool) {
+	return NewVersion(m.minVersion), m.minVersion != ""
+}
+
+func TestCheckSupportedVersion(t *testing.T) {
+	provider := &mockMinVersionProvider{mockProvider: mockProvider{name: "python", displayName: "Python"}, minVersion: "3.5.0"}
+
+	tests := []struct {
+		version     string
+		unsupported bool
+	}{
+		{version: "2.7.18", unsupported: true},
+		{version: "3.4.10", unsupported: true},
+		{version: "3.5.0"},
+		{version: "3.12.1"},
+		{version: "system"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			err := CheckSupportedVersion(provider, tt.version)
+			var notSupported *VersionNotSupportedError
+			if got := errors.As(err, &notSupported); got != tt.unsupported {
+				t.Fatalf("CheckSupportedVersion(%q) = %v, want unsupported %v", tt.version, err, tt.unsupported)
+			}
+			if tt.unsupported && notSupported.MinVersion != "3.5.0" {
+				t.Errorf("MinVersion = %q, want 3.5.0", notSupported.MinVersion)
+			}
+		})
+	}
+
+	if err := CheckSupportedVersion(&mockProvider{name: "plain"}, "0.1.0"); err != nil {
+		t.Errorf("CheckSupportedVersion() without a minimum = %v, want nil", err)
+	}
+	provider.minVersion = ""
+	if err := CheckSupportedVersion(provider, "0.1.0"); err != nil {
+		t.Errorf("CheckSupportedVersion() with no minimum reported = %v, want nil", err)
+	}
+}
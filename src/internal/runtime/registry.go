@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -37,6 +38,15 @@ func (r *Registry) Register(provider Provider) error {
 	return nil
 }
 
+// RegisterOrReplace adds a runtime provider to the registry, replacing any
+// provider already registered under the same name
+func (r *Registry) RegisterOrReplace(provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers[provider.Name()] = provider
+}
+
 // Get retrieves a runtime provider by name
 func (r *Registry) Get(name string) (Provider, error) {
 	r.mu.RLock()
@@ -44,13 +54,13 @@ func (r *Registry) Get(name string) (Provider, error) {
 
 	provider, exists := r.providers[name]
 	if !exists {
-		return nil, fmt.Errorf("runtime provider '%s' not found", name)
+		return nil, &RuntimeNotFoundError{Runtime: name}
 	}
 
 	return provider, nil
 }
 
-// List returns all registered runtime provider names
+// List returns all registered runtime provider names, sorted by name
 func (r *Registry) List() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -59,10 +69,11 @@ func (r *Registry) List() []string {
 	for name := range r.providers {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
-// GetAll returns all registered providers
+// GetAll returns all registered providers, sorted by name
 func (r *Registry) GetAll() []Provider {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -71,9 +82,37 @@ func (r *Registry) GetAll() []Provider {
 	for _, provider := range r.providers {
 		providers = append(providers, provider)
 	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name() < providers[j].Name()
+	})
 	return providers
 }
 
+// RuntimeGroup is the runtimes of one language family
+type RuntimeGroup struct {
+	Family   string
+	Runtimes []string
+}
+
+// Groups returns the registered runtime names grouped by language family, with
+// the families and the runtimes in each sorted by name
+func (r *Registry) Groups() []RuntimeGroup {
+	byFamily := make(map[string][]string)
+	for _, provider := range r.GetAll() {
+		family := FamilyOf(provider)
+		byFamily[family] = append(byFamily[family], provider.Name())
+	}
+
+	groups := make([]RuntimeGroup, 0, len(byFamily))
+	for family, runtimes := range byFamily {
+		groups = append(groups, RuntimeGroup{Family: family, Runtimes: runtimes})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Family < groups[j].Family
+	})
+	return groups
+}
+
 // Has checks if a runtime provider is registered
 func (r *Registry) Has(name string) bool {
 	r.mu.RLock()
@@ -89,7 +128,7 @@ func (r *Registry) Unregister(name string) error {
 	defer r.mu.Unlock()
 
 	if _, exists := r.providers[name]; !exists {
-		return fmt.Errorf("runtime provider '%s' not found", name)
+		return &RuntimeNotFoundError{Runtime: name}
 	}
 
 	delete(r.providers, name)
@@ -103,6 +142,12 @@ func Register(provider Provider) error {
 	return globalRegistry.Register(provider)
 }
 
+// RegisterOrReplace adds a provider to the global registry, replacing any
+// provider already registered under the same name
+func RegisterOrReplace(provider Provider) {
+	globalRegistry.RegisterOrReplace(provider)
+}
+
 // Get retrieves a provider from the global registry
 func Get(name string) (Provider, error) {
 	return globalRegistry.Get(name)
@@ -118,6 +163,11 @@ func GetAll() []Provider {
 	return globalRegistry.GetAll()
 }
 
+// Groups returns the runtime names of the global registry grouped by language family
+func Groups() []RuntimeGroup {
+	return globalRegistry.Groups()
+}
+
 // Has checks if a provider exists in the global registry
 func Has(name string) bool {
 	return globalRegistry.Has(name)
@@ -133,6 +183,15 @@ func GetRegistry() *Registry {
 	return globalRegistry
 }
 
+// UseRegistry makes r the global registry and returns a function that restores
+// the previous one. This is primarily useful for testing: a test that registers
+// its providers in a fresh registry can't collide with providers of other tests.
+func UseRegistry(r *Registry) (restore func()) {
+	previous := globalRegistry
+	globalRegistry = r
+	return func() { globalRegistry = previous }
+}
+
 // GetShimProvider retrieves a provider as ShimProvider from the global registry.
 // This returns only the minimal interface needed by the shim.
 func GetShimProvider(name string) (ShimProvider, error) {
@@ -1,6 +1,8 @@
 package runtime
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -96,6 +98,62 @@ func TestRegistry_Register(t *testing.T) {
 	}
 }
 
+func TestRegistry_RegisterOrReplace(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&mockProvider{name: "test", displayName: "Test 1"}); err != nil {
+		t.Fatalf("Register() error: %v", err)
+	}
+
+	r.RegisterOrReplace(&mockProvider{name: "test", displayName: "Test 2"})
+	r.RegisterOrReplace(&mockProvider{name: "other", displayName: "Other"})
+
+	provider, err := r.Get("test")
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if provider.DisplayName() != "Test 2" {
+		t.Errorf("Get() = %q, want the replacing provider %q", provider.DisplayName(), "Test 2")
+	}
+	if !r.Has("other") {
+		t.Error("RegisterOrReplace() did not add a new provider")
+	}
+	if len(r.List()) != 2 {
+		t.Errorf("List() = %v, want 2 providers", r.List())
+	}
+}
+
+func TestUseRegistry(t *testing.T) {
+	outer := NewRegistry()
+	restoreOuter := UseRegistry(outer)
+	defer restoreOuter()
+
+	if err := Register(&mockProvider{name: "test", displayName: "Outer"}); err != nil {
+		t.Fatalf("Register() error: %v", err)
+	}
+
+	// A fresh registry doesn't see the outer providers, and registering the same
+	// name in it doesn't collide with them
+	restoreInner := UseRegistry(NewRegistry())
+	if Has("test") {
+		t.Error("fresh registry has a provider of the previous registry")
+	}
+	if err := Register(&mockProvider{name: "test", displayName: "Inner"}); err != nil {
+		t.Errorf("Register() in a fresh registry error: %v", err)
+	}
+	restoreInner()
+
+	provider, err := Get("test")
+	if err != nil {
+		t.Fatalf("Get() after restore error: %v", err)
+	}
+	if provider.DisplayName() != "Outer" {
+		t.Errorf("Get() after restore = %q, want %q", provider.DisplayName(), "Outer")
+	}
+	if GetRegistry() != outer {
+		t.Error("restore did not bring back the previous registry")
+	}
+}
+
 func TestRegistry_Get(t *testing.T) {
 	r := NewRegistry()
 	provider := &mockProvider{name: "test", displayName: "Test"}
@@ -131,6 +189,10 @@ func TestRegistry_Get(t *testing.T) {
 				if p != nil {
 					t.Error("Get() expected nil provider on error")
 				}
+				var notFound *RuntimeNotFoundError
+				if !errors.As(err, &notFound) || notFound.Runtime != tt.searchName {
+					t.Errorf("Get() error = %v, want a RuntimeNotFoundError for %q", err, tt.searchName)
+				}
 			} else {
 				if err != nil {
 					t.Errorf("Get() unexpected error: %v", err)
@@ -206,6 +268,64 @@ func TestRegistry_List(t *testing.T) {
 	}
 }
 
+func TestRegistry_ListSorted(t *testing.T) {
+	r := NewRegistry()
+	for _, name := range []string{"ruby", "node", "python", "bun", "deno"} {
+		if err := r.Register(&mockProvider{name: name, displayName: name}); err != nil {
+			t.Fatalf("Failed to register provider: %v", err)
+		}
+	}
+
+	want := []string{"bun", "deno", "node", "python", "ruby"}
+	// Map iteration order varies between calls, so check several times
+	for i := 0; i < 10; i++ {
+		if got := r.List(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("List() = %v, want %v", got, want)
+		}
+
+		all := r.GetAll()
+		got := make([]string, len(all))
+		for j, p := range all {
+			got[j] = p.Name()
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetAll() names = %v, want %v", got, want)
+		}
+	}
+}
+
+// mockFamilyProvider is a mock provider that reports a language family
+type mockFamilyProvider struct {
+	mockProvider
+	family string
+}
+
+func (m *mockFamilyProvider) Family() string { return m.family }
+
+func TestRegistry_Groups(t *testing.T) {
+	r := NewRegistry()
+	providers := []Provider{
+		&mockFamilyProvider{mockProvider{name: "node", displayName: "Node.js"}, "JavaScript"},
+		&mockProvider{name: "ruby", displayName: "Ruby"},
+		&mockFamilyProvider{mockProvider{name: "bun", displayName: "Bun"}, "JavaScript"},
+		&mockProvider{name: "python", displayName: "Python"},
+	}
+	for _, p := range providers {
+		if err := r.Register(p); err != nil {
+			t.Fatalf("Failed to register provider: %v", err)
+		}
+	}
+
+	want := []RuntimeGroup{
+		{Family: "JavaScript", Runtimes: []string{"bun", "node"}},
+		{Family: "Python", Runtimes: []string{"python"}},
+		{Family: "Ruby", Runtimes: []string{"ruby"}},
+	}
+	if got := r.Groups(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Groups() = %+v, want %+v", got, want)
+	}
+}
+
 func TestRegistry_GetAll(t *testing.T) {
 	tests := []struct {
 		name      string
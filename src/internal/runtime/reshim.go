@@ -0,0 +1,78 @@
+package runtime
+
+import "strings"
+
+// ReshimTrigger describes a command that installs or removes executables, such as
+// "npm install -g" or "uv tool install". Providers list their triggers and match
+// them with ShouldReshim in ShouldReshimAfter.
+type ReshimTrigger struct {
+	// Shims are the commands the trigger applies to (e.g. "pip", "pip3")
+	Shims []string
+	// Subcommands are the leading arguments that trigger a reshim. A subcommand of
+	// several words (e.g. "tool install") must match that many leading arguments.
+	Subcommands []string
+	// Flags, when set, must include at least one argument of the command
+	// (e.g. "-g" and "--global" for package managers that also install locally)
+	Flags []string
+}
+
+// matches reports whether the command is one of the trigger's
+func (t ReshimTrigger) matches(shimName string, args []string) bool {
+	if !containsArg(t.Shims, shimName) {
+		return false
+	}
+
+	subcommandMatched := false
+	for _, subcommand := range t.Subcommands {
+		if hasLeadingArgs(args, strings.Fields(subcommand)) {
+			subcommandMatched = true
+			break
+		}
+	}
+	if !subcommandMatched {
+		return false
+	}
+
+	if len(t.Flags) == 0 {
+		return true
+	}
+	for _, arg := range args {
+		if containsArg(t.Flags, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldReshim reports whether any of the triggers matches the command
+func ShouldReshim(triggers []ReshimTrigger, shimName string, args []string) bool {
+	for _, trigger := range triggers {
+		if trigger.matches(shimName, args) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLeadingArgs reports whether args starts with prefix
+func hasLeadingArgs(args, prefix []string) bool {
+	if len(prefix) == 0 || len(args) < len(prefix) {
+		return false
+	}
+	for i, word := range prefix {
+		if args[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// containsArg reports whether s is in list
+func containsArg(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package runtime
+
+import "testing"
+
+func TestShouldReshim(t *testing.T) {
+	triggers := []ReshimTrigger{
+		{Shims: []string{"pip", "pip3"}, Subcommands: []string{"install"}},
+		{Shims: []string{"uv"}, Subcommands: []string{"tool install"}},
+		{Shims: []string{"npm"}, Subcommands: []string{"install"}, Flags: []string{"-g", "--global"}},
+	}
+
+	tests := []struct {
+		name     string
+		shimName string
+		args     []string
+		want     bool
+	}{
+		{"single-word subcommand", "pip3", []string{"install", "black"}, true},
+		{"multi-word subcommand", "uv", []string{"tool", "install", "ruff"}, true},
+		{"multi-word subcommand needs every word", "uv", []string{"tool", "list"}, false},
+		{"subcommand must lead", "uv", []string{"run", "tool", "install"}, false},
+		{"too few args", "uv", []string{"tool"}, false},
+		{"no args", "pip", nil, false},
+		{"required flag present", "npm", []string{"install", "--global", "typescript"}, true},
+		{"required flag missing", "npm", []string{"install", "typescript"}, false},
+		{"flag alone is not enough", "npm", []string{"run", "-g"}, false},
+		{"other shim", "python", []string{"install"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldReshim(triggers, tt.shimName, tt.args); got != tt.want {
+				t.Errorf("ShouldReshim(%q, %v) = %v, want %v", tt.shimName, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldReshim_NoTriggers(t *testing.T) {
+	if ShouldReshim(nil, "pip", []string{"install"}) {
+		t.Error("ShouldReshim() with no triggers should be false")
+	}
+}
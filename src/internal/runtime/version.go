@@ -2,6 +2,8 @@ package runtime
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,15 +15,24 @@ type Version struct {
 	Major int
 	Minor int
 	Patch int
+	// Prerelease is the tag of a prerelease (e.g. "preview2" in "4.0.0-preview2")
+	Prerelease string
+	// Build is the build metadata (e.g. "20251209" in "3.13.1+20251209")
+	Build string
 }
 
-// NewVersion creates a new Version from a version string
+// NewVersion creates a new Version from a version string. Raw keeps the string
+// exactly as given, for display; the install directory is named by Normalized and
+// the other fields are parsed from it.
 func NewVersion(version string) Version {
-	return Version{
-		Raw: version,
-		// TODO: Parse major, minor, patch from version string
-		// For now, just store the raw version
+	parts, prerelease, build := splitVersion(version)
+	v := Version{Raw: version, Prerelease: prerelease, Build: build}
+	for i, field := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if i < len(parts) {
+			*field = parts[i]
+		}
 	}
+	return v
 }
 
 // String returns the string representation of the version
@@ -29,9 +40,16 @@ func (v Version) String() string {
 	return v.Raw
 }
 
+// Normalized returns the version without a leading "v" or surrounding whitespace.
+// It names the version's install directory and is what versions are compared by,
+// so "v20.11.1" and "20.11.1" are the same install.
+func (v Version) Normalized() string {
+	return NormalizeVersion(v.Raw)
+}
+
 // Equal checks if two versions are equal
 func (v Version) Equal(other Version) bool {
-	return v.Raw == other.Raw
+	return v.Normalized() == other.Normalized()
 }
 
 // InstalledVersion represents an installed runtime version with metadata
@@ -88,11 +106,18 @@ func SortVersionsDesc(versions []AvailableVersion) {
 	})
 }
 
-// compareVersionStrings compares two version strings semantically.
+// CompareVersions compares two version strings semantically.
+// Returns >0 if a > b, <0 if a < b, 0 if equal.
+func CompareVersions(a, b string) int {
+	return compareVersionStrings(a, b)
+}
+
+// compareVersionStrings compares two version strings semantically. A prerelease
+// comes before its release ("4.0.0-preview2" < "4.0.0") and build metadata is ignored.
 // Returns >0 if a > b, <0 if a < b, 0 if equal.
 func compareVersionStrings(a, b string) int {
-	aParts := parseVersionParts(a)
-	bParts := parseVersionParts(b)
+	aParts, aPre, _ := splitVersion(a)
+	bParts, bPre, _ := splitVersion(b)
 
 	// Compare each part
 	maxLen := len(aParts)
@@ -114,27 +139,161 @@ func compareVersionStrings(a, b string) int {
 		}
 	}
 
-	return 0
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return comparePrerelease(aPre, bPre)
 }
 
-// parseVersionParts splits a version string into numeric parts.
-// For example, "3.11.0" becomes [3, 11, 0].
-func parseVersionParts(version string) []int {
-	// Remove common prefixes
-	version = strings.TrimPrefix(version, "v")
+// splitVersion splits a version string into its numeric release parts, its
+// prerelease tag and its build metadata, ignoring a leading "v". For example,
+// "4.0.0-preview2" becomes [4, 0, 0] and "preview2", "3.14.0rc1" becomes
+// [3, 14, 0] and "rc1", and "3.13.1+20251209" becomes [3, 13, 1] and build "20251209".
+func splitVersion(version string) (parts []int, prerelease, build string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, build, _ = strings.Cut(version, "+")
 
-	// Split by dots and dashes
-	parts := strings.FieldsFunc(version, func(c rune) bool {
-		return c == '.' || c == '-'
+	// The release is the leading run of digits and dots
+	end := strings.IndexFunc(version, func(c rune) bool {
+		return c != '.' && (c < '0' || c > '9')
 	})
+	if end < 0 {
+		end = len(version)
+	}
+	prerelease = strings.TrimLeft(version[end:], "-.")
 
-	var result []int
-	for _, part := range parts {
-		// Try to parse as integer, skip non-numeric parts
+	for _, part := range strings.Split(version[:end], ".") {
 		if val, err := strconv.Atoi(part); err == nil {
-			result = append(result, val)
+			parts = append(parts, val)
 		}
 	}
 
-	return result
+	return parts, prerelease, build
+}
+
+// comparePrerelease compares two prerelease tags identifier by identifier. Numbers
+// in an identifier compare numerically, so "preview10" comes after "preview2" and
+// "rc.10" after "rc.2"; letters compare alphabetically, so "a1" < "b1" < "rc1".
+func comparePrerelease(a, b string) int {
+	aIDs := strings.FieldsFunc(a, isPrereleaseSeparator)
+	bIDs := strings.FieldsFunc(b, isPrereleaseSeparator)
+
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		aText, aNum := splitPrereleaseID(aIDs[i])
+		bText, bNum := splitPrereleaseID(bIDs[i])
+		if aText != bText {
+			return strings.Compare(aText, bText)
+		}
+		if aNum != bNum {
+			return aNum - bNum
+		}
+	}
+
+	// More identifiers come later: "rc.1.1" > "rc.1"
+	return len(aIDs) - len(bIDs)
+}
+
+// isPrereleaseSeparator reports whether c separates prerelease identifiers
+func isPrereleaseSeparator(c rune) bool {
+	return c == '.' || c == '-'
+}
+
+// splitPrereleaseID splits a prerelease identifier into its text and trailing
+// number: "preview2" is ("preview", 2) and "1" is ("", 1)
+func splitPrereleaseID(id string) (string, int) {
+	i := len(id)
+	for i > 0 && id[i-1] >= '0' && id[i-1] <= '9' {
+		i--
+	}
+	num, _ := strconv.Atoi(id[i:])
+	return id[:i], num
+}
+
+// LooksLikeVersion reports whether name could be a version, i.e. it starts with a digit
+// after an optional "v". It is used to ignore stray entries (such as .DS_Store or a
+// leftover archive) when scanning version directories.
+func LooksLikeVersion(name string) bool {
+	name = strings.TrimPrefix(name, "v")
+	return name != "" && name[0] >= '0' && name[0] <= '9'
+}
+
+// NumericComponents returns the number of dot-separated components of version when
+// every one of them is a plain number ("3.12" has 2), and false otherwise (as for
+// "3.12.0rc1" or "20.x")
+func NumericComponents(version string) (int, bool) {
+	parts := strings.Split(version, ".")
+	if !allNumeric(parts) {
+		return 0, false
+	}
+	return len(parts), true
+}
+
+// allNumeric reports whether every version component is a non-empty plain number
+func allNumeric(parts []string) bool {
+	for _, part := range parts {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// IsVersionDir reports whether an entry of a runtime's versions directory is an
+// installed version: a directory named like a version, or a link to one (a named
+// install pointing at a real version). Broken and looping links are skipped, as is a
+// link to versionsDir or one of its parents, which would make scans recurse.
+func IsVersionDir(versionsDir string, entry os.DirEntry) bool {
+	if !LooksLikeVersion(entry.Name()) {
+		return false
+	}
+	if entry.IsDir() {
+		return true
+	}
+
+	// Follow symlinks (and Windows junctions) to see what the entry points at
+	target, err := os.Stat(filepath.Join(versionsDir, entry.Name()))
+	if err != nil || !target.IsDir() {
+		return false
+	}
+
+	dir, err := filepath.Abs(versionsDir)
+	if err != nil {
+		return false
+	}
+	for {
+		if info, err := os.Stat(dir); err == nil && os.SameFile(info, target) {
+			return false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return true
+		}
+		dir = parent
+	}
+}
+
+// NormalizeVersion cleans up a version typed or pasted by a user: surrounding
+// whitespace (including a trailing newline) is removed, as is a leading "v" or "V"
+// when it is followed by a digit ("v20.11.1" becomes "20.11.1"). Other strings,
+// such as aliases like "lts", are returned trimmed but otherwise unchanged.
+func NormalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && version[1] >= '0' && version[1] <= '9' {
+		return version[1:]
+	}
+	return version
+}
+
+// SystemVersion is the reserved version that defers to the runtime installed on the
+// system PATH instead of a dtvem-managed version, like nvm's "nvm use system"
+const SystemVersion = "system"
+
+// IsSystemVersion reports whether version is the reserved system version
+func IsSystemVersion(version string) bool {
+	return strings.EqualFold(strings.TrimSpace(version), SystemVersion)
 }
@@ -1,6 +1,8 @@
 package runtime
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -96,6 +98,12 @@ func TestVersion_Equal(t *testing.T) {
 			v2:       Version{Raw: ""},
 			expected: false,
 		},
+		{
+			name:     "v prefix on one side",
+			v1:       Version{Raw: "v20.11.1"},
+			v2:       Version{Raw: "20.11.1"},
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -224,3 +232,240 @@ func TestParseVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestLooksLikeVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"20.11.1", true},
+		{"v18.16.0", true},
+		{"3.13.0t", true},
+		{"3", true},
+		{".DS_Store", false},
+		{"node-v20.11.1-linux-x64.tar.gz", false},
+		{"tmp", false},
+		{"v", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LooksLikeVersion(tt.name); got != tt.want {
+				t.Errorf("LooksLikeVersion(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumericComponents(t *testing.T) {
+	tests := []struct {
+		version     string
+		want        int
+		wantNumeric bool
+	}{
+		{"20", 1, true},
+		{"3.12", 2, true},
+		{"3.12.1", 3, true},
+		{"3.12.x", 0, false},
+		{"3.13.0t", 0, false},
+		{"3..1", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, numeric := NumericComponents(tt.version)
+			if got != tt.want || numeric != tt.wantNumeric {
+				t.Errorf("NumericComponents(%q) = %d, %v, want %d, %v", tt.version, got, numeric, tt.want, tt.wantNumeric)
+			}
+		})
+	}
+}
+
+func TestIsVersionDir(t *testing.T) {
+	root := t.TempDir()
+	versionsDir := filepath.Join(root, "versions", "python")
+	if err := os.MkdirAll(filepath.Join(versionsDir, "3.12.1"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(versionsDir, "tmp"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(versionsDir, "3.11.0.tar.gz"), []byte("archive"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	links := map[string]string{
+		"3.12-work":  filepath.Join(versionsDir, "3.12.1"),
+		"3.10-gone":  filepath.Join(versionsDir, "missing"),
+		"3.9-file":   filepath.Join(versionsDir, "3.11.0.tar.gz"),
+		"3.8-self":   versionsDir,
+		"3.7-parent": root,
+		"3.6-loop":   filepath.Join(versionsDir, "3.6-loop"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(versionsDir, name)); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"3.12.1", true},
+		{"3.12-work", true},
+		{"tmp", false},
+		{"3.11.0.tar.gz", false},
+		{"3.10-gone", false},
+		{"3.9-file", false},
+		{"3.8-self", false},
+		{"3.7-parent", false},
+		{"3.6-loop", false},
+	}
+
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		t.Fatalf("Failed to read versions directory: %v", err)
+	}
+	byName := make(map[string]os.DirEntry, len(entries))
+	for _, entry := range entries {
+		byName[entry.Name()] = entry
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsVersionDir(versionsDir, byName[tt.name]); got != tt.want {
+				t.Errorf("IsVersionDir(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"already clean", "20.11.1", "20.11.1"},
+		{"v prefix", "v20.11.1", "20.11.1"},
+		{"uppercase V prefix", "V3.12.0", "3.12.0"},
+		{"surrounding whitespace", "  20.11.1 ", "20.11.1"},
+		{"trailing newline", "v18.16.0\n", "18.16.0"},
+		{"windows line ending", "3.3.0\r\n", "3.3.0"},
+		{"pin", "v20.x", "20.x"},
+		{"alias untouched", "lts", "lts"},
+		{"word starting with v untouched", "vendor", "vendor"},
+		{"bare v", "v", "v"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeVersion(tt.input); got != tt.want {
+				t.Errorf("NormalizeVersion(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersions_BuildTag(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int // sign of the result
+	}{
+		{"build tag ignored", "3.13.1+20251209", "3.13.1", 0},
+		{"different builds compare equal", "3.13.1+20251209", "3.13.1+20250101", 0},
+		{"patch still compared", "3.13.2+20251209", "3.13.1+20251209", 1},
+		{"older version with newer build", "3.12.8+20251209", "3.13.1", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareVersions(tt.a, tt.b)
+			if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+				t.Errorf("CompareVersions(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewVersion_Components(t *testing.T) {
+	tests := []struct {
+		input      string
+		major      int
+		minor      int
+		patch      int
+		prerelease string
+		build      string
+	}{
+		{"3.11.0", 3, 11, 0, "", ""},
+		{"v20.11.1", 20, 11, 1, "", ""},
+		{"3.13.1+20251209", 3, 13, 1, "", "20251209"},
+		{"4.0.0-preview2", 4, 0, 0, "preview2", ""},
+		{"3.14.0rc1", 3, 14, 0, "rc1", ""},
+		{"22.0.0-rc.1", 22, 0, 0, "rc.1", ""},
+		{"3.12", 3, 12, 0, "", ""},
+		{"lts", 0, 0, 0, "lts", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v := NewVersion(tt.input)
+			if v.Raw != tt.input {
+				t.Errorf("Raw = %q, want it unchanged", v.Raw)
+			}
+			if v.Major != tt.major || v.Minor != tt.minor || v.Patch != tt.patch {
+				t.Errorf("components = %d.%d.%d, want %d.%d.%d", v.Major, v.Minor, v.Patch, tt.major, tt.minor, tt.patch)
+			}
+			if v.Prerelease != tt.prerelease || v.Build != tt.build {
+				t.Errorf("Prerelease, Build = %q, %q, want %q, %q", v.Prerelease, v.Build, tt.prerelease, tt.build)
+			}
+		})
+	}
+}
+
+func TestCompareVersions_Prerelease(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int // sign of the result
+	}{
+		{"prerelease before release", "4.0.0-preview2", "4.0.0", -1},
+		{"prerelease after previous release", "4.0.0-preview2", "3.4.1", 1},
+		{"preview numbers compare numerically", "4.0.0-preview10", "4.0.0-preview2", 1},
+		{"python prerelease stages", "3.14.0a7", "3.14.0b1", -1},
+		{"release candidate after beta", "3.14.0rc1", "3.14.0b4", 1},
+		{"dotted prerelease", "22.0.0-rc.10", "22.0.0-rc.2", 1},
+		{"same prerelease", "4.0.0-preview2", "4.0.0-preview2", 0},
+		{"prerelease with build tag", "3.14.0rc1+20251209", "3.14.0", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareVersions(tt.a, tt.b)
+			if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+				t.Errorf("CompareVersions(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortVersionsDesc_BuildAndPrerelease(t *testing.T) {
+	var versions []AvailableVersion
+	for _, v := range []string{"3.13.0", "4.0.0-preview2", "3.13.1+20251209", "4.0.0", "3.14.0rc1", "4.0.0-preview1"} {
+		versions = append(versions, AvailableVersion{Version: NewVersion(v)})
+	}
+
+	SortVersionsDesc(versions)
+
+	want := []string{"4.0.0", "4.0.0-preview2", "4.0.0-preview1", "3.14.0rc1", "3.13.1+20251209", "3.13.0"}
+	for i, v := range versions {
+		if v.Raw != want[i] {
+			t.Errorf("SortVersionsDesc()[%d] = %q, want %q", i, v.Raw, want[i])
+		}
+	}
+}
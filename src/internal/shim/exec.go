@@ -0,0 +1,157 @@
+package shim
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+// Exec runs execPath with args and the provider environment in place of the current
+// process. On Unix the process is replaced with syscall.Exec; where that isn't
+// available (Windows) the command runs as a child and the current process exits
+// with its exit code. An error means the command could not be started.
+func Exec(execPath string, args []string, providerEnv map[string]string) error {
+	// Build full args (executable name + arguments)
+	execPath, fullArgs := commandFor(execPath, args)
+
+	// Get current environment and apply provider overrides
+	env := MergeEnvironment(os.Environ(), providerEnv)
+
+	// On Unix systems, use Exec to replace the current process
+	// On Windows, Exec is not available, so we use StartProcess
+	if err := syscall.Exec(execPath, fullArgs, env); err != nil {
+		// If Exec fails (e.g., on Windows), fall back to starting a new process
+		cmd := &exec.Cmd{
+			Path:   execPath,
+			Args:   fullArgs,
+			Env:    env,
+			Stdin:  os.Stdin,
+			Stdout: os.Stdout,
+			Stderr: os.Stderr,
+		}
+		if err := cmd.Run(); err != nil {
+			// Check if this is an exit error (command ran but returned non-zero)
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				// Command executed successfully but returned non-zero exit code
+				// This is not a shim error - propagate the exit code
+				os.Exit(exitErr.ExitCode())
+			}
+			// Other errors (couldn't start, etc.) are actual failures
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExecAndWait runs execPath with args and the provider environment as a child
+// process and returns its exit code, for callers that have work to do afterwards
+func ExecAndWait(execPath string, args []string, providerEnv map[string]string) int {
+	// Build full args (executable name + arguments)
+	execPath, fullArgs := commandFor(execPath, args)
+
+	// Get current environment and apply provider overrides
+	env := MergeEnvironment(os.Environ(), providerEnv)
+
+	// Use exec.Command to run the command and wait for completion
+	cmd := &exec.Cmd{
+		Path:   execPath,
+		Args:   fullArgs,
+		Env:    env,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+
+	if err := cmd.Run(); err != nil {
+		// Check if this is an exit error
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		// Other errors (couldn't start, etc.) return 1
+		return 1
+	}
+
+	return 0
+}
+
+// commandFor returns the program to start and its full arguments for running execPath
+// with args. Windows can't start PowerShell scripts (such as yarn.ps1, found when
+// PATHEXT lists .PS1) directly, so they run through powershell.exe, which applies the
+// user's execution policy.
+func commandFor(execPath string, args []string) (string, []string) {
+	if runtime.GOOS != constants.OSWindows || !strings.EqualFold(filepath.Ext(execPath), ".ps1") {
+		return execPath, append([]string{execPath}, args...)
+	}
+
+	powershell, err := exec.LookPath("powershell.exe")
+	if err != nil {
+		powershell = "powershell.exe"
+	}
+	return powershell, append([]string{powershell, "-NoProfile", "-File", execPath}, args...)
+}
+
+// MergeEnvironment merges provider environment variables into the base environment.
+// Provider variables are prepended to existing values (for PATH-like variables) or set directly.
+// On Windows, where variable names are case-insensitive, a provider's PATH updates the
+// existing Path variable.
+func MergeEnvironment(baseEnv []string, providerEnv map[string]string) []string {
+	if len(providerEnv) == 0 {
+		return baseEnv
+	}
+
+	// Build a map of existing environment variables for easy lookup
+	envMap := make(map[string]string)
+	for _, e := range baseEnv {
+		if idx := strings.Index(e, "="); idx != -1 {
+			key := e[:idx]
+			value := e[idx+1:]
+			envMap[key] = value
+		}
+	}
+
+	// Apply provider environment variables
+	// For PATH-like variables (LD_LIBRARY_PATH, DYLD_LIBRARY_PATH), prepend the new value
+	for key, value := range providerEnv {
+		key = existingEnvKey(envMap, key)
+		if existing, ok := envMap[key]; ok && existing != "" {
+			// Prepend new value to existing (for PATH-like variables)
+			envMap[key] = value + string(filepath.ListSeparator) + existing
+		} else {
+			envMap[key] = value
+		}
+	}
+
+	// Convert back to slice format
+	result := make([]string, 0, len(envMap))
+	for key, value := range envMap {
+		result = append(result, key+"="+value)
+	}
+
+	return result
+}
+
+// existingEnvKey returns the name under which key is already set in envMap. Names
+// only differ from key on Windows, where "PATH" and "Path" are the same variable.
+func existingEnvKey(envMap map[string]string, key string) string {
+	if runtime.GOOS != constants.OSWindows {
+		return key
+	}
+	if _, ok := envMap[key]; ok {
+		return key
+	}
+	for existing := range envMap {
+		if strings.EqualFold(existing, key) {
+			return existing
+		}
+	}
+	return key
+}
@@ -0,0 +1,97 @@
+package shim
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+func TestMergeEnvironment(t *testing.T) {
+	sep := string(filepath.ListSeparator)
+
+	tests := []struct {
+		name        string
+		baseEnv     []string
+		providerEnv map[string]string
+		want        []string
+	}{
+		{
+			name:    "no provider variables",
+			baseEnv: []string{"HOME=/home/user"},
+			want:    []string{"HOME=/home/user"},
+		},
+		{
+			name:        "new variable is set",
+			baseEnv:     []string{"HOME=/home/user"},
+			providerEnv: map[string]string{"LD_LIBRARY_PATH": "/ruby/lib"},
+			want:        []string{"HOME=/home/user", "LD_LIBRARY_PATH=/ruby/lib"},
+		},
+		{
+			name:        "existing variable is prepended to",
+			baseEnv:     []string{"PATH=/usr/bin"},
+			providerEnv: map[string]string{"PATH": "/node/bin"},
+			want:        []string{"PATH=/node/bin" + sep + "/usr/bin"},
+		},
+		{
+			name:        "empty existing variable is replaced",
+			baseEnv:     []string{"LD_LIBRARY_PATH="},
+			providerEnv: map[string]string{"LD_LIBRARY_PATH": "/ruby/lib"},
+			want:        []string{"LD_LIBRARY_PATH=/ruby/lib"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeEnvironment(tt.baseEnv, tt.providerEnv)
+			sort.Strings(got)
+			sort.Strings(tt.want)
+			if len(got) != len(tt.want) {
+				t.Fatalf("MergeEnvironment() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("MergeEnvironment() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestMergeEnvironment_WindowsPathCase(t *testing.T) {
+	got := MergeEnvironment([]string{`Path=C:\Windows`}, map[string]string{"PATH": `C:\node`})
+
+	want := []string{`Path=C:\node;C:\Windows`}
+	if runtime.GOOS != constants.OSWindows {
+		// Names are case-sensitive elsewhere, so PATH is a separate variable
+		want = []string{`PATH=C:\node`, `Path=C:\Windows`}
+	}
+	sort.Strings(got)
+	if len(got) != len(want) || got[0] != want[0] || got[len(got)-1] != want[len(want)-1] {
+		t.Errorf("MergeEnvironment() = %v, want %v", got, want)
+	}
+}
+
+func TestCommandFor(t *testing.T) {
+	program, args := commandFor(filepath.Join("bin", "tool"), []string{"--version"})
+	if program != filepath.Join("bin", "tool") || len(args) != 2 || args[0] != program || args[1] != "--version" {
+		t.Errorf("commandFor() = (%q, %v), want the executable and its arguments", program, args)
+	}
+
+	script := filepath.Join("bin", "yarn.PS1")
+	program, args = commandFor(script, []string{"install"})
+	if runtime.GOOS != constants.OSWindows {
+		if program != script {
+			t.Errorf("commandFor(%q) = %q, want it run directly outside Windows", script, program)
+		}
+		return
+	}
+	want := []string{program, "-NoProfile", "-File", script, "install"}
+	if !strings.EqualFold(filepath.Base(program), "powershell.exe") || strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("commandFor(%q) = (%q, %v), want powershell.exe with %v", script, program, args, want)
+	}
+}
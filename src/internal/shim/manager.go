@@ -2,11 +2,13 @@
 package shim
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 
 	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/constants"
@@ -23,18 +25,42 @@ func NewManager() (*Manager, error) {
 	// Find the shim executable
 	// It should be in the same directory as the dtvem executable
 	// Or we'll build it on demand
-	shimSource, err := findShimExecutable()
+	shimSource, err := FindShimExecutable()
 	if err != nil {
 		return nil, fmt.Errorf("could not find shim executable: %w", err)
 	}
 
+	if _, err := ShimsDir(); err != nil {
+		return nil, err
+	}
+
 	return &Manager{
 		shimSource: shimSource,
 	}, nil
 }
 
-// findShimExecutable locates the shim executable
-func findShimExecutable() (string, error) {
+// NewManagerWithSource creates a shim manager that copies shims from the given executable.
+// This is useful for testing or when the shim executable lives elsewhere.
+func NewManagerWithSource(shimSource string) *Manager {
+	return &Manager{
+		shimSource: shimSource,
+	}
+}
+
+// ShimsDir returns the shims directory, creating it if it doesn't exist yet.
+// Shims can be created before any runtime is installed (e.g. by a reshim on a
+// fresh root), so every path that writes shims goes through here.
+func ShimsDir() (string, error) {
+	dir := config.DefaultPaths().Shims
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create shims directory: %w", err)
+	}
+	return dir, nil
+}
+
+// FindShimExecutable locates the dtvem-shim executable next to the running dtvem,
+// which every shim is a copy of
+func FindShimExecutable() (string, error) {
 	// Get the directory where dtvem is installed
 	execPath, err := os.Executable()
 	if err != nil {
@@ -63,6 +89,9 @@ func findShimExecutable() (string, error) {
 
 // CreateShim creates a shim for the given executable name
 func (m *Manager) CreateShim(shimName string) error {
+	if _, err := ShimsDir(); err != nil {
+		return err
+	}
 	shimPath := config.ShimPath(shimName)
 
 	// Copy the shim executable to the new location
@@ -144,12 +173,19 @@ type RuntimeShimInfo struct {
 	Shims       []string
 }
 
+// ErrNoRuntimesInstalled is returned by a rehash when no installed version has shims
+var ErrNoRuntimesInstalled = errors.New("no runtimes installed - nothing to reshim")
+
 // RehashCallback is called before processing each runtime
 // runtimeName is the internal name, displayName is the user-friendly name
 type RehashCallback func(runtimeName, displayName string)
 
 // RehashWithCallback regenerates all shims, calling the callback before each runtime
 func (m *Manager) RehashWithCallback(callback RehashCallback) (*RehashResult, error) {
+	if _, err := ShimsDir(); err != nil {
+		return nil, err
+	}
+
 	paths := config.DefaultPaths()
 	versionsDir := paths.Versions
 
@@ -157,7 +193,7 @@ func (m *Manager) RehashWithCallback(callback RehashCallback) (*RehashResult, er
 	entries, err := os.ReadDir(versionsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("no versions directory found - no runtimes installed yet")
+			return nil, fmt.Errorf("no versions directory found: %w", ErrNoRuntimesInstalled)
 		}
 		return nil, fmt.Errorf("failed to read versions directory: %w", err)
 	}
@@ -173,84 +209,24 @@ func (m *Manager) RehashWithCallback(callback RehashCallback) (*RehashResult, er
 		}
 
 		runtimeName := entry.Name()
-
-		// Check if this runtime has any versions installed
-		runtimeVersionsDir := filepath.Join(versionsDir, runtimeName)
-		versionEntries, err := os.ReadDir(runtimeVersionsDir)
-		if err != nil {
-			continue
-		}
-
-		// Skip if no versions installed
-		hasVersions := false
-		for _, ve := range versionEntries {
-			if ve.IsDir() {
-				hasVersions = true
-				break
-			}
-		}
-		if !hasVersions {
+		versionEntries := installedVersionEntries(filepath.Join(versionsDir, runtimeName))
+		if len(versionEntries) == 0 {
 			continue
 		}
 
-		// Get display name from provider
-		displayName := runtimeName
-		if provider, err := runtimepkg.Get(runtimeName); err == nil {
-			displayName = provider.DisplayName()
-		}
-
 		// Call the callback before processing this runtime
 		if callback != nil {
-			callback(runtimeName, displayName)
+			callback(runtimeName, runtimeDisplayName(runtimeName))
 		}
 
-		// For each installed version, scan for executables
-		for _, versionEntry := range versionEntries {
-			if !versionEntry.IsDir() {
-				continue
-			}
-
-			versionDir := filepath.Join(runtimeVersionsDir, versionEntry.Name())
-
-			// First, add core runtime shims (from provider)
-			coreShims := RuntimeShims(runtimeName)
-			for _, shimName := range coreShims {
-				shimMap[shimName] = runtimeName
-				shimsByRuntime[runtimeName] = appendUnique(shimsByRuntime[runtimeName], shimName)
-			}
-
-			// Then, scan bin directory for globally installed packages
-			binDir := filepath.Join(versionDir, "bin")
-			if execs, err := findExecutables(binDir); err == nil {
-				for _, exec := range execs {
-					shimMap[exec] = runtimeName
-					shimsByRuntime[runtimeName] = appendUnique(shimsByRuntime[runtimeName], exec)
-				}
-			}
-
-			// On Windows, also check the root version directory for .cmd/.bat files
-			// and Scripts directory for Python packages
-			if runtime.GOOS == constants.OSWindows {
-				if execs, err := findExecutables(versionDir); err == nil {
-					for _, exec := range execs {
-						shimMap[exec] = runtimeName
-						shimsByRuntime[runtimeName] = appendUnique(shimsByRuntime[runtimeName], exec)
-					}
-				}
-				// Check Scripts directory for Python pip packages
-				scriptsDir := filepath.Join(versionDir, "Scripts")
-				if execs, err := findExecutables(scriptsDir); err == nil {
-					for _, exec := range execs {
-						shimMap[exec] = runtimeName
-						shimsByRuntime[runtimeName] = appendUnique(shimsByRuntime[runtimeName], exec)
-					}
-				}
-			}
+		for _, shimName := range runtimeShims(versionsDir, runtimeName, versionEntries) {
+			shimMap[shimName] = runtimeName
+			shimsByRuntime[runtimeName] = appendUnique(shimsByRuntime[runtimeName], shimName)
 		}
 	}
 
 	if len(shimMap) == 0 {
-		return nil, fmt.Errorf("no runtimes installed - nothing to reshim")
+		return nil, ErrNoRuntimesInstalled
 	}
 
 	// Save the shim map cache
@@ -276,6 +252,223 @@ func (m *Manager) Rehash() (*RehashResult, error) {
 	return m.RehashWithCallback(nil)
 }
 
+// RehashAndPrune regenerates all shims like Rehash, then removes the shims that were
+// in the shim map before but no longer belong to any installed version, such as the
+// package executables of an uninstalled version. Returns the removed shim names.
+func (m *Manager) RehashAndPrune() (*RehashResult, []string, error) {
+	previous, err := loadShimMapFromDisk()
+	if err != nil {
+		previous = make(ShimMap)
+	}
+
+	current := make(ShimMap)
+	result, err := m.Rehash()
+	switch {
+	case errors.Is(err, ErrNoRuntimesInstalled):
+		// The last version is gone, so every mapped shim is stale
+		if err := SaveShimMap(current); err != nil {
+			return nil, nil, fmt.Errorf("failed to save shim map cache: %w", err)
+		}
+		result = &RehashResult{ShimsByRuntime: map[string][]string{}}
+	case err != nil:
+		return nil, nil, err
+	default:
+		for runtimeName, shimNames := range result.ShimsByRuntime {
+			for _, shimName := range shimNames {
+				current[shimName] = runtimeName
+			}
+		}
+	}
+	ResetShimMapCache()
+
+	var removed []string
+	for shimName := range previous {
+		if _, ok := current[shimName]; ok {
+			continue
+		}
+		if err := m.RemoveShim(shimName); err != nil {
+			return result, removed, err
+		}
+		removed = append(removed, shimName)
+	}
+	sort.Strings(removed)
+
+	return result, removed, nil
+}
+
+// RehashRuntime regenerates the shims of a single runtime by scanning only its installed
+// versions, calling the callback before it starts. Shims that were mapped to the runtime
+// but no longer belong to any of its versions are removed; the shims and shim map entries
+// of other runtimes are left alone. Returns the removed shim names.
+func (m *Manager) RehashRuntime(runtimeName string, callback RehashCallback) (*RehashResult, []string, error) {
+	if _, err := ShimsDir(); err != nil {
+		return nil, nil, err
+	}
+
+	versionsDir := config.DefaultPaths().Versions
+	versionEntries := installedVersionEntries(filepath.Join(versionsDir, runtimeName))
+	if callback != nil && len(versionEntries) > 0 {
+		callback(runtimeName, runtimeDisplayName(runtimeName))
+	}
+	shimNames := runtimeShims(versionsDir, runtimeName, versionEntries)
+
+	shimMap, err := loadShimMapFromDisk()
+	if err != nil {
+		shimMap = make(ShimMap)
+	}
+
+	current := make(map[string]bool, len(shimNames))
+	for _, shimName := range shimNames {
+		current[shimName] = true
+	}
+	var stale []string
+	for shimName, mappedRuntime := range shimMap {
+		if mappedRuntime == runtimeName && !current[shimName] {
+			stale = append(stale, shimName)
+			delete(shimMap, shimName)
+		}
+	}
+	sort.Strings(stale)
+	for _, shimName := range shimNames {
+		shimMap[shimName] = runtimeName
+	}
+
+	if err := SaveShimMap(shimMap); err != nil {
+		return nil, nil, fmt.Errorf("failed to save shim map cache: %w", err)
+	}
+	ResetShimMapCache()
+
+	if err := m.CreateShims(shimNames); err != nil {
+		return nil, nil, err
+	}
+
+	var removed []string
+	for _, shimName := range stale {
+		if err := m.RemoveShim(shimName); err != nil {
+			return nil, removed, err
+		}
+		removed = append(removed, shimName)
+	}
+
+	result := &RehashResult{ShimsByRuntime: map[string][]string{}, TotalShims: len(shimNames)}
+	if len(shimNames) > 0 {
+		result.ShimsByRuntime[runtimeName] = shimNames
+	}
+	return result, removed, nil
+}
+
+// RehashVersion recreates the shims of a single installed version: the runtime's core
+// shims and the executables of its globally installed packages. Shims and shim map
+// entries of other runtimes and versions are left alone. Returns the shim names.
+func (m *Manager) RehashVersion(runtimeName, version string) ([]string, error) {
+	versionDir := config.RuntimeVersionPath(runtimeName, version)
+	if info, err := os.Stat(versionDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s %s is not installed", runtimeName, version)
+	}
+
+	shimNames := versionShims(runtimeName, version, versionDir)
+	if len(shimNames) == 0 {
+		return nil, fmt.Errorf("no shims found for %s %s", runtimeName, version)
+	}
+
+	shimMap, err := loadShimMapFromDisk()
+	if err != nil {
+		shimMap = make(ShimMap)
+	}
+	for _, shimName := range shimNames {
+		shimMap[shimName] = runtimeName
+	}
+	if err := SaveShimMap(shimMap); err != nil {
+		return nil, fmt.Errorf("failed to save shim map cache: %w", err)
+	}
+	ResetShimMapCache()
+
+	if err := m.CreateShims(shimNames); err != nil {
+		return nil, err
+	}
+
+	return shimNames, nil
+}
+
+// installedVersionEntries returns the version directories in a runtime's versions
+// directory, skipping stray entries. It returns nil if the runtime has none.
+func installedVersionEntries(runtimeVersionsDir string) []os.DirEntry {
+	entries, err := os.ReadDir(runtimeVersionsDir)
+	if err != nil {
+		return nil
+	}
+
+	var versions []os.DirEntry
+	for _, entry := range entries {
+		if runtimepkg.IsVersionDir(runtimeVersionsDir, entry) {
+			versions = append(versions, entry)
+		}
+	}
+	return versions
+}
+
+// runtimeShims returns the shim names of every given installed version of a runtime
+func runtimeShims(versionsDir, runtimeName string, versionEntries []os.DirEntry) []string {
+	var shimNames []string
+	for _, versionEntry := range versionEntries {
+		versionDir := filepath.Join(versionsDir, runtimeName, versionEntry.Name())
+		for _, shimName := range versionShims(runtimeName, versionEntry.Name(), versionDir) {
+			shimNames = appendUnique(shimNames, shimName)
+		}
+	}
+	return shimNames
+}
+
+// runtimeDisplayName returns the display name of a runtime's provider, or its name
+// if no provider is registered
+func runtimeDisplayName(runtimeName string) string {
+	if provider, err := runtimepkg.Get(runtimeName); err == nil {
+		return provider.DisplayName()
+	}
+	return runtimeName
+}
+
+// versionShims returns the shim names for an installed version: the runtime's core
+// shims, then the executables found in the version's executable directories
+func versionShims(runtimeName, version, versionDir string) []string {
+	shimNames := make([]string, 0)
+	for _, shimName := range RuntimeShims(runtimeName) {
+		shimNames = appendUnique(shimNames, shimName)
+	}
+
+	for _, dir := range VersionExecutableDirs(runtimeName, version, versionDir) {
+		execs, err := findExecutables(dir)
+		if err != nil {
+			continue
+		}
+		for _, exec := range execs {
+			shimNames = appendUnique(shimNames, exec)
+		}
+	}
+
+	return shimNames
+}
+
+// VersionExecutableDirs returns the directories to scan for executables in an installed version.
+// This is the version's bin directory, plus on Windows the version root (.cmd/.bat files)
+// and the Scripts directory (Python pip packages), plus any directories the provider
+// reports through runtime.ExecutableDirsProvider.
+func VersionExecutableDirs(runtimeName, version, versionDir string) []string {
+	dirs := []string{filepath.Join(versionDir, "bin")}
+
+	if runtime.GOOS == constants.OSWindows {
+		dirs = append(dirs, versionDir, filepath.Join(versionDir, "Scripts"))
+	}
+
+	if provider, err := runtimepkg.Get(runtimeName); err == nil {
+		if extra, ok := provider.(runtimepkg.ExecutableDirsProvider); ok {
+			dirs = append(dirs, extra.ExecutableDirs(version)...)
+		}
+	}
+
+	return dirs
+}
+
 // appendUnique appends a string to a slice only if it's not already present
 func appendUnique(slice []string, s string) []string {
 	for _, existing := range slice {
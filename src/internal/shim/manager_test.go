@@ -7,10 +7,18 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/dtvem/dtvem/src/internal/constants"
 	runtimepkg "github.com/dtvem/dtvem/src/internal/runtime"
 )
 
+// useTestRegistry gives the test a fresh runtime registry, so the mock providers
+// it registers can't collide with those of other tests
+func useTestRegistry(t *testing.T) {
+	t.Helper()
+	t.Cleanup(runtimepkg.UseRegistry(runtimepkg.NewRegistry()))
+}
+
 // mockProvider for testing
 type mockProvider struct {
 	name  string
@@ -43,21 +51,16 @@ func (m *mockProvider) GetEnvironment(_ string) (map[string]string, error) {
 
 func TestRuntimeShims(t *testing.T) {
 	// Register test providers
-	_ = runtimepkg.Register(&mockProvider{
+	useTestRegistry(t)
+	runtimepkg.RegisterOrReplace(&mockProvider{
 		name:  "python",
 		shims: []string{"python", "python3", "pip", "pip3"},
 	})
-	_ = runtimepkg.Register(&mockProvider{
+	runtimepkg.RegisterOrReplace(&mockProvider{
 		name:  "node",
 		shims: []string{"node", "npm", "npx"},
 	})
 
-	// Cleanup after test
-	defer func() {
-		_ = runtimepkg.Unregister("python")
-		_ = runtimepkg.Unregister("node")
-	}()
-
 	tests := []struct {
 		name          string
 		runtimeName   string
@@ -327,3 +330,477 @@ func TestRuntimeShims_AllKnownRuntimes(t *testing.T) {
 		}
 	}
 }
+
+// execDirsProvider is a mockProvider that reports an extra executable directory
+type execDirsProvider struct {
+	mockProvider
+	extraDir string
+}
+
+func (m *execDirsProvider) ExecutableDirs(version string) []string {
+	return []string{m.extraDir}
+}
+
+func TestRehash_IncludesProviderExecutableDirs(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(tmpRoot, "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpRoot, "shims"), 0755); err != nil {
+		t.Fatalf("Failed to create shims directory: %v", err)
+	}
+
+	// An installed version with an empty bin directory
+	if err := os.MkdirAll(filepath.Join(tmpRoot, "versions", "testgobin", "1.0.0", "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+
+	// Executables installed into a directory outside the version's bin
+	extraDir := filepath.Join(tmpRoot, "gobin")
+	if err := os.MkdirAll(extraDir, 0755); err != nil {
+		t.Fatalf("Failed to create extra directory: %v", err)
+	}
+	toolName := "gopls"
+	if runtime.GOOS == constants.OSWindows {
+		toolName += constants.ExtExe
+	}
+	if err := os.WriteFile(filepath.Join(extraDir, toolName), []byte("tool"), 0755); err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+
+	provider := &execDirsProvider{
+		mockProvider: mockProvider{name: "testgobin", shims: []string{"testgobin"}},
+		extraDir:     extraDir,
+	}
+	useTestRegistry(t)
+	if err := runtimepkg.Register(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	manager := &Manager{shimSource: shimSource}
+	result, err := manager.Rehash()
+	if err != nil {
+		t.Fatalf("Rehash() error: %v", err)
+	}
+
+	shims := result.ShimsByRuntime["testgobin"]
+	if !reflect.DeepEqual(shims, []string{"testgobin", "gopls"}) {
+		t.Errorf("ShimsByRuntime[testgobin] = %v, want [testgobin gopls]", shims)
+	}
+
+	if runtimeName, ok := LookupRuntime("gopls"); !ok || runtimeName != "testgobin" {
+		t.Errorf("LookupRuntime(gopls) = (%q, %v), want (testgobin, true)", runtimeName, ok)
+	}
+}
+
+func TestRehash_IgnoresStrayVersionEntries(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(tmpRoot, "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpRoot, "shims"), 0755); err != nil {
+		t.Fatalf("Failed to create shims directory: %v", err)
+	}
+
+	// A runtime directory holding only stray entries has no installed versions
+	strayDir := filepath.Join(tmpRoot, "versions", "straytest")
+	if err := os.MkdirAll(filepath.Join(strayDir, "tmp", "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create stray directory: %v", err)
+	}
+	for _, file := range []string{".DS_Store", "straytest-1.0.0.tar.gz"} {
+		if err := os.WriteFile(filepath.Join(strayDir, file), []byte("stray"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+	toolName := "straytool"
+	if runtime.GOOS == constants.OSWindows {
+		toolName += constants.ExtExe
+	}
+	if err := os.WriteFile(filepath.Join(strayDir, "tmp", "bin", toolName), []byte("tool"), 0755); err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+
+	// A .DS_Store alongside the runtime directories
+	if err := os.WriteFile(filepath.Join(tmpRoot, "versions", ".DS_Store"), []byte("stray"), 0644); err != nil {
+		t.Fatalf("Failed to create .DS_Store: %v", err)
+	}
+
+	// One real installed version
+	if err := os.MkdirAll(filepath.Join(tmpRoot, "versions", "realtest", "1.0.0", "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+
+	manager := NewManagerWithSource(shimSource)
+	result, err := manager.Rehash()
+	if err != nil {
+		t.Fatalf("Rehash() error: %v", err)
+	}
+
+	if _, ok := result.ShimsByRuntime["straytest"]; ok {
+		t.Errorf("Rehash() created shims for a runtime with only stray entries: %v", result.ShimsByRuntime["straytest"])
+	}
+	if _, ok := LookupRuntime("straytool"); ok {
+		t.Error("Rehash() mapped an executable from a non-version directory")
+	}
+	if _, ok := result.ShimsByRuntime["realtest"]; !ok {
+		t.Errorf("Rehash() = %v, want shims for realtest", result.ShimsByRuntime)
+	}
+}
+
+func TestRehash_CreatesShimsDirOnFreshRoot(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(t.TempDir(), "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+
+	// An installed version, but no shims directory yet
+	if err := os.MkdirAll(filepath.Join(tmpRoot, "versions", "freshtest", "1.0.0", "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+
+	manager := NewManagerWithSource(shimSource)
+	if _, err := manager.Rehash(); err != nil {
+		t.Fatalf("Rehash() on a fresh root error: %v", err)
+	}
+
+	if _, err := os.Stat(config.ShimPath("freshtest")); err != nil {
+		t.Errorf("shim not created in a new shims directory: %v", err)
+	}
+}
+
+func TestRehash_NothingInstalledStillCreatesShimsDir(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	manager := NewManagerWithSource(filepath.Join(tmpRoot, "dtvem-shim"))
+	if _, err := manager.Rehash(); err == nil {
+		t.Error("Rehash() with nothing installed should report that there is nothing to reshim")
+	}
+
+	if info, err := os.Stat(filepath.Join(tmpRoot, "shims")); err != nil || !info.IsDir() {
+		t.Errorf("shims directory not created: %v", err)
+	}
+}
+
+func TestRehashVersion(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(t.TempDir(), "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+
+	toolName := "versiontool"
+	if runtime.GOOS == constants.OSWindows {
+		toolName += constants.ExtExe
+	}
+	binDir := filepath.Join(tmpRoot, "versions", "versiontest", "1.0.0", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, toolName), []byte("tool"), 0755); err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+
+	// Another runtime's entry in the shim map must survive
+	if err := SaveShimMap(ShimMap{"othertool": "otherruntime"}); err != nil {
+		t.Fatalf("Failed to save shim map: %v", err)
+	}
+
+	manager := NewManagerWithSource(shimSource)
+	shims, err := manager.RehashVersion("versiontest", "1.0.0")
+	if err != nil {
+		t.Fatalf("RehashVersion() error: %v", err)
+	}
+	if !reflect.DeepEqual(shims, []string{"versiontest", "versiontool"}) {
+		t.Errorf("RehashVersion() = %v, want [versiontest versiontool]", shims)
+	}
+
+	for _, name := range shims {
+		if _, err := os.Stat(config.ShimPath(name)); err != nil {
+			t.Errorf("shim %s not created: %v", name, err)
+		}
+		if runtimeName, ok := LookupRuntime(name); !ok || runtimeName != "versiontest" {
+			t.Errorf("LookupRuntime(%s) = (%q, %v), want (versiontest, true)", name, runtimeName, ok)
+		}
+	}
+	if runtimeName, ok := LookupRuntime("othertool"); !ok || runtimeName != "otherruntime" {
+		t.Errorf("LookupRuntime(othertool) = (%q, %v), want (otherruntime, true)", runtimeName, ok)
+	}
+
+	if _, err := manager.RehashVersion("versiontest", "2.0.0"); err == nil {
+		t.Error("RehashVersion() for a version that is not installed should fail")
+	}
+}
+
+func TestRehashAndPrune(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(t.TempDir(), "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+
+	versionDirs := map[string]string{"1.0.0": "oldtool", "2.0.0": "newtool"}
+	for version, tool := range versionDirs {
+		if runtime.GOOS == constants.OSWindows {
+			tool += constants.ExtExe
+		}
+		binDir := filepath.Join(tmpRoot, "versions", "prunetest", version, "bin")
+		if err := os.MkdirAll(binDir, 0755); err != nil {
+			t.Fatalf("Failed to create version directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(binDir, tool), []byte("tool"), 0755); err != nil {
+			t.Fatalf("Failed to create tool: %v", err)
+		}
+	}
+
+	manager := NewManagerWithSource(shimSource)
+	if _, err := manager.Rehash(); err != nil {
+		t.Fatalf("Rehash() error: %v", err)
+	}
+	// A shim that was never in the shim map isn't dtvem's to remove
+	if err := manager.CreateShim("unmapped"); err != nil {
+		t.Fatalf("CreateShim() error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		uninstall   string
+		wantRemoved []string
+		wantKept    []string
+	}{
+		{"package executable of the removed version", "2.0.0", []string{"newtool"}, []string{"prunetest", "oldtool", "unmapped"}},
+		{"every shim once the last version is gone", "1.0.0", []string{"oldtool", "prunetest"}, []string{"unmapped"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.RemoveAll(filepath.Join(tmpRoot, "versions", "prunetest", tt.uninstall)); err != nil {
+				t.Fatalf("Failed to remove version: %v", err)
+			}
+
+			_, removed, err := manager.RehashAndPrune()
+			if err != nil {
+				t.Fatalf("RehashAndPrune() error: %v", err)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("RehashAndPrune() removed %v, want %v", removed, tt.wantRemoved)
+			}
+
+			for _, name := range tt.wantRemoved {
+				if _, err := os.Stat(config.ShimPath(name)); !os.IsNotExist(err) {
+					t.Errorf("shim %s still exists", name)
+				}
+				if _, ok := LookupRuntime(name); ok {
+					t.Errorf("shim %s still in the shim map", name)
+				}
+			}
+			for _, name := range tt.wantKept {
+				if _, err := os.Stat(config.ShimPath(name)); err != nil {
+					t.Errorf("shim %s removed: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRehash_SymlinkedVersionDir(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(t.TempDir(), "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+
+	// A build kept outside dtvem's root, linked in as a named install
+	toolName := "linkedtool"
+	if runtime.GOOS == constants.OSWindows {
+		toolName += constants.ExtExe
+	}
+	buildDir := filepath.Join(t.TempDir(), "custom-build")
+	if err := os.MkdirAll(filepath.Join(buildDir, "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create build directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(buildDir, "bin", toolName), []byte("tool"), 0755); err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+
+	runtimeDir := filepath.Join(tmpRoot, "versions", "linktest")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		t.Fatalf("Failed to create runtime directory: %v", err)
+	}
+	if err := os.Symlink(buildDir, filepath.Join(runtimeDir, "1.0-custom")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	// A link back to the runtime directory must not be scanned as a version
+	if err := os.Symlink(runtimeDir, filepath.Join(runtimeDir, "2.0-loop")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	manager := NewManagerWithSource(shimSource)
+	result, err := manager.Rehash()
+	if err != nil {
+		t.Fatalf("Rehash() error: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.ShimsByRuntime["linktest"], []string{"linktest", "linkedtool"}) {
+		t.Errorf("Rehash() shims = %v, want [linktest linkedtool]", result.ShimsByRuntime["linktest"])
+	}
+	if _, err := os.Stat(config.ShimPath("linkedtool")); err != nil {
+		t.Errorf("shim for the linked version's tool not created: %v", err)
+	}
+}
+
+func TestShimsDir(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	dir, err := ShimsDir()
+	if err != nil {
+		t.Fatalf("ShimsDir() error: %v", err)
+	}
+	if want := filepath.Join(tmpRoot, "shims"); dir != want {
+		t.Errorf("ShimsDir() = %q, want %q", dir, want)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("ShimsDir() did not create %s: %v", dir, err)
+	}
+}
+
+func TestRehashRuntime(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(t.TempDir(), "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+
+	writeTool := func(runtimeName, tool string) string {
+		t.Helper()
+		if runtime.GOOS == constants.OSWindows {
+			tool += constants.ExtExe
+		}
+		binDir := filepath.Join(tmpRoot, "versions", runtimeName, "1.0.0", "bin")
+		if err := os.MkdirAll(binDir, 0755); err != nil {
+			t.Fatalf("Failed to create version directory: %v", err)
+		}
+		toolPath := filepath.Join(binDir, tool)
+		if err := os.WriteFile(toolPath, []byte("tool"), 0755); err != nil {
+			t.Fatalf("Failed to create tool: %v", err)
+		}
+		return toolPath
+	}
+
+	oldTool := writeTool("scopedtest", "oldgem")
+	writeTool("othertest", "othertool")
+
+	manager := NewManagerWithSource(shimSource)
+	if _, err := manager.Rehash(); err != nil {
+		t.Fatalf("Rehash() error: %v", err)
+	}
+
+	// One package of the runtime is replaced by another, and another runtime gains a
+	// tool that a scoped rehash must not pick up
+	if err := os.Remove(oldTool); err != nil {
+		t.Fatalf("Failed to remove tool: %v", err)
+	}
+	writeTool("scopedtest", "newgem")
+	writeTool("othertest", "unscanned")
+
+	var called []string
+	result, removed, err := manager.RehashRuntime("scopedtest", func(runtimeName, displayName string) {
+		called = append(called, runtimeName)
+	})
+	if err != nil {
+		t.Fatalf("RehashRuntime() error: %v", err)
+	}
+	if !reflect.DeepEqual(called, []string{"scopedtest"}) {
+		t.Errorf("callback called for %v, want [scopedtest]", called)
+	}
+	if !reflect.DeepEqual(removed, []string{"oldgem"}) {
+		t.Errorf("RehashRuntime() removed %v, want [oldgem]", removed)
+	}
+	if got, want := result.ShimsByRuntime["scopedtest"], []string{"scopedtest", "newgem"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RehashRuntime() shims = %v, want %v", got, want)
+	}
+	if _, ok := result.ShimsByRuntime["othertest"]; ok {
+		t.Error("RehashRuntime() reported shims of another runtime")
+	}
+
+	for name, want := range map[string]string{"newgem": "scopedtest", "othertest": "othertest", "othertool": "othertest"} {
+		if got, ok := LookupRuntime(name); !ok || got != want {
+			t.Errorf("LookupRuntime(%s) = %q, %v, want %q", name, got, ok, want)
+		}
+		if _, err := os.Stat(config.ShimPath(name)); err != nil {
+			t.Errorf("shim %s is missing: %v", name, err)
+		}
+	}
+	for _, name := range []string{"oldgem", "unscanned"} {
+		if _, ok := LookupRuntime(name); ok {
+			t.Errorf("shim %s is in the shim map", name)
+		}
+		if _, err := os.Stat(config.ShimPath(name)); !os.IsNotExist(err) {
+			t.Errorf("shim %s exists", name)
+		}
+	}
+}
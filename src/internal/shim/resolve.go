@@ -0,0 +1,190 @@
+package shim
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/path"
+	runtimepkg "github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// MappingSource describes how a shim name was mapped to its runtime
+type MappingSource string
+
+const (
+	// MappingFromCache means the shim was found in the shim map cache written by reshim
+	MappingFromCache MappingSource = "shim map cache"
+	// MappingFromProvider means the shim exactly matched a provider's shim list
+	MappingFromProvider MappingSource = "provider shims"
+	// MappingFromPrefix means the shim starts with one of a provider's shims (e.g., python3 -> python)
+	MappingFromPrefix MappingSource = "provider shim prefix"
+	// MappingFromName means nothing matched and the shim name is used as the runtime name
+	MappingFromName MappingSource = "shim name"
+)
+
+// NameFromPath returns the shim name for the path a shim was invoked as,
+// stripping the directory and any .exe extension (e.g., shims/npm.exe -> npm).
+func NameFromPath(shimPath string) string {
+	name := filepath.Base(shimPath)
+	if ext := filepath.Ext(name); strings.EqualFold(ext, constants.ExtExe) {
+		name = name[:len(name)-len(ext)]
+	}
+	return name
+}
+
+// MapToRuntime maps a shim name to its runtime
+// For example: python3 -> python, pip -> python, npm -> node, tsc -> node
+func MapToRuntime(shimName string) string {
+	runtimeName, _ := ResolveRuntime(shimName)
+	return runtimeName
+}
+
+// ResolveRuntime maps a shim name to its runtime and reports how the mapping was made.
+// It first checks the shim map cache (generated by reshim), then falls back
+// to querying registered providers.
+func ResolveRuntime(shimName string) (string, MappingSource) {
+	// First, try the shim map cache for O(1) lookup
+	// This handles both core shims and dynamically installed packages (tsc, eslint, black, etc.)
+	if runtimeName, ok := LookupRuntime(shimName); ok {
+		return runtimeName, MappingFromCache
+	}
+
+	// Fall back to provider-based lookup if cache is missing or doesn't have the shim
+	providers := runtimepkg.GetAllShimProviders()
+
+	// Check each provider's shims for an exact match first
+	for _, provider := range providers {
+		for _, s := range provider.Shims() {
+			if s == shimName {
+				return provider.Name(), MappingFromProvider
+			}
+		}
+	}
+
+	// Check for prefix match (e.g., python3 -> python)
+	for _, provider := range providers {
+		for _, s := range provider.Shims() {
+			if strings.HasPrefix(shimName, s) {
+				return provider.Name(), MappingFromPrefix
+			}
+		}
+	}
+
+	// Default: use shim name as runtime name
+	return shimName, MappingFromName
+}
+
+// ErrExecutableNotFound is returned when a shim's executable exists neither in the
+// configured version nor on the system PATH.
+var ErrExecutableNotFound = errors.New("executable not found")
+
+// Resolution is the executable a shim should run
+type Resolution struct {
+	// Path is the executable to run
+	Path string
+	// SystemFallback is true when the configured version doesn't contain the
+	// executable and a system installation is used instead
+	SystemFallback bool
+}
+
+// ResolveExecutable finds the executable for shimName given the runtime's base executable.
+// A core shim of the runtime without its own executable in the version (such as python3
+// on Windows, where there is only python.exe) runs the base executable. Otherwise, if
+// the configured version doesn't contain it (for example, a package shim left behind
+// after the version that installed the package was removed), it falls back to an
+// installation found on the system PATH outside the shims directory.
+func ResolveExecutable(execPath, shimName, runtimeName string) (Resolution, error) {
+	if found, ok := findRelatedExecutable(execPath, shimName, runtimeName); ok {
+		return Resolution{Path: found}, nil
+	}
+
+	if isCoreShim(shimName, runtimeName) {
+		if _, err := os.Stat(execPath); err == nil {
+			return Resolution{Path: execPath}, nil
+		}
+	}
+
+	if systemPath := path.LookPathExcludingShims(shimName); systemPath != "" {
+		return Resolution{Path: systemPath, SystemFallback: true}, nil
+	}
+
+	return Resolution{}, fmt.Errorf("%w: %s", ErrExecutableNotFound, shimName)
+}
+
+// isCoreShim reports whether shimName is one of the shims the runtime's provider lists
+func isCoreShim(shimName, runtimeName string) bool {
+	provider, err := runtimepkg.GetShimProvider(runtimeName)
+	if err != nil {
+		return false
+	}
+	for _, s := range provider.Shims() {
+		if s == shimName {
+			return true
+		}
+	}
+	return false
+}
+
+// AdjustExecutablePath adjusts the executable path based on the shim name
+// For example, if shim is "pip" but base executable is "python",
+// we need to find "pip" in the same directory or Scripts subdirectory.
+// If no related executable is found, the original path is returned.
+func AdjustExecutablePath(execPath, shimName, runtimeName string) string {
+	if found, ok := findRelatedExecutable(execPath, shimName, runtimeName); ok {
+		return found
+	}
+	return execPath
+}
+
+// findRelatedExecutable looks for shimName next to the runtime's base executable
+// and reports whether it exists.
+func findRelatedExecutable(execPath, shimName, runtimeName string) (string, bool) {
+	// If shim name matches runtime name, use the path as-is
+	if shimName == runtimeName {
+		_, err := os.Stat(execPath)
+		return execPath, err == nil
+	}
+
+	// Otherwise, try to find the related executable
+	// For example: if execPath is /path/to/python and shimName is pip,
+	// look for /path/to/pip
+	dir := filepath.Dir(execPath)
+
+	// Directories to search (in order)
+	searchDirs := []string{
+		dir,                                 // Same directory as runtime executable
+		filepath.Join(dir, "Scripts"),       // Python Scripts directory (Windows)
+		filepath.Join(dir, "..", "Scripts"), // Alternative Python Scripts location
+	}
+
+	// On Windows, try multiple extensions
+	if runtime.GOOS == constants.OSWindows {
+		for _, searchDir := range searchDirs {
+			newExec := filepath.Join(searchDir, shimName)
+
+			// Try .cmd first (npm, npx use .cmd on Windows)
+			if _, err := os.Stat(newExec + ".cmd"); err == nil {
+				return newExec + ".cmd", true
+			}
+			// Try .exe
+			if _, err := os.Stat(newExec + constants.ExtExe); err == nil {
+				return newExec + constants.ExtExe, true
+			}
+		}
+	} else {
+		// On Unix, check if the file exists as-is
+		for _, searchDir := range searchDirs {
+			newExec := filepath.Join(searchDir, shimName)
+			if _, err := os.Stat(newExec); err == nil {
+				return newExec, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,231 @@
+package shim
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	runtimepkg "github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+func TestNameFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"npm", "npm"},
+		{filepath.Join("shims", "python3"), "python3"},
+		{filepath.Join("shims", "npm.exe"), "npm"},
+		{filepath.Join("shims", "NODE.EXE"), "NODE"},
+		{filepath.Join("shims", "pip.cmd"), "pip.cmd"},
+		{filepath.Join("shims", "python3.11"), "python3.11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := NameFromPath(tt.path); got != tt.want {
+				t.Errorf("NameFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRuntime(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		ResetShimMapCache()
+	})
+
+	providers := []*mockProvider{
+		{name: "rslvpy", shims: []string{"rslvpy", "rslvpip"}},
+		{name: "rslvnode", shims: []string{"rslvnode", "rslvnpm"}},
+	}
+	useTestRegistry(t)
+	for _, p := range providers {
+		if err := runtimepkg.Register(p); err != nil {
+			t.Fatalf("Failed to register provider: %v", err)
+		}
+	}
+
+	if err := SaveShimMap(ShimMap{"rslvtsc": "rslvnode"}); err != nil {
+		t.Fatalf("SaveShimMap() error: %v", err)
+	}
+
+	tests := []struct {
+		shimName    string
+		wantRuntime string
+		wantSource  MappingSource
+	}{
+		{"rslvtsc", "rslvnode", MappingFromCache},
+		{"rslvpip", "rslvpy", MappingFromProvider},
+		{"rslvnpm", "rslvnode", MappingFromProvider},
+		{"rslvpy3", "rslvpy", MappingFromPrefix},
+		{"rslvpip3.12", "rslvpy", MappingFromPrefix},
+		{"unrelated", "unrelated", MappingFromName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shimName, func(t *testing.T) {
+			gotRuntime, gotSource := ResolveRuntime(tt.shimName)
+			if gotRuntime != tt.wantRuntime || gotSource != tt.wantSource {
+				t.Errorf("ResolveRuntime(%q) = (%q, %q), want (%q, %q)",
+					tt.shimName, gotRuntime, gotSource, tt.wantRuntime, tt.wantSource)
+			}
+			if got := MapToRuntime(tt.shimName); got != tt.wantRuntime {
+				t.Errorf("MapToRuntime(%q) = %q, want %q", tt.shimName, got, tt.wantRuntime)
+			}
+		})
+	}
+}
+
+func TestAdjustExecutablePath(t *testing.T) {
+	versionDir := t.TempDir()
+	binDir := filepath.Join(versionDir, "bin")
+	scriptsDir := filepath.Join(versionDir, "Scripts")
+	for _, dir := range []string{binDir, scriptsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	ext := ""
+	if runtime.GOOS == constants.OSWindows {
+		ext = constants.ExtExe
+	}
+	writeExec := func(dir, name string) string {
+		p := filepath.Join(dir, name+ext)
+		if err := os.WriteFile(p, []byte("exec"), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", p, err)
+		}
+		return p
+	}
+
+	python := writeExec(binDir, "python")
+	pip := writeExec(binDir, "pip")
+	black := writeExec(scriptsDir, "black")
+
+	tests := []struct {
+		name     string
+		shimName string
+		want     string
+	}{
+		{"shim matches runtime", "python", python},
+		{"sibling executable", "pip", pip},
+		{"Scripts directory", "black", black},
+		{"missing falls back to base", "eslint", python},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AdjustExecutablePath(python, tt.shimName, "python")
+			if filepath.Clean(got) != filepath.Clean(tt.want) {
+				t.Errorf("AdjustExecutablePath(%q) = %q, want %q", tt.shimName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveExecutable(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", filepath.Join(tmpRoot, "dtvem"))
+
+	ext := ""
+	if runtime.GOOS == constants.OSWindows {
+		ext = constants.ExtExe
+	}
+	writeExec := func(dir, name string) string {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		p := filepath.Join(dir, name+ext)
+		if err := os.WriteFile(p, []byte("exec"), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", p, err)
+		}
+		return p
+	}
+
+	// The configured version has node and npm, but not eslint (its version was uninstalled)
+	binDir := filepath.Join(tmpRoot, "dtvem", "versions", "node", "20.0.0", "bin")
+	node := writeExec(binDir, "node")
+	npm := writeExec(binDir, "npm")
+
+	// A system installation of eslint, plus a stale eslint shim that must be skipped
+	systemDir := filepath.Join(tmpRoot, "usr", "bin")
+	systemEslint := writeExec(systemDir, "eslint")
+	writeExec(filepath.Join(tmpRoot, "dtvem", "shims"), "eslint")
+
+	t.Setenv("PATH", filepath.Join(tmpRoot, "dtvem", "shims")+string(os.PathListSeparator)+systemDir)
+
+	tests := []struct {
+		name         string
+		shimName     string
+		wantPath     string
+		wantFallback bool
+		wantErr      bool
+	}{
+		{name: "runtime executable", shimName: "node", wantPath: node},
+		{name: "sibling executable", shimName: "npm", wantPath: npm},
+		{name: "missing with system fallback", shimName: "eslint", wantPath: systemEslint, wantFallback: true},
+		{name: "missing everywhere", shimName: "prettier", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveExecutable(node, tt.shimName, "node")
+			if tt.wantErr {
+				if !errors.Is(err, ErrExecutableNotFound) {
+					t.Errorf("ResolveExecutable(%q) error = %v, want ErrExecutableNotFound", tt.shimName, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveExecutable(%q) error: %v", tt.shimName, err)
+			}
+			if filepath.Clean(got.Path) != filepath.Clean(tt.wantPath) || got.SystemFallback != tt.wantFallback {
+				t.Errorf("ResolveExecutable(%q) = %+v, want {Path:%s SystemFallback:%v}", tt.shimName, got, tt.wantPath, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestResolveExecutable_CoreShimWithoutOwnExecutable(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", filepath.Join(tmpRoot, "dtvem"))
+	t.Setenv("PATH", "")
+
+	useTestRegistry(t)
+	if err := runtimepkg.Register(&mockProvider{name: "python", shims: []string{"python", "python3", "pip"}}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	// A Windows Python install has python.exe but no python3.exe
+	versionDir := filepath.Join(tmpRoot, "dtvem", "versions", "python", "3.12.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	python := filepath.Join(versionDir, "python.exe")
+	if err := os.WriteFile(python, []byte("exec"), 0755); err != nil {
+		t.Fatalf("Failed to create python.exe: %v", err)
+	}
+
+	got, err := ResolveExecutable(python, "python3", "python")
+	if err != nil {
+		t.Fatalf("ResolveExecutable(python3) error: %v", err)
+	}
+	if got.Path != python || got.SystemFallback {
+		t.Errorf("ResolveExecutable(python3) = %+v, want {Path:%s SystemFallback:false}", got, python)
+	}
+
+	// Executables that aren't shims of the runtime still aren't run as python
+	if _, err := ResolveExecutable(python, "black", "python"); !errors.Is(err, ErrExecutableNotFound) {
+		t.Errorf("ResolveExecutable(black) error = %v, want ErrExecutableNotFound", err)
+	}
+}
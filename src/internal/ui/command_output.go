@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// commandOutputIndent indents a child command's output below dtvem's own messages
+const commandOutputIndent = "    "
+
+// CommandOutput is an io.Writer that shows the output of a child command (such as a
+// package manager) as it runs, one dimmed and indented line at a time. It is safe
+// for concurrent use, so a command's stdout and stderr can share one.
+type CommandOutput struct {
+	mu      sync.Mutex
+	w       io.Writer
+	partial []byte
+}
+
+// NewCommandOutput creates a CommandOutput that prints to the console
+func NewCommandOutput() *CommandOutput {
+	return newCommandOutput(color.Output)
+}
+
+// newCommandOutput creates a CommandOutput that prints to w
+func newCommandOutput(w io.Writer) *CommandOutput {
+	return &CommandOutput{w: w}
+}
+
+// Write prints every complete line of p; an unterminated last line is kept until
+// the rest of it arrives or Flush is called
+func (c *CommandOutput) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.partial = append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(c.partial, '\n')
+		if i < 0 {
+			break
+		}
+		c.printLine(c.partial[:i])
+		c.partial = c.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush prints an unterminated last line, if any
+func (c *CommandOutput) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.partial) > 0 {
+		c.printLine(c.partial)
+		c.partial = nil
+	}
+}
+
+// printLine prints one line of output, dropping a trailing carriage return
+func (c *CommandOutput) printLine(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	_, _ = fmt.Fprintf(c.w, "%s%s\n", commandOutputIndent, DimText(string(line)))
+}
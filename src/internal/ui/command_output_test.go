@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestCommandOutput(t *testing.T) {
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = originalNoColor })
+
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{
+			name:   "complete lines",
+			writes: []string{"added 3 packages\nfound 0 vulnerabilities\n"},
+			want:   "    added 3 packages\n    found 0 vulnerabilities\n",
+		},
+		{
+			name:   "line split across writes",
+			writes: []string{"Successfully ", "installed rails\n"},
+			want:   "    Successfully installed rails\n",
+		},
+		{
+			name:   "unterminated last line is flushed",
+			writes: []string{"Collecting requests\nDownloading"},
+			want:   "    Collecting requests\n    Downloading\n",
+		},
+		{
+			name:   "windows line endings",
+			writes: []string{"done\r\n"},
+			want:   "    done\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			out := newCommandOutput(&buf)
+			for _, w := range tt.writes {
+				if _, err := out.Write([]byte(w)); err != nil {
+					t.Fatalf("Write() error: %v", err)
+				}
+			}
+			out.Flush()
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
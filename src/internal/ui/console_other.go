@@ -0,0 +1,9 @@
+//go:build !windows
+
+package ui
+
+// initConsole prepares the console for output; terminals outside Windows handle
+// colors and UTF-8 without setup. It reports whether Unicode symbols can be used.
+func initConsole() bool {
+	return true
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package ui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// utf8CodePage is the Windows code page identifier for UTF-8
+const utf8CodePage = 65001
+
+// initConsole enables virtual terminal processing so ANSI colors work in the
+// Windows console, and reports whether Unicode symbols can be used.
+func initConsole() bool {
+	stdout := windows.Handle(os.Stdout.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(stdout, &mode); err != nil {
+		// Not a console (redirected to a file or pipe): write UTF-8 as usual
+		return true
+	}
+	_ = windows.SetConsoleMode(stdout, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+
+	codePage, err := windows.GetConsoleOutputCP()
+	if err != nil {
+		return true
+	}
+	return supportsUnicode(codePage, os.Getenv)
+}
+
+// supportsUnicode reports whether a console with the given output code page can
+// display the Unicode symbols. Windows Terminal (which sets WT_SESSION) always can;
+// legacy consoles only when they use the UTF-8 code page.
+func supportsUnicode(codePage uint32, getenv func(string) string) bool {
+	if getenv("WT_SESSION") != "" {
+		return true
+	}
+	return codePage == utf8CodePage
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package ui
+
+import "testing"
+
+func TestSupportsUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		codePage uint32
+		env      map[string]string
+		want     bool
+	}{
+		{"UTF-8 console", utf8CodePage, nil, true},
+		{"legacy code page", 437, nil, false},
+		{"Windows Terminal with legacy code page", 437, map[string]string{"WT_SESSION": "abc"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := supportsUnicode(tt.codePage, getenv); got != tt.want {
+				t.Errorf("supportsUnicode(%d) = %v, want %v", tt.codePage, got, tt.want)
+			}
+		})
+	}
+}
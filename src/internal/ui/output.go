@@ -2,12 +2,16 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/dtvem/dtvem/src/internal/config"
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 )
 
 // Environment variable values
@@ -16,6 +20,27 @@ const (
 	envFalse = "false"
 )
 
+// AutoInstallEnvVar controls whether missing versions are installed without asking.
+// "true" installs automatically, "false" never installs, unset prompts.
+// It overrides the auto-install setting.
+const AutoInstallEnvVar = config.AutoInstallEnvVar
+
+// OutputEnvVar selects the output format for programmatic consumers. With "json",
+// a command failure is written to stdout as a single JSON error envelope, and
+// messages meant for people go to stderr.
+const OutputEnvVar = "DTVEM_OUTPUT"
+
+// ErrorCodeGeneric is the error code for failures without a more specific code
+const ErrorCodeGeneric = "error"
+
+// ErrorDetail is the "error" object of the JSON error envelope
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Runtime string `json:"runtime,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
 var (
 	// Color functions for different message types
 	successColor  = color.New(color.FgGreen, color.Bold)
@@ -25,45 +50,90 @@ var (
 	progressColor = color.New(color.FgBlue)
 	debugColor    = color.New(color.Faint)
 
-	// Symbols
-	successSymbol = "✓"
-	errorSymbol   = "✗"
-	warningSymbol = "⚠"
-	infoSymbol    = "→"
-	debugSymbol   = "·"
+	// Symbols (replaced by ASCII equivalents on consoles that can't display them)
+	successSymbol = unicodeSymbols.success
+	errorSymbol   = unicodeSymbols.error
+	warningSymbol = unicodeSymbols.warning
+	infoSymbol    = unicodeSymbols.info
+	debugSymbol   = unicodeSymbols.debug
 
 	// Verbose mode flag - controls debug output visibility
 	verboseMode = false
+
+	// jsonErrorReported records that the JSON error envelope was written, as only
+	// one is written per run
+	jsonErrorReported = false
+
+	// jsonErrorOutput is where the JSON error envelope is written
+	jsonErrorOutput io.Writer = os.Stdout
 )
 
 // Success prints a success message in green with a checkmark
 func Success(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = successColor.Printf("%s %s\n", successSymbol, message)
+	_, _ = successColor.Fprintf(messageOutput(), "%s %s\n", successSymbol, message)
 }
 
-// Error prints an error message in red with an X
+// Error prints an error message in red with an X. In JSON output mode it reports
+// the message as the JSON error envelope instead.
 func Error(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = errorColor.Printf("%s %s\n", errorSymbol, message)
+	if IsJSONOutput() {
+		ReportJSONError(ErrorDetail{Code: ErrorCodeGeneric, Message: message})
+		return
+	}
+	_, _ = errorColor.Fprintf(messageOutput(), "%s %s\n", errorSymbol, message)
+}
+
+// IsJSONOutput reports whether DTVEM_OUTPUT asks for JSON output
+func IsJSONOutput() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv(OutputEnvVar)), "json")
+}
+
+// ReportJSONError writes the JSON error envelope of a failure to stdout. Only the
+// first error reported becomes the envelope, so stdout holds a single JSON
+// document; later ones are printed to stderr as plain messages.
+func ReportJSONError(detail ErrorDetail) {
+	if jsonErrorReported {
+		_, _ = errorColor.Fprintf(messageOutput(), "%s %s\n", errorSymbol, detail.Message)
+		return
+	}
+	jsonErrorReported = true
+	_ = WriteJSONError(jsonErrorOutput, detail)
+}
+
+// messageOutput is where messages for people are written: stdout, or stderr in
+// JSON output mode so they don't get mixed into the JSON on stdout
+func messageOutput() io.Writer {
+	if IsJSONOutput() {
+		return color.Error
+	}
+	return color.Output
+}
+
+// WriteJSONError writes {"error": detail} to w as a single line
+func WriteJSONError(w io.Writer, detail ErrorDetail) error {
+	return json.NewEncoder(w).Encode(struct {
+		Error ErrorDetail `json:"error"`
+	}{detail})
 }
 
 // Warning prints a warning message in yellow with a warning symbol
 func Warning(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = warningColor.Printf("%s %s\n", warningSymbol, message)
+	_, _ = warningColor.Fprintf(messageOutput(), "%s %s\n", warningSymbol, message)
 }
 
 // Info prints an info message in cyan with an arrow
 func Info(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = infoColor.Printf("%s %s\n", infoSymbol, message)
+	_, _ = infoColor.Fprintf(messageOutput(), "%s %s\n", infoSymbol, message)
 }
 
 // Progress prints a progress message in blue with an arrow
 func Progress(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = progressColor.Printf("  %s %s\n", infoSymbol, message)
+	_, _ = progressColor.Fprintf(messageOutput(), "  %s %s\n", infoSymbol, message)
 }
 
 // Debug prints a debug message only when verbose mode is enabled
@@ -74,7 +144,7 @@ func Debug(format string, args ...interface{}) {
 	}
 	message := fmt.Sprintf(format, args...)
 	timestamp := time.Now().Format("15:04:05.000")
-	_, _ = debugColor.Printf("%s %s %s\n", debugSymbol, timestamp, message)
+	_, _ = debugColor.Fprintf(messageOutput(), "%s %s %s\n", debugSymbol, timestamp, message)
 }
 
 // Debugf is an alias for Debug (for consistency with fmt.Printf naming)
@@ -140,18 +210,16 @@ func DimText(text string) string {
 
 // PromptInstall prompts the user to install a missing version.
 // Returns true if the user wants to install, false otherwise.
-// Respects DTVEM_AUTO_INSTALL environment variable:
+// Respects DTVEM_AUTO_INSTALL environment variable (or the auto-install setting):
 //   - "true": auto-install without prompting
 //   - "false": never prompt, return false
 //   - unset: prompt interactively
 func PromptInstall(displayName, version string) bool {
-	// Check if running in non-interactive mode (CI/automation)
-	if os.Getenv("DTVEM_AUTO_INSTALL") == envFalse {
+	// DTVEM_AUTO_INSTALL (or the auto-install setting) skips the prompt for CI/automation
+	switch config.Setting(config.SettingAutoInstall) {
+	case envFalse:
 		return false
-	}
-
-	// If DTVEM_AUTO_INSTALL=true, auto-install without prompting
-	if os.Getenv("DTVEM_AUTO_INSTALL") == envTrue {
+	case envTrue:
 		return true
 	}
 
@@ -166,6 +234,12 @@ func PromptInstall(displayName, version string) bool {
 	return response == "" || response == "y" || response == "yes"
 }
 
+// IsInteractive reports whether stdin is a terminal, i.e. a prompt can be answered.
+func IsInteractive() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
 // MissingRuntime represents a runtime that needs to be installed
 type MissingRuntime interface {
 	DisplayName() string
@@ -180,13 +254,11 @@ func PromptInstallMissing[T any](missing []T) bool {
 		return false
 	}
 
-	// Check if running in non-interactive mode (CI/automation)
-	if os.Getenv("DTVEM_AUTO_INSTALL") == envFalse {
+	// DTVEM_AUTO_INSTALL (or the auto-install setting) skips the prompt for CI/automation
+	switch config.Setting(config.SettingAutoInstall) {
+	case envFalse:
 		return false
-	}
-
-	// If DTVEM_AUTO_INSTALL=true, auto-install without prompting
-	if os.Getenv("DTVEM_AUTO_INSTALL") == envTrue {
+	case envTrue:
 		return true
 	}
 
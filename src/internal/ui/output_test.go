@@ -223,3 +223,85 @@ func TestDebugOutput(t *testing.T) {
 	// Restore original state
 	verboseMode = originalVerbose
 }
+
+func TestPromptInstall_AutoInstallEnv(t *testing.T) {
+	t.Setenv(AutoInstallEnvVar, "true")
+	if !PromptInstall("Node.js", "20.0.0") {
+		t.Errorf("PromptInstall() should return true when %s=true", AutoInstallEnvVar)
+	}
+
+	t.Setenv(AutoInstallEnvVar, "false")
+	if PromptInstall("Node.js", "20.0.0") {
+		t.Errorf("PromptInstall() should return false when %s=false", AutoInstallEnvVar)
+	}
+}
+
+func TestSelectSymbols(t *testing.T) {
+	if got := selectSymbols(true); got != unicodeSymbols {
+		t.Errorf("selectSymbols(true) = %+v, want Unicode symbols", got)
+	}
+
+	ascii := selectSymbols(false)
+	if ascii != asciiSymbols {
+		t.Errorf("selectSymbols(false) = %+v, want ASCII symbols", ascii)
+	}
+	for _, symbol := range []string{ascii.success, ascii.error, ascii.warning, ascii.info, ascii.debug} {
+		for _, r := range symbol {
+			if r > 127 {
+				t.Errorf("ASCII symbol %q contains non-ASCII character %q", symbol, r)
+			}
+		}
+	}
+}
+
+func TestIsJSONOutput(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"json", true},
+		{"JSON", true},
+		{" json ", true},
+		{"text", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(OutputEnvVar, tt.value)
+			if got := IsJSONOutput(); got != tt.want {
+				t.Errorf("IsJSONOutput() with %s=%q = %v, want %v", OutputEnvVar, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONError(t *testing.T) {
+	var buf strings.Builder
+	if err := WriteJSONError(&buf, ErrorDetail{Code: ErrorCodeGeneric, Message: "something failed"}); err != nil {
+		t.Fatalf("WriteJSONError() error: %v", err)
+	}
+
+	want := `{"error":{"code":"error","message":"something failed"}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("WriteJSONError() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestError_JSONOutputWritesOneEnvelope(t *testing.T) {
+	t.Setenv(OutputEnvVar, "json")
+	var buf strings.Builder
+	originalOutput, originalReported := jsonErrorOutput, jsonErrorReported
+	jsonErrorOutput, jsonErrorReported = &buf, false
+	t.Cleanup(func() { jsonErrorOutput, jsonErrorReported = originalOutput, originalReported })
+
+	// A failure that is reported more than once still produces a single JSON document
+	Error("download failed")
+	ReportJSONError(ErrorDetail{Code: "version_not_available", Message: "not available"})
+	Error("cleanup failed")
+
+	want := `{"error":{"code":"error","message":"download failed"}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("JSON output = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ProgressPhase is the stage a ProgressLine is in
+type ProgressPhase int
+
+const (
+	// ProgressPending means neither the download nor the extraction has started
+	ProgressPending ProgressPhase = iota
+	// ProgressDownloading means the archive is being downloaded
+	ProgressDownloading
+	// ProgressExtracting means the archive is being extracted
+	ProgressExtracting
+	// ProgressDone means the line resolved to a success message
+	ProgressDone
+	// ProgressFailed means the line resolved to an error message
+	ProgressFailed
+)
+
+// ProgressLine shows the download and extraction of an archive on a single line.
+// While downloading it shows the throughput and, when the size is known, the ETA;
+// while extracting it counts the extracted files. It resolves to one success or
+// error line. Phases only move forward: updates for an earlier phase are ignored.
+type ProgressLine struct {
+	mu           sync.Mutex
+	phase        ProgressPhase
+	current      int64
+	total        int64
+	files        int
+	phaseStarted time.Time
+	now          func() time.Time
+	spinner      *Spinner
+}
+
+// NewProgressLine creates a progress line; call Start to display it
+func NewProgressLine() *ProgressLine {
+	p := newProgressLine(time.Now)
+	p.spinner = NewSpinner(p.Message())
+	return p
+}
+
+// newProgressLine creates a progress line that isn't displayed, using now as its clock
+func newProgressLine(now func() time.Time) *ProgressLine {
+	return &ProgressLine{now: now, phaseStarted: now()}
+}
+
+// Start displays the progress line
+func (p *ProgressLine) Start() {
+	if p.spinner != nil {
+		p.spinner.Start()
+	}
+}
+
+// Download records download progress. total is -1 (or 0) when the size is unknown.
+// Its signature matches the progress callback of download.FileWithProgress.
+func (p *ProgressLine) Download(current, total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.phase {
+	case ProgressPending:
+		p.enter(ProgressDownloading)
+	case ProgressDownloading:
+	default:
+		return
+	}
+	p.current = current
+	p.total = total
+	p.render()
+}
+
+// Extract moves the line to the extraction phase
+func (p *ProgressLine) Extract() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.phase != ProgressPending && p.phase != ProgressDownloading {
+		return
+	}
+	p.enter(ProgressExtracting)
+	p.render()
+}
+
+// FileExtracted counts an extracted file
+func (p *ProgressLine) FileExtracted() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.phase != ProgressExtracting {
+		return
+	}
+	p.files++
+	p.render()
+}
+
+// Success resolves the line to a success message
+func (p *ProgressLine) Success(message string) {
+	p.finish(ProgressDone, message)
+}
+
+// Error resolves the line to an error message
+func (p *ProgressLine) Error(message string) {
+	p.finish(ProgressFailed, message)
+}
+
+// Phase returns the current phase
+func (p *ProgressLine) Phase() ProgressPhase {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.phase
+}
+
+// Message returns the text currently shown on the line
+func (p *ProgressLine) Message() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.message()
+}
+
+// finish moves the line to a final phase and prints message
+func (p *ProgressLine) finish(phase ProgressPhase, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.phase == ProgressDone || p.phase == ProgressFailed {
+		return
+	}
+	p.enter(phase)
+
+	if p.spinner == nil {
+		return
+	}
+	if phase == ProgressDone {
+		p.spinner.Success(message)
+	} else {
+		p.spinner.Error(message)
+	}
+}
+
+// enter switches to a phase and restarts the phase timer
+func (p *ProgressLine) enter(phase ProgressPhase) {
+	p.phase = phase
+	p.phaseStarted = p.now()
+}
+
+// render updates the displayed message
+func (p *ProgressLine) render() {
+	if p.spinner != nil {
+		p.spinner.UpdateMessage(p.message())
+	}
+}
+
+// message formats the text for the current phase
+func (p *ProgressLine) message() string {
+	switch p.phase {
+	case ProgressDownloading:
+		elapsed := p.now().Sub(p.phaseStarted).Seconds()
+		var rate float64
+		if elapsed > 0 {
+			rate = float64(p.current) / elapsed
+		}
+
+		if p.total <= 0 {
+			return fmt.Sprintf("Downloading %s (%s/s)", FormatBytes(p.current), FormatBytes(int64(rate)))
+		}
+
+		eta := "unknown"
+		if rate > 0 {
+			remaining := time.Duration(float64(p.total-p.current) / rate * float64(time.Second))
+			eta = remaining.Round(time.Second).String()
+		}
+		return fmt.Sprintf("Downloading %s / %s (%s/s, ETA %s)",
+			FormatBytes(p.current), FormatBytes(p.total), FormatBytes(int64(rate)), eta)
+
+	case ProgressExtracting:
+		if p.files == 0 {
+			return "Extracting archive..."
+		}
+		return fmt.Sprintf("Extracting archive... %d files", p.files)
+
+	default:
+		return "Preparing download..."
+	}
+}
+
+// FormatBytes formats a byte count for display (e.g. "12.3 MB")
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
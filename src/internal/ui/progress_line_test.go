@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when advanced
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestProgressLine() (*ProgressLine, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return newProgressLine(clock.Now), clock
+}
+
+func TestProgressLine_Transitions(t *testing.T) {
+	p, clock := newTestProgressLine()
+	if p.Phase() != ProgressPending {
+		t.Fatalf("initial phase = %v, want pending", p.Phase())
+	}
+
+	clock.Advance(time.Second)
+	p.Download(0, 4*1024*1024)
+	clock.Advance(2 * time.Second)
+	p.Download(2*1024*1024, 4*1024*1024)
+	if p.Phase() != ProgressDownloading {
+		t.Fatalf("phase after Download = %v, want downloading", p.Phase())
+	}
+	if got, want := p.Message(), "Downloading 2.0 MB / 4.0 MB (1.0 MB/s, ETA 2s)"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+
+	p.Extract()
+	if got, want := p.Message(), "Extracting archive..."; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	p.FileExtracted()
+	p.FileExtracted()
+	p.FileExtracted()
+	if got, want := p.Message(), "Extracting archive... 3 files"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+
+	// Late download updates don't move the line back
+	p.Download(4*1024*1024, 4*1024*1024)
+	if p.Phase() != ProgressExtracting {
+		t.Errorf("phase after late Download = %v, want extracting", p.Phase())
+	}
+
+	p.Success("Installed")
+	if p.Phase() != ProgressDone {
+		t.Fatalf("phase after Success = %v, want done", p.Phase())
+	}
+
+	// A resolved line stays resolved
+	p.Error("Failed")
+	p.Extract()
+	p.FileExtracted()
+	if p.Phase() != ProgressDone {
+		t.Errorf("phase after updates to a resolved line = %v, want done", p.Phase())
+	}
+}
+
+func TestProgressLine_ExtractWithoutDownload(t *testing.T) {
+	// A cached archive goes straight to extraction
+	p, _ := newTestProgressLine()
+	p.FileExtracted()
+	p.Extract()
+	p.FileExtracted()
+
+	if got, want := p.Message(), "Extracting archive... 1 files"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+}
+
+func TestProgressLine_Error(t *testing.T) {
+	p, _ := newTestProgressLine()
+	p.Download(100, 1000)
+	p.Error("Download failed")
+	p.Success("Installed")
+
+	if p.Phase() != ProgressFailed {
+		t.Errorf("phase = %v, want failed", p.Phase())
+	}
+}
+
+func TestProgressLine_DownloadMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int64
+		total   int64
+		elapsed time.Duration
+		want    string
+	}{
+		{"unknown size", 3 * 1024 * 1024, -1, 3 * time.Second, "Downloading 3.0 MB (1.0 MB/s)"},
+		{"no bytes yet", 0, 2048, time.Second, "Downloading 0 B / 2.0 KB (0 B/s, ETA unknown)"},
+		{"complete", 2048, 2048, 2 * time.Second, "Downloading 2.0 KB / 2.0 KB (1.0 KB/s, ETA 0s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, clock := newTestProgressLine()
+			p.Download(0, tt.total)
+			clock.Advance(tt.elapsed)
+			p.Download(tt.current, tt.total)
+
+			if got := p.Message(); got != tt.want {
+				t.Errorf("Message() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{45 * 1024 * 1024, "45.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.bytes); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
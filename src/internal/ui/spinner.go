@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"os"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -13,12 +14,19 @@ type Spinner struct {
 
 // NewSpinner creates a new spinner with a message
 func NewSpinner(message string) *Spinner {
-	s := spinner.New(
-		spinner.CharSets[14], // dots style
-		100*time.Millisecond,
+	charSet := spinner.CharSets[14] // dots style
+	if !unicodeConsole {
+		charSet = spinner.CharSets[9] // | / - \
+	}
+
+	options := []spinner.Option{
 		spinner.WithColor("cyan"),
-		spinner.WithSuffix(" "+message),
-	)
+		spinner.WithSuffix(" " + message),
+	}
+	if IsJSONOutput() {
+		options = append(options, spinner.WithWriterFile(os.Stderr))
+	}
+	s := spinner.New(charSet, 100*time.Millisecond, options...)
 	return &Spinner{spinner: s}
 }
 
@@ -35,24 +43,26 @@ func (s *Spinner) Stop() {
 // Success stops the spinner and shows a success message
 func (s *Spinner) Success(message string) {
 	s.spinner.Stop()
-	_, _ = successColor.Printf("%s %s\n", successSymbol, message)
+	_, _ = successColor.Fprintf(messageOutput(), "%s %s\n", successSymbol, message)
 }
 
 // Error stops the spinner and shows an error message
 func (s *Spinner) Error(message string) {
 	s.spinner.Stop()
-	_, _ = errorColor.Printf("%s %s\n", errorSymbol, message)
+	_, _ = errorColor.Fprintf(messageOutput(), "%s %s\n", errorSymbol, message)
 }
 
 // Warning stops the spinner and shows a warning message
 func (s *Spinner) Warning(message string) {
 	s.spinner.Stop()
-	_, _ = warningColor.Printf("%s %s\n", warningSymbol, message)
+	_, _ = warningColor.Fprintf(messageOutput(), "%s %s\n", warningSymbol, message)
 }
 
 // UpdateMessage updates the spinner message while it's running
 func (s *Spinner) UpdateMessage(message string) {
+	s.spinner.Lock()
 	s.spinner.Suffix = " " + message
+	s.spinner.Unlock()
 }
 
 // WithSpinner runs a function with a spinner
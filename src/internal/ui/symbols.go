@@ -0,0 +1,54 @@
+package ui
+
+// symbolSet holds the symbols that prefix each kind of message
+type symbolSet struct {
+	success string
+	error   string
+	warning string
+	info    string
+	debug   string
+}
+
+// unicodeSymbols are used on terminals that can display them
+var unicodeSymbols = symbolSet{
+	success: "✓",
+	error:   "✗",
+	warning: "⚠",
+	info:    "→",
+	debug:   "·",
+}
+
+// asciiSymbols are used on legacy consoles that would render Unicode as mojibake
+var asciiSymbols = symbolSet{
+	success: "[OK]",
+	error:   "[X]",
+	warning: "[!]",
+	info:    "->",
+	debug:   "-",
+}
+
+// selectSymbols returns the symbol set to use for a console
+func selectSymbols(unicode bool) symbolSet {
+	if unicode {
+		return unicodeSymbols
+	}
+	return asciiSymbols
+}
+
+// useSymbols switches the message symbols to the given set
+func useSymbols(symbols symbolSet) {
+	successSymbol = symbols.success
+	errorSymbol = symbols.error
+	warningSymbol = symbols.warning
+	infoSymbol = symbols.info
+	debugSymbol = symbols.debug
+}
+
+// unicodeConsole reports whether the console can display the Unicode symbols.
+// It is set up once at startup by initConsole.
+var unicodeConsole = true
+
+func init() {
+	unicodeConsole = initConsole()
+	useSymbols(selectSymbols(unicodeConsole))
+}
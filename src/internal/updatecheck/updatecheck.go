@@ -0,0 +1,197 @@
+// Package updatecheck looks for newer dtvem releases, at most once per day.
+// Nothing is sent besides the request for the latest release.
+package updatecheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// DisableEnvVar turns off the update check when set to "true" or "1".
+// It overrides the no-update-check setting.
+const DisableEnvVar = config.NoUpdateCheckEnvVar
+
+// DefaultReleaseURL is the GitHub API endpoint for the latest dtvem release
+const DefaultReleaseURL = "https://api.github.com/repos/dtvem/dtvem/releases/latest"
+
+// DefaultInterval is how long a check result is reused before checking again
+const DefaultInterval = 24 * time.Hour
+
+// DefaultTimeout bounds the network request so a slow connection never holds up a command
+const DefaultTimeout = 1500 * time.Millisecond
+
+// StateFileName is the name of the file in the cache directory that records the last check
+const StateFileName = "update-check.json"
+
+// ReleasesURL is where users can download new releases
+const ReleasesURL = "https://github.com/dtvem/dtvem/releases/latest"
+
+// state is the last check result stored in the cache directory
+type state struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	LatestVersion string    `json:"latest_version,omitempty"`
+}
+
+// Checker checks for the latest release, caching the result on disk
+type Checker struct {
+	// StatePath is the file that records the last check
+	StatePath string
+	// Interval is how long a recorded result is reused
+	Interval time.Duration
+	// Fetch returns the latest release version from the network
+	Fetch func(ctx context.Context) (string, error)
+	// Now returns the current time
+	Now func() time.Time
+}
+
+// NewChecker creates a Checker that queries GitHub and stores its state in the cache directory
+func NewChecker() *Checker {
+	return &Checker{
+		StatePath: filepath.Join(config.DefaultPaths().Cache, StateFileName),
+		Interval:  DefaultInterval,
+		Fetch:     fetchLatestRelease,
+		Now:       time.Now,
+	}
+}
+
+// Latest returns the latest release version. A result recorded within the check
+// interval is returned without touching the network. Otherwise the network is
+// queried and the result recorded; a failed query is also recorded so it isn't
+// retried on every command, and the previously known version is returned.
+func (c *Checker) Latest(ctx context.Context) (string, error) {
+	previous, _ := c.readState()
+	if !previous.CheckedAt.IsZero() && c.Now().Sub(previous.CheckedAt) < c.Interval {
+		return previous.LatestVersion, nil
+	}
+
+	latest, err := c.Fetch(ctx)
+	if err != nil {
+		_ = c.writeState(state{CheckedAt: c.Now(), LatestVersion: previous.LatestVersion})
+		return previous.LatestVersion, err
+	}
+
+	if err := c.writeState(state{CheckedAt: c.Now(), LatestVersion: latest}); err != nil {
+		return latest, err
+	}
+	return latest, nil
+}
+
+// readState reads the last check result
+func (c *Checker) readState() (state, error) {
+	var s state
+	data, err := os.ReadFile(c.StatePath)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// writeState records a check result
+func (c *Checker) writeState(s state) error {
+	if err := os.MkdirAll(filepath.Dir(c.StatePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.StatePath, data, 0644)
+}
+
+// Enabled reports whether update checks should run for the given build version.
+// Development builds and users who turned on no-update-check are never checked.
+func Enabled(currentVersion string) bool {
+	if disabled := config.Setting(config.SettingNoUpdateCheck); disabled == "true" || disabled == "1" {
+		return false
+	}
+	return currentVersion != "" && currentVersion != "dev"
+}
+
+// Notice returns a message if latest is newer than current, or an empty string
+func Notice(current, latest string) string {
+	if latest == "" || runtime.CompareVersions(latest, current) <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("A new version of dtvem is available: %s (current: %s)\nDownload it from %s",
+		latest, strings.TrimPrefix(current, "v"), ReleasesURL)
+}
+
+// Start begins an update check in the background and returns a function that
+// yields the notice to print, if any. The returned function waits no longer than
+// the network timeout, so the command is never held up by a slow connection.
+// It returns nil when update checks are disabled.
+func Start(currentVersion string) func() string {
+	if !Enabled(currentVersion) {
+		return nil
+	}
+
+	checker := NewChecker()
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	result := make(chan string, 1)
+
+	go func() {
+		latest, _ := checker.Latest(ctx)
+		result <- latest
+	}()
+
+	return func() string {
+		defer cancel()
+		return awaitNotice(ctx, result, currentVersion)
+	}
+}
+
+// awaitNotice returns the notice for the check's result, waiting for it until ctx is
+// done. A result that is already there is always used, even once ctx has expired:
+// by the time a command finishes, the deadline has often passed.
+func awaitNotice(ctx context.Context, result <-chan string, currentVersion string) string {
+	select {
+	case latest := <-result:
+		return Notice(currentVersion, latest)
+	default:
+	}
+
+	select {
+	case latest := <-result:
+		return Notice(currentVersion, latest)
+	case <-ctx.Done():
+		return ""
+	}
+}
+
+// fetchLatestRelease queries GitHub for the latest release tag
+func fetchLatestRelease(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, DefaultReleaseURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to check for updates: HTTP %d", resp.StatusCode)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to parse release information: %w", err)
+	}
+
+	return strings.TrimPrefix(release.TagName, "v"), nil
+}
@@ -0,0 +1,165 @@
+package updatecheck
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+)
+
+// newTestChecker returns a Checker with a fake clock and a fetch that counts network calls
+func newTestChecker(t *testing.T, latest string, fetchErr error) (*Checker, *int, *time.Time) {
+	t.Helper()
+	calls := 0
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	checker := &Checker{
+		StatePath: filepath.Join(t.TempDir(), StateFileName),
+		Interval:  DefaultInterval,
+		Fetch: func(ctx context.Context) (string, error) {
+			calls++
+			return latest, fetchErr
+		},
+		Now: func() time.Time { return now },
+	}
+	return checker, &calls, &now
+}
+
+func TestChecker_SkipsNetworkWhenRecentlyChecked(t *testing.T) {
+	checker, calls, now := newTestChecker(t, "1.2.0", nil)
+
+	got, err := checker.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if got != "1.2.0" || *calls != 1 {
+		t.Fatalf("first Latest() = %q after %d fetches, want %q after 1", got, *calls, "1.2.0")
+	}
+
+	*now = now.Add(time.Hour)
+	got, err = checker.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if got != "1.2.0" {
+		t.Errorf("cached Latest() = %q, want %q", got, "1.2.0")
+	}
+	if *calls != 1 {
+		t.Errorf("Latest() within the interval fetched %d times, want 1", *calls)
+	}
+}
+
+func TestChecker_ChecksAgainAfterInterval(t *testing.T) {
+	checker, calls, now := newTestChecker(t, "1.2.0", nil)
+
+	if _, err := checker.Latest(context.Background()); err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+
+	*now = now.Add(DefaultInterval + time.Minute)
+	if _, err := checker.Latest(context.Background()); err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Latest() after the interval fetched %d times, want 2", *calls)
+	}
+}
+
+func TestChecker_FailedCheckIsNotRetriedImmediately(t *testing.T) {
+	checker, calls, now := newTestChecker(t, "", errors.New("offline"))
+
+	if _, err := checker.Latest(context.Background()); err == nil {
+		t.Fatal("Latest() should return the fetch error")
+	}
+
+	*now = now.Add(time.Minute)
+	if _, err := checker.Latest(context.Background()); err != nil {
+		t.Errorf("Latest() within the interval should not fetch, got error: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("Latest() fetched %d times after a failure, want 1", *calls)
+	}
+}
+
+func TestNotice(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    string
+		latest     string
+		wantNotice bool
+	}{
+		{"newer release", "1.0.0", "1.1.0", true},
+		{"current has v prefix", "v1.0.0", "1.0.1", true},
+		{"same version", "1.1.0", "1.1.0", false},
+		{"older release", "1.2.0", "1.1.0", false},
+		{"unknown latest", "1.0.0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Notice(tt.current, tt.latest); (got != "") != tt.wantNotice {
+				t.Errorf("Notice(%q, %q) = %q, want notice: %v", tt.current, tt.latest, got, tt.wantNotice)
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	config.ResetSettingsCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		config.ResetSettingsCache()
+	})
+
+	t.Setenv(DisableEnvVar, "")
+	if Enabled("dev") {
+		t.Error("Enabled(dev) = true, development builds should not check")
+	}
+
+	tests := []struct {
+		disable string
+		want    bool
+	}{
+		{"", true},
+		{"1", false},
+		{"true", false},
+		{"false", true},
+		{"0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.disable, func(t *testing.T) {
+			t.Setenv(DisableEnvVar, tt.disable)
+			if got := Enabled("1.0.0"); got != tt.want {
+				t.Errorf("Enabled() with %s=%q = %v, want %v", DisableEnvVar, tt.disable, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAwaitNotice_UsesReadyResultAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Both the result and the expired deadline are ready; the result must win every time
+	for i := 0; i < 100; i++ {
+		result := make(chan string, 1)
+		result <- "1.1.0"
+		if got := awaitNotice(ctx, result, "1.0.0"); got == "" {
+			t.Fatal("awaitNotice() = \"\", want the notice for the ready result")
+		}
+	}
+}
+
+func TestAwaitNotice_GivesUpAtDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if got := awaitNotice(ctx, make(chan string), "1.0.0"); got != "" {
+		t.Errorf("awaitNotice() = %q, want no notice without a result", got)
+	}
+}
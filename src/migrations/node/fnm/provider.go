@@ -33,6 +33,15 @@ func (p *Provider) Runtime() string {
 	return "node"
 }
 
+// fnmDirs returns the directories fnm stores versions in, which vary by platform
+func fnmDirs(home string) []string {
+	return []string{
+		filepath.Join(home, ".local", "share", "fnm", "node-versions"),
+		filepath.Join(home, ".fnm", "node-versions"),
+		filepath.Join(home, "Library", "Application Support", "fnm", "node-versions"), // macOS
+	}
+}
+
 // IsPresent checks if fnm is installed on the system.
 func (p *Provider) IsPresent() bool {
 	home, err := os.UserHomeDir()
@@ -40,14 +49,7 @@ func (p *Provider) IsPresent() bool {
 		return false
 	}
 
-	// fnm stores versions in various locations
-	fnmDirs := []string{
-		filepath.Join(home, ".local", "share", "fnm", "node-versions"),
-		filepath.Join(home, ".fnm", "node-versions"),
-		filepath.Join(home, "Library", "Application Support", "fnm", "node-versions"), // macOS
-	}
-
-	for _, fnmDir := range fnmDirs {
+	for _, fnmDir := range fnmDirs(home) {
 		if _, err := os.Stat(fnmDir); err == nil {
 			return true
 		}
@@ -65,14 +67,7 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 		return detected, nil //nolint:nilerr // Expected: no home dir means no fnm
 	}
 
-	// fnm stores versions in ~/.local/share/fnm/node-versions or similar
-	fnmDirs := []string{
-		filepath.Join(home, ".local", "share", "fnm", "node-versions"),
-		filepath.Join(home, ".fnm", "node-versions"),
-		filepath.Join(home, "Library", "Application Support", "fnm", "node-versions"), // macOS
-	}
-
-	for _, fnmDir := range fnmDirs {
+	for _, fnmDir := range fnmDirs(home) {
 		if entries, err := os.ReadDir(fnmDir); err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() {
@@ -107,6 +102,15 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	return detected, nil
 }
 
+// SourcePaths returns fnm's version directories.
+func (p *Provider) SourcePaths() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return fnmDirs(home)
+}
+
 // CanAutoUninstall returns true because fnm supports automatic uninstall.
 func (p *Provider) CanAutoUninstall() bool {
 	return true
@@ -114,6 +114,45 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	return detected, nil
 }
 
+// maxAliasDepth limits how many nvm aliases are followed when resolving the default
+const maxAliasDepth = 5
+
+// DefaultVersion returns the version of nvm's "default" alias. Aliases that point at
+// other aliases (e.g. "lts/iron") are followed; "node" and unresolvable aliases are ignored.
+func (p *Provider) DefaultVersion() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+
+	aliasDir := filepath.Join(home, ".nvm", "alias")
+	alias := "default"
+	for i := 0; i < maxAliasDepth; i++ {
+		value, ok := migration.ReadVersionFile(filepath.Join(aliasDir, filepath.FromSlash(alias)))
+		if !ok {
+			return "", false
+		}
+		if value != "" && value[0] >= '0' && value[0] <= '9' {
+			return value, true
+		}
+		// Named aliases are files too, e.g. alias/lts/iron
+		alias = value
+	}
+	return "", false
+}
+
+// SourcePaths returns the directories nvm installs versions in.
+func (p *Provider) SourcePaths() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(home, ".nvm", "versions", "node"),
+		filepath.Join(home, "AppData", "Roaming", "nvm"),
+	}
+}
+
 // CanAutoUninstall returns true because nvm supports automatic uninstall.
 func (p *Provider) CanAutoUninstall() bool {
 	return true
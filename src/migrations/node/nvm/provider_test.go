@@ -1,6 +1,8 @@
 package nvm
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -97,3 +99,67 @@ func containsSubstringHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestProvider_DefaultVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases map[string]string
+		want    string
+		wantOK  bool
+	}{
+		{
+			name:    "version alias",
+			aliases: map[string]string{"default": "v20.11.1\n"},
+			want:    "20.11.1",
+			wantOK:  true,
+		},
+		{
+			name:    "partial version",
+			aliases: map[string]string{"default": "20\n"},
+			want:    "20",
+			wantOK:  true,
+		},
+		{
+			name: "alias to lts",
+			aliases: map[string]string{
+				"default":  "lts/iron\n",
+				"lts/iron": "v20.11.1\n",
+			},
+			want:   "20.11.1",
+			wantOK: true,
+		},
+		{
+			name:    "unresolvable alias",
+			aliases: map[string]string{"default": "node\n"},
+			wantOK:  false,
+		},
+		{
+			name:    "no default",
+			aliases: map[string]string{},
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+
+			for alias, content := range tt.aliases {
+				path := filepath.Join(home, ".nvm", "alias", filepath.FromSlash(alias))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create alias dir: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write alias: %v", err)
+				}
+			}
+
+			got, ok := NewProvider().DefaultVersion()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("DefaultVersion() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
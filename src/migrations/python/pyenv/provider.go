@@ -118,6 +118,28 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	return detected, nil
 }
 
+// DefaultVersion returns the global version from pyenv's version file.
+func (p *Provider) DefaultVersion() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+
+	return migration.ReadVersionFile(filepath.Join(home, ".pyenv", "version"))
+}
+
+// SourcePaths returns the directories pyenv installs versions in.
+func (p *Provider) SourcePaths() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(home, ".pyenv", "versions"),
+		filepath.Join(home, ".pyenv", "pyenv-win", "versions"),
+	}
+}
+
 // CanAutoUninstall returns true because pyenv supports automatic uninstall.
 func (p *Provider) CanAutoUninstall() bool {
 	return true
@@ -33,6 +33,14 @@ func (p *Provider) Runtime() string {
 	return "ruby"
 }
 
+// chrubyDirs returns the directories chruby looks for rubies in
+func chrubyDirs(home string) []string {
+	return []string{
+		"/opt/rubies",
+		filepath.Join(home, ".rubies"),
+	}
+}
+
 // IsPresent checks if chruby is installed on the system.
 func (p *Provider) IsPresent() bool {
 	home, err := os.UserHomeDir()
@@ -40,13 +48,7 @@ func (p *Provider) IsPresent() bool {
 		return false
 	}
 
-	// chruby looks in /opt/rubies and ~/.rubies
-	chrubyDirs := []string{
-		"/opt/rubies",
-		filepath.Join(home, ".rubies"),
-	}
-
-	for _, chrubyDir := range chrubyDirs {
+	for _, chrubyDir := range chrubyDirs(home) {
 		if _, err := os.Stat(chrubyDir); err == nil {
 			return true
 		}
@@ -67,13 +69,7 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	// chruby uses ruby-X.Y.Z format for directory names
 	versionRegex := regexp.MustCompile(`^ruby-(\d+\.\d+\.\d+)`)
 
-	// chruby looks in /opt/rubies and ~/.rubies
-	chrubyDirs := []string{
-		"/opt/rubies",
-		filepath.Join(home, ".rubies"),
-	}
-
-	for _, chrubyDir := range chrubyDirs {
+	for _, chrubyDir := range chrubyDirs(home) {
 		if entries, err := os.ReadDir(chrubyDir); err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() {
@@ -99,6 +95,15 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	return detected, nil
 }
 
+// SourcePaths returns the directories chruby looks for rubies in.
+func (p *Provider) SourcePaths() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return chrubyDirs(home)
+}
+
 // CanAutoUninstall returns false because chruby doesn't have a built-in uninstall command.
 func (p *Provider) CanAutoUninstall() bool {
 	return false
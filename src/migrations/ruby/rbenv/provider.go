@@ -81,6 +81,27 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	return detected, nil
 }
 
+// DefaultVersion returns the global version from rbenv's version file.
+func (p *Provider) DefaultVersion() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+
+	return migration.ReadVersionFile(filepath.Join(home, ".rbenv", "version"))
+}
+
+// SourcePaths returns the directory rbenv installs versions in.
+func (p *Provider) SourcePaths() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(home, ".rbenv", "versions"),
+	}
+}
+
 // CanAutoUninstall returns true because rbenv supports automatic uninstall.
 func (p *Provider) CanAutoUninstall() bool {
 	return true
@@ -85,6 +85,17 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	return detected, nil
 }
 
+// SourcePaths returns the directory rvm installs versions in.
+func (p *Provider) SourcePaths() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(home, ".rvm", "rubies"),
+	}
+}
+
 // CanAutoUninstall returns true because rvm supports automatic uninstall.
 func (p *Provider) CanAutoUninstall() bool {
 	return true
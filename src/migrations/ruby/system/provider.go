@@ -79,6 +79,12 @@ func (p *Provider) getVersion(execPath string) string {
 	return ""
 }
 
+// SourcePaths returns the PATH directories searched for Ruby, so cached results
+// are discarded when an installation is added to or removed from PATH.
+func (p *Provider) SourcePaths() []string {
+	return path.PathDirsExcludingShims()
+}
+
 // CanAutoUninstall returns false because system installs require manual removal.
 func (p *Provider) CanAutoUninstall() bool {
 	return false
@@ -144,6 +144,15 @@ func (p *Provider) DetectVersions() ([]migration.DetectedVersion, error) {
 	return detected, nil
 }
 
+// SourcePaths returns uru's registry of rubies.
+func (p *Provider) SourcePaths() []string {
+	uruHome := p.getUruHome()
+	if uruHome == "" {
+		return nil
+	}
+	return []string{filepath.Join(uruHome, "rubies.json")}
+}
+
 // CanAutoUninstall returns true because uru supports removing registered rubies.
 func (p *Provider) CanAutoUninstall() bool {
 	return true
@@ -2,10 +2,12 @@
 package node
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	goruntime "runtime"
 	"strings"
@@ -39,6 +41,11 @@ func (p *Provider) DisplayName() string {
 	return "Node.js"
 }
 
+// Family returns the language family Node.js shares with other JavaScript runtimes
+func (p *Provider) Family() string {
+	return "JavaScript"
+}
+
 // Shims returns the list of shim executables for Node.js
 func (p *Provider) Shims() []string {
 	return []string{"node", "npm", "npx"}
@@ -46,55 +53,314 @@ func (p *Provider) Shims() []string {
 
 // Install downloads and installs a specific version
 func (p *Provider) Install(version string) error {
+	return p.install(version, true, func(installPath string) error {
+		return p.installFiles(version, manifest.CurrentPlatform(), installPath)
+	})
+}
+
+// InstallWithoutShims downloads and installs a specific version without creating
+// or updating shims, so it is only reachable through exec and env
+func (p *Provider) InstallWithoutShims(version string) error {
+	return p.install(version, false, func(installPath string) error {
+		return p.installFiles(version, manifest.CurrentPlatform(), installPath)
+	})
+}
+
+// InstallFromArchive installs a version from a local archive instead of downloading it
+func (p *Provider) InstallFromArchive(version, archivePath string) error {
+	return p.install(version, true, func(installPath string) error {
+		ui.Progress("Installing from %s", archivePath)
+		progress := ui.NewProgressLine()
+		progress.Start()
+		return p.installArchive(version, archivePath, installPath, progress)
+	})
+}
+
+// install puts a version's files in place with installFiles, then creates the shims (if withShims
+// is set) and runs PostInstall to enable Corepack
+func (p *Provider) install(version string, withShims bool, installFiles func(installPath string) error) error {
 	// Ensure dtvem directories exist
 	if err := config.EnsureDirectories(); err != nil {
 		return fmt.Errorf("failed to create dtvem directories: %w", err)
 	}
 
-	// Check if already installed
+	// Wait for another dtvem process installing the same version, then see if it did
+	lock, err := download.LockInstall("node", version)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// Check if already installed, which is what was asked for if another process
+	// installed it while this one waited
 	if installed, _ := p.IsInstalled(version); installed {
+		if lock.Waited() {
+			ui.Success("Node.js %s was installed by another dtvem process", version)
+			return nil
+		}
 		return fmt.Errorf("Node.js %s is already installed", version)
 	}
 
 	ui.Header("Installing Node.js v%s...", version)
 
-	// Get platform-specific download URL
-	downloadURL, archiveName, err := p.getDownloadURL(version)
+	installPath := config.RuntimeVersionPath("node", version)
+	if err := installFiles(installPath); err != nil {
+		return err
+	}
+
+	if withShims {
+		shimSpinner := ui.NewSpinner("Creating shims...")
+		shimSpinner.Start()
+		if err := p.createShims(); err != nil {
+			shimSpinner.Error("Failed to create shims")
+			return fmt.Errorf("failed to create shims: %w", err)
+		}
+		shimSpinner.Success("Shims created")
+	}
+
+	ui.Success("Node.js v%s installed successfully", version)
+	ui.Info("Location: %s", installPath)
+
+	if err := runtime.RunPostInstall(p, version); err != nil {
+		ui.Warning("%v", err)
+		ui.Info("To enable it manually, run: corepack enable")
+	} else if withShims && corepackEnabled() {
+		// Shim the yarn and pnpm that corepack enable added to the version
+		if err := p.rehashVersion(version); err != nil {
+			ui.Warning("Failed to create shims for yarn and pnpm: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// corepackEnabled reports whether installs run 'corepack enable' (the node-corepack setting)
+func corepackEnabled() bool {
+	return config.Setting(config.SettingNodeCorepack) == "true"
+}
+
+// PostInstall runs 'corepack enable' in a newly installed version when the
+// node-corepack setting is on, so yarn and pnpm run the version the project asks for
+func (p *Provider) PostInstall(version string) error {
+	if !corepackEnabled() {
+		return nil
+	}
+
+	execPath, err := p.ExecutablePath(version)
+	if err != nil {
+		return fmt.Errorf("could not find node executable: %w", err)
+	}
+	installDir := filepath.Dir(execPath)
+	corepackPath := findToolInInstall(installDir, "corepack")
+	if corepackPath == "" {
+		return fmt.Errorf("corepack is not included with Node.js %s", version)
+	}
+
+	spinner := ui.NewSpinner("Enabling Corepack...")
+	spinner.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), runtime.PackageListTimeout)
+	defer cancel()
+	if _, err := runtime.RunPackageCommand(ctx, nil, corepackPath, "enable", "--install-directory", installDir); err != nil {
+		spinner.Warning("Failed to enable Corepack")
+		return fmt.Errorf("corepack enable failed: %w", err)
+	}
+	spinner.Success("Corepack enabled")
+	return nil
+}
+
+// CheckArch returns an error if the version has no download for arch on this OS
+func (p *Provider) CheckArch(version, arch string) error {
+	_, _, err := p.getDownloadURL(version, manifest.PlatformForArch(arch))
+	return err
+}
+
+// InstallArch installs the build of a version for arch into installPath, without shims
+func (p *Provider) InstallArch(version, arch, installPath string) error {
+	ui.Header("Installing Node.js v%s (%s)...", version, arch)
+
+	if err := p.installFiles(version, manifest.PlatformForArch(arch), installPath); err != nil {
+		return err
+	}
+
+	ui.Success("Node.js v%s (%s) installed successfully", version, arch)
+	ui.Info("Location: %s", installPath)
+	return nil
+}
+
+// distBaseURL is where nodejs.org publishes releases, including the Windows .7z
+// archives that the manifest doesn't list
+const distBaseURL = "https://nodejs.org/dist"
+
+// windowsDistArch maps Windows platforms to the architecture in nodejs.org file names
+var windowsDistArch = map[string]string{
+	manifest.PlatformWindowsAMD64: "x64",
+	manifest.PlatformWindows386:   "x86",
+	manifest.PlatformWindowsARM64: "arm64",
+}
+
+// sevenZipURL returns the nodejs.org .7z archive of a version for a Windows platform,
+// which is about half the size of the .zip, or "" for other platforms
+func sevenZipURL(version, platform string) string {
+	arch, ok := windowsDistArch[platform]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s/v%s/node-v%s-win-%s.7z", distBaseURL, version, version, arch)
+}
+
+// archiveURLs returns the archives to try for a version, in order: the .7z first on
+// Windows when prefer7z is set, then the manifest's archive
+func archiveURLs(version, platform, manifestURL string, prefer7z bool) []string {
+	if prefer7z && strings.HasSuffix(manifestURL, ".zip") {
+		if url := sevenZipURL(version, platform); url != "" {
+			return []string{url, manifestURL}
+		}
+	}
+	return []string{manifestURL}
+}
+
+// prefer7z reports whether Windows installs try the .7z archive first (the node-7z
+// setting, on unless set to false). The .7z comes from nodejs.org, not the mirror,
+// so it isn't tried when a mirror is set.
+func prefer7z() bool {
+	if config.Setting(config.SettingMirror) != "" {
+		return false
+	}
+	return config.Setting(config.SettingNode7z) != "false"
+}
+
+// firstSuccessfulArchive calls attempt with each URL in turn until one succeeds, and
+// returns the error of the last one if none does
+func firstSuccessfulArchive(urls []string, attempt func(url string) error) error {
+	var err error
+	for i, url := range urls {
+		if err = attempt(url); err == nil {
+			return nil
+		}
+		if i < len(urls)-1 {
+			ui.Warning("Couldn't use %s (%v); falling back to %s", filepath.Base(url), err, filepath.Base(urls[i+1]))
+		}
+	}
+	return err
+}
+
+// installFiles downloads the archive of a version for platform and extracts it to installPath
+func (p *Provider) installFiles(version, platform, installPath string) error {
+	// Get platform-specific download URL and checksum
+	resolved, err := p.resolveDownload(version, platform)
 	if err != nil {
 		return fmt.Errorf("failed to get download URL: %w", err)
 	}
 
-	ui.Progress("Downloading from %s", downloadURL)
+	return firstSuccessfulArchive(archiveURLs(version, platform, resolved.URL, prefer7z()), func(url string) error {
+		checksum, err := archiveChecksum(resolved, version, url)
+		if err != nil {
+			return err
+		}
+		return p.installDownload(version, url, archiveName(resolved, url), checksum, installPath)
+	})
+}
 
-	// Create temporary directory for download
-	tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("dtvem-node-%s", version))
-	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+// archiveChecksum returns the checksum the archive at url is verified against: the
+// manifest's for its own archive, and the one nodejs.org publishes in SHASUMS256.txt
+// for the .7z alternative, which the manifest doesn't list. The .7z is never used
+// unverified: without a published checksum, the install falls back to the manifest's archive.
+func archiveChecksum(resolved *runtime.ResolvedDownload, version, url string) (string, error) {
+	if url == resolved.URL {
+		return resolved.SHA256, nil
 	}
-	defer func() { _ = os.RemoveAll(tempDir) }()
 
-	// Download archive
-	archivePath := filepath.Join(tempDir, archiveName)
-	if err := download.File(downloadURL, archivePath); err != nil {
-		return fmt.Errorf("failed to download: %w", err)
+	shasums, err := fetchDistShasums(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch checksums from nodejs.org: %w", err)
+	}
+	checksum := shasumFor(shasums, filepath.Base(url))
+	if checksum == "" {
+		return "", fmt.Errorf("nodejs.org publishes no checksum for %s", filepath.Base(url))
 	}
+	return checksum, nil
+}
 
-	// Get install path
-	installPath := config.RuntimeVersionPath("node", version)
+// fetchDistShasums returns the SHASUMS256.txt nodejs.org publishes for a version
+var fetchDistShasums = func(version string) (string, error) {
+	url := fmt.Sprintf("%s/v%s/SHASUMS256.txt", distBaseURL, version)
+	resp, err := download.HTTPClient().Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
 
-	// Extract archive with spinner
-	extractDir := filepath.Join(tempDir, "extracted")
-	spinner := ui.NewSpinner("Extracting archive...")
-	spinner.Start()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
 
-	var extractErr error
-	if strings.HasSuffix(archiveName, ".zip") {
-		extractErr = download.ExtractZip(archivePath, extractDir)
-	} else if strings.HasSuffix(archiveName, ".tar.gz") {
-		extractErr = download.ExtractTarGz(archivePath, extractDir)
-	} else {
-		extractErr = fmt.Errorf("unsupported archive format: %s", archiveName)
+// shasumFor returns the checksum of fileName in a SHASUMS256.txt, whose lines are
+// "<sha256>  <file name>", or "" if it isn't listed
+func shasumFor(shasums, fileName string) string {
+	for _, line := range strings.Split(shasums, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == fileName {
+			return strings.ToLower(fields[0])
+		}
+	}
+	return ""
+}
+
+// archiveName returns the name the archive at url is cached under. The format the
+// manifest declares is that of its own archive, so the .7z alternative goes by its URL.
+func archiveName(resolved *runtime.ResolvedDownload, url string) string {
+	if url != resolved.URL {
+		return filepath.Base(url)
+	}
+	return download.ArchiveName(url, resolved.Format)
+}
+
+// installDownload downloads an archive, caching it as archiveName, verifies it against
+// expectedSHA256 when that is set, and extracts it to installPath
+func (p *Provider) installDownload(version, downloadURL, archiveName, expectedSHA256, installPath string) error {
+	// A corrupt archive is discarded and downloaded once more
+	return download.RetryCorrupt("node", version, archiveName, func() error {
+		ui.Progress("Downloading from %s", downloadURL)
+
+		// Download and extract the archive on a single progress line
+		progress := ui.NewProgressLine()
+		progress.Start()
+
+		// Download archive (kept in the cache until the install succeeds)
+		archivePath, err := download.CachedArchiveWithProgress("node", version, downloadURL, archiveName, expectedSHA256, progress.Download)
+		if err != nil {
+			progress.Error("Download failed")
+			return fmt.Errorf("failed to download: %w", err)
+		}
+
+		if err := p.installArchive(version, archivePath, installPath, progress); err != nil {
+			return err
+		}
+		download.RemoveCachedArchive("node", version, archiveName)
+
+		return nil
+	})
+}
+
+// installArchive extracts a Node.js archive and moves its contents to installPath
+func (p *Provider) installArchive(version, archivePath, installPath string, progress *ui.ProgressLine) error {
+	// Create temporary directory for extraction
+	tempDir := download.TempDir("node", version)
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		progress.Error("Extraction failed")
+		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	extractDir := filepath.Join(tempDir, "extracted")
+	progress.Extract()
+	extractErr := download.ExtractWithProgress(archivePath, extractDir, progress.FileExtracted)
 
 	if extractErr == nil {
 		// Strip top-level directory (Node.js archives have node-v18.16.0/ at the top)
@@ -102,10 +368,10 @@ func (p *Provider) Install(version string) error {
 	}
 
 	if extractErr != nil {
-		spinner.Error("Extraction failed")
+		progress.Error("Extraction failed")
 		return fmt.Errorf("failed to extract: %w", extractErr)
 	}
-	spinner.Success("Extraction complete")
+	progress.Success("Extracted " + filepath.Base(archivePath))
 
 	// Move extracted directory to install location
 	if err := os.MkdirAll(filepath.Dir(installPath), 0755); err != nil {
@@ -116,40 +382,70 @@ func (p *Provider) Install(version string) error {
 		return fmt.Errorf("failed to move to install location: %w", err)
 	}
 
-	// Create shims with spinner
-	shimSpinner := ui.NewSpinner("Creating shims...")
-	shimSpinner.Start()
-	if err := p.createShims(); err != nil {
-		shimSpinner.Error("Failed to create shims")
-		return fmt.Errorf("failed to create shims: %w", err)
+	return nil
+}
+
+// Prefetch downloads the archive for a version into the archive cache
+func (p *Provider) Prefetch(version string) error {
+	platform := manifest.CurrentPlatform()
+	resolved, err := p.resolveDownload(version, platform)
+	if err != nil {
+		return err
 	}
-	shimSpinner.Success("Shims created")
+	return firstSuccessfulArchive(archiveURLs(version, platform, resolved.URL, prefer7z()), func(url string) error {
+		checksum, err := archiveChecksum(resolved, version, url)
+		if err != nil {
+			return err
+		}
+		return download.PrefetchArchive("node", version, url, archiveName(resolved, url), checksum)
+	})
+}
 
-	ui.Success("Node.js v%s installed successfully", version)
-	ui.Info("Location: %s", installPath)
+// ResolveDownload returns where the archive for a version on the current platform comes from
+func (p *Provider) ResolveDownload(version string) (*runtime.ResolvedDownload, error) {
+	return p.resolveDownload(version, manifest.CurrentPlatform())
+}
 
-	return nil
+// getDownloadURL returns the download URL and archive name for a given version and platform
+func (p *Provider) getDownloadURL(version, platform string) (string, string, error) {
+	resolved, err := p.resolveDownload(version, platform)
+	if err != nil {
+		return "", "", err
+	}
+	return resolved.URL, download.ArchiveName(resolved.URL, resolved.Format), nil
 }
 
-// getDownloadURL returns the download URL and archive name for a given version
-func (p *Provider) getDownloadURL(version string) (string, string, error) {
+// resolveDownload looks up the download for a version and platform in the manifest
+func (p *Provider) resolveDownload(version, platform string) (*runtime.ResolvedDownload, error) {
+	if err := checkPlatform(platform, isMusl()); err != nil {
+		return nil, err
+	}
+
 	// Get the manifest (uses cached remote with embedded fallback)
 	m, err := manifest.DefaultSource().GetManifest("node")
 	if err != nil {
-		return "", "", fmt.Errorf("failed to load manifest: %w", err)
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
 	}
 
 	// Get the download info for this version and platform
-	platform := manifest.CurrentPlatform()
 	dl := m.GetDownload(version, platform)
 	if dl == nil {
-		return "", "", fmt.Errorf("Node.js %s is not available for %s", version, platform)
+		return nil, &runtime.VersionNotAvailableError{
+			Runtime:     "node",
+			DisplayName: "Node.js",
+			Version:     version,
+			Platform:    platform,
+			Hint:        m.EmulationHint(version, platform),
+		}
 	}
 
-	// Extract archive name from URL
-	archiveName := filepath.Base(dl.URL)
-
-	return dl.URL, archiveName, nil
+	return &runtime.ResolvedDownload{
+		Platform:     platform,
+		URL:          dl.URL,
+		SHA256:       dl.SHA256,
+		SHA256Source: dl.SHA256Source,
+		Format:       dl.Format,
+	}, nil
 }
 
 // createShims creates shims for Node.js executables
@@ -166,10 +462,21 @@ func (p *Provider) createShims() error {
 	return manager.CreateShims(shimNames)
 }
 
+// rehashVersion recreates the shims of a version, including executables added to it
+// after install
+func (p *Provider) rehashVersion(version string) error {
+	manager, err := shim.NewManager()
+	if err != nil {
+		return err
+	}
+	_, err = manager.RehashVersion("node", version)
+	return err
+}
+
 // Uninstall removes an installed version
 func (p *Provider) Uninstall(version string) error {
 	// TODO: Implement Node.js uninstallation
-	return fmt.Errorf("not yet implemented")
+	return runtime.ErrNotImplemented
 }
 
 // ListInstalled returns all installed Node.js versions
@@ -191,7 +498,8 @@ func (p *Provider) ListInstalled() ([]runtime.InstalledVersion, error) {
 	// Build list of installed versions
 	versions := make([]runtime.InstalledVersion, 0)
 	for _, entry := range entries {
-		if entry.IsDir() {
+		// Skip stray files (e.g. .DS_Store) and directories that aren't versions
+		if runtime.IsVersionDir(nodeVersionsDir, entry) {
 			versions = append(versions, runtime.InstalledVersion{
 				Version:     runtime.NewVersion(entry.Name()),
 				InstallPath: filepath.Join(nodeVersionsDir, entry.Name()),
@@ -211,23 +519,31 @@ func (p *Provider) ListAvailable() ([]runtime.AvailableVersion, error) {
 		return nil, fmt.Errorf("failed to load manifest: %w", err)
 	}
 
-	// Get versions available for current platform
-	platform := manifest.CurrentPlatform()
-	versionStrings := m.ListAvailableVersions(platform)
+	return m.AvailableVersions(manifest.CurrentPlatform()), nil
+}
 
-	// Convert to AvailableVersion format and sort by semantic version (newest first)
-	versions := make([]runtime.AvailableVersion, 0, len(versionStrings))
-	for _, v := range versionStrings {
-		versions = append(versions, runtime.AvailableVersion{
-			Version: runtime.NewVersion(v),
-			Notes:   "",
-		})
+// checkPlatform explains why Node.js can't be installed on a platform that has no
+// official builds. Node.js publishes x64, arm64 and armv7l (GOARCH arm) builds for
+// Linux, and its Linux builds are linked against glibc, so they don't run on
+// musl-based distributions such as Alpine.
+func checkPlatform(platform string, musl bool) error {
+	if !manifest.IsValidPlatform(platform) {
+		return fmt.Errorf("Node.js has no official builds for %s (supported platforms: %s)",
+			platform, strings.Join(manifest.ValidPlatforms(), ", "))
 	}
+	if musl && strings.HasPrefix(platform, constants.OSLinux+"-") {
+		return fmt.Errorf("Node.js's official Linux builds need glibc and don't run on musl-based systems such as Alpine; install Node.js with the system package manager instead (e.g. apk add nodejs)")
+	}
+	return nil
+}
 
-	// Sort by version descending (newest first)
-	runtime.SortVersionsDesc(versions)
-
-	return versions, nil
+// isMusl reports whether this is a Linux system whose C library is musl
+func isMusl() bool {
+	if goruntime.GOOS != constants.OSLinux {
+		return false
+	}
+	matches, _ := filepath.Glob("/lib/ld-musl-*.so.1")
+	return len(matches) > 0
 }
 
 // ExecutablePath returns the path to the Node.js executable
@@ -310,8 +626,15 @@ func (p *Provider) DetectInstalled() ([]runtime.DetectedVersion, error) {
 	return []runtime.DetectedVersion{}, nil
 }
 
-// GetGlobalPackages detects globally installed npm packages
+// GlobalPackages detects globally installed npm packages
 func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runtime.PackageListTimeout)
+	defer cancel()
+	return p.GlobalPackagesContext(ctx, installPath)
+}
+
+// GlobalPackagesContext detects globally installed npm packages, giving up when ctx ends
+func (p *Provider) GlobalPackagesContext(ctx context.Context, installPath string) ([]string, error) {
 	// Find npm executable in the installation
 	npmPath := findNpmInInstall(installPath)
 	if npmPath == "" {
@@ -319,8 +642,15 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 	}
 
 	// Run npm list -g --depth=0 --json
-	cmd := exec.Command(npmPath, "list", "-g", "--depth=0", "--json")
-	output, err := cmd.Output()
+	output, err := runtime.RunPackageCommand(ctx, nil, npmPath, "list", "-g", "--depth=0", "--json")
+	if ctx.Err() != nil {
+		// Incomplete JSON can't be parsed, so a timeout finds no packages
+		packages, parseErr := parseNpmList(output)
+		if parseErr != nil {
+			packages = []string{}
+		}
+		return packages, err
+	}
 	if err != nil {
 		// npm list returns exit code 1 if there are issues, but might still have output
 		// Try to parse anyway
@@ -329,7 +659,11 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 		}
 	}
 
-	// Parse JSON output
+	return parseNpmList(output)
+}
+
+// parseNpmList returns the package names of `npm list --json` output, except npm itself
+func parseNpmList(output []byte) ([]string, error) {
 	var result struct {
 		Dependencies map[string]interface{} `json:"dependencies"`
 	}
@@ -338,7 +672,6 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 		return nil, fmt.Errorf("failed to parse npm list output: %w", err)
 	}
 
-	// Extract package names (exclude npm itself)
 	packages := make([]string, 0)
 	for name := range result.Dependencies {
 		if name != "npm" {
@@ -351,6 +684,14 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 
 // InstallGlobalPackages reinstalls global packages to a specific version
 func (p *Provider) InstallGlobalPackages(version string, packages []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), runtime.PackageInstallTimeout)
+	defer cancel()
+	return p.InstallGlobalPackagesContext(ctx, version, packages)
+}
+
+// InstallGlobalPackagesContext reinstalls global packages to a specific version,
+// showing npm's output as it runs and giving up when ctx ends
+func (p *Provider) InstallGlobalPackagesContext(ctx context.Context, version string, packages []string) error {
 	if len(packages) == 0 {
 		return nil
 	}
@@ -370,12 +711,10 @@ func (p *Provider) InstallGlobalPackages(version string, packages []string) erro
 
 	// Install all packages at once
 	args := append([]string{"install", "-g"}, packages...)
-	cmd := exec.Command(npmPath, args...)
-
-	// Capture output for errors
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("npm install failed: %w\n%s", err, string(output))
+	output := ui.NewCommandOutput()
+	defer output.Flush()
+	if _, err := runtime.RunPackageCommand(ctx, output, npmPath, args...); err != nil {
+		return fmt.Errorf("npm install failed: %w", err)
 	}
 
 	return nil
@@ -391,20 +730,26 @@ func (p *Provider) ManualPackageInstallCommand(packages []string) string {
 
 // findNpmInInstall finds the npm executable in an installation directory
 func findNpmInInstall(installDir string) string {
+	return findToolInInstall(installDir, "npm")
+}
+
+// findToolInInstall finds an executable bundled with Node.js, such as npm or corepack,
+// in an installation directory
+func findToolInInstall(installDir, name string) string {
 	// Common locations to check
 	searchPaths := []string{
 		installDir,                       // Same directory
 		filepath.Join(installDir, "bin"), // Unix bin/
 	}
 
-	// On Windows, try with .cmd extension (npm uses .cmd on Windows)
+	// On Windows, try with .cmd extension (npm and corepack use .cmd on Windows)
 	if goruntime.GOOS == constants.OSWindows {
 		for _, searchPath := range searchPaths {
-			cmdPath := filepath.Join(searchPath, "npm.cmd")
+			cmdPath := filepath.Join(searchPath, name+".cmd")
 			if _, err := os.Stat(cmdPath); err == nil {
 				return cmdPath
 			}
-			exePath := filepath.Join(searchPath, "npm.exe")
+			exePath := filepath.Join(searchPath, name+".exe")
 			if _, err := os.Stat(exePath); err == nil {
 				return exePath
 			}
@@ -412,7 +757,7 @@ func findNpmInInstall(installDir string) string {
 	} else {
 		// On Unix, check without extension
 		for _, searchPath := range searchPaths {
-			execPath := filepath.Join(searchPath, "npm")
+			execPath := filepath.Join(searchPath, name)
 			if _, err := os.Stat(execPath); err == nil {
 				return execPath
 			}
@@ -422,36 +767,49 @@ func findNpmInInstall(installDir string) string {
 	return ""
 }
 
+// reshimTriggers are the commands that add or remove global executables. yarn and
+// pnpm are usually installed through npm or corepack, so they run through Node.js shims.
+var reshimTriggers = []runtime.ReshimTrigger{
+	{
+		Shims:       []string{"npm"},
+		Subcommands: []string{"install", "i", "uninstall", "remove", "rm", "un"},
+		Flags:       []string{"-g", "--global"},
+	},
+	{
+		Shims:       []string{"pnpm"},
+		Subcommands: []string{"add", "install", "i", "remove", "rm", "uninstall", "un"},
+		Flags:       []string{"-g", "--global"},
+	},
+	{
+		Shims:       []string{"yarn"},
+		Subcommands: []string{"global add", "global remove"},
+	},
+}
+
 // ShouldReshimAfter checks if the given command should trigger a reshim.
 // Returns true if the command installs or uninstalls global packages.
 func (p *Provider) ShouldReshimAfter(shimName string, args []string) bool {
-	// Only npm installs global packages
-	if shimName != "npm" {
-		return false
-	}
-
-	// Need at least one argument (the command)
-	if len(args) == 0 {
-		return false
-	}
+	return runtime.ShouldReshim(reshimTriggers, shimName, args)
+}
 
-	// Check if this is an install or uninstall command
-	cmd := args[0]
-	isPackageCommand := cmd == "install" || cmd == "i" ||
-		cmd == "uninstall" || cmd == "remove" || cmd == "rm" || cmd == "un"
+// Capabilities reports that npm global packages can be migrated. Uninstall is left
+// to the uninstall command.
+func (p *Provider) Capabilities() runtime.ProviderCapabilities {
+	return runtime.ProviderCapabilities{GlobalPackages: true}
+}
 
-	if !isPackageCommand {
-		return false
-	}
+// VersionArgs returns the arguments that make node print its version
+func (p *Provider) VersionArgs() []string {
+	return []string{"--version"}
+}
 
-	// Check for -g or --global flag
-	for _, arg := range args {
-		if arg == "-g" || arg == "--global" {
-			return true
-		}
+// ParseVersionOutput extracts the version from `node --version` output, e.g. "v20.11.1"
+func (p *Provider) ParseVersionOutput(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "v") || !runtime.LooksLikeVersion(fields[0][1:]) {
+		return "", fmt.Errorf("unexpected node --version output: %q", strings.TrimSpace(output))
 	}
-
-	return false
+	return fields[0][1:], nil
 }
 
 // GetEnvironment returns environment variables needed to run Node.js binaries.
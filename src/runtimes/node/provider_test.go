@@ -1,10 +1,24 @@
 package node
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	goruntime "runtime"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/manifest"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/testutil"
+	"github.com/dtvem/dtvem/src/internal/ui"
 )
 
 // TestNodeProviderContract runs the generic provider test harness
@@ -82,3 +96,535 @@ func TestNodeProvider_InstallPath(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeProvider_AvailableVersionsFiltersByArch(t *testing.T) {
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"0.10.0": {
+				manifest.PlatformDarwinAMD64: {URL: "https://example.com/0.10.0/darwin-amd64.tar.gz"},
+			},
+			"14.21.3": {
+				manifest.PlatformDarwinAMD64: {URL: "https://example.com/14.21.3/darwin-amd64.tar.gz"},
+				manifest.PlatformDarwinARM64: nil,
+			},
+			"16.0.0": {
+				manifest.PlatformDarwinAMD64: {URL: "https://example.com/16.0.0/darwin-amd64.tar.gz"},
+				manifest.PlatformDarwinARM64: {URL: "https://example.com/16.0.0/darwin-arm64.tar.gz"},
+			},
+			"20.11.1": {
+				manifest.PlatformDarwinAMD64: {URL: "https://example.com/20.11.1/darwin-amd64.tar.gz"},
+				manifest.PlatformDarwinARM64: {URL: "https://example.com/20.11.1/darwin-arm64.tar.gz"},
+			},
+		},
+	}
+
+	tests := []struct {
+		platform string
+		want     []string
+	}{
+		{manifest.PlatformDarwinARM64, []string{"20.11.1", "16.0.0"}},
+		{manifest.PlatformDarwinAMD64, []string{"20.11.1", "16.0.0", "14.21.3", "0.10.0"}},
+		{manifest.PlatformLinuxARM64, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			versions := m.AvailableVersions(tt.platform)
+
+			got := make([]string, 0, len(versions))
+			for _, v := range versions {
+				got = append(got, v.Version.Raw)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AvailableVersions(%s) = %v, want %v", tt.platform, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeProvider_ListInstalledIgnoresStrayEntries(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	versionsDir := filepath.Join(tmpRoot, "versions", "node")
+	for _, dir := range []string{"20.11.1", "18.16.0", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(versionsDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	for _, file := range []string{".DS_Store", "node-v22.0.0-linux-x64.tar.gz", "22.0.0"} {
+		if err := os.WriteFile(filepath.Join(versionsDir, file), []byte("stray"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+
+	installed, err := NewProvider().ListInstalled()
+	if err != nil {
+		t.Fatalf("ListInstalled() error: %v", err)
+	}
+
+	var got []string
+	for _, v := range installed {
+		got = append(got, v.Version.Raw)
+	}
+	sort.Strings(got)
+
+	want := []string{"18.16.0", "20.11.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListInstalled() = %v, want %v", got, want)
+	}
+}
+
+func TestNodeProvider_InstallArchiveFromFixture(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	// A Node.js archive has a single top-level directory
+	archivePath := filepath.Join(t.TempDir(), "node-v20.11.1-linux-x64.tar.gz")
+	files := map[string]string{
+		"node-v20.11.1-linux-x64/bin/node":         "binary",
+		"node-v20.11.1-linux-x64/lib/node_modules": "",
+	}
+	out, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		_, _ = tw.Write([]byte(content))
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	_ = out.Close()
+
+	p := NewProvider()
+	installPath := config.RuntimeVersionPath("node", "20.11.1")
+	if err := p.installArchive("20.11.1", archivePath, installPath, ui.NewProgressLine()); err != nil {
+		t.Fatalf("installArchive() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(installPath, "bin", "node"))
+	if err != nil || string(data) != "binary" {
+		t.Errorf("bin/node = (%q, %v), want the archive's file without its top-level directory", data, err)
+	}
+	if installed, _ := p.IsInstalled("20.11.1"); !installed {
+		t.Error("IsInstalled() = false after installing from the archive")
+	}
+}
+
+func TestCheckPlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		musl     bool
+		wantErr  bool
+	}{
+		{"linux x64", manifest.PlatformLinuxAMD64, false, false},
+		{"linux arm64", manifest.PlatformLinuxARM64, false, false},
+		{"linux armv7l", manifest.PlatformLinuxARM, false, false},
+		{"armv7l spelling", "linux-" + manifest.PlatformArch(manifest.PlatformForArch("armv7l")), false, false},
+		{"linux ppc64le", "linux-ppc64le", false, true},
+		{"linux armv6l", "linux-armv6l", false, true},
+		{"linux on musl", manifest.PlatformLinuxAMD64, true, true},
+		{"musl only affects linux", manifest.PlatformDarwinARM64, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPlatform(tt.platform, tt.musl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkPlatform(%q, musl=%v) error = %v, wantErr %v", tt.platform, tt.musl, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAvailableVersions_Armv7l(t *testing.T) {
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"20.11.1": {
+				manifest.PlatformLinuxARM: {URL: "https://example.com/node/20.11.1/linux-arm.tar.gz"},
+			},
+			"22.0.0": {
+				manifest.PlatformLinuxARM64: {URL: "https://example.com/node/22.0.0/linux-arm64.tar.gz"},
+			},
+		},
+	}
+
+	got := m.AvailableVersions(manifest.PlatformLinuxARM)
+	if len(got) != 1 || got[0].Version.Raw != "20.11.1" {
+		t.Errorf("AvailableVersions(linux-arm) = %v, want only 20.11.1", got)
+	}
+}
+
+func TestNodeProvider_ShouldReshimAfter(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name     string
+		shimName string
+		args     []string
+		want     bool
+	}{
+		{"npm install -g", "npm", []string{"install", "-g", "typescript"}, true},
+		{"npm i --global", "npm", []string{"i", "--global", "typescript"}, true},
+		{"npm uninstall -g", "npm", []string{"uninstall", "-g", "typescript"}, true},
+		{"npm local install", "npm", []string{"install", "typescript"}, false},
+		{"npm run", "npm", []string{"run", "build"}, false},
+		{"pnpm add -g", "pnpm", []string{"add", "-g", "typescript"}, true},
+		{"pnpm remove --global", "pnpm", []string{"remove", "--global", "typescript"}, true},
+		{"pnpm local add", "pnpm", []string{"add", "typescript"}, false},
+		{"yarn global add", "yarn", []string{"global", "add", "typescript"}, true},
+		{"yarn global remove", "yarn", []string{"global", "remove", "typescript"}, true},
+		{"yarn global list", "yarn", []string{"global", "list"}, false},
+		{"yarn local add", "yarn", []string{"add", "typescript"}, false},
+		{"node", "node", []string{"install", "-g"}, false},
+		{"empty args", "npm", []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := provider.ShouldReshimAfter(tt.shimName, tt.args); got != tt.want {
+				t.Errorf("ShouldReshimAfter(%q, %v) = %v, want %v", tt.shimName, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeProvider_Capabilities(t *testing.T) {
+	want := runtime.ProviderCapabilities{GlobalPackages: true}
+	if got := runtime.CapabilitiesOf(NewProvider()); got != want {
+		t.Errorf("Capabilities() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNodeProvider_InstallWithoutShims(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	p := NewProvider()
+	err := p.install("20.11.1", false, func(installPath string) error {
+		if err := os.MkdirAll(filepath.Join(installPath, "bin"), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(installPath, "bin", "node"), []byte("binary"), 0755)
+	})
+	if err != nil {
+		t.Fatalf("install() error: %v", err)
+	}
+
+	if installed, _ := p.IsInstalled("20.11.1"); !installed {
+		t.Error("IsInstalled() = false after installing without shims")
+	}
+	entries, err := os.ReadDir(config.DefaultPaths().Shims)
+	if err != nil {
+		t.Fatalf("Failed to read shims directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("shims directory has %d entries, want none", len(entries))
+	}
+}
+
+func TestNodeProvider_PostInstallCorepack(t *testing.T) {
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("fake corepack executables are shell scripts")
+	}
+
+	tests := []struct {
+		name       string
+		setting    string
+		script     string
+		wantRun    bool
+		wantErrMsg string
+	}{
+		{name: "disabled by default", script: "#!/bin/sh\necho \"$@\" > \"$0.args\"\n"},
+		{name: "enabled", setting: "true", script: "#!/bin/sh\necho \"$@\" > \"$0.args\"\n", wantRun: true},
+		{name: "corepack fails", setting: "true", script: "#!/bin/sh\necho \"$@\" > \"$0.args\"\necho denied >&2\nexit 1\n", wantRun: true, wantErrMsg: "corepack enable failed"},
+		{name: "corepack missing", setting: "true", wantErrMsg: "corepack is not included"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DTVEM_ROOT", t.TempDir())
+			config.ResetPathsCache()
+			t.Cleanup(config.ResetPathsCache)
+			t.Setenv(config.NodeCorepackEnvVar, tt.setting)
+
+			p := NewProvider()
+			var binDir string
+			err := p.install("20.11.1", false, func(installPath string) error {
+				binDir = filepath.Join(installPath, "bin")
+				if err := os.MkdirAll(binDir, 0755); err != nil {
+					return err
+				}
+				if tt.script != "" {
+					if err := os.WriteFile(filepath.Join(binDir, "corepack"), []byte(tt.script), 0755); err != nil {
+						return err
+					}
+				}
+				return os.WriteFile(filepath.Join(binDir, "node"), []byte("binary"), 0755)
+			})
+			// A failed post-install step leaves a usable install
+			if err != nil {
+				t.Fatalf("install() error: %v", err)
+			}
+
+			args, readErr := os.ReadFile(filepath.Join(binDir, "corepack.args"))
+			if ran := readErr == nil; ran != tt.wantRun {
+				t.Fatalf("corepack ran = %v, want %v", ran, tt.wantRun)
+			}
+			if tt.wantRun && strings.TrimSpace(string(args)) != "enable --install-directory "+binDir {
+				t.Errorf("corepack args = %q, want enable --install-directory %s", args, binDir)
+			}
+
+			err = p.PostInstall("20.11.1")
+			if tt.wantErrMsg == "" && err != nil {
+				t.Errorf("PostInstall() error: %v", err)
+			}
+			if tt.wantErrMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErrMsg)) {
+				t.Errorf("PostInstall() error = %v, want one containing %q", err, tt.wantErrMsg)
+			}
+		})
+	}
+}
+
+func TestArchiveChecksum(t *testing.T) {
+	resolved := &runtime.ResolvedDownload{
+		URL:    "https://builds.dtvem.io/node/22.0.0/windows-amd64.zip",
+		SHA256: "abc123",
+	}
+	sevenZip := "https://nodejs.org/dist/v22.0.0/node-v22.0.0-win-x64.7z"
+
+	original := fetchDistShasums
+	t.Cleanup(func() { fetchDistShasums = original })
+
+	tests := []struct {
+		name    string
+		url     string
+		shasums string
+		fetch   error
+		want    string
+		wantErr bool
+	}{
+		{name: "manifest archive", url: resolved.URL, want: "abc123"},
+		{
+			name:    "7z checked against nodejs.org",
+			url:     sevenZip,
+			shasums: "1111  node-v22.0.0-win-x64.zip\nDEF456  node-v22.0.0-win-x64.7z\n",
+			want:    "def456",
+		},
+		{name: "7z not in SHASUMS256.txt", url: sevenZip, shasums: "1111  node-v22.0.0-win-x64.zip\n", wantErr: true},
+		{name: "SHASUMS256.txt unavailable", url: sevenZip, fetch: errors.New("offline"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetchDistShasums = func(version string) (string, error) {
+				if version != "22.0.0" {
+					t.Errorf("fetched checksums of %s, want 22.0.0", version)
+				}
+				return tt.shasums, tt.fetch
+			}
+
+			got, err := archiveChecksum(resolved, "22.0.0", tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("archiveChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("archiveChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	// A mirror serving the zip at a URL whose extension disagrees with the declared format
+	resolved := &runtime.ResolvedDownload{
+		URL:    "https://mirror.example.com/node/22.0.0/windows-amd64.tgz",
+		Format: "zip",
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"manifest archive uses the declared format", resolved.URL, "windows-amd64.zip"},
+		{"7z alternative goes by its URL", "https://nodejs.org/dist/v22.0.0/node-v22.0.0-win-x64.7z", "node-v22.0.0-win-x64.7z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := archiveName(resolved, tt.url); got != tt.want {
+				t.Errorf("archiveName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveURLs(t *testing.T) {
+	zipURL := "https://builds.dtvem.io/node/22.0.0/windows-amd64.zip"
+
+	tests := []struct {
+		name        string
+		platform    string
+		manifestURL string
+		prefer7z    bool
+		want        []string
+	}{
+		{
+			name:        "windows amd64 tries the 7z first",
+			platform:    manifest.PlatformWindowsAMD64,
+			manifestURL: zipURL,
+			prefer7z:    true,
+			want:        []string{"https://nodejs.org/dist/v22.0.0/node-v22.0.0-win-x64.7z", zipURL},
+		},
+		{
+			name:        "windows 386",
+			platform:    manifest.PlatformWindows386,
+			manifestURL: "https://builds.dtvem.io/node/22.0.0/windows-386.zip",
+			prefer7z:    true,
+			want:        []string{"https://nodejs.org/dist/v22.0.0/node-v22.0.0-win-x86.7z", "https://builds.dtvem.io/node/22.0.0/windows-386.zip"},
+		},
+		{
+			name:        "windows arm64",
+			platform:    manifest.PlatformWindowsARM64,
+			manifestURL: "https://builds.dtvem.io/node/22.0.0/windows-arm64.zip",
+			prefer7z:    true,
+			want:        []string{"https://nodejs.org/dist/v22.0.0/node-v22.0.0-win-arm64.7z", "https://builds.dtvem.io/node/22.0.0/windows-arm64.zip"},
+		},
+		{
+			name:        "7z disabled",
+			platform:    manifest.PlatformWindowsAMD64,
+			manifestURL: zipURL,
+			prefer7z:    false,
+			want:        []string{zipURL},
+		},
+		{
+			name:        "manifest already a 7z",
+			platform:    manifest.PlatformWindowsAMD64,
+			manifestURL: "https://builds.dtvem.io/node/22.0.0/windows-amd64.7z",
+			prefer7z:    true,
+			want:        []string{"https://builds.dtvem.io/node/22.0.0/windows-amd64.7z"},
+		},
+		{
+			name:        "not windows",
+			platform:    manifest.PlatformLinuxAMD64,
+			manifestURL: "https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz",
+			prefer7z:    true,
+			want:        []string{"https://builds.dtvem.io/node/22.0.0/linux-amd64.tar.gz"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := archiveURLs("22.0.0", tt.platform, tt.manifestURL, tt.prefer7z)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("archiveURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefer7z(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	t.Setenv(config.Node7zEnvVar, "")
+	t.Setenv(config.MirrorEnvVar, "")
+	if !prefer7z() {
+		t.Error("prefer7z() = false by default, want true")
+	}
+
+	t.Setenv(config.Node7zEnvVar, "false")
+	if prefer7z() {
+		t.Errorf("prefer7z() = true with %s=false", config.Node7zEnvVar)
+	}
+
+	// The .7z isn't on the mirror
+	t.Setenv(config.Node7zEnvVar, "")
+	t.Setenv(config.MirrorEnvVar, "https://mirror.corp/dtvem")
+	if prefer7z() {
+		t.Errorf("prefer7z() = true with %s set", config.MirrorEnvVar)
+	}
+}
+
+func TestFirstSuccessfulArchive(t *testing.T) {
+	urls := []string{"https://example.com/node.7z", "https://example.com/node.zip"}
+
+	tests := []struct {
+		name      string
+		failing   map[string]bool
+		wantTried []string
+		wantErr   bool
+	}{
+		{"7z works", nil, urls[:1], false},
+		{"7z fails, zip works", map[string]bool{urls[0]: true}, urls, false},
+		{"both fail", map[string]bool{urls[0]: true, urls[1]: true}, urls, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tried []string
+			err := firstSuccessfulArchive(urls, func(url string) error {
+				tried = append(tried, url)
+				if tt.failing[url] {
+					return fmt.Errorf("failed to extract %s", url)
+				}
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("firstSuccessfulArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), urls[1]) {
+				t.Errorf("error = %v, want the last archive's error", err)
+			}
+			if strings.Join(tried, " ") != strings.Join(tt.wantTried, " ") {
+				t.Errorf("tried %v, want %v", tried, tt.wantTried)
+			}
+		})
+	}
+}
+
+func TestNodeProvider_ParseVersionOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "release", output: "v20.11.1\n", want: "20.11.1"},
+		{name: "prerelease", output: "v22.0.0-rc.1\r\n", want: "22.0.0-rc.1"},
+		{name: "no v prefix", output: "20.11.1\n", wantErr: true},
+		{name: "error message", output: "node: error while loading shared libraries\n", wantErr: true},
+		{name: "empty", output: "", wantErr: true},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ParseVersionOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersionOutput(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersionOutput(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package php
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// composerURL is where the latest stable composer.phar is downloaded from
+const composerURL = "https://getcomposer.org/download/latest-stable/composer.phar"
+
+// composerChecksumURL is where getcomposer.org publishes the SHA256 of composerURL
+const composerChecksumURL = composerURL + ".sha256"
+
+// PostInstall adds Composer to a version: composer.phar next to the php executable
+// and a wrapper that runs it with that php
+func (p *Provider) PostInstall(version string) error {
+	spinner := ui.NewSpinner("Installing Composer...")
+	spinner.Start()
+	if err := p.installComposer(version); err != nil {
+		spinner.Warning("Failed to install Composer")
+		return fmt.Errorf("failed to install composer: %w", err)
+	}
+	spinner.Success("Composer installed")
+	return nil
+}
+
+// installComposer downloads composer.phar into the directory of the php executable
+// of a version and writes the composer wrapper next to it
+func (p *Provider) installComposer(version string) error {
+	phpPath, err := p.ExecutablePath(version)
+	if err != nil {
+		return fmt.Errorf("could not find php executable: %w", err)
+	}
+	binDir := filepath.Dir(phpPath)
+
+	checksum, err := fetchComposerChecksum()
+	if err != nil {
+		return fmt.Errorf("failed to fetch the composer.phar checksum: %w", err)
+	}
+	if err := download.FileVerified(composerURL, filepath.Join(binDir, "composer.phar"), checksum); err != nil {
+		return err
+	}
+	return writeComposerWrapper(binDir)
+}
+
+// fetchComposerChecksum returns the SHA256 getcomposer.org publishes for composer.phar
+var fetchComposerChecksum = func() (string, error) {
+	resp, err := download.HTTPClient().Get(composerChecksumURL)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d from %s", resp.StatusCode, composerChecksumURL)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	return parseComposerChecksum(string(body))
+}
+
+// parseComposerChecksum reads the checksum from a composer.phar.sha256 file, which
+// holds the hex SHA256, optionally followed by the file name
+func parseComposerChecksum(body string) (string, error) {
+	fields := strings.Fields(body)
+	if len(fields) == 0 || !sha256Pattern.MatchString(fields[0]) {
+		return "", fmt.Errorf("unexpected checksum file contents %q", strings.TrimSpace(body))
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// sha256Pattern matches a hex SHA256 checksum
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// writeComposerWrapper writes the script that runs composer.phar with the php
+// executable in the same directory
+func writeComposerWrapper(binDir string) error {
+	if goruntime.GOOS == constants.OSWindows {
+		script := "@\"%~dp0php.exe\" \"%~dp0composer.phar\" %*\r\n"
+		return os.WriteFile(filepath.Join(binDir, "composer.cmd"), []byte(script), 0644)
+	}
+
+	script := "#!/bin/sh\nexec \"$(dirname \"$0\")/php\" \"$(dirname \"$0\")/composer.phar\" \"$@\"\n"
+	return os.WriteFile(filepath.Join(binDir, "composer"), []byte(script), 0755)
+}
+
+// composerWrapperPath returns the path of the composer wrapper in a version's install
+func composerWrapperPath(installPath string) string {
+	if goruntime.GOOS == constants.OSWindows {
+		return filepath.Join(installPath, "composer.cmd")
+	}
+	return filepath.Join(installPath, "bin", "composer")
+}
+
+// CheckHealth checks that an installed version has Composer
+func (p *Provider) CheckHealth(version string) []runtime.HealthProblem {
+	installPath := config.RuntimeVersionPath("php", version)
+	if _, err := os.Stat(composerWrapperPath(installPath)); err == nil {
+		return nil
+	}
+
+	return []runtime.HealthProblem{{
+		Problem:        fmt.Sprintf("PHP %s: composer is missing", version),
+		FixDescription: fmt.Sprintf("Install Composer into PHP %s", version),
+		Fix:            func() error { return p.installComposer(version) },
+		Hint:           fmt.Sprintf("Download %s into %s", composerURL, filepath.Dir(composerWrapperPath(installPath))),
+	}}
+}
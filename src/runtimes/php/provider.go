@@ -0,0 +1,518 @@
+// Package php implements the PHP runtime provider for dtvem.
+//
+// The provider isn't imported by dtvem or the shim yet: no php.json manifest ships,
+// so PHP versions can't be listed or installed. Register it once the builds are
+// mirrored and the manifest is generated, and at the same time add "php" to the
+// runtimes.json schema and .php-version to config's runtimeVersionFiles.
+package php
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/download"
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
+	"github.com/dtvem/dtvem/src/internal/ui"
+)
+
+// Provider implements the runtime.Provider interface for PHP. Windows versions are
+// the zip builds from windows.php.net; macOS and Linux versions are the single-binary
+// builds of static-php-cli. Composer is added to each version after the install.
+type Provider struct{}
+
+// NewProvider creates a new PHP runtime provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the runtime name
+func (p *Provider) Name() string {
+	return "php"
+}
+
+// DisplayName returns the human-readable name
+func (p *Provider) DisplayName() string {
+	return "PHP"
+}
+
+// Shims returns the list of shim executables for PHP
+func (p *Provider) Shims() []string {
+	return []string{"php", "composer"}
+}
+
+// Install downloads and installs a specific version
+func (p *Provider) Install(version string) error {
+	return p.install(version, true, func(installPath string) error {
+		return p.installFiles(version, installPath)
+	})
+}
+
+// InstallWithoutShims downloads and installs a specific version without creating
+// or updating shims, so it is only reachable through exec and env
+func (p *Provider) InstallWithoutShims(version string) error {
+	return p.install(version, false, func(installPath string) error {
+		return p.installFiles(version, installPath)
+	})
+}
+
+// InstallFromArchive installs a version from a local archive instead of downloading it
+func (p *Provider) InstallFromArchive(version, archivePath string) error {
+	return p.install(version, true, func(installPath string) error {
+		ui.Progress("Installing from %s", archivePath)
+		progress := ui.NewProgressLine()
+		progress.Start()
+		return p.installArchive(version, archivePath, installPath, progress)
+	})
+}
+
+// install puts a version's files in place with installFiles and, if withShims is set, creates the shims
+func (p *Provider) install(version string, withShims bool, installFiles func(installPath string) error) error {
+	ui.Debug("Starting PHP installation for version %s", version)
+
+	// Ensure dtvem directories exist
+	if err := config.EnsureDirectories(); err != nil {
+		return fmt.Errorf("failed to create dtvem directories: %w", err)
+	}
+
+	// Wait for another dtvem process installing the same version, then see if it did
+	lock, err := download.LockInstall("php", version)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// Check if already installed, which is what was asked for if another process
+	// installed it while this one waited
+	if installed, _ := p.IsInstalled(version); installed {
+		if lock.Waited() {
+			ui.Success("PHP %s was installed by another dtvem process", version)
+			return nil
+		}
+		return fmt.Errorf("PHP %s is already installed", version)
+	}
+
+	ui.Header("Installing PHP v%s...", version)
+
+	installPath := config.RuntimeVersionPath("php", version)
+	if err := installFiles(installPath); err != nil {
+		return err
+	}
+
+	if withShims {
+		shimSpinner := ui.NewSpinner("Creating shims...")
+		shimSpinner.Start()
+		if err := p.createShims(); err != nil {
+			shimSpinner.Error("Failed to create shims")
+			return fmt.Errorf("failed to create shims: %w", err)
+		}
+		shimSpinner.Success("Shims created")
+	}
+
+	ui.Success("PHP v%s installed successfully", version)
+	ui.Info("Location: %s", installPath)
+
+	if err := runtime.RunPostInstall(p, version); err != nil {
+		ui.Debug("Post-install failed: %v", err)
+		ui.Info("Run 'dtvem doctor --fix' to install Composer later")
+	}
+
+	return nil
+}
+
+// installFiles downloads the archive of a version and installs its contents to installPath
+func (p *Provider) installFiles(version, installPath string) error {
+	resolved, err := p.ResolveDownload(version)
+	if err != nil {
+		return fmt.Errorf("failed to get download URL: %w", err)
+	}
+	downloadURL, archiveName := resolved.URL, download.ArchiveName(resolved.URL, resolved.Format)
+
+	// A corrupt archive is discarded and downloaded once more
+	return download.RetryCorrupt("php", version, archiveName, func() error {
+		ui.Progress("Downloading from %s", downloadURL)
+
+		// Download and extract the archive on a single progress line
+		progress := ui.NewProgressLine()
+		progress.Start()
+
+		// Download archive (kept in the cache until the install succeeds)
+		archivePath, err := download.CachedArchiveWithProgress("php", version, downloadURL, archiveName, resolved.SHA256, progress.Download)
+		if err != nil {
+			progress.Error("Download failed")
+			return fmt.Errorf("failed to download: %w", err)
+		}
+
+		if err := p.installArchive(version, archivePath, installPath, progress); err != nil {
+			return err
+		}
+		download.RemoveCachedArchive("php", version, archiveName)
+
+		return nil
+	})
+}
+
+// installArchive extracts a PHP archive and moves the result to installPath
+func (p *Provider) installArchive(version, archivePath, installPath string, progress *ui.ProgressLine) error {
+	tempDir := download.TempDir("php", version)
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		progress.Error("Extraction failed")
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	extractDir := filepath.Join(tempDir, "extracted")
+	progress.Extract()
+	if err := download.ExtractWithProgress(archivePath, extractDir, progress.FileExtracted); err != nil {
+		progress.Error("Extraction failed")
+		return fmt.Errorf("failed to extract: %w", err)
+	}
+	progress.Success("Extracted " + filepath.Base(archivePath))
+
+	sourceDir := determineSourceDir(extractDir)
+	ui.Debug("Source directory: %s", sourceDir)
+	ui.Debug("Install path: %s", installPath)
+
+	if err := os.MkdirAll(filepath.Dir(installPath), 0755); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+	if err := os.Rename(sourceDir, installPath); err != nil {
+		return fmt.Errorf("failed to move to install location: %w", err)
+	}
+
+	if goruntime.GOOS == constants.OSWindows {
+		return writeWindowsIni(installPath)
+	}
+	return moveBinaryToBin(installPath)
+}
+
+// determineSourceDir returns the directory holding the PHP files in an extracted
+// archive: the archive root, or its only directory when everything is nested in one
+func determineSourceDir(extractDir string) string {
+	entries, err := os.ReadDir(extractDir)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(extractDir, entries[0].Name())
+	}
+	return extractDir
+}
+
+// moveBinaryToBin moves the php binary of a static-php-cli build, which is the only
+// file in its archive, into bin/ where ExecutablePath and reshim look for it
+func moveBinaryToBin(installPath string) error {
+	binary := filepath.Join(installPath, "php")
+	info, err := os.Stat(binary)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+
+	binDir := filepath.Join(installPath, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(binary, filepath.Join(binDir, "php")); err != nil {
+		return fmt.Errorf("failed to move php to %s: %w", binDir, err)
+	}
+	return os.Chmod(filepath.Join(binDir, "php"), 0755)
+}
+
+// windowsExtensions are the extensions enabled in the php.ini of Windows builds,
+// which ship with none enabled. Composer needs openssl, curl and zip.
+var windowsExtensions = []string{"curl", "mbstring", "openssl", "zip"}
+
+// writeWindowsIni creates php.ini for a Windows build from its php.ini-development,
+// with the extension directory set and windowsExtensions enabled. An existing php.ini
+// is left alone.
+func writeWindowsIni(installPath string) error {
+	iniPath := filepath.Join(installPath, "php.ini")
+	if _, err := os.Stat(iniPath); err == nil {
+		return nil
+	}
+
+	template, err := os.ReadFile(filepath.Join(installPath, "php.ini-development"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read php.ini-development: %w", err)
+	}
+
+	return os.WriteFile(iniPath, []byte(windowsIni(string(template), filepath.Join(installPath, "ext"))), 0644)
+}
+
+// windowsIni returns the php.ini template with extension_dir set to extDir and the
+// lines enabling windowsExtensions uncommented
+func windowsIni(template, extDir string) string {
+	lines := strings.Split(template, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		switch {
+		case strings.HasPrefix(trimmed, ";extension_dir") && strings.Contains(trimmed, `"ext"`):
+			lines[i] = fmt.Sprintf("extension_dir = %q", extDir)
+		case strings.HasPrefix(trimmed, ";extension="):
+			name := strings.TrimPrefix(trimmed, ";extension=")
+			for _, ext := range windowsExtensions {
+				if name == ext {
+					lines[i] = "extension=" + ext
+				}
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Prefetch downloads the archive for a version into the archive cache
+func (p *Provider) Prefetch(version string) error {
+	resolved, err := p.ResolveDownload(version)
+	if err != nil {
+		return err
+	}
+	return download.PrefetchArchive("php", version, resolved.URL, download.ArchiveName(resolved.URL, resolved.Format), resolved.SHA256)
+}
+
+// ResolveDownload returns where the archive for a version on the current platform comes from
+func (p *Provider) ResolveDownload(version string) (*runtime.ResolvedDownload, error) {
+	// Get the manifest (uses cached remote with embedded fallback)
+	m, err := manifest.DefaultSource().GetManifest("php")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
+	}
+	return downloadFromManifest(m, version, manifest.CurrentPlatform())
+}
+
+// downloadFromManifest returns the download for a version and platform in m
+func downloadFromManifest(m *manifest.Manifest, version, platform string) (*runtime.ResolvedDownload, error) {
+	dl := m.GetDownload(version, platform)
+	if dl == nil {
+		return nil, &runtime.VersionNotAvailableError{
+			Runtime:     "php",
+			DisplayName: "PHP",
+			Version:     version,
+			Platform:    platform,
+			Hint:        m.EmulationHint(version, platform),
+		}
+	}
+
+	return &runtime.ResolvedDownload{
+		Platform:     platform,
+		URL:          dl.URL,
+		SHA256:       dl.SHA256,
+		SHA256Source: dl.SHA256Source,
+		Format:       dl.Format,
+	}, nil
+}
+
+// createShims creates shims for PHP executables
+func (p *Provider) createShims() error {
+	manager, err := shim.NewManager()
+	if err != nil {
+		return err
+	}
+	return manager.CreateShims(shim.RuntimeShims("php"))
+}
+
+// Uninstall removes an installed version
+func (p *Provider) Uninstall(version string) error {
+	return runtime.ErrNotImplemented
+}
+
+// ListInstalled returns all installed PHP versions
+func (p *Provider) ListInstalled() ([]runtime.InstalledVersion, error) {
+	phpVersionsDir := filepath.Join(config.DefaultPaths().Versions, "php")
+
+	entries, err := os.ReadDir(phpVersionsDir)
+	if os.IsNotExist(err) {
+		return []runtime.InstalledVersion{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read versions directory: %w", err)
+	}
+
+	versions := make([]runtime.InstalledVersion, 0)
+	for _, entry := range entries {
+		// Skip stray files (e.g. .DS_Store) and directories that aren't versions
+		if runtime.IsVersionDir(phpVersionsDir, entry) {
+			versions = append(versions, runtime.InstalledVersion{
+				Version:     runtime.NewVersion(entry.Name()),
+				InstallPath: filepath.Join(phpVersionsDir, entry.Name()),
+			})
+		}
+	}
+
+	return versions, nil
+}
+
+// ListAvailable returns all available PHP versions
+func (p *Provider) ListAvailable() ([]runtime.AvailableVersion, error) {
+	m, err := manifest.DefaultSource().GetManifest("php")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
+	}
+
+	return m.AvailableVersions(manifest.CurrentPlatform()), nil
+}
+
+// ExecutablePath returns the path to the PHP executable: php.exe in the version
+// directory on Windows, bin/php elsewhere
+func (p *Provider) ExecutablePath(version string) (string, error) {
+	installPath, err := p.InstallPath(version)
+	if err != nil {
+		return "", err
+	}
+
+	phpPath := filepath.Join(installPath, "bin", "php")
+	if goruntime.GOOS == constants.OSWindows {
+		phpPath = filepath.Join(installPath, "php.exe")
+	}
+
+	if _, err := os.Stat(phpPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("php executable not found at %s", phpPath)
+	}
+
+	return phpPath, nil
+}
+
+// ShimExecutablePath returns the executable a shim runs. The composer shim runs the
+// Composer wrapper of the version, which is missing when downloading Composer after
+// the install failed; that is reported instead of running something else.
+func (p *Provider) ShimExecutablePath(version, shimName string) (string, error) {
+	if shimName != "composer" {
+		return p.ExecutablePath(version)
+	}
+
+	installPath, err := p.InstallPath(version)
+	if err != nil {
+		return "", err
+	}
+	wrapper := composerWrapperPath(installPath)
+	if _, err := os.Stat(wrapper); err != nil {
+		return "", fmt.Errorf("composer is not installed in PHP %s; run 'dtvem doctor --fix' to install it", version)
+	}
+	return wrapper, nil
+}
+
+// IsInstalled checks if a version is installed
+func (p *Provider) IsInstalled(version string) (bool, error) {
+	_, err := os.Stat(config.RuntimeVersionPath("php", version))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// InstallPath returns the installation directory for a version
+func (p *Provider) InstallPath(version string) (string, error) {
+	return config.RuntimeVersionPath("php", version), nil
+}
+
+// GlobalVersion returns the globally configured version
+func (p *Provider) GlobalVersion() (string, error) {
+	return config.GlobalVersion("php")
+}
+
+// SetGlobalVersion sets the global default version
+func (p *Provider) SetGlobalVersion(version string) error {
+	return config.SetGlobalVersion("php", version)
+}
+
+// LocalVersion returns the locally configured version
+func (p *Provider) LocalVersion() (string, error) {
+	return config.ResolveVersion("php")
+}
+
+// SetLocalVersion sets the local version for current directory
+func (p *Provider) SetLocalVersion(version string) error {
+	return config.SetLocalVersion("php", version)
+}
+
+// CurrentVersion returns the currently active version
+func (p *Provider) CurrentVersion() (string, error) {
+	return config.ResolveVersion("php")
+}
+
+// DetectInstalled returns no versions; there are no PHP migration providers yet
+func (p *Provider) DetectInstalled() ([]runtime.DetectedVersion, error) {
+	return []runtime.DetectedVersion{}, nil
+}
+
+// GlobalPackages returns no packages. Composer's global packages live in
+// COMPOSER_HOME, which all PHP versions share, so there is nothing to carry over.
+func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
+	return []string{}, nil
+}
+
+// InstallGlobalPackages does nothing, see GlobalPackages
+func (p *Provider) InstallGlobalPackages(version string, packages []string) error {
+	return nil
+}
+
+// ManualPackageInstallCommand returns the command for manually installing global Composer packages
+func (p *Provider) ManualPackageInstallCommand(packages []string) string {
+	if len(packages) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("composer global require %s", strings.Join(packages, " "))
+}
+
+// ShouldReshimAfter returns false: Composer installs global packages' executables
+// into COMPOSER_HOME, outside the version, where reshim doesn't look
+func (p *Provider) ShouldReshimAfter(shimName string, args []string) bool {
+	return false
+}
+
+// Capabilities reports that PHP has no packages to migrate
+func (p *Provider) Capabilities() runtime.ProviderCapabilities {
+	return runtime.ProviderCapabilities{}
+}
+
+// MinSupportedVersion returns 8.1.0, the oldest series static-php-cli builds
+func (p *Provider) MinSupportedVersion() (runtime.Version, bool) {
+	return runtime.NewVersion("8.1.0"), true
+}
+
+// VersionArgs returns the arguments that make php print its version
+func (p *Provider) VersionArgs() []string {
+	return []string{"--version"}
+}
+
+// phpVersionPattern matches the version in `php --version` output, with an optional
+// prerelease tag as in "8.4.0RC1"
+var phpVersionPattern = regexp.MustCompile(`^(\d+\.\d+\.\d+)(?:-?([A-Za-z][A-Za-z0-9.]*))?$`)
+
+// ParseVersionOutput extracts the version from `php --version` output, e.g.
+// "PHP 8.3.10 (cli) (built: Jul 30 2024 15:15:59) (NTS)"
+func (p *Provider) ParseVersionOutput(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 || fields[0] != "PHP" {
+		return "", fmt.Errorf("unexpected php --version output: %q", strings.TrimSpace(output))
+	}
+	matches := phpVersionPattern.FindStringSubmatch(fields[1])
+	if matches == nil {
+		return "", fmt.Errorf("unexpected php --version output: %q", strings.TrimSpace(output))
+	}
+	if matches[2] != "" {
+		return matches[1] + "-" + matches[2], nil
+	}
+	return matches[1], nil
+}
+
+// GetEnvironment returns no variables; both kinds of builds are self-contained
+func (p *Provider) GetEnvironment(version string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+// init registers the PHP provider on package load
+func init() {
+	if err := runtime.Register(NewProvider()); err != nil {
+		panic(fmt.Sprintf("failed to register PHP provider: %v", err))
+	}
+}
@@ -0,0 +1,247 @@
+package php
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/manifest"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// TestPhpProviderContract runs the generic provider test harness
+// This ensures the PHP provider correctly implements the Provider interface
+func TestPhpProviderContract(t *testing.T) {
+	harness := &runtime.ProviderTestHarness{
+		Provider:            NewProvider(),
+		T:                   t,
+		ExpectedName:        "php",
+		ExpectedDisplayName: "PHP",
+		SampleVersion:       "8.3.10",
+	}
+
+	harness.RunAllTests()
+}
+
+func TestPhpProvider_Shims(t *testing.T) {
+	if got, want := NewProvider().Shims(), []string{"php", "composer"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Shims() = %v, want %v", got, want)
+	}
+}
+
+func TestPhpProvider_Capabilities(t *testing.T) {
+	if got, want := runtime.CapabilitiesOf(NewProvider()), (runtime.ProviderCapabilities{}); got != want {
+		t.Errorf("Capabilities() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPhpProvider_ManualPackageInstallCommand(t *testing.T) {
+	p := NewProvider()
+	if got, want := p.ManualPackageInstallCommand([]string{"laravel/installer", "phpunit/phpunit"}), "composer global require laravel/installer phpunit/phpunit"; got != want {
+		t.Errorf("ManualPackageInstallCommand() = %q, want %q", got, want)
+	}
+	if got := p.ManualPackageInstallCommand(nil); got != "" {
+		t.Errorf("ManualPackageInstallCommand(nil) = %q, want empty", got)
+	}
+}
+
+func TestPhpProvider_ParseVersionOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "release", output: "PHP 8.3.10 (cli) (built: Jul 30 2024 15:15:59) (NTS)\nCopyright (c) The PHP Group\nZend Engine v4.3.10\n", want: "8.3.10"},
+		{name: "Windows line endings", output: "PHP 7.4.33 (cli) (built: Nov  2 2022 16:00:55) ( NTS Visual C++ 2017 x64 )\r\n", want: "7.4.33"},
+		{name: "release candidate", output: "PHP 8.4.0RC1 (cli) (built: Sep 24 2024 10:00:00) (NTS)\n", want: "8.4.0-RC1"},
+		{name: "other program", output: "HipHop VM 4.172.1 (rel)\n", wantErr: true},
+		{name: "error message", output: "PHP Warning:  PHP Startup: Unable to load dynamic library 'zip'\n", wantErr: true},
+		{name: "empty", output: "", wantErr: true},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ParseVersionOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersionOutput(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersionOutput(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailableVersions_ReleaseNotes(t *testing.T) {
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"7.4.33": {manifest.PlatformLinuxAMD64: {URL: "https://example.com/php/7.4.33/linux-amd64.tar.gz"}},
+			"8.3.10": {manifest.PlatformLinuxAMD64: {URL: "https://example.com/php/8.3.10/linux-amd64.tar.gz"}},
+			"8.2.22": {manifest.PlatformWindowsAMD64: {URL: "https://example.com/php/8.2.22/windows-amd64.zip"}},
+		},
+		Releases: map[string]*manifest.Release{
+			"7.4.33": {Date: "2022-11-03", EOL: true},
+		},
+	}
+
+	got := m.AvailableVersions(manifest.PlatformLinuxAMD64)
+	if len(got) != 2 {
+		t.Fatalf("AvailableVersions() returned %d versions, want 2", len(got))
+	}
+	if got[0].Version.Raw != "8.3.10" || got[1].Version.Raw != "7.4.33" {
+		t.Errorf("AvailableVersions() = %s, %s, want newest first", got[0].Version.Raw, got[1].Version.Raw)
+	}
+	if got[1].Notes != "EOL" {
+		t.Errorf("Notes for 7.4.33 = %q, want EOL", got[1].Notes)
+	}
+
+	if _, err := downloadFromManifest(m, "8.2.22", manifest.PlatformLinuxAMD64); err == nil {
+		t.Error("downloadFromManifest() succeeded for a version without a build for the platform")
+	}
+}
+
+func TestDownloadFromManifest_DeclaredFormat(t *testing.T) {
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"8.3.10": {manifest.PlatformLinuxAMD64: {URL: "https://mirror.example.com/php/8.3.10/linux-amd64", Format: "tar.gz"}},
+		},
+	}
+
+	resolved, err := downloadFromManifest(m, "8.3.10", manifest.PlatformLinuxAMD64)
+	if err != nil {
+		t.Fatalf("downloadFromManifest() error: %v", err)
+	}
+	if resolved.Format != "tar.gz" {
+		t.Errorf("Format = %q, want tar.gz", resolved.Format)
+	}
+}
+
+func TestMoveBinaryToBin(t *testing.T) {
+	installPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(installPath, "php"), []byte("binary"), 0644); err != nil {
+		t.Fatalf("Failed to create php: %v", err)
+	}
+
+	if err := moveBinaryToBin(installPath); err != nil {
+		t.Fatalf("moveBinaryToBin() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installPath, "bin", "php")); err != nil {
+		t.Errorf("bin/php is missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installPath, "php")); !os.IsNotExist(err) {
+		t.Errorf("php was left in the version directory")
+	}
+
+	// An install that already has bin/ is left alone
+	if err := moveBinaryToBin(installPath); err != nil {
+		t.Errorf("moveBinaryToBin() on a normalized install error: %v", err)
+	}
+}
+
+func TestWindowsIni(t *testing.T) {
+	template := strings.Join([]string{
+		"[PHP]",
+		`; extension_dir = "./"`,
+		`; On windows:`,
+		`;extension_dir = "ext"`,
+		";extension=bz2",
+		";extension=curl",
+		";extension=mbstring",
+		";extension=openssl",
+		";extension=zip",
+		"",
+	}, "\r\n")
+
+	got := windowsIni(template, `C:\dtvem\versions\php\8.3.10\ext`)
+	for _, want := range []string{
+		`extension_dir = "C:\\dtvem\\versions\\php\\8.3.10\\ext"`,
+		"\nextension=curl",
+		"\nextension=mbstring",
+		"\nextension=openssl",
+		"\nextension=zip",
+		";extension=bz2",
+		`; extension_dir = "./"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("windowsIni() does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestPhpProvider_ShimExecutablePath(t *testing.T) {
+	t.Setenv("DTVEM_ROOT", t.TempDir())
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	p := NewProvider()
+	installPath := config.RuntimeVersionPath("php", "8.3.10")
+	phpPath := filepath.Join(installPath, "bin", "php")
+	if goruntime.GOOS == constants.OSWindows {
+		phpPath = filepath.Join(installPath, "php.exe")
+	}
+	if err := os.MkdirAll(filepath.Dir(phpPath), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	if err := os.WriteFile(phpPath, []byte("binary"), 0755); err != nil {
+		t.Fatalf("Failed to create php: %v", err)
+	}
+
+	if got, err := p.ShimExecutablePath("8.3.10", "php"); err != nil || got != phpPath {
+		t.Errorf("ShimExecutablePath(php) = %q, %v, want %q", got, err, phpPath)
+	}
+
+	// Without Composer the shim reports how to install it
+	_, err := p.ShimExecutablePath("8.3.10", "composer")
+	if err == nil || !strings.Contains(err.Error(), "dtvem doctor --fix") {
+		t.Errorf("ShimExecutablePath(composer) error = %v, want one pointing to 'dtvem doctor --fix'", err)
+	}
+	if problems := p.CheckHealth("8.3.10"); len(problems) != 1 || problems[0].Fix == nil {
+		t.Errorf("CheckHealth() = %+v, want one fixable problem", problems)
+	}
+
+	if err := writeComposerWrapper(filepath.Dir(phpPath)); err != nil {
+		t.Fatalf("writeComposerWrapper() error: %v", err)
+	}
+	if got, err := p.ShimExecutablePath("8.3.10", "composer"); err != nil || got != composerWrapperPath(installPath) {
+		t.Errorf("ShimExecutablePath(composer) = %q, %v, want %q", got, err, composerWrapperPath(installPath))
+	}
+	if problems := p.CheckHealth("8.3.10"); len(problems) != 0 {
+		t.Errorf("CheckHealth() = %+v, want no problems", problems)
+	}
+}
+
+func TestParseComposerChecksum(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{name: "checksum only", body: sum + "\n", want: sum},
+		{name: "with file name", body: strings.ToUpper(sum) + "  composer.phar\n", want: sum},
+		{name: "HTML error page", body: "<html>Not Found</html>", wantErr: true},
+		{name: "empty", body: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseComposerChecksum(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseComposerChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseComposerChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package python
+
+import (
+	"context"
+	"fmt"
+	"os"
+	goruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/runtime"
+)
+
+// pipCheckTimeout bounds how long `pip --version` may take
+const pipCheckTimeout = 30 * time.Second
+
+// ensurePipTimeout bounds how long bootstrapping pip with ensurepip may take
+const ensurePipTimeout = 5 * time.Minute
+
+// CheckHealth checks that an installed version has a pip that runs and, for a
+// Windows embeddable package, that its ._pth file enables site-packages (without
+// it pip and installed packages can't be imported)
+func (p *Provider) CheckHealth(version string) []runtime.HealthProblem {
+	installPath := config.RuntimeVersionPath("python", version)
+	var problems []runtime.HealthProblem
+
+	if goruntime.GOOS == constants.OSWindows {
+		pthFile := pthFilePath(installPath, version)
+		if problem := sitePackagesProblem(pthFile); problem != "" {
+			problems = append(problems, runtime.HealthProblem{
+				Problem:        fmt.Sprintf("Python %s: %s", version, problem),
+				FixDescription: fmt.Sprintf("Enable import site in %s", pthFile),
+				Fix:            func() error { return p.enableSitePackages(pthFile) },
+				Hint:           fmt.Sprintf("Uncomment 'import site' in %s", pthFile),
+			})
+		}
+	}
+
+	if problem := pipProblem(installPath); problem != "" {
+		problems = append(problems, runtime.HealthProblem{
+			Problem:        fmt.Sprintf("Python %s: %s", version, problem),
+			FixDescription: fmt.Sprintf("Reinstall pip into Python %s", version),
+			Fix:            func() error { return p.repairPip(version) },
+			Hint:           fmt.Sprintf("Download %s and run it with Python %s", p.getPipURL(version), version),
+		})
+	}
+
+	return problems
+}
+
+// pipProblem describes what is wrong with the pip of an installation, or returns ""
+// if pip is present and `pip --version` succeeds
+func pipProblem(installPath string) string {
+	pipPath := findPipInInstall(installPath)
+	if pipPath == "" {
+		return "pip is missing"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pipCheckTimeout)
+	defer cancel()
+	if _, err := runtime.RunPackageCommand(ctx, nil, pipPath, "--version"); err != nil {
+		return fmt.Sprintf("pip does not run: %v", err)
+	}
+	return ""
+}
+
+// sitePackagesProblem describes why a Windows embeddable package's ._pth file keeps
+// pip from working, or returns "" if it enables site-packages. Installs without the
+// file aren't embeddable packages and have no problem.
+func sitePackagesProblem(pthFile string) string {
+	content, err := os.ReadFile(pthFile)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		return fmt.Sprintf("cannot read %s: %v", pthFile, err)
+	}
+	if !sitePackagesEnabled(string(content)) {
+		return fmt.Sprintf("site-packages is disabled ('import site' is commented out in %s)", pthFile)
+	}
+	return ""
+}
+
+// sitePackagesEnabled reports whether the contents of a ._pth file enable site-packages
+func sitePackagesEnabled(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "import site" {
+			return true
+		}
+	}
+	return false
+}
+
+// repairPip reinstalls pip into an installed version. Windows embeddable packages
+// get the install-time bootstrap again; other builds ship ensurepip, with get-pip.py
+// from the URL for the version as the fallback.
+func (p *Provider) repairPip(version string) error {
+	if goruntime.GOOS == constants.OSWindows {
+		return p.installPip(version)
+	}
+
+	pythonPath, err := p.ExecutablePath(version)
+	if err != nil {
+		return fmt.Errorf("could not find python executable: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ensurePipTimeout)
+	defer cancel()
+	if _, err := runtime.RunPackageCommand(ctx, nil, pythonPath, "-m", "ensurepip", "--upgrade", "--default-pip"); err == nil {
+		return nil
+	}
+	return p.runGetPip(pythonPath, config.RuntimeVersionPath("python", version), version)
+}
@@ -0,0 +1,152 @@
+package python
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+)
+
+func TestSitePackagesEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"enabled", "python312.zip\n.\n\nimport site\n", true},
+		{"enabled with CRLF", "python312.zip\r\n.\r\nimport site\r\n", true},
+		{"commented out", "python312.zip\n.\n\n# Uncomment to run site.main() automatically\n#import site\n", false},
+		{"missing", "python312.zip\n.\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sitePackagesEnabled(tt.content); got != tt.want {
+				t.Errorf("sitePackagesEnabled(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSitePackagesProblem(t *testing.T) {
+	installPath := t.TempDir()
+	pthFile := pthFilePath(installPath, "3.12.1")
+	if filepath.Base(pthFile) != "python312._pth" {
+		t.Errorf("pthFilePath() = %q, want python312._pth", pthFile)
+	}
+
+	// Not an embeddable package
+	if problem := sitePackagesProblem(pthFile); problem != "" {
+		t.Errorf("sitePackagesProblem() without a ._pth file = %q, want none", problem)
+	}
+
+	if err := os.WriteFile(pthFile, []byte("python312.zip\n.\n#import site\n"), 0644); err != nil {
+		t.Fatalf("Failed to write ._pth file: %v", err)
+	}
+	if problem := sitePackagesProblem(pthFile); !strings.Contains(problem, "site-packages is disabled") {
+		t.Errorf("sitePackagesProblem() with import site commented out = %q", problem)
+	}
+
+	if err := NewProvider().enableSitePackages(pthFile); err != nil {
+		t.Fatalf("enableSitePackages() error: %v", err)
+	}
+	if problem := sitePackagesProblem(pthFile); problem != "" {
+		t.Errorf("sitePackagesProblem() after enabling = %q, want none", problem)
+	}
+}
+
+func TestCheckHealth_Pip(t *testing.T) {
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("fake pip executables are shell scripts")
+	}
+
+	tests := []struct {
+		name        string
+		pipScript   string
+		wantProblem string
+	}{
+		{name: "working pip", pipScript: "#!/bin/sh\necho 'pip 24.0'\n"},
+		{name: "broken pip", pipScript: "#!/bin/sh\necho 'bad interpreter' >&2\nexit 1\n", wantProblem: "pip does not run"},
+		{name: "missing pip", wantProblem: "pip is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DTVEM_ROOT", t.TempDir())
+			config.ResetPathsCache()
+			t.Cleanup(config.ResetPathsCache)
+
+			binDir := filepath.Join(config.RuntimeVersionPath("python", "3.12.1"), "bin")
+			if err := os.MkdirAll(binDir, 0755); err != nil {
+				t.Fatalf("Failed to create version directory: %v", err)
+			}
+			if tt.pipScript != "" {
+				if err := os.WriteFile(filepath.Join(binDir, "pip"), []byte(tt.pipScript), 0755); err != nil {
+					t.Fatalf("Failed to create fake pip: %v", err)
+				}
+			}
+
+			problems := NewProvider().CheckHealth("3.12.1")
+			if tt.wantProblem == "" {
+				if len(problems) != 0 {
+					t.Errorf("CheckHealth() = %+v, want no problems", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.Contains(problems[0].Problem, tt.wantProblem) {
+				t.Fatalf("CheckHealth() = %+v, want one problem containing %q", problems, tt.wantProblem)
+			}
+			if problems[0].Fix == nil || !strings.Contains(problems[0].Hint, "get-pip.py") {
+				t.Errorf("CheckHealth() problem = %+v, want a fix and a get-pip.py hint", problems[0])
+			}
+		})
+	}
+}
+
+func TestPythonProvider_PostInstall(t *testing.T) {
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("fake pip executables are shell scripts")
+	}
+
+	tests := []struct {
+		name      string
+		pipScript string
+		wantErr   bool
+	}{
+		{name: "included pip works", pipScript: "#!/bin/sh\necho 'pip 24.0'\n"},
+		// Without a python to run ensurepip, the repair fails
+		{name: "pip missing and cannot be installed", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DTVEM_ROOT", t.TempDir())
+			config.ResetPathsCache()
+			t.Cleanup(config.ResetPathsCache)
+
+			p := NewProvider()
+			err := p.install("3.12.1", false, func(installPath string) error {
+				binDir := filepath.Join(installPath, "bin")
+				if err := os.MkdirAll(binDir, 0755); err != nil {
+					return err
+				}
+				if tt.pipScript == "" {
+					return nil
+				}
+				return os.WriteFile(filepath.Join(binDir, "pip"), []byte(tt.pipScript), 0755)
+			})
+			// A failed post-install step leaves a usable install
+			if err != nil {
+				t.Fatalf("install() error: %v", err)
+			}
+
+			if err := p.PostInstall("3.12.1"); (err != nil) != tt.wantErr {
+				t.Errorf("PostInstall() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
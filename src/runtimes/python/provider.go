@@ -2,6 +2,7 @@
 package python
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -45,48 +46,41 @@ func (p *Provider) Shims() []string {
 	return []string{"python", "python3", "pip", "pip3"}
 }
 
-// Install downloads and installs a specific version
-// downloadAndExtract downloads and extracts the Python archive
-func (p *Provider) downloadAndExtract(version, downloadURL, archiveName string) (extractDir string, cleanup func(), err error) {
-	ui.Progress("Downloading from %s", downloadURL)
-
+// installArchive extracts a Python archive and moves its contents to installPath
+func (p *Provider) installArchive(version, archivePath, installPath string, progress *ui.ProgressLine) error {
 	// Create temporary directory
-	tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("dtvem-python-%s", version))
+	tempDir := download.TempDir("python", version)
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+		progress.Error("Extraction failed")
+		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
 
-	cleanupFunc := func() { _ = os.RemoveAll(tempDir) }
+	// Extract archive
+	extractDir := filepath.Join(tempDir, "extracted")
+	progress.Extract()
 
-	// Download archive
-	archivePath := filepath.Join(tempDir, archiveName)
-	if err := download.File(downloadURL, archivePath); err != nil {
-		cleanupFunc()
-		return "", nil, fmt.Errorf("failed to download: %w", err)
+	if err := download.ExtractWithProgress(archivePath, extractDir, progress.FileExtracted); err != nil {
+		progress.Error("Extraction failed")
+		return fmt.Errorf("failed to extract: %w", err)
 	}
+	progress.Success("Extracted " + filepath.Base(archivePath))
 
-	// Extract archive
-	extractDir = filepath.Join(tempDir, "extracted")
-	spinner := ui.NewSpinner("Extracting archive...")
-	spinner.Start()
-
-	var extractErr error
-	if strings.HasSuffix(archiveName, ".zip") {
-		extractErr = download.ExtractZip(archivePath, extractDir)
-	} else if strings.HasSuffix(archiveName, ".tar.gz") {
-		extractErr = download.ExtractTarGz(archivePath, extractDir)
-	} else {
-		extractErr = fmt.Errorf("unsupported archive format: %s", archiveName)
+	// Determine source directory
+	sourceDir := determineSourceDir(extractDir)
+	ui.Debug("Source directory: %s", sourceDir)
+	ui.Debug("Install path: %s", installPath)
+
+	if err := os.MkdirAll(filepath.Dir(installPath), 0755); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
 	}
 
-	if extractErr != nil {
-		spinner.Error("Extraction failed")
-		cleanupFunc()
-		return "", nil, fmt.Errorf("failed to extract: %w", extractErr)
+	ui.Debug("Moving files from %s to %s", sourceDir, installPath)
+	if err := os.Rename(sourceDir, installPath); err != nil {
+		return fmt.Errorf("failed to move to install location: %w", err)
 	}
 
-	spinner.Success("Extraction complete")
-	return extractDir, cleanupFunc, nil
+	return nil
 }
 
 // determineSourceDir determines the source directory from extracted archive
@@ -102,27 +96,34 @@ func determineSourceDir(extractDir string) string {
 	return extractDir
 }
 
-// installPipIfNeeded installs pip on Windows or shows success message on Unix
-func (p *Provider) installPipIfNeeded(version string) {
-	if goruntime.GOOS == constants.OSWindows {
-		// Windows embeddable packages need pip installed
-		pipSpinner := ui.NewSpinner("Installing pip...")
-		pipSpinner.Start()
-		if err := p.installPip(version); err != nil {
-			pipSpinner.Warning("Failed to install pip")
-			ui.Info("To install pip manually:")
-			ui.Info("  1. Download: %s", p.getPipURL(version))
-			ui.Info("  2. Run: python get-pip.py")
-		} else {
-			pipSpinner.Success("pip installed successfully")
-		}
-	} else {
-		// python-build-standalone includes pip
-		ui.Success("pip included")
-	}
+// Install downloads and installs a specific version
+func (p *Provider) Install(version string) error {
+	return p.install(version, true, func(installPath string) error {
+		return p.installFiles(version, manifest.CurrentPlatform(), installPath)
+	})
 }
 
-func (p *Provider) Install(version string) error {
+// InstallWithoutShims downloads and installs a specific version without creating
+// or updating shims, so it is only reachable through exec and env
+func (p *Provider) InstallWithoutShims(version string) error {
+	return p.install(version, false, func(installPath string) error {
+		return p.installFiles(version, manifest.CurrentPlatform(), installPath)
+	})
+}
+
+// InstallFromArchive installs a version from a local archive instead of downloading it
+func (p *Provider) InstallFromArchive(version, archivePath string) error {
+	return p.install(version, true, func(installPath string) error {
+		ui.Progress("Installing from %s", archivePath)
+		progress := ui.NewProgressLine()
+		progress.Start()
+		return p.installArchive(version, archivePath, installPath, progress)
+	})
+}
+
+// install puts a version's files in place with installFiles, then creates the shims (if withShims
+// is set) and runs PostInstall to set up pip
+func (p *Provider) install(version string, withShims bool, installFiles func(installPath string) error) error {
 	ui.Debug("Starting Python installation for version %s", version)
 
 	// Ensure dtvem directories exist
@@ -130,87 +131,174 @@ func (p *Provider) Install(version string) error {
 		return fmt.Errorf("failed to create dtvem directories: %w", err)
 	}
 
-	// Check if already installed
+	// Wait for another dtvem process installing the same version, then see if it did
+	lock, err := download.LockInstall("python", version)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// Check if already installed, which is what was asked for if another process
+	// installed it while this one waited
 	if installed, _ := p.IsInstalled(version); installed {
+		if lock.Waited() {
+			ui.Success("Python %s was installed by another dtvem process", version)
+			return nil
+		}
 		return fmt.Errorf("Python %s is already installed", version)
 	}
 
 	ui.Header("Installing Python v%s...", version)
 
-	// Get platform-specific download URL
-	downloadURL, archiveName, err := p.getDownloadURL(version)
-	if err != nil {
-		return fmt.Errorf("failed to get download URL: %w", err)
-	}
-	ui.Debug("Download URL: %s", downloadURL)
-	ui.Debug("Archive name: %s", archiveName)
-
-	// Download and extract
-	extractDir, cleanup, err := p.downloadAndExtract(version, downloadURL, archiveName)
-	if err != nil {
+	installPath := config.RuntimeVersionPath("python", version)
+	if err := installFiles(installPath); err != nil {
 		return err
 	}
-	defer cleanup()
 
-	// Determine source directory
-	sourceDir := determineSourceDir(extractDir)
-	ui.Debug("Source directory: %s", sourceDir)
+	if withShims {
+		shimSpinner := ui.NewSpinner("Creating shims...")
+		shimSpinner.Start()
+		if err := p.createShims(); err != nil {
+			shimSpinner.Error("Failed to create shims")
+			return fmt.Errorf("failed to create shims: %w", err)
+		}
+		shimSpinner.Success("Shims created")
+	}
 
-	// Get install path and move files
-	installPath := config.RuntimeVersionPath("python", version)
-	ui.Debug("Install path: %s", installPath)
+	ui.Success("Python v%s installed successfully", version)
+	ui.Info("Location: %s", installPath)
 
-	if err := os.MkdirAll(filepath.Dir(installPath), 0755); err != nil {
-		return fmt.Errorf("failed to create install directory: %w", err)
+	if err := runtime.RunPostInstall(p, version); err != nil {
+		ui.Debug("Post-install failed: %v", err)
+		ui.Info("To install pip manually:")
+		ui.Info("  1. Download: %s", p.getPipURL(version))
+		ui.Info("  2. Run: python get-pip.py")
 	}
 
-	ui.Debug("Moving files from %s to %s", sourceDir, installPath)
-	if err := os.Rename(sourceDir, installPath); err != nil {
-		return fmt.Errorf("failed to move to install location: %w", err)
-	}
+	return nil
+}
 
-	// Create shims
-	shimSpinner := ui.NewSpinner("Creating shims...")
-	shimSpinner.Start()
-	if err := p.createShims(); err != nil {
-		shimSpinner.Error("Failed to create shims")
-		return fmt.Errorf("failed to create shims: %w", err)
+// CheckArch returns an error if the version has no download for arch on this OS
+func (p *Provider) CheckArch(version, arch string) error {
+	_, _, err := p.getDownloadURL(version, manifest.PlatformForArch(arch))
+	return err
+}
+
+// InstallArch installs the build of a version for arch into installPath, without shims
+func (p *Provider) InstallArch(version, arch, installPath string) error {
+	ui.Header("Installing Python v%s (%s)...", version, arch)
+
+	if err := p.installFiles(version, manifest.PlatformForArch(arch), installPath); err != nil {
+		return err
 	}
-	shimSpinner.Success("Shims created")
 
-	ui.Success("Python v%s installed successfully", version)
+	ui.Success("Python v%s (%s) installed successfully", version, arch)
 	ui.Info("Location: %s", installPath)
+	return nil
+}
+
+// installFiles downloads the archive of a version for platform and installs its contents to installPath
+func (p *Provider) installFiles(version, platform, installPath string) error {
+	// Get platform-specific download URL and checksum
+	resolved, err := p.resolveDownload(version, platform)
+	if err != nil {
+		return fmt.Errorf("failed to get download URL: %w", err)
+	}
+	downloadURL, archiveName := resolved.URL, download.ArchiveName(resolved.URL, resolved.Format)
 
-	// Install/verify pip
-	p.installPipIfNeeded(version)
+	// A corrupt archive is discarded and downloaded once more
+	return download.RetryCorrupt("python", version, archiveName, func() error {
+		ui.Progress("Downloading from %s", downloadURL)
 
-	return nil
+		// Download and extract the archive on a single progress line
+		progress := ui.NewProgressLine()
+		progress.Start()
+
+		// Download archive (kept in the cache until the install succeeds)
+		archivePath, err := download.CachedArchiveWithProgress("python", version, downloadURL, archiveName, resolved.SHA256, progress.Download)
+		if err != nil {
+			progress.Error("Download failed")
+			return fmt.Errorf("failed to download: %w", err)
+		}
+
+		if err := p.installArchive(version, archivePath, installPath, progress); err != nil {
+			return err
+		}
+		download.RemoveCachedArchive("python", version, archiveName)
+
+		return nil
+	})
 }
 
-// getDownloadURL returns the download URL and archive name for a given version
-func (p *Provider) getDownloadURL(version string) (string, string, error) {
+// Prefetch downloads the archive for a version into the archive cache
+func (p *Provider) Prefetch(version string) error {
+	resolved, err := p.resolveDownload(version, manifest.CurrentPlatform())
+	if err != nil {
+		return err
+	}
+	return download.PrefetchArchive("python", version, resolved.URL, download.ArchiveName(resolved.URL, resolved.Format), resolved.SHA256)
+}
+
+// ResolveDownload returns where the archive for a version on the current platform comes from
+func (p *Provider) ResolveDownload(version string) (*runtime.ResolvedDownload, error) {
+	return p.resolveDownload(version, manifest.CurrentPlatform())
+}
+
+// getDownloadURL returns the download URL and archive name for a given version and platform
+func (p *Provider) getDownloadURL(version, platform string) (string, string, error) {
+	resolved, err := p.resolveDownload(version, platform)
+	if err != nil {
+		return "", "", err
+	}
+	return resolved.URL, download.ArchiveName(resolved.URL, resolved.Format), nil
+}
+
+// resolveDownload looks up the download for a version and platform in the manifest
+func (p *Provider) resolveDownload(version, platform string) (*runtime.ResolvedDownload, error) {
 	// Get the manifest (uses cached remote with embedded fallback)
 	m, err := manifest.DefaultSource().GetManifest("python")
 	if err != nil {
-		return "", "", fmt.Errorf("failed to load manifest: %w", err)
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
 	}
 
 	// Get the download info for this version and platform
-	platform := manifest.CurrentPlatform()
 	dl := m.GetDownload(version, platform)
 	if dl == nil {
-		return "", "", fmt.Errorf("Python %s is not available for %s", version, platform)
+		if base, build := manifest.SplitBuild(version); build != "" {
+			return nil, fmt.Errorf("Python %s build %s is not available for %s%s", base, build, platform, availableBuildsHint(m.Builds(version, platform)))
+		}
+		return nil, &runtime.VersionNotAvailableError{
+			Runtime:     "python",
+			DisplayName: "Python",
+			Version:     version,
+			Platform:    platform,
+			Hint:        m.EmulationHint(version, platform),
+		}
 	}
 
-	// Extract archive name from URL
-	archiveName := filepath.Base(dl.URL)
+	return &runtime.ResolvedDownload{
+		Platform:     platform,
+		URL:          dl.URL,
+		SHA256:       dl.SHA256,
+		SHA256Source: dl.SHA256Source,
+		Format:       dl.Format,
+	}, nil
+}
 
-	return dl.URL, archiveName, nil
+// availableBuildsHint returns a note listing the available build tags for an error message
+func availableBuildsHint(builds []string) string {
+	if len(builds) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (available builds: %s)", strings.Join(builds, ", "))
 }
 
+// newShimManager creates the shim manager, replaced in tests that have no shim executable
+var newShimManager = shim.NewManager
+
 // createShims creates shims for Python executables
 func (p *Provider) createShims() error {
-	manager, err := shim.NewManager()
+	manager, err := newShimManager()
 	if err != nil {
 		return err
 	}
@@ -222,6 +310,25 @@ func (p *Provider) createShims() error {
 	return manager.CreateShims(shimNames)
 }
 
+// PostInstall makes sure a newly installed version has a working pip. Windows
+// embeddable packages don't include pip, so it is installed; python-build-standalone
+// builds include it and are only repaired if it doesn't run.
+func (p *Provider) PostInstall(version string) error {
+	if goruntime.GOOS != constants.OSWindows && pipProblem(config.RuntimeVersionPath("python", version)) == "" {
+		ui.Success("pip included")
+		return nil
+	}
+
+	pipSpinner := ui.NewSpinner("Installing pip...")
+	pipSpinner.Start()
+	if err := p.repairPip(version); err != nil {
+		pipSpinner.Warning("Failed to install pip")
+		return fmt.Errorf("failed to install pip: %w", err)
+	}
+	pipSpinner.Success("pip installed successfully")
+	return nil
+}
+
 // installPip installs pip for Windows embeddable Python packages
 func (p *Provider) installPip(version string) error {
 	pythonPath, err := p.ExecutablePath(version)
@@ -236,12 +343,27 @@ func (p *Provider) installPip(version string) error {
 	// 2. Download and run get-pip.py
 
 	// Step 1: Enable site-packages by uncommenting the import site line
-	pthFile := filepath.Join(installPath, fmt.Sprintf("python%s._pth", strings.Join(strings.Split(version, ".")[:2], "")))
-	if err := p.enableSitePackages(pthFile); err != nil {
+	if err := p.enableSitePackages(pthFilePath(installPath, version)); err != nil {
 		return fmt.Errorf("failed to enable site-packages: %w", err)
 	}
 
-	// Step 2: Download get-pip.py (use version-specific URL for older Python)
+	// Step 2: Download and run get-pip.py
+	return p.runGetPip(pythonPath, installPath, version)
+}
+
+// pthFilePath returns the ._pth file of a Windows embeddable package, which lists
+// the module search paths (e.g. python311._pth for 3.11.x)
+func pthFilePath(installPath, version string) string {
+	parts := strings.Split(version, ".")
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return filepath.Join(installPath, fmt.Sprintf("python%s._pth", strings.Join(parts, "")))
+}
+
+// runGetPip downloads get-pip.py (from the URL for the version, since older Pythons
+// need their own) and runs it with the version's python
+func (p *Provider) runGetPip(pythonPath, installPath, version string) error {
 	getPipURL := p.getPipURL(version)
 	getPipPath := filepath.Join(installPath, "get-pip.py")
 	if err := download.File(getPipURL, getPipPath); err != nil {
@@ -249,7 +371,6 @@ func (p *Provider) installPip(version string) error {
 	}
 	defer func() { _ = os.Remove(getPipPath) }()
 
-	// Step 3: Run get-pip.py
 	cmd := exec.Command(pythonPath, getPipPath)
 	cmd.Dir = installPath
 	output, err := cmd.CombinedOutput()
@@ -306,10 +427,44 @@ func (p *Provider) enableSitePackages(pthFile string) error {
 	return os.WriteFile(pthFile, []byte(newContent), 0644)
 }
 
-// Uninstall removes an installed version
+// Uninstall removes an installed Python version, along with the shims of console
+// scripts that only it provided
 func (p *Provider) Uninstall(version string) error {
-	// TODO: Implement Python uninstallation
-	return fmt.Errorf("not yet implemented")
+	// Don't pull the directory out from under another dtvem process installing it
+	lock, err := download.LockInstall("python", version)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if installed, _ := p.IsInstalled(version); !installed {
+		return fmt.Errorf("Python %s is not installed", version)
+	}
+
+	// Shims, including those of console scripts installed with pip, are left to
+	// the caller's rehash, so removing several versions rehashes only once
+	installPath := config.RuntimeVersionPath("python", version)
+	if err := removeInstallDir(installPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", installPath, err)
+	}
+	return nil
+}
+
+// removeInstallDir deletes an installed version. Windows refuses to delete read-only
+// files, which the embeddable package and compiled bytecode can leave behind, so a
+// failed removal is retried once everything has been made writable.
+func removeInstallDir(path string) error {
+	if err := os.RemoveAll(path); err == nil {
+		return nil
+	}
+
+	_ = filepath.WalkDir(path, func(p string, _ os.DirEntry, err error) error {
+		if err == nil {
+			_ = os.Chmod(p, 0755)
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
 }
 
 // ListInstalled returns all installed Python versions
@@ -331,7 +486,8 @@ func (p *Provider) ListInstalled() ([]runtime.InstalledVersion, error) {
 	// Build list of installed versions
 	versions := make([]runtime.InstalledVersion, 0)
 	for _, entry := range entries {
-		if entry.IsDir() {
+		// Skip stray files (e.g. .DS_Store) and directories that aren't versions
+		if runtime.IsVersionDir(pythonVersionsDir, entry) {
 			versions = append(versions, runtime.InstalledVersion{
 				Version:     runtime.NewVersion(entry.Name()),
 				InstallPath: filepath.Join(pythonVersionsDir, entry.Name()),
@@ -351,23 +507,7 @@ func (p *Provider) ListAvailable() ([]runtime.AvailableVersion, error) {
 		return nil, fmt.Errorf("failed to load manifest: %w", err)
 	}
 
-	// Get versions available for current platform
-	platform := manifest.CurrentPlatform()
-	versionStrings := m.ListAvailableVersions(platform)
-
-	// Convert to AvailableVersion format and sort by semantic version (newest first)
-	versions := make([]runtime.AvailableVersion, 0, len(versionStrings))
-	for _, v := range versionStrings {
-		versions = append(versions, runtime.AvailableVersion{
-			Version: runtime.NewVersion(v),
-			Notes:   "",
-		})
-	}
-
-	// Sort by version descending (newest first)
-	runtime.SortVersionsDesc(versions)
-
-	return versions, nil
+	return m.AvailableVersions(manifest.CurrentPlatform()), nil
 }
 
 // ExecutablePath returns the path to the Python executable
@@ -452,8 +592,15 @@ func (p *Provider) DetectInstalled() ([]runtime.DetectedVersion, error) {
 	return []runtime.DetectedVersion{}, nil
 }
 
-// GetGlobalPackages detects globally installed pip packages
+// GlobalPackages detects globally installed pip packages
 func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runtime.PackageListTimeout)
+	defer cancel()
+	return p.GlobalPackagesContext(ctx, installPath)
+}
+
+// GlobalPackagesContext detects globally installed pip packages, giving up when ctx ends
+func (p *Provider) GlobalPackagesContext(ctx context.Context, installPath string) ([]string, error) {
 	// Find pip executable in the installation
 	pipPath := findPipInInstall(installPath)
 	if pipPath == "" {
@@ -461,13 +608,25 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 	}
 
 	// Run pip list --format=json
-	cmd := exec.Command(pipPath, "list", "--format=json")
-	output, err := cmd.Output()
+	output, err := runtime.RunPackageCommand(ctx, nil, pipPath, "list", "--format=json")
+	if ctx.Err() != nil {
+		// Incomplete JSON can't be parsed, so a timeout finds no packages
+		packages, parseErr := parsePipList(output)
+		if parseErr != nil {
+			packages = []string{}
+		}
+		return packages, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pip packages: %w", err)
 	}
 
-	// Parse JSON output
+	return parsePipList(output)
+}
+
+// parsePipList returns the package names of `pip list --format=json` output,
+// except pip, setuptools and wheel which are built in
+func parsePipList(output []byte) ([]string, error) {
 	var packages []struct {
 		Name    string `json:"name"`
 		Version string `json:"version"`
@@ -477,7 +636,6 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 		return nil, fmt.Errorf("failed to parse pip list output: %w", err)
 	}
 
-	// Extract package names (exclude pip and setuptools which are built-in)
 	packageNames := make([]string, 0, len(packages))
 	for _, pkg := range packages {
 		name := strings.ToLower(pkg.Name)
@@ -491,6 +649,14 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 
 // InstallGlobalPackages reinstalls global packages to a specific version
 func (p *Provider) InstallGlobalPackages(version string, packages []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), runtime.PackageInstallTimeout)
+	defer cancel()
+	return p.InstallGlobalPackagesContext(ctx, version, packages)
+}
+
+// InstallGlobalPackagesContext reinstalls global packages to a specific version,
+// showing pip's output as it runs and giving up when ctx ends
+func (p *Provider) InstallGlobalPackagesContext(ctx context.Context, version string, packages []string) error {
 	if len(packages) == 0 {
 		return nil
 	}
@@ -510,12 +676,10 @@ func (p *Provider) InstallGlobalPackages(version string, packages []string) erro
 
 	// Install all packages at once
 	args := append([]string{"install"}, packages...)
-	cmd := exec.Command(pipPath, args...)
-
-	// Capture output for errors
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("pip install failed: %w\n%s", err, string(output))
+	output := ui.NewCommandOutput()
+	defer output.Flush()
+	if _, err := runtime.RunPackageCommand(ctx, output, pipPath, args...); err != nil {
+		return fmt.Errorf("pip install failed: %w", err)
 	}
 
 	return nil
@@ -560,22 +724,53 @@ func findPipInInstall(installDir string) string {
 	return ""
 }
 
+// reshimTriggers are the commands that can add or remove executables. uv and pipx
+// are typically installed with pip, so they run through Python shims.
+var reshimTriggers = []runtime.ReshimTrigger{
+	{
+		Shims:       []string{"pip", "pip3"},
+		Subcommands: []string{"install", "uninstall"},
+	},
+	{
+		Shims:       []string{"uv"},
+		Subcommands: []string{"tool install", "tool uninstall", "pip install", "pip uninstall"},
+	},
+	{
+		Shims:       []string{"pipx"},
+		Subcommands: []string{"install", "uninstall", "reinstall"},
+	},
+}
+
 // ShouldReshimAfter checks if the given command should trigger a reshim.
 // Returns true if the command installs or uninstalls packages.
 func (p *Provider) ShouldReshimAfter(shimName string, args []string) bool {
-	// pip, pip3 can install packages with executables
-	if shimName != "pip" && shimName != "pip3" {
-		return false
-	}
+	return runtime.ShouldReshim(reshimTriggers, shimName, args)
+}
 
-	// Need at least one argument (the command)
-	if len(args) == 0 {
-		return false
-	}
+// Capabilities reports that pip packages can be migrated and versions uninstalled.
+func (p *Provider) Capabilities() runtime.ProviderCapabilities {
+	return runtime.ProviderCapabilities{GlobalPackages: true, Uninstall: true}
+}
 
-	// Check if this is an install or uninstall command
-	cmd := args[0]
-	return cmd == "install" || cmd == "uninstall"
+// MinSupportedVersion returns 3.5.0: older versions have neither Windows embeddable
+// packages nor standalone builds
+func (p *Provider) MinSupportedVersion() (runtime.Version, bool) {
+	return runtime.NewVersion("3.5.0"), true
+}
+
+// VersionArgs returns the arguments that make python print its version
+func (p *Provider) VersionArgs() []string {
+	return []string{"--version"}
+}
+
+// ParseVersionOutput extracts the version from `python --version` output, e.g.
+// "Python 3.12.1"
+func (p *Provider) ParseVersionOutput(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 || fields[0] != "Python" || !runtime.LooksLikeVersion(fields[1]) {
+		return "", fmt.Errorf("unexpected python --version output: %q", strings.TrimSpace(output))
+	}
+	return fields[1], nil
 }
 
 // GetEnvironment returns environment variables needed to run Python binaries.
@@ -1,9 +1,16 @@
 package python
 
 import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
 	"testing"
 
+	"github.com/dtvem/dtvem/src/internal/config"
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/manifest"
 	"github.com/dtvem/dtvem/src/internal/runtime"
+	"github.com/dtvem/dtvem/src/internal/shim"
 	"github.com/dtvem/dtvem/src/internal/testutil"
 )
 
@@ -143,3 +150,302 @@ func TestPythonProvider_GetPipURL(t *testing.T) {
 		})
 	}
 }
+
+func TestAvailableBuildsHint(t *testing.T) {
+	if got := availableBuildsHint(nil); got != "" {
+		t.Errorf("availableBuildsHint(nil) = %q, want empty", got)
+	}
+	want := " (available builds: 20250115, 20251209)"
+	if got := availableBuildsHint([]string{"20250115", "20251209"}); got != want {
+		t.Errorf("availableBuildsHint() = %q, want %q", got, want)
+	}
+}
+
+func TestAvailableVersions_ReleaseNotes(t *testing.T) {
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"3.8.20": {manifest.PlatformLinuxAMD64: {URL: "https://example.com/python/3.8.20/linux-amd64.tar.gz"}},
+			"3.13.1": {manifest.PlatformLinuxAMD64: {URL: "https://example.com/python/3.13.1/linux-amd64.tar.gz"}},
+			"9.9.9":  {manifest.PlatformLinuxAMD64: {URL: "https://example.com/python/9.9.9/linux-amd64.tar.gz"}},
+		},
+		Releases: map[string]*manifest.Release{
+			"3.8.20": {Date: "2024-09-06", EOL: true},
+			"3.13.1": {Date: "2024-12-03"},
+		},
+	}
+
+	want := map[string]string{
+		"3.8.20": "EOL",
+		"3.13.1": "released 2024-12-03",
+		"9.9.9":  "",
+	}
+
+	got := m.AvailableVersions(manifest.PlatformLinuxAMD64)
+	if len(got) != len(want) {
+		t.Fatalf("AvailableVersions() returned %d versions, want %d", len(got), len(want))
+	}
+	for _, v := range got {
+		if v.Notes != want[v.Version.Raw] {
+			t.Errorf("Notes for %s = %q, want %q", v.Version.Raw, v.Notes, want[v.Version.Raw])
+		}
+	}
+	if got[0].Version.Raw != "9.9.9" {
+		t.Errorf("AvailableVersions()[0] = %s, want newest first", got[0].Version.Raw)
+	}
+}
+
+func TestAvailableVersions_SelectLatestMatching(t *testing.T) {
+	download := func(version string) *manifest.Download {
+		return &manifest.Download{URL: "https://example.com/python/" + version + ".tar.gz"}
+	}
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"3.12.1":  {manifest.PlatformLinuxAMD64: download("3.12.1")},
+			"3.12.8":  {manifest.PlatformLinuxAMD64: download("3.12.8")},
+			"3.12.10": {manifest.PlatformLinuxAMD64: download("3.12.10")},
+			// Newer, but without a build for this platform
+			"3.12.11":   {manifest.PlatformDarwinARM64: download("3.12.11")},
+			"3.13.0":    {manifest.PlatformLinuxAMD64: download("3.13.0")},
+			"3.13.2":    {manifest.PlatformLinuxAMD64: download("3.13.2")},
+			"3.14.0rc1": {manifest.PlatformLinuxAMD64: download("3.14.0rc1")},
+		},
+	}
+	available := m.AvailableVersions(manifest.PlatformLinuxAMD64)
+
+	tests := []struct {
+		prefix string
+		want   string
+		wantOK bool
+	}{
+		{"3.12", "3.12.10", true},
+		{"3.13", "3.13.2", true},
+		{"3.12.x", "3.12.10", true},
+		{"3", "3.13.2", true},
+		{"3.14", "", false},
+		{"3.11", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, ok := runtime.SelectLatestMatching(available, tt.prefix)
+			if ok != tt.wantOK || got.Version.Raw != tt.want {
+				t.Errorf("SelectLatestMatching(%q) = (%q, %v), want (%q, %v)", tt.prefix, got.Version.Raw, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPythonProvider_ShouldReshimAfter(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name     string
+		shimName string
+		args     []string
+		want     bool
+	}{
+		{"pip install", "pip", []string{"install", "black"}, true},
+		{"pip3 uninstall", "pip3", []string{"uninstall", "-y", "black"}, true},
+		{"pip list", "pip", []string{"list"}, false},
+		{"uv tool install", "uv", []string{"tool", "install", "ruff"}, true},
+		{"uv tool uninstall", "uv", []string{"tool", "uninstall", "ruff"}, true},
+		{"uv pip install", "uv", []string{"pip", "install", "--system", "black"}, true},
+		{"uv pip uninstall", "uv", []string{"pip", "uninstall", "--system", "black"}, true},
+		{"uv tool run", "uv", []string{"tool", "run", "ruff"}, false},
+		{"uv sync", "uv", []string{"sync"}, false},
+		{"pipx install", "pipx", []string{"install", "poetry"}, true},
+		{"pipx uninstall", "pipx", []string{"uninstall", "poetry"}, true},
+		{"pipx reinstall", "pipx", []string{"reinstall", "poetry"}, true},
+		{"pipx run", "pipx", []string{"run", "cowsay"}, false},
+		{"python", "python", []string{"-m", "pip", "install"}, false},
+		{"empty args", "pip", []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := provider.ShouldReshimAfter(tt.shimName, tt.args); got != tt.want {
+				t.Errorf("ShouldReshimAfter(%q, %v) = %v, want %v", tt.shimName, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPythonProvider_Capabilities(t *testing.T) {
+	want := runtime.ProviderCapabilities{GlobalPackages: true, Uninstall: true}
+	if got := runtime.CapabilitiesOf(NewProvider()); got != want {
+		t.Errorf("Capabilities() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPythonProvider_ListInstalledBuildAndPrereleaseDirs(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	dirs := []string{"3.12.8", "3.13.1+20251209", "3.14.0rc1"}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(tmpRoot, "versions", "python", dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	p := NewProvider()
+	installed, err := p.ListInstalled()
+	if err != nil {
+		t.Fatalf("ListInstalled() error: %v", err)
+	}
+
+	available := make([]runtime.AvailableVersion, 0, len(installed))
+	for _, v := range installed {
+		available = append(available, runtime.AvailableVersion{Version: v.Version})
+	}
+	runtime.SortVersionsDesc(available)
+
+	want := []string{"3.14.0rc1", "3.13.1+20251209", "3.12.8"}
+	if len(available) != len(want) {
+		t.Fatalf("ListInstalled() = %d versions, want %d", len(available), len(want))
+	}
+	for i, v := range available {
+		if v.Raw != want[i] {
+			t.Errorf("sorted installed[%d] = %q, want %q", i, v.Raw, want[i])
+		}
+	}
+
+	// The directory name is matched exactly
+	if ok, _ := p.IsInstalled("3.13.1+20251209"); !ok {
+		t.Error("IsInstalled(3.13.1+20251209) = false, want true")
+	}
+	if ok, _ := p.IsInstalled("3.13.1"); ok {
+		t.Error("IsInstalled(3.13.1) = true, want only the build-tagged directory to be installed")
+	}
+}
+
+func TestPythonProvider_ListInstalledSymlinkedVersion(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	t.Cleanup(config.ResetPathsCache)
+
+	versionsDir := filepath.Join(tmpRoot, "versions", "python")
+	if err := os.MkdirAll(filepath.Join(versionsDir, "3.12.8"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(versionsDir, "3.12.8"), filepath.Join(versionsDir, "3.12-work")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	installed, err := NewProvider().ListInstalled()
+	if err != nil {
+		t.Fatalf("ListInstalled() error: %v", err)
+	}
+
+	got := make(map[string]bool, len(installed))
+	for _, v := range installed {
+		got[v.Version.Raw] = true
+	}
+	if len(installed) != 2 || !got["3.12.8"] || !got["3.12-work"] {
+		t.Errorf("ListInstalled() = %v, want 3.12.8 and the symlinked 3.12-work", installed)
+	}
+}
+
+func TestPythonProvider_ParseVersionOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "release", output: "Python 3.12.1\n", want: "3.12.1"},
+		{name: "prerelease", output: "Python 3.14.0rc1\r\n", want: "3.14.0rc1"},
+		{name: "other program", output: "pypy 7.3.15\n", wantErr: true},
+		{name: "error message", output: "python: cannot execute binary file\n", wantErr: true},
+		{name: "empty", output: "", wantErr: true},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ParseVersionOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersionOutput(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersionOutput(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPythonProvider_Uninstall(t *testing.T) {
+	tmpRoot := t.TempDir()
+	t.Setenv("DTVEM_ROOT", tmpRoot)
+	config.ResetPathsCache()
+	shim.ResetShimMapCache()
+	t.Cleanup(func() {
+		config.ResetPathsCache()
+		shim.ResetShimMapCache()
+	})
+
+	shimSource := filepath.Join(t.TempDir(), "dtvem-shim")
+	if err := os.WriteFile(shimSource, []byte("fake shim"), 0755); err != nil {
+		t.Fatalf("Failed to create fake shim: %v", err)
+	}
+	manager := shim.NewManagerWithSource(shimSource)
+
+	// black was pip-installed into 3.12.1 only
+	executables := map[string][]string{
+		"3.11.0": {"python3"},
+		"3.12.1": {"python3", "black"},
+	}
+	for version, names := range executables {
+		binDir := filepath.Join(tmpRoot, "versions", "python", version, "bin")
+		if err := os.MkdirAll(binDir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", binDir, err)
+		}
+		for _, name := range names {
+			if goruntime.GOOS == constants.OSWindows {
+				name += constants.ExtExe
+			}
+			if err := os.WriteFile(filepath.Join(binDir, name), []byte("exe"), 0755); err != nil {
+				t.Fatalf("Failed to create %s: %v", name, err)
+			}
+		}
+	}
+	// A read-only leftover like the embeddable package's ._pth file
+	pthFile := filepath.Join(tmpRoot, "versions", "python", "3.12.1", "python312._pth")
+	if err := os.WriteFile(pthFile, []byte("python312.zip\n"), 0444); err != nil {
+		t.Fatalf("Failed to create ._pth file: %v", err)
+	}
+
+	if _, err := manager.Rehash(); err != nil {
+		t.Fatalf("Rehash() error: %v", err)
+	}
+
+	p := NewProvider()
+	if err := p.Uninstall("3.12.1"); err != nil {
+		t.Fatalf("Uninstall() error: %v", err)
+	}
+	// The uninstall and prune commands rehash once the versions are removed
+	if _, _, err := manager.RehashAndPrune(); err != nil {
+		t.Fatalf("RehashAndPrune() error: %v", err)
+	}
+
+	if installed, _ := p.IsInstalled("3.12.1"); installed {
+		t.Error("3.12.1 is still installed")
+	}
+	if _, err := os.Stat(config.ShimPath("black")); !os.IsNotExist(err) {
+		t.Error("shim for black should be removed with the version that provided it")
+	}
+	for _, name := range []string{"python", "python3"} {
+		if _, err := os.Stat(config.ShimPath(name)); err != nil {
+			t.Errorf("shim %s should be kept for 3.11.0: %v", name, err)
+		}
+	}
+
+	if err := p.Uninstall("3.12.1"); err == nil {
+		t.Error("Uninstall() of a version that is not installed should fail")
+	}
+}
@@ -2,6 +2,8 @@
 package ruby
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -46,6 +48,32 @@ func (p *Provider) Shims() []string {
 
 // Install downloads and installs a specific version
 func (p *Provider) Install(version string) error {
+	return p.install(version, true, func(installPath string) error {
+		return p.installFiles(version, manifest.CurrentPlatform(), installPath)
+	})
+}
+
+// InstallWithoutShims downloads and installs a specific version without creating
+// or updating shims, so it is only reachable through exec and env
+func (p *Provider) InstallWithoutShims(version string) error {
+	return p.install(version, false, func(installPath string) error {
+		return p.installFiles(version, manifest.CurrentPlatform(), installPath)
+	})
+}
+
+// InstallFromArchive installs a version from a local archive (or RubyInstaller .exe)
+// instead of downloading it
+func (p *Provider) InstallFromArchive(version, archivePath string) error {
+	return p.install(version, true, func(installPath string) error {
+		ui.Progress("Installing from %s", archivePath)
+		progress := ui.NewProgressLine()
+		progress.Start()
+		return p.installArchive(version, archivePath, installPath, progress)
+	})
+}
+
+// install puts a version's files in place with installFiles and, if withShims is set, creates the shims
+func (p *Provider) install(version string, withShims bool, installFiles func(installPath string) error) error {
 	ui.Debug("Starting Ruby installation for version %s", version)
 
 	// Ensure dtvem directories exist
@@ -53,53 +81,39 @@ func (p *Provider) Install(version string) error {
 		return fmt.Errorf("failed to create dtvem directories: %w", err)
 	}
 
-	// Check if already installed
-	if installed, _ := p.IsInstalled(version); installed {
-		return fmt.Errorf("Ruby %s is already installed", version)
-	}
-
-	ui.Header("Installing Ruby v%s...", version)
-
-	// Get platform-specific download URL
-	downloadURL, archiveName, err := p.getDownloadURL(version)
+	// Wait for another dtvem process installing the same version, then see if it did
+	lock, err := download.LockInstall("ruby", version)
 	if err != nil {
-		return fmt.Errorf("failed to get download URL: %w", err)
+		return err
 	}
-	ui.Debug("Download URL: %s", downloadURL)
-	ui.Debug("Archive name: %s", archiveName)
+	defer lock.Release()
 
-	// Download and extract
-	extractDir, cleanup, err := p.downloadAndExtract(version, downloadURL, archiveName)
-	if err != nil {
-		return err
+	// Check if already installed, which is what was asked for if another process
+	// installed it while this one waited
+	if installed, _ := p.IsInstalled(version); installed {
+		if lock.Waited() {
+			ui.Success("Ruby %s was installed by another dtvem process", version)
+			return nil
+		}
+		return fmt.Errorf("Ruby %s is already installed", version)
 	}
-	defer cleanup()
 
-	// Determine source directory
-	sourceDir := p.determineSourceDir(extractDir)
-	ui.Debug("Source directory: %s", sourceDir)
+	ui.Header("Installing Ruby v%s...", version)
 
-	// Get install path and move files
 	installPath := config.RuntimeVersionPath("ruby", version)
-	ui.Debug("Install path: %s", installPath)
-
-	if err := os.MkdirAll(filepath.Dir(installPath), 0755); err != nil {
-		return fmt.Errorf("failed to create install directory: %w", err)
-	}
-
-	ui.Debug("Moving files from %s to %s", sourceDir, installPath)
-	if err := os.Rename(sourceDir, installPath); err != nil {
-		return fmt.Errorf("failed to move to install location: %w", err)
+	if err := installFiles(installPath); err != nil {
+		return err
 	}
 
-	// Create shims
-	shimSpinner := ui.NewSpinner("Creating shims...")
-	shimSpinner.Start()
-	if err := p.createShims(); err != nil {
-		shimSpinner.Error("Failed to create shims")
-		return fmt.Errorf("failed to create shims: %w", err)
+	if withShims {
+		shimSpinner := ui.NewSpinner("Creating shims...")
+		shimSpinner.Start()
+		if err := p.createShims(); err != nil {
+			shimSpinner.Error("Failed to create shims")
+			return fmt.Errorf("failed to create shims: %w", err)
+		}
+		shimSpinner.Success("Shims created")
 	}
-	shimSpinner.Success("Shims created")
 
 	ui.Success("Ruby v%s installed successfully", version)
 	ui.Info("Location: %s", installPath)
@@ -107,90 +121,139 @@ func (p *Provider) Install(version string) error {
 	return nil
 }
 
-// downloadAndExtract downloads and extracts the Ruby archive
-func (p *Provider) downloadAndExtract(version, downloadURL, archiveName string) (extractDir string, cleanup func(), err error) {
-	ui.Progress("Downloading from %s", downloadURL)
-
+// installArchive extracts a Ruby archive, or runs a RubyInstaller .exe, and moves
+// the result to installPath
+func (p *Provider) installArchive(version, archivePath, installPath string, progress *ui.ProgressLine) error {
 	// Create temporary directory
-	tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("dtvem-ruby-%s", version))
+	tempDir := download.TempDir("ruby", version)
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+		progress.Error("Extraction failed")
+		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
 
-	cleanupFunc := func() { _ = os.RemoveAll(tempDir) }
+	var extractDir string
+	if strings.HasSuffix(archivePath, ".exe") {
+		// Handle .exe installer specially (Windows RubyInstaller)
+		progress.Success("Using installer " + filepath.Base(archivePath))
 
-	// Download archive
-	archivePath := filepath.Join(tempDir, archiveName)
-	if err := download.File(downloadURL, archivePath); err != nil {
-		cleanupFunc()
-		return "", nil, fmt.Errorf("failed to download: %w", err)
-	}
+		var err error
+		if extractDir, err = p.runWindowsInstaller(archivePath, tempDir); err != nil {
+			return err
+		}
+	} else {
+		// Extract archive
+		extractDir = filepath.Join(tempDir, "extracted")
+		progress.Extract()
 
-	// Handle .exe installer specially (Windows RubyInstaller)
-	if strings.HasSuffix(archiveName, ".exe") {
-		return p.runWindowsInstaller(version, archivePath, tempDir, cleanupFunc)
+		if err := download.ExtractWithProgress(archivePath, extractDir, progress.FileExtracted); err != nil {
+			progress.Error("Extraction failed")
+			return fmt.Errorf("failed to extract: %w", err)
+		}
+		progress.Success("Extracted " + filepath.Base(archivePath))
 	}
 
-	// Extract archive
-	extractDir = filepath.Join(tempDir, "extracted")
-	spinner := ui.NewSpinner("Extracting archive...")
-	spinner.Start()
+	// Determine source directory
+	sourceDir := p.determineSourceDir(extractDir)
+	ui.Debug("Source directory: %s", sourceDir)
+	ui.Debug("Install path: %s", installPath)
 
-	var extractErr error
-	if strings.HasSuffix(archiveName, ".zip") {
-		extractErr = download.ExtractZip(archivePath, extractDir)
-	} else if strings.HasSuffix(archiveName, ".tar.gz") || strings.HasSuffix(archiveName, ".tar.xz") {
-		extractErr = download.ExtractTarGz(archivePath, extractDir)
-	} else if strings.HasSuffix(archiveName, ".7z") {
-		extractErr = download.Extract7z(archivePath, extractDir)
-	} else {
-		extractErr = fmt.Errorf("unsupported archive format: %s", archiveName)
+	if err := os.MkdirAll(filepath.Dir(installPath), 0755); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
 	}
 
-	if extractErr != nil {
-		spinner.Error("Extraction failed")
-		cleanupFunc()
-		return "", nil, fmt.Errorf("failed to extract: %w", extractErr)
+	ui.Debug("Moving files from %s to %s", sourceDir, installPath)
+	if err := os.Rename(sourceDir, installPath); err != nil {
+		return fmt.Errorf("failed to move to install location: %w", err)
 	}
 
-	spinner.Success("Extraction complete")
-	return extractDir, cleanupFunc, nil
+	return nil
 }
 
-// runWindowsInstaller runs the RubyInstaller .exe in silent mode
-func (p *Provider) runWindowsInstaller(version, installerPath, tempDir string, cleanupFunc func()) (string, func(), error) {
+// runWindowsInstaller runs the RubyInstaller .exe in silent mode and returns the directory it installed into
+func (p *Provider) runWindowsInstaller(installerPath, tempDir string) (string, error) {
+	arch := installerArch(installerPath)
+	emulated, err := checkInstallerArch(arch, manifest.PlatformArch(manifest.CurrentPlatform()))
+	if err != nil {
+		return "", err
+	}
+	if emulated {
+		ui.Warning("%s is a %s installer; Ruby will run under emulation on this ARM64 machine", filepath.Base(installerPath), arch)
+	}
+
 	// Install to a temporary location, then we'll move it
 	extractDir := filepath.Join(tempDir, "installed")
 
 	spinner := ui.NewSpinner("Running installer (silent mode)...")
 	spinner.Start()
 
-	// Run the installer in very silent mode with:
-	// - /VERYSILENT: no UI at all
-	// - /SUPPRESSMSGBOXES: suppress message boxes
-	// - /NORESTART: don't restart
-	// - /CURRENTUSER: per-user install (no admin required)
-	// - /DIR=...: custom install directory
-	// - /TASKS="": no additional tasks (no PATH modification, no file associations)
-	cmd := exec.Command(installerPath,
-		"/VERYSILENT",
-		"/SUPPRESSMSGBOXES",
-		"/NORESTART",
-		"/CURRENTUSER",
-		"/DIR="+extractDir,
-		"/TASKS=",
-	)
+	cmd := exec.Command(installerPath, windowsInstallerArgs(extractDir)...)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		spinner.Error("Installation failed")
-		cleanupFunc()
 		ui.Debug("Installer output: %s", string(output))
-		return "", nil, fmt.Errorf("installer failed: %w", err)
+		return "", fmt.Errorf("installer failed: %w", err)
 	}
 
 	spinner.Success("Installation complete")
-	return extractDir, cleanupFunc, nil
+	return extractDir, nil
+}
+
+// windowsInstallerArgs returns the arguments that install RubyInstaller into
+// installDir without any interaction. RubyInstaller is an Inno Setup installer,
+// which ignores switches it doesn't know, so installers built with an Inno Setup
+// version that predates /CURRENTUSER still run (as they did before).
+//   - /VERYSILENT: no UI at all
+//   - /SUPPRESSMSGBOXES: suppress message boxes
+//   - /NORESTART: don't restart
+//   - /CURRENTUSER: per-user install (no admin required)
+//   - /NOICONS: no Start Menu folder, whose shortcuts would point at installDir,
+//     which is moved after the install
+//   - /DIR=...: custom install directory
+//   - /TASKS="": no additional tasks (no PATH modification, no file associations)
+func windowsInstallerArgs(installDir string) []string {
+	return []string{
+		"/VERYSILENT",
+		"/SUPPRESSMSGBOXES",
+		"/NORESTART",
+		"/CURRENTUSER",
+		"/NOICONS",
+		"/DIR=" + installDir,
+		"/TASKS=",
+	}
+}
+
+// installerArch returns the architecture of a RubyInstaller .exe from its name,
+// e.g. "amd64" for rubyinstaller-devkit-3.3.0-1-x64.exe, or "" if the name doesn't say
+func installerArch(installerPath string) string {
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(installerPath)), ".exe")
+	switch {
+	case strings.HasSuffix(name, "-x64"):
+		return "amd64"
+	case strings.HasSuffix(name, "-x86"):
+		return "386"
+	case strings.HasSuffix(name, "-arm64"), strings.HasSuffix(name, "-aarch64"):
+		return "arm64"
+	default:
+		return ""
+	}
+}
+
+// checkInstallerArch checks that an installer for installerArch runs on a hostArch
+// machine. It reports whether the installed Ruby runs under emulation, which is the
+// case for x64 and x86 builds on ARM64. An unknown installerArch is assumed to fit.
+func checkInstallerArch(installerArch, hostArch string) (bool, error) {
+	switch {
+	case installerArch == "" || installerArch == hostArch:
+		return false, nil
+	case hostArch == "arm64" && (installerArch == "amd64" || installerArch == "386"):
+		return true, nil
+	case hostArch == "amd64" && installerArch == "386":
+		return false, nil
+	default:
+		return false, fmt.Errorf("the installer is for %s and can't run on this %s machine", installerArch, hostArch)
+	}
 }
 
 // determineSourceDir determines the source directory from extracted archive
@@ -212,25 +275,139 @@ func (p *Provider) determineSourceDir(extractDir string) string {
 	return extractDir
 }
 
-// getDownloadURL returns the download URL and archive name for a given version
-func (p *Provider) getDownloadURL(version string) (string, string, error) {
+// CheckArch returns an error if the version has no download for arch on this OS
+func (p *Provider) CheckArch(version, arch string) error {
+	_, _, err := p.getDownloadURL(version, manifest.PlatformForArch(arch))
+	return err
+}
+
+// InstallArch installs the build of a version for arch into installPath, without shims
+func (p *Provider) InstallArch(version, arch, installPath string) error {
+	ui.Header("Installing Ruby v%s (%s)...", version, arch)
+
+	if err := p.installFiles(version, manifest.PlatformForArch(arch), installPath); err != nil {
+		return err
+	}
+
+	ui.Success("Ruby v%s (%s) installed successfully", version, arch)
+	ui.Info("Location: %s", installPath)
+	return nil
+}
+
+// installFiles downloads the archive of a version for platform and installs its contents to installPath
+func (p *Provider) installFiles(version, platform, installPath string) error {
+	// Get platform-specific download URL and checksum
+	resolved, err := p.resolveInstallDownload(version, platform)
+	if err != nil {
+		return fmt.Errorf("failed to get download URL: %w", err)
+	}
+	if resolved.Platform != platform {
+		ui.Warning("Ruby %s has no %s build; installing the %s build, which runs under emulation",
+			version, manifest.PlatformArch(platform), manifest.PlatformArch(resolved.Platform))
+	}
+	downloadURL, archiveName := resolved.URL, download.ArchiveName(resolved.URL, resolved.Format)
+
+	// A corrupt archive is discarded and downloaded once more
+	return download.RetryCorrupt("ruby", version, archiveName, func() error {
+		ui.Progress("Downloading from %s", downloadURL)
+
+		// Download and extract the archive on a single progress line
+		progress := ui.NewProgressLine()
+		progress.Start()
+
+		// Download archive (kept in the cache until the install succeeds)
+		archivePath, err := download.CachedArchiveWithProgress("ruby", version, downloadURL, archiveName, resolved.SHA256, progress.Download)
+		if err != nil {
+			progress.Error("Download failed")
+			return fmt.Errorf("failed to download: %w", err)
+		}
+
+		if err := p.installArchive(version, archivePath, installPath, progress); err != nil {
+			return err
+		}
+		download.RemoveCachedArchive("ruby", version, archiveName)
+
+		return nil
+	})
+}
+
+// Prefetch downloads the archive for a version into the archive cache
+func (p *Provider) Prefetch(version string) error {
+	resolved, err := p.resolveInstallDownload(version, manifest.CurrentPlatform())
+	if err != nil {
+		return err
+	}
+	return download.PrefetchArchive("ruby", version, resolved.URL, download.ArchiveName(resolved.URL, resolved.Format), resolved.SHA256)
+}
+
+// ResolveDownload returns where the archive for a version on the current platform comes from
+func (p *Provider) ResolveDownload(version string) (*runtime.ResolvedDownload, error) {
+	return p.resolveInstallDownload(version, manifest.CurrentPlatform())
+}
+
+// getDownloadURL returns the download URL and archive name for a given version and platform
+func (p *Provider) getDownloadURL(version, platform string) (string, string, error) {
+	resolved, err := p.resolveDownload(version, platform)
+	if err != nil {
+		return "", "", err
+	}
+	return resolved.URL, download.ArchiveName(resolved.URL, resolved.Format), nil
+}
+
+// resolveDownload looks up the download for a version and platform in the manifest
+func (p *Provider) resolveDownload(version, platform string) (*runtime.ResolvedDownload, error) {
 	// Get the manifest (uses cached remote with embedded fallback)
 	m, err := manifest.DefaultSource().GetManifest("ruby")
 	if err != nil {
-		return "", "", fmt.Errorf("failed to load manifest: %w", err)
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
+	}
+	return downloadFromManifest(m, version, platform)
+}
+
+// resolveInstallDownload looks up the download to install a version on platform,
+// which may be an emulated build (see installPlatform)
+func (p *Provider) resolveInstallDownload(version, platform string) (*runtime.ResolvedDownload, error) {
+	m, err := manifest.DefaultSource().GetManifest("ruby")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest: %w", err)
 	}
+	return downloadFromManifest(m, version, installPlatform(m, version, platform))
+}
 
+// installPlatform returns the platform whose build of a version is installed on
+// platform. Ruby has no Windows ARM64 builds, so Windows ARM64 gets the x64 build,
+// which Windows runs under emulation.
+func installPlatform(m *manifest.Manifest, version, platform string) string {
+	if platform != manifest.PlatformWindowsARM64 || m.GetDownload(version, platform) != nil {
+		return platform
+	}
+	if emulated, ok := manifest.EmulatedPlatform(platform); ok && m.GetDownload(version, emulated) != nil {
+		return emulated
+	}
+	return platform
+}
+
+// downloadFromManifest returns the download for a version and platform in m
+func downloadFromManifest(m *manifest.Manifest, version, platform string) (*runtime.ResolvedDownload, error) {
 	// Get the download info for this version and platform
-	platform := manifest.CurrentPlatform()
 	dl := m.GetDownload(version, platform)
 	if dl == nil {
-		return "", "", fmt.Errorf("Ruby %s is not available for %s", version, platform)
+		return nil, &runtime.VersionNotAvailableError{
+			Runtime:     "ruby",
+			DisplayName: "Ruby",
+			Version:     version,
+			Platform:    platform,
+			Hint:        m.EmulationHint(version, platform),
+		}
 	}
 
-	// Extract archive name from URL
-	archiveName := filepath.Base(dl.URL)
-
-	return dl.URL, archiveName, nil
+	return &runtime.ResolvedDownload{
+		Platform:     platform,
+		URL:          dl.URL,
+		SHA256:       dl.SHA256,
+		SHA256Source: dl.SHA256Source,
+		Format:       dl.Format,
+	}, nil
 }
 
 // createShims creates shims for Ruby executables
@@ -249,7 +426,7 @@ func (p *Provider) createShims() error {
 
 // Uninstall removes an installed version
 func (p *Provider) Uninstall(version string) error {
-	return fmt.Errorf("not yet implemented")
+	return runtime.ErrNotImplemented
 }
 
 // ListInstalled returns all installed Ruby versions
@@ -271,7 +448,8 @@ func (p *Provider) ListInstalled() ([]runtime.InstalledVersion, error) {
 	// Build list of installed versions
 	versions := make([]runtime.InstalledVersion, 0)
 	for _, entry := range entries {
-		if entry.IsDir() {
+		// Skip stray files (e.g. .DS_Store) and directories that aren't versions
+		if runtime.IsVersionDir(rubyVersionsDir, entry) {
 			versions = append(versions, runtime.InstalledVersion{
 				Version:     runtime.NewVersion(entry.Name()),
 				InstallPath: filepath.Join(rubyVersionsDir, entry.Name()),
@@ -283,7 +461,8 @@ func (p *Provider) ListInstalled() ([]runtime.InstalledVersion, error) {
 	return versions, nil
 }
 
-// ListAvailable returns all available Ruby versions
+// ListAvailable returns all available Ruby versions, including those installed as
+// an emulated build (see installPlatform)
 func (p *Provider) ListAvailable() ([]runtime.AvailableVersion, error) {
 	// Get the manifest (uses cached remote with embedded fallback)
 	m, err := manifest.DefaultSource().GetManifest("ruby")
@@ -291,23 +470,33 @@ func (p *Provider) ListAvailable() ([]runtime.AvailableVersion, error) {
 		return nil, fmt.Errorf("failed to load manifest: %w", err)
 	}
 
-	// Get versions available for current platform
-	platform := manifest.CurrentPlatform()
-	versionStrings := m.ListAvailableVersions(platform)
+	return installableVersions(m, manifest.CurrentPlatform()), nil
+}
 
-	// Convert to AvailableVersion format and sort by semantic version (newest first)
-	versions := make([]runtime.AvailableVersion, 0, len(versionStrings))
-	for _, v := range versionStrings {
-		versions = append(versions, runtime.AvailableVersion{
-			Version: runtime.NewVersion(v),
-			Notes:   "",
-		})
+// installableVersions returns the versions installPlatform finds a build of for
+// platform: the native builds, and on Windows ARM64 the x64 builds as well
+func installableVersions(m *manifest.Manifest, platform string) []runtime.AvailableVersion {
+	versions := m.AvailableVersions(platform)
+	if platform != manifest.PlatformWindowsARM64 {
+		return versions
+	}
+	emulated, ok := manifest.EmulatedPlatform(platform)
+	if !ok {
+		return versions
 	}
 
-	// Sort by version descending (newest first)
+	native := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		native[v.Version.Raw] = true
+	}
+	for _, v := range m.AvailableVersions(emulated) {
+		if !native[v.Version.Raw] {
+			versions = append(versions, v)
+		}
+	}
 	runtime.SortVersionsDesc(versions)
 
-	return versions, nil
+	return versions
 }
 
 // ExecutablePath returns the path to the Ruby executable
@@ -393,6 +582,13 @@ func (p *Provider) DetectInstalled() ([]runtime.DetectedVersion, error) {
 
 // GlobalPackages detects globally installed gems
 func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), runtime.PackageListTimeout)
+	defer cancel()
+	return p.GlobalPackagesContext(ctx, installPath)
+}
+
+// GlobalPackagesContext detects globally installed gems, giving up when ctx ends
+func (p *Provider) GlobalPackagesContext(ctx context.Context, installPath string) ([]string, error) {
 	// Find gem executable in the installation
 	gemPath := findGemInInstall(installPath)
 	if gemPath == "" {
@@ -400,13 +596,23 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 	}
 
 	// Run gem list --no-details
-	cmd := exec.Command(gemPath, "list", "--no-details")
-	output, err := cmd.Output()
+	output, err := runtime.RunPackageCommand(ctx, nil, gemPath, "list", "--no-details")
+	if ctx.Err() != nil {
+		// gem lists one gem per line, so the complete lines so far are usable
+		complete := output[:bytes.LastIndexByte(output, '\n')+1]
+		return parseGemList(complete), err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to list gems: %w", err)
 	}
 
-	// Parse output - each line is "gemname (version)" or just "gemname"
+	return parseGemList(output), nil
+}
+
+// parseGemList returns the gem names of `gem list --no-details` output, except
+// default and bundled gems
+func parseGemList(output []byte) []string {
+	// Each line is "gemname (version)" or just "gemname"
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 	packages := make([]string, 0, len(lines))
 
@@ -511,11 +717,19 @@ func (p *Provider) GlobalPackages(installPath string) ([]string, error) {
 		}
 	}
 
-	return packages, nil
+	return packages
 }
 
 // InstallGlobalPackages reinstalls global gems to a specific version
 func (p *Provider) InstallGlobalPackages(version string, packages []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), runtime.PackageInstallTimeout)
+	defer cancel()
+	return p.InstallGlobalPackagesContext(ctx, version, packages)
+}
+
+// InstallGlobalPackagesContext reinstalls global gems to a specific version,
+// showing gem's output as it runs and giving up when ctx ends
+func (p *Provider) InstallGlobalPackagesContext(ctx context.Context, version string, packages []string) error {
 	if len(packages) == 0 {
 		return nil
 	}
@@ -535,12 +749,10 @@ func (p *Provider) InstallGlobalPackages(version string, packages []string) erro
 
 	// Install all gems at once
 	args := append([]string{"install"}, packages...)
-	cmd := exec.Command(gemPath, args...)
-
-	// Capture output for errors
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("gem install failed: %w\n%s", err, string(output))
+	output := ui.NewCommandOutput()
+	defer output.Flush()
+	if _, err := runtime.RunPackageCommand(ctx, output, gemPath, args...); err != nil {
+		return fmt.Errorf("gem install failed: %w", err)
 	}
 
 	return nil
@@ -591,28 +803,64 @@ func findGemInInstall(installDir string) string {
 	return ""
 }
 
+// reshimTriggers are the commands that can add or remove executables
+var reshimTriggers = []runtime.ReshimTrigger{
+	// gem install/uninstall can add/remove executables
+	{
+		Shims:       []string{"gem"},
+		Subcommands: []string{"install", "uninstall"},
+	},
+	// bundle install/update can add/remove executables via binstubs
+	{
+		Shims:       []string{"bundle"},
+		Subcommands: []string{"install", "update"},
+	},
+}
+
 // ShouldReshimAfter checks if the given command should trigger a reshim.
 // Returns true if the command installs or uninstalls gems.
 func (p *Provider) ShouldReshimAfter(shimName string, args []string) bool {
-	// gem install/uninstall can add/remove executables
-	if shimName == "gem" {
-		if len(args) == 0 {
-			return false
-		}
-		cmd := args[0]
-		return cmd == "install" || cmd == "uninstall"
-	}
+	return runtime.ShouldReshim(reshimTriggers, shimName, args)
+}
 
-	// bundle install/update can add/remove executables via binstubs
-	if shimName == "bundle" {
-		if len(args) == 0 {
-			return false
-		}
-		cmd := args[0]
-		return cmd == "install" || cmd == "update"
-	}
+// Capabilities reports that gems can be migrated. Uninstall is left to the
+// uninstall command.
+func (p *Provider) Capabilities() runtime.ProviderCapabilities {
+	return runtime.ProviderCapabilities{GlobalPackages: true}
+}
+
+// MinSupportedVersion returns 2.1.0, the series of the oldest prebuilt Ruby (2.1.9,
+// for darwin-amd64 and linux-amd64 only). Other platforms start later (windows-amd64
+// at 2.4.0, darwin-arm64 at 2.6.7); the manifest reports those versions as unavailable.
+func (p *Provider) MinSupportedVersion() (runtime.Version, bool) {
+	return runtime.NewVersion("2.1.0"), true
+}
+
+// VersionArgs returns the arguments that make ruby print its version
+func (p *Provider) VersionArgs() []string {
+	return []string{"--version"}
+}
+
+// rubyVersionPattern matches the version in `ruby --version` output: the release,
+// an optional patch level (dropped, as versions are named without it) and an
+// optional prerelease tag, as in "3.3.0p0" or "3.4.0preview1"
+var rubyVersionPattern = regexp.MustCompile(`^(\d+\.\d+\.\d+)(?:p\d+)?([A-Za-z][A-Za-z0-9.]*)?$`)
 
-	return false
+// ParseVersionOutput extracts the version from `ruby --version` output, e.g.
+// "ruby 3.3.0p0 (2023-12-25 revision 5124f9ac75) [x86_64-linux]"
+func (p *Provider) ParseVersionOutput(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 || fields[0] != "ruby" {
+		return "", fmt.Errorf("unexpected ruby --version output: %q", strings.TrimSpace(output))
+	}
+	matches := rubyVersionPattern.FindStringSubmatch(fields[1])
+	if matches == nil {
+		return "", fmt.Errorf("unexpected ruby --version output: %q", strings.TrimSpace(output))
+	}
+	if matches[2] != "" {
+		return matches[1] + "-" + matches[2], nil
+	}
+	return matches[1], nil
 }
 
 // GetEnvironment returns environment variables needed to run Ruby binaries.
@@ -1,8 +1,18 @@
 package ruby
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	goruntime "runtime"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dtvem/dtvem/src/internal/constants"
+	"github.com/dtvem/dtvem/src/internal/manifest"
 	"github.com/dtvem/dtvem/src/internal/runtime"
 	"github.com/dtvem/dtvem/src/internal/testutil"
 )
@@ -181,3 +191,248 @@ func TestRubyProvider_ShouldReshimAfter(t *testing.T) {
 		})
 	}
 }
+
+func TestAvailableVersions_ReleaseNotes(t *testing.T) {
+	m := &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"3.1.6": {manifest.PlatformLinuxAMD64: {URL: "https://example.com/ruby/3.1.6/linux-amd64.tar.gz"}},
+			"3.4.1": {manifest.PlatformLinuxAMD64: {URL: "https://example.com/ruby/3.4.1/linux-amd64.tar.gz"}},
+			"9.9.9": {manifest.PlatformLinuxAMD64: {URL: "https://example.com/ruby/9.9.9/linux-amd64.tar.gz"}},
+		},
+		Releases: map[string]*manifest.Release{
+			"3.1.6": {Date: "2024-05-29", EOL: true},
+			"3.4.1": {Date: "2024-12-25"},
+		},
+	}
+
+	want := map[string]string{
+		"3.1.6": "EOL",
+		"3.4.1": "released 2024-12-25",
+		"9.9.9": "",
+	}
+
+	got := m.AvailableVersions(manifest.PlatformLinuxAMD64)
+	if len(got) != len(want) {
+		t.Fatalf("AvailableVersions() returned %d versions, want %d", len(got), len(want))
+	}
+	for _, v := range got {
+		if v.Notes != want[v.Version.Raw] {
+			t.Errorf("Notes for %s = %q, want %q", v.Version.Raw, v.Notes, want[v.Version.Raw])
+		}
+	}
+	if got[0].Version.Raw != "9.9.9" {
+		t.Errorf("AvailableVersions()[0] = %s, want newest first", got[0].Version.Raw)
+	}
+}
+
+func TestRubyProvider_Capabilities(t *testing.T) {
+	want := runtime.ProviderCapabilities{GlobalPackages: true}
+	if got := runtime.CapabilitiesOf(NewProvider()); got != want {
+		t.Errorf("Capabilities() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRubyProvider_GlobalPackagesContext_Timeout(t *testing.T) {
+	if goruntime.GOOS == constants.OSWindows {
+		t.Skip("the fake gem command is a shell script")
+	}
+
+	// A gem command that lists some gems and then hangs mid-line
+	installPath := t.TempDir()
+	binDir := filepath.Join(installPath, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("Failed to create bin directory: %v", err)
+	}
+	script := "#!/bin/sh\nprintf 'rails (7.1.3)\\nrake (13.1.0)\\nrspec (3.13.0)\\npum'\nexec sleep 30\n"
+	if err := os.WriteFile(filepath.Join(binDir, "gem"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create fake gem: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	packages, err := NewProvider().GlobalPackagesContext(ctx, installPath)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GlobalPackagesContext() error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+	// The bundled rake is skipped and the unfinished last line is dropped
+	if !reflect.DeepEqual(packages, []string{"rails", "rspec"}) {
+		t.Errorf("GlobalPackagesContext() = %v, want [rails rspec]", packages)
+	}
+}
+
+func TestRubyProvider_ParseVersionOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "release with patch level", output: "ruby 3.3.0p0 (2023-12-25 revision 5124f9ac75) [x86_64-linux]\n", want: "3.3.0"},
+		{name: "release with YJIT", output: "ruby 3.2.2 (2023-03-30 revision e51014f9c0) +YJIT [arm64-darwin22]\n", want: "3.2.2"},
+		{name: "prerelease", output: "ruby 3.4.0preview1 (2024-05-16 master 9d69619623) [x64-mingw-ucrt]\r\n", want: "3.4.0-preview1"},
+		{name: "other program", output: "jruby 9.4.5.0 (3.1.4) 2023-11-02\n", wantErr: true},
+		{name: "error message", output: "ruby: error while loading shared libraries: libruby.so.3.3\n", wantErr: true},
+		{name: "empty", output: "", wantErr: true},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ParseVersionOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersionOutput(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersionOutput(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+// testEmulationManifest has a version with only an x64 build on Windows, one with a
+// native Windows ARM64 build and one without a Windows build
+func testEmulationManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		Version: 1,
+		Versions: map[string]map[string]*manifest.Download{
+			"3.3.0": {
+				manifest.PlatformWindowsAMD64: {URL: "https://example.com/ruby/3.3.0/windows-amd64.7z"},
+				manifest.PlatformLinuxARM64:   {URL: "https://example.com/ruby/3.3.0/linux-arm64.tar.gz"},
+			},
+			"3.4.0": {
+				manifest.PlatformWindowsAMD64: {URL: "https://example.com/ruby/3.4.0/windows-amd64.7z"},
+				manifest.PlatformWindowsARM64: {URL: "https://example.com/ruby/3.4.0/windows-arm64.7z"},
+			},
+			"3.2.0": {
+				manifest.PlatformLinuxAMD64: {URL: "https://example.com/ruby/3.2.0/linux-amd64.tar.gz"},
+			},
+		},
+	}
+}
+
+func TestInstallPlatform(t *testing.T) {
+	m := testEmulationManifest()
+
+	tests := []struct {
+		name     string
+		version  string
+		platform string
+		want     string
+	}{
+		{"Windows ARM64 falls back to x64", "3.3.0", manifest.PlatformWindowsARM64, manifest.PlatformWindowsAMD64},
+		{"native Windows ARM64 build preferred", "3.4.0", manifest.PlatformWindowsARM64, manifest.PlatformWindowsARM64},
+		{"no x64 build to fall back to", "3.2.0", manifest.PlatformWindowsARM64, manifest.PlatformWindowsARM64},
+		{"Windows x64 unchanged", "3.3.0", manifest.PlatformWindowsAMD64, manifest.PlatformWindowsAMD64},
+		{"other platforms unchanged", "3.3.0", manifest.PlatformDarwinARM64, manifest.PlatformDarwinARM64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := installPlatform(m, tt.version, tt.platform); got != tt.want {
+				t.Errorf("installPlatform(%s, %s) = %s, want %s", tt.version, tt.platform, got, tt.want)
+			}
+		})
+	}
+
+	// The fallback resolves to the x64 download, so installs can warn about emulation
+	resolved, err := downloadFromManifest(m, "3.3.0", installPlatform(m, "3.3.0", manifest.PlatformWindowsARM64))
+	if err != nil {
+		t.Fatalf("downloadFromManifest() error: %v", err)
+	}
+	if resolved.Platform != manifest.PlatformWindowsAMD64 || resolved.URL != "https://example.com/ruby/3.3.0/windows-amd64.7z" {
+		t.Errorf("downloadFromManifest() = %+v, want the windows-amd64 download", resolved)
+	}
+}
+
+func TestInstallableVersions(t *testing.T) {
+	m := testEmulationManifest()
+
+	tests := []struct {
+		platform string
+		want     string
+	}{
+		{manifest.PlatformWindowsARM64, "3.4.0,3.3.0"},
+		{manifest.PlatformWindowsAMD64, "3.4.0,3.3.0"},
+		{manifest.PlatformLinuxAMD64, "3.2.0"},
+		{manifest.PlatformDarwinARM64, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			var got []string
+			for _, v := range installableVersions(m, tt.platform) {
+				got = append(got, v.Version.Raw)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("installableVersions(%s) = %v, want %s", tt.platform, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallerArch(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`C:\Downloads\rubyinstaller-devkit-3.3.0-1-x64.exe`, "amd64"},
+		{"rubyinstaller-2.7.8-1-x86.exe", "386"},
+		{"RUBYINSTALLER-3.4.1-1-ARM64.EXE", "arm64"},
+		{"rubyinstaller-3.4.1-1-aarch64.exe", "arm64"},
+		{"ruby-setup.exe", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := installerArch(tt.path); got != tt.want {
+				t.Errorf("installerArch(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckInstallerArch(t *testing.T) {
+	tests := []struct {
+		name          string
+		installerArch string
+		hostArch      string
+		wantEmulated  bool
+		wantErr       bool
+	}{
+		{"native x64", "amd64", "amd64", false, false},
+		{"unknown installer", "", "arm64", false, false},
+		{"x64 on ARM64 is emulated", "amd64", "arm64", true, false},
+		{"x86 on ARM64 is emulated", "386", "arm64", true, false},
+		{"x86 on x64 runs under WOW64", "386", "amd64", false, false},
+		{"ARM64 on x64 can't run", "arm64", "amd64", false, true},
+		{"x64 on x86 can't run", "amd64", "386", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emulated, err := checkInstallerArch(tt.installerArch, tt.hostArch)
+			if emulated != tt.wantEmulated || (err != nil) != tt.wantErr {
+				t.Errorf("checkInstallerArch(%q, %q) = (%v, %v), want (%v, error %v)",
+					tt.installerArch, tt.hostArch, emulated, err, tt.wantEmulated, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWindowsInstallerArgs(t *testing.T) {
+	got := windowsInstallerArgs(`C:\dtvem\tmp\installed`)
+	want := []string{
+		"/VERYSILENT",
+		"/SUPPRESSMSGBOXES",
+		"/NORESTART",
+		"/CURRENTUSER",
+		"/NOICONS",
+		`/DIR=C:\dtvem\tmp\installed`,
+		"/TASKS=",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("windowsInstallerArgs() = %v, want %v", got, want)
+	}
+}